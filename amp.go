@@ -0,0 +1,53 @@
+package readability
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-shiori/dom"
+)
+
+// getAMPURL returns the absolute URL of the AMP version of the page,
+// which is specified using <link rel="amphtml">.
+func (ps *Parser) getAMPURL() string {
+	for _, link := range dom.QuerySelectorAll(ps.doc, `link[rel][href]`) {
+		for _, rel := range strings.Fields(dom.GetAttribute(link, "rel")) {
+			if strings.EqualFold(rel, "amphtml") {
+				href := strings.TrimSpace(dom.GetAttribute(link, "href"))
+				return toAbsoluteURI(href, ps.documentURI)
+			}
+		}
+	}
+	return ""
+}
+
+// fetchAMP fetches the AMP version of the article when its content is
+// too short or extracted with low confidence. The content of the article
+// is replaced only if the AMP version is longer, or more confident for
+// the latter, while its metadata is kept as it is. The AMP version that
+// can't be fetched is only logged, since the article is still usable.
+func (f *Fetcher) fetchAMP(ctx context.Context, article *Article) error {
+	tooShort := f.AMPFallbackLength > 0 && article.Length < f.AMPFallbackLength
+	unsure := f.AMPFallbackConfidence > 0 && article.Confidence < f.AMPFallbackConfidence
+	if article.AMPURL == "" || (!tooShort && !unsure) {
+		return nil
+	}
+
+	parser := f.parser()
+	ampArticle, _, err := f.fetchArticle(ctx, article.AMPURL)
+	if err != nil {
+		parser.logWarn("failed to fetch AMP version", "url", article.AMPURL, "error", err)
+		return nil
+	}
+
+	if (!tooShort || ampArticle.Length <= article.Length) &&
+		(!unsure || ampArticle.Confidence <= article.Confidence) {
+		return nil
+	}
+
+	if err = parser.replaceContent(article, ampArticle); err != nil {
+		return fmt.Errorf("failed to use AMP version: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package readability
+
+import (
+	"strings"
+
+	"github.com/go-shiori/dom"
+	"golang.org/x/net/html"
+)
+
+// ContentNode returns the root node of the cleaned article content, which
+// contains all of its pages. This is the same tree that serialized into
+// Content, so it can be modified further without parsing Content again.
+// Be aware that Content is not updated when the tree is modified. If the
+// article doesn't have node, e.g. it's decoded from JSON, its HTML content
+// will be parsed instead.
+func (article Article) ContentNode() *html.Node {
+	if article.Node != nil {
+		return pagesContainer(article.Node)
+	}
+
+	if strings.TrimSpace(article.Content) == "" {
+		return nil
+	}
+
+	root := dom.CreateElement("div")
+	dom.SetInnerHTML(root, article.Content)
+	return root
+}
@@ -0,0 +1,42 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-shiori/dom"
+)
+
+func Test_Article_ContentNode(t *testing.T) {
+	paragraph := articleParagraph(10)
+	input := "<html><body><article>" + paragraph + paragraph + "</article></body></html>"
+
+	article, err := FromReader(strings.NewReader(input), fakeHostURL)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	// Node of parsed article is used directly, without parsing the content
+	root := article.ContentNode()
+	if root == nil || root != article.Node.Parent {
+		t.Fatalf("content node should be the container of article node")
+	}
+
+	if html := dom.InnerHTML(root); html != article.Content {
+		t.Errorf("\n"+
+			"want : %q\n"+
+			"got  : %q", article.Content, html)
+	}
+
+	// Article without node, e.g. decoded from JSON, uses its content
+	decoded := Article{Content: article.Content}
+	if html := dom.InnerHTML(decoded.ContentNode()); html != article.Content {
+		t.Errorf("\n"+
+			"want : %q\n"+
+			"got  : %q", article.Content, html)
+	}
+
+	if (Article{}).ContentNode() != nil {
+		t.Errorf("empty article should not have content node")
+	}
+}
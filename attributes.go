@@ -0,0 +1,39 @@
+package readability
+
+import (
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// rxUnsafeStyle matches the CSS values that able to load resource or run
+// script in some browsers.
+var rxUnsafeStyle = regexp.MustCompile(`(?i)url\s*\(|expression\s*\(|javascript:|behavior\s*:|@import|[\\<>]`)
+
+// AttributeFilter decides whether an attribute is kept when the article
+// content is cleaned up. The keep is the decision of the cleaner, so the
+// filter may only override the attributes it's interested in.
+type AttributeFilter func(node *html.Node, attr html.Attribute, keep bool) bool
+
+// filterStyle keeps the declarations in style attribute whose property
+// matches any of properties. If safeOnly is true, declarations with value
+// that may load resource or run script are removed as well.
+func filterStyle(style string, properties []string, safeOnly bool) string {
+	var kept []string
+	for _, declaration := range strings.Split(style, ";") {
+		property, value, found := strings.Cut(declaration, ":")
+		property = strings.TrimSpace(property)
+		value = strings.TrimSpace(value)
+		if !found || property == "" || value == "" || !matchNamePattern(property, properties) {
+			continue
+		}
+
+		if safeOnly && rxUnsafeStyle.MatchString(value) {
+			continue
+		}
+
+		kept = append(kept, strings.ToLower(property)+": "+value)
+	}
+	return strings.Join(kept, "; ")
+}
@@ -0,0 +1,72 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+)
+
+func Test_Parser_KeepAttributes(t *testing.T) {
+	paragraph := articleParagraph(10)
+	input := `<html><body><article>` + paragraph +
+		`<p data-line="12" aria-label="Note" align="center" style="text-align: center; color: red; ` +
+		`background: url(x.png)" onclick="alert(1)">Styled</p>` +
+		`<pre width="80" bgcolor="white">Code</pre>` + paragraph + `</article></body></html>`
+
+	tests := []struct {
+		name       string
+		opts       []Option
+		expected   []string
+		unexpected []string
+	}{{
+		name:       "default",
+		expected:   []string{`data-line="12"`, `aria-label="Note"`},
+		unexpected: []string{`align=`, `style=`, `width=`, `bgcolor=`},
+	}, {
+		name:       "keep attributes",
+		opts:       []Option{WithKeepAttributes("align", "bgcolor")},
+		expected:   []string{`align="center"`, `bgcolor="white"`},
+		unexpected: []string{`style=`, `width=`},
+	}, {
+		name:       "keep styles",
+		opts:       []Option{WithKeepStyles("text-align", "background*")},
+		expected:   []string{`style="text-align: center; background: url(x.png)"`},
+		unexpected: []string{`color: red`},
+	}, {
+		name: "attribute filter",
+		opts: []Option{WithAttributeFilter(func(node *html.Node, attr html.Attribute, keep bool) bool {
+			return keep && !strings.HasPrefix(attr.Key, "data-") || attr.Key == "width"
+		})},
+		expected:   []string{`width="80"`, `aria-label="Note"`},
+		unexpected: []string{`data-line=`, `bgcolor=`},
+	}, {
+		name: "sanitize",
+		opts: []Option{WithSanitize(nil), WithKeepAttributes("data-*", "onclick", "style"),
+			WithKeepStyles("text-align", "background")},
+		expected:   []string{`data-line="12"`, `style="text-align: center"`},
+		unexpected: []string{`aria-label=`, `onclick=`, `url(x.png)`},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ps := NewParser(test.opts...)
+			article, err := ps.Parse(strings.NewReader(input), fakeHostURL)
+			if err != nil {
+				t.Fatalf("failed to parse: %v", err)
+			}
+
+			for _, expected := range test.expected {
+				if !strings.Contains(article.Content, expected) {
+					t.Errorf("content should contain %q: %s", expected, article.Content)
+				}
+			}
+
+			for _, unexpected := range test.unexpected {
+				if strings.Contains(article.Content, unexpected) {
+					t.Errorf("content should not contain %q: %s", unexpected, article.Content)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package readability
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/go-shiori/dom"
+)
+
+var (
+	rxBylinePrefix    = regexp.MustCompile(`(?i)^\s*(by|written by|posted by|von|par|por)\s*:?\s+`)
+	rxBylineSeparator = regexp.MustCompile(`(?i)\s*(?:,|;|&|\band\b|\bund\b)\s*`)
+)
+
+// Author is the author of the article.
+type Author struct {
+	Name string `json:"name"`
+	URL  string `json:"url,omitempty"`
+}
+
+// jsonLDAuthors converts the value of "author" in JSON-LD into authors.
+// The value may be a single author or an array of them, and each author
+// may be written as Person object or just its name.
+func jsonLDAuthors(value interface{}) []Author {
+	var authors []Author
+	switch val := value.(type) {
+	case string:
+		if name := strings.TrimSpace(val); name != "" {
+			authors = append(authors, Author{Name: name})
+		}
+
+	case map[string]interface{}:
+		name, _ := val["name"].(string)
+		url, _ := val["url"].(string)
+		if url == "" {
+			url, _ = val["sameAs"].(string)
+		}
+
+		if name = strings.TrimSpace(name); name != "" {
+			authors = append(authors, Author{Name: name, URL: strings.TrimSpace(url)})
+		}
+
+	case []interface{}:
+		for _, item := range val {
+			authors = append(authors, jsonLDAuthors(item)...)
+		}
+	}
+	return authors
+}
+
+// getRelAuthors returns the authors from the links with rel="author".
+func (ps *Parser) getRelAuthors() []Author {
+	var authors []Author
+	seen := make(map[string]struct{})
+	for _, a := range dom.QuerySelectorAll(ps.doc, `a[rel~="author"]`) {
+		name := strings.Join(strings.Fields(dom.TextContent(a)), " ")
+		if name == "" {
+			continue
+		}
+
+		if _, exist := seen[name]; exist {
+			continue
+		}
+		seen[name] = struct{}{}
+
+		url := dom.GetAttribute(a, "href")
+		if url != "" {
+			url = toAbsoluteURI(url, ps.documentURI)
+		}
+		authors = append(authors, Author{Name: name, URL: url})
+	}
+	return authors
+}
+
+// splitByline splits byline like "By A, B and C" into the names of its
+// authors.
+func splitByline(byline string) []string {
+	byline = strings.Join(strings.Fields(byline), " ")
+	byline = rxBylinePrefix.ReplaceAllString(byline, "")
+
+	var names []string
+	for _, name := range rxBylineSeparator.Split(byline, -1) {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// getArticleAuthors returns the authors of article. The authors in JSON-LD
+// are preferred since they're structured, followed by author of h-entry and
+// microdata, rel="author" links, and finally the names in byline.
+func (ps *Parser) getArticleAuthors(byline string, hEntry *HEntry, microdata *Microdata) []Author {
+	if len(ps.jsonLdAuthors) > 0 {
+		return ps.jsonLdAuthors
+	}
+
+	if hEntry != nil && hEntry.Author != nil {
+		return []Author{{Name: hEntry.Author.Name, URL: hEntry.Author.URL}}
+	}
+
+	if microdata != nil && len(microdata.Authors) > 0 {
+		return microdata.Authors
+	}
+
+	if authors := ps.getRelAuthors(); len(authors) > 0 {
+		return authors
+	}
+
+	var authors []Author
+	for _, name := range splitByline(byline) {
+		authors = append(authors, Author{Name: name})
+	}
+	return authors
+}
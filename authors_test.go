@@ -0,0 +1,70 @@
+package readability
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_splitByline(t *testing.T) {
+	scenarios := map[string][]string{
+		"":                              nil,
+		"John Doe":                      {"John Doe"},
+		"By John Doe":                   {"John Doe"},
+		"by: John Doe and Jane Roe":     {"John Doe", "Jane Roe"},
+		"By A. Smith, B. Jones & C. Li": {"A. Smith", "B. Jones", "C. Li"},
+		"Alexander Anderson; Brandon":   {"Alexander Anderson", "Brandon"},
+	}
+
+	for byline, expected := range scenarios {
+		if names := splitByline(byline); !reflect.DeepEqual(names, expected) {
+			t.Errorf("\n"+
+				"byline : %q\n"+
+				"want   : %q\n"+
+				"got    : %q", byline, expected, names)
+		}
+	}
+}
+
+func Test_Parser_Authors(t *testing.T) {
+	paragraph := articleParagraph(10)
+	article := `<article>` + paragraph + paragraph + `</article>`
+
+	scenarios := []struct {
+		html     string
+		expected []Author
+	}{{
+		html: `<html><head><script type="application/ld+json">{"@context": "https://schema.org", "@type": "NewsArticle", ` +
+			`"author": [{"@type": "Person", "name": "John Doe", "url": "http://fakehost/john"}, "Jane Roe"]}</script></head>` +
+			`<body>` + article + `</body></html>`,
+		expected: []Author{{Name: "John Doe", URL: "http://fakehost/john"}, {Name: "Jane Roe"}},
+	}, {
+		html: `<html><body><div class="meta">Written by <a rel="author" href="/authors/john">John Doe</a> and ` +
+			`<a rel="author" href="/authors/jane">Jane Roe</a></div>` + article + `</body></html>`,
+		expected: []Author{
+			{Name: "John Doe", URL: "http://fakehost/authors/john"},
+			{Name: "Jane Roe", URL: "http://fakehost/authors/jane"},
+		},
+	}, {
+		html:     `<html><head><meta name="author" content="By John Doe and Jane Roe"></head><body>` + article + `</body></html>`,
+		expected: []Author{{Name: "John Doe"}, {Name: "Jane Roe"}},
+	}, {
+		html:     `<html><body>` + article + `</body></html>`,
+		expected: nil,
+	}}
+
+	for _, scenario := range scenarios {
+		ps := NewParser()
+		result, err := ps.Parse(strings.NewReader(scenario.html), fakeHostURL)
+		if err != nil {
+			t.Fatalf("failed to parse: %v", err)
+		}
+
+		if !reflect.DeepEqual(result.Authors, scenario.expected) {
+			t.Errorf("\n"+
+				"html : %q\n"+
+				"want : %+v\n"+
+				"got  : %+v", scenario.html, scenario.expected, result.Authors)
+		}
+	}
+}
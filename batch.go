@@ -0,0 +1,160 @@
+package readability
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultBatchConcurrency is the default number of workers in FromURLs.
+const DefaultBatchConcurrency = 4
+
+// BatchOptions configures how FromURLs fetches the web pages.
+type BatchOptions struct {
+	// Fetcher is used to fetch and parse each web page. If nil, a zero
+	// Fetcher will be used.
+	Fetcher *Fetcher
+	// Concurrency is the number of pages that fetched at the same time.
+	// Default: DefaultBatchConcurrency.
+	Concurrency int
+	// PerHostInterval is the minimum interval between two requests to
+	// the same host, which applies to every request made by Fetcher, e.g.
+	// the redirects, retries and subsequent pages. Default: 0 (no rate
+	// limit).
+	PerHostInterval time.Duration
+}
+
+// BatchResult is the result of fetching a web page in FromURLs.
+type BatchResult struct {
+	URL     string
+	Article Article
+	Err     error
+}
+
+// FromURLs fetches and parses many web pages concurrently. The results
+// are returned in the same order as urls.
+func FromURLs(urls []string, opts BatchOptions) []BatchResult {
+	return FromURLsWithContext(context.Background(), urls, opts)
+}
+
+// FromURLsWithContext is like FromURLs, but the pages that haven't been
+// fetched when ctx is done will have the context's error as result.
+func FromURLsWithContext(ctx context.Context, urls []string, opts BatchOptions) []BatchResult {
+	fetcher := opts.Fetcher
+	if fetcher == nil {
+		fetcher = &Fetcher{}
+	}
+
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultBatchConcurrency
+	}
+
+	// Every request of the fetcher is rate limited, including the
+	// redirects, retries and the subsequent pages of article
+	if opts.PerHostInterval > 0 {
+		fetcher = fetcher.withHostLimiter(&hostLimiter{
+			interval: opts.PerHostInterval,
+			next:     make(map[string]time.Time),
+		})
+	}
+
+	results := make([]BatchResult, len(urls))
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range indexes {
+				results[idx] = fetchBatchItem(ctx, fetcher, urls[idx])
+			}
+		}()
+	}
+
+	for i := range urls {
+		indexes <- i
+	}
+	close(indexes)
+
+	wg.Wait()
+	return results
+}
+
+// fetchBatchItem fetches a single page in the batch.
+func fetchBatchItem(ctx context.Context, fetcher *Fetcher, pageURL string) BatchResult {
+	result := BatchResult{URL: pageURL}
+	result.Article, result.Err = fetcher.Fetch(ctx, pageURL)
+	return result
+}
+
+// withHostLimiter returns the copy of fetcher whose requests wait for
+// limiter. The limiter is applied in the transport of client, so no
+// request made by the fetcher can skip it.
+func (f *Fetcher) withHostLimiter(limiter *hostLimiter) *Fetcher {
+	client := http.DefaultClient
+	if f.Client != nil {
+		client = f.Client
+	}
+
+	transport := client.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	limitedClient := *client
+	limitedClient.Transport = &limitedTransport{base: transport, limiter: limiter}
+
+	limited := *f
+	limited.Client = &limitedClient
+	return &limited
+}
+
+// limitedTransport is a RoundTripper that waits for the host limiter
+// before sending each request.
+type limitedTransport struct {
+	base    http.RoundTripper
+	limiter *hostLimiter
+}
+
+func (lt *limitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := lt.limiter.wait(req.Context(), req.URL.Host); err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, err
+	}
+	return lt.base.RoundTrip(req)
+}
+
+// hostLimiter limits the rate of requests for each host.
+type hostLimiter struct {
+	interval time.Duration
+	mu       sync.Mutex
+	next     map[string]time.Time
+}
+
+// wait blocks until the request for host is allowed, or until ctx is done.
+func (hl *hostLimiter) wait(ctx context.Context, host string) error {
+	if hl.interval <= 0 {
+		return ctx.Err()
+	}
+
+	hl.mu.Lock()
+	now := time.Now()
+	allowedAt := hl.next[host]
+	if allowedAt.Before(now) {
+		allowedAt = now
+	}
+	hl.next[host] = allowedAt.Add(hl.interval)
+	hl.mu.Unlock()
+
+	select {
+	case <-time.After(time.Until(allowedAt)):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
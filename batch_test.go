@@ -0,0 +1,107 @@
+package readability
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	fp "path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func Test_FromURLs(t *testing.T) {
+	source, err := os.ReadFile(fp.Join("test-pages", "001", "source.html"))
+	if err != nil {
+		t.Fatalf("failed to read source: %v", err)
+	}
+
+	var mu sync.Mutex
+	var requestTimes []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requestTimes = append(requestTimes, time.Now())
+		mu.Unlock()
+
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/html")
+		w.Write(source)
+	}))
+	defer server.Close()
+
+	urls := []string{server.URL + "/a", "not a url", server.URL + "/missing", server.URL + "/b"}
+	interval := 50 * time.Millisecond
+	results := FromURLs(urls, BatchOptions{Concurrency: 3, PerHostInterval: interval})
+
+	if len(results) != len(urls) {
+		t.Fatalf("number of results, want %d got %d", len(urls), len(results))
+	}
+
+	for i, result := range results {
+		if result.URL != urls[i] {
+			t.Errorf("result %d, want URL %q got %q", i, urls[i], result.URL)
+		}
+
+		expectError := i == 1 || i == 2
+		if hasError := result.Err != nil; hasError != expectError {
+			t.Errorf("result %d, want error %v got %v", i, expectError, result.Err)
+		}
+	}
+
+	// Requests to the same host must be separated by the interval
+	if len(requestTimes) != 3 {
+		t.Fatalf("number of requests, want %d got %d", 3, len(requestTimes))
+	}
+
+	// The first request might be delayed by connection setup, so only
+	// make sure the requests are not sent at once.
+	if elapsed := requestTimes[2].Sub(requestTimes[0]); elapsed < interval*3/2 {
+		t.Errorf("requests should be rate limited, got %v for 3 requests", elapsed)
+	}
+}
+
+func Test_FromURLs_subsequentPages(t *testing.T) {
+	paragraph := articleParagraph(10)
+
+	var mu sync.Mutex
+	var requestTimes []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requestTimes = append(requestTimes, time.Now())
+		mu.Unlock()
+
+		next := ""
+		if r.URL.Path == "/article" {
+			next = `<link rel="next" href="/article/2">`
+		}
+
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><head>` + next + `</head><body><article>` + paragraph + `</article></body></html>`))
+	}))
+	defer server.Close()
+
+	// The second page of article is rate limited as well
+	interval := 100 * time.Millisecond
+	fetcher := &Fetcher{MaxPages: 2}
+	results := FromURLs([]string{server.URL + "/article"}, BatchOptions{Fetcher: fetcher, PerHostInterval: interval})
+	if results[0].Err != nil {
+		t.Fatalf("failed to fetch: %v", results[0].Err)
+	}
+
+	if len(requestTimes) != 2 {
+		t.Fatalf("number of requests, want %d got %d", 2, len(requestTimes))
+	}
+
+	if elapsed := requestTimes[1].Sub(requestTimes[0]); elapsed < interval*3/4 {
+		t.Errorf("subsequent page should be rate limited, got %v between requests", elapsed)
+	}
+
+	// The fetcher in options is not modified
+	if fetcher.Client != nil {
+		t.Errorf("fetcher in options should not be modified")
+	}
+}
@@ -0,0 +1,70 @@
+package readability
+
+import "sync"
+
+// CacheEntry is the web page that stored in Cache.
+type CacheEntry struct {
+	// ETag is the value of ETag header of the response.
+	ETag string
+	// LastModified is the value of Last-Modified header of the response.
+	LastModified string
+	// Body is the decoded body of the response.
+	Body []byte
+}
+
+// Cache stores the web pages that fetched by Fetcher, keyed by URL. The
+// cached ETag and Last-Modified are used to send conditional request,
+// so when the page is not modified the cached body will be parsed
+// instead of downloading it again. Cache must be safe for concurrent use.
+type Cache interface {
+	// Get returns the cached entry for the url.
+	Get(url string) (*CacheEntry, bool)
+	// Set saves the entry for the url.
+	Set(url string, entry *CacheEntry)
+}
+
+// MemoryCache is a Cache that stores the entries in memory. The zero value
+// is an empty cache without limit, ready to use.
+type MemoryCache struct {
+	// MaxEntries is the max number of entries in cache. Once it's exceeded,
+	// the oldest entry is evicted. Default: 0 (no limit).
+	MaxEntries int
+
+	mu      sync.RWMutex
+	entries map[string]*CacheEntry
+	order   []string
+}
+
+// NewMemoryCache returns an empty MemoryCache without limit.
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{}
+}
+
+// Get returns the cached entry for the url.
+func (mc *MemoryCache) Get(url string) (*CacheEntry, bool) {
+	mc.mu.RLock()
+	defer mc.mu.RUnlock()
+
+	entry, exist := mc.entries[url]
+	return entry, exist
+}
+
+// Set saves the entry for the url.
+func (mc *MemoryCache) Set(url string, entry *CacheEntry) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	if mc.entries == nil {
+		mc.entries = make(map[string]*CacheEntry)
+	}
+
+	if _, exist := mc.entries[url]; !exist {
+		mc.order = append(mc.order, url)
+	}
+	mc.entries[url] = entry
+
+	for mc.MaxEntries > 0 && len(mc.order) > mc.MaxEntries {
+		delete(mc.entries, mc.order[0])
+		mc.order = mc.order[1:]
+	}
+}
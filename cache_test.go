@@ -0,0 +1,29 @@
+package readability
+
+import "testing"
+
+func Test_MemoryCache(t *testing.T) {
+	// Zero value is ready to use
+	var cache MemoryCache
+	cache.Set("a", &CacheEntry{ETag: "1"})
+	if entry, exist := cache.Get("a"); !exist || entry.ETag != "1" {
+		t.Errorf("want entry with ETag 1, got %+v", entry)
+	}
+
+	// Once the limit is exceeded, the oldest entry is evicted
+	limited := MemoryCache{MaxEntries: 2}
+	limited.Set("a", &CacheEntry{ETag: "1"})
+	limited.Set("b", &CacheEntry{ETag: "2"})
+	limited.Set("a", &CacheEntry{ETag: "3"})
+	limited.Set("c", &CacheEntry{ETag: "4"})
+
+	if _, exist := limited.Get("a"); exist {
+		t.Errorf("oldest entry should be evicted")
+	}
+
+	for url, etag := range map[string]string{"b": "2", "c": "4"} {
+		if entry, exist := limited.Get(url); !exist || entry.ETag != etag {
+			t.Errorf("%s: want entry with ETag %s, got %+v", url, etag, entry)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package readability
+
+import (
+	"github.com/go-shiori/dom"
+	"golang.org/x/net/html"
+)
+
+// Candidate is a node that considered by the parser as the container of
+// the article content.
+type Candidate struct {
+	// Node is the candidate node. It belongs to a copy of the document,
+	// which has been prepared for scoring.
+	Node *html.Node
+	// Score is the final score of the candidate, after scaled by its
+	// link density.
+	Score float64
+	// HTML is the outer HTML of the candidate. The descendants that has
+	// been scored are annotated with data-readability-score attribute.
+	HTML string
+}
+
+// Candidates returns the top candidates of the document, ranked from the
+// highest score, without grabbing and cleaning up the article. This is
+// useful to see what the parser considers when tuning the extraction.
+// The number of candidates is limited by NTopCandidates.
+func (ps *Parser) Candidates(doc *html.Node) (_ []Candidate, err error) {
+	defer ps.recoverPanic(&err)
+	return ps.clone().candidates(doc)
+}
+
+// candidates returns the top candidates using the state of ps, so like
+// parseDocument it must only be called on a copy of parser.
+func (ps *Parser) candidates(doc *html.Node) ([]Candidate, error) {
+	if err := checkDocumentLimits(doc, ps.MaxElemsToParse, ps.MaxElementDepth); err != nil {
+		return nil, err
+	}
+
+	// Prepare the document the same way as in the first parse attempt
+	ps.doc = dom.Clone(doc, true)
+	ps.flags = flags{
+		stripUnlikelys:     true,
+		useWeightClasses:   true,
+		cleanConditionally: true,
+	}
+
+	if !ps.DisableNoscriptUnwrap {
+		ps.unwrapNoscriptImages(ps.doc)
+	}
+
+	ps.removeScripts(ps.doc)
+	ps.prepDocument()
+	ps.articleTitle = ps.getArticleTitle()
+
+	nodes, err := ps.scoreCandidates(ps.doc)
+	if err != nil {
+		return nil, err
+	}
+
+	if nTopCandidates := ps.nTopCandidates(); len(nodes) > nTopCandidates {
+		nodes = nodes[:nTopCandidates]
+	}
+
+	candidates := make([]Candidate, len(nodes))
+	for i, node := range nodes {
+		candidates[i] = Candidate{
+			Node:  node,
+			Score: ps.getContentScore(node),
+			HTML:  dom.OuterHTML(node),
+		}
+	}
+
+	return candidates, nil
+}
@@ -0,0 +1,48 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-shiori/dom"
+)
+
+func Test_Parser_Candidates(t *testing.T) {
+	paragraph := articleParagraph(10)
+	input := `<html><body>` +
+		`<div id="main">` + paragraph + paragraph + paragraph + `</div>` +
+		`<div id="aside">` + paragraph + `</div>` +
+		`</body></html>`
+
+	doc, err := dom.Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("failed to parse input: %v", err)
+	}
+
+	ps := NewParser(WithNTopCandidates(2))
+	candidates, err := ps.Candidates(doc)
+	if err != nil {
+		t.Fatalf("failed to get candidates: %v", err)
+	}
+
+	if len(candidates) != 2 {
+		t.Fatalf("want 2 candidates, got %d", len(candidates))
+	}
+
+	if id := dom.ID(candidates[0].Node); id != "main" {
+		t.Errorf("top candidate, want %q got %q", "main", id)
+	}
+
+	if candidates[0].Score <= candidates[1].Score {
+		t.Errorf("candidates should be ranked, got scores %v and %v", candidates[0].Score, candidates[1].Score)
+	}
+
+	if !strings.HasPrefix(candidates[0].HTML, `<div id="main" data-readability-score=`) {
+		t.Errorf("candidate HTML should be annotated with score, got %q", candidates[0].HTML)
+	}
+
+	// Original document should be untouched
+	if strings.Contains(dom.OuterHTML(doc), "data-readability-score") {
+		t.Errorf("original document shouldn't be modified")
+	}
+}
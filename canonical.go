@@ -0,0 +1,46 @@
+package readability
+
+import (
+	nurl "net/url"
+	"strings"
+
+	"github.com/go-shiori/dom"
+)
+
+// getCanonicalURL returns the absolute canonical URL of the page, which is
+// specified using <link rel="canonical"> or og:url meta tag.
+func (ps *Parser) getCanonicalURL() string {
+	var href string
+	for _, link := range dom.QuerySelectorAll(ps.doc, `link[rel][href]`) {
+		for _, rel := range strings.Fields(dom.GetAttribute(link, "rel")) {
+			if strings.EqualFold(rel, "canonical") {
+				href = strings.TrimSpace(dom.GetAttribute(link, "href"))
+				break
+			}
+		}
+
+		if href != "" {
+			break
+		}
+	}
+
+	if href == "" {
+		for _, meta := range dom.QuerySelectorAll(ps.doc, `meta[property="og:url"], meta[name="og:url"]`) {
+			if href = strings.TrimSpace(dom.GetAttribute(meta, "content")); href != "" {
+				break
+			}
+		}
+	}
+
+	if href == "" {
+		return ""
+	}
+
+	canonicalURL, err := nurl.Parse(toAbsoluteURI(href, ps.documentURI))
+	if err != nil || (canonicalURL.Scheme != "http" && canonicalURL.Scheme != "https") {
+		return ""
+	}
+
+	canonicalURL.Fragment = ""
+	return canonicalURL.String()
+}
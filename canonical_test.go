@@ -0,0 +1,36 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_Parser_CanonicalURL(t *testing.T) {
+	paragraph := articleParagraph(10)
+	scenarios := map[string]string{
+		`<link rel="canonical" href="/article#top"><meta property="og:url" content="http://fakehost/og">`: "http://fakehost/article",
+		`<meta property="og:url" content="http://fakehost/og">`:                                           "http://fakehost/og",
+		`<link rel="Canonical amphtml" href="https://example.com/article">`:                               "https://example.com/article",
+		`<link rel="canonical" href="javascript:void(0)">`:                                                "",
+		``: "",
+	}
+
+	for head, expected := range scenarios {
+		input := `<html><head>` + head + `</head><body><article>` + paragraph + `</article></body></html>`
+		article, err := FromReader(strings.NewReader(input), fakeHostURL)
+		if err != nil {
+			t.Fatalf("failed to parse: %v", err)
+		}
+
+		if article.CanonicalURL != expected {
+			t.Errorf("\n"+
+				"html : %q\n"+
+				"want : %q\n"+
+				"got  : %q", head, expected, article.CanonicalURL)
+		}
+
+		if article.URL != fakeHostURL.String() {
+			t.Errorf("want URL %q, got %q", fakeHostURL.String(), article.URL)
+		}
+	}
+}
@@ -2,17 +2,19 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"log"
 	"net/http"
 	nurl "net/url"
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	readability "github.com/go-shiori/go-readability"
+	"github.com/go-shiori/go-readability/server"
 	"github.com/spf13/cobra"
 )
 
@@ -45,6 +47,11 @@ func main() {
 
 	rootCmd.Flags().StringP("http", "l", "", "start the http server at the specified address")
 	rootCmd.Flags().BoolP("metadata", "m", false, "only print the page's metadata")
+	rootCmd.Flags().StringP("format", "f", "html", "output format: html, text, markdown or json")
+	rootCmd.Flags().DurationP("timeout", "t", 30*time.Second, "timeout for fetching the web page")
+	rootCmd.Flags().StringP("user-agent", "u", "", "user agent that used to fetch the web page")
+	rootCmd.Flags().BoolP("check", "c", false, "only check whether the page is readable")
+	rootCmd.Flags().IntP("concurrency", "n", 0, "max number of pages that parsed at once by the http server")
 
 	err := rootCmd.Execute()
 	if err != nil {
@@ -52,94 +59,152 @@ func main() {
 	}
 }
 
+// options is the options that used to get the readable content.
+type options struct {
+	metadataOnly bool
+	format       string
+	timeout      time.Duration
+	userAgent    string
+}
+
 func rootCmdHandler(cmd *cobra.Command, args []string) {
+	// Get cmd parameter
+	var opts options
+	opts.metadataOnly, _ = cmd.Flags().GetBool("metadata")
+	opts.format, _ = cmd.Flags().GetString("format")
+	opts.timeout, _ = cmd.Flags().GetDuration("timeout")
+	opts.userAgent, _ = cmd.Flags().GetString("user-agent")
+	checkOnly, _ := cmd.Flags().GetBool("check")
+
+	switch opts.format {
+	case "html", "text", "markdown", "json":
+	default:
+		log.Fatalf("unknown format %q\n", opts.format)
+	}
+
 	// Start HTTP server
 	httpListen, _ := cmd.Flags().GetString("http")
 	if httpListen != "" {
-		http.HandleFunc("/", httpHandler)
+		concurrency, _ := cmd.Flags().GetInt("concurrency")
+		http.Handle("/parse", &server.Server{
+			Client:         &http.Client{Timeout: opts.timeout},
+			MaxConcurrency: concurrency,
+			Logger:         log.Default(),
+		})
+		http.HandleFunc("/", httpHandler(opts))
 		log.Println("Starting HTTP server at", httpListen)
 		log.Fatal(http.ListenAndServe(httpListen, nil))
 	}
 
-	// Get cmd parameter
-	metadataOnly, _ := cmd.Flags().GetBool("metadata")
-	if len(args) > 0 {
-		content, err := getContent(args[0], metadataOnly)
+	if len(args) == 0 {
+		cmd.Help()
+		return
+	}
+
+	if checkOnly {
+		readable, err := checkContent(args[0], opts)
 		if err != nil {
 			log.Fatalln(err)
 		}
 
-		fmt.Println(content)
-	} else {
-		cmd.Help()
+		if !readable {
+			fmt.Println("not readable")
+			os.Exit(1)
+		}
+
+		fmt.Println("readable")
+		return
+	}
+
+	content, err := getContent(args[0], opts)
+	if err != nil {
+		log.Fatalln(err)
 	}
+
+	fmt.Println(content)
 }
 
-func httpHandler(w http.ResponseWriter, r *http.Request) {
-	metadata := r.URL.Query().Get("metadata")
-	metadataOnly, _ := strconv.ParseBool(metadata)
-	url := r.URL.Query().Get("url")
-	if url == "" {
-		w.Write([]byte(index))
-	} else {
+func httpHandler(opts options) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		metadata := r.URL.Query().Get("metadata")
+		metadataOnly, _ := strconv.ParseBool(metadata)
+		url := r.URL.Query().Get("url")
+		if url == "" {
+			w.Write([]byte(index))
+			return
+		}
+
 		log.Println("process URL", url)
-		content, err := getContent(url, metadataOnly)
+		opts.metadataOnly = metadataOnly
+		content, err := getContent(url, opts)
 		if err != nil {
 			log.Println(err)
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
-		if metadataOnly {
+
+		if metadataOnly || opts.format == "json" {
 			w.Header().Set("Content-Type", "application/json")
 		}
 		w.Write([]byte(content))
 	}
 }
 
-func getContent(srcPath string, metadataOnly bool) (string, error) {
-	// Open or fetch web page that will be parsed
-	var (
-		pageURL   *nurl.URL
-		srcReader io.Reader
-	)
-
-	if url, isURL := validateURL(srcPath); isURL {
-		resp, err := http.Get(srcPath)
-		if err != nil {
-			return "", fmt.Errorf("failed to fetch web page: %v", err)
+// openSource reads the web page that will be parsed. The source can be
+// an URL or a path to existing file. The URL is fetched using Fetcher, so
+// the response is decoded and checked the same way as the package does.
+func openSource(srcPath string, opts options) ([]byte, *nurl.URL, error) {
+	if _, isURL := validateURL(srcPath); isURL {
+		fetcher := readability.Fetcher{
+			Client:    &http.Client{Timeout: opts.timeout},
+			UserAgent: opts.userAgent,
 		}
-		defer resp.Body.Close()
 
-		pageURL = url
-		srcReader = resp.Body
-	} else {
-		srcFile, err := os.Open(srcPath)
+		content, pageURL, err := fetcher.FetchPage(context.Background(), srcPath)
 		if err != nil {
-			return "", fmt.Errorf("failed to open source file: %v", err)
+			return nil, nil, fmt.Errorf("failed to fetch web page: %v", err)
 		}
-		defer srcFile.Close()
+		return content, pageURL, nil
+	}
+
+	content, err := os.ReadFile(srcPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open source file: %v", err)
+	}
 
-		pageURL, _ = nurl.ParseRequestURI("http://fakehost.com")
-		srcReader = srcFile
+	pageURL, _ := nurl.ParseRequestURI("http://fakehost.com")
+	return content, pageURL, nil
+}
+
+func checkContent(srcPath string, opts options) (bool, error) {
+	content, _, err := openSource(srcPath, opts)
+	if err != nil {
+		return false, err
 	}
 
-	// Use tee so the reader can be used twice
-	buf := bytes.NewBuffer(nil)
-	tee := io.TeeReader(srcReader, buf)
+	return readability.Check(bytes.NewReader(content)), nil
+}
+
+func getContent(srcPath string, opts options) (string, error) {
+	// Open or fetch web page that will be parsed
+	content, pageURL, err := openSource(srcPath, opts)
+	if err != nil {
+		return "", err
+	}
 
 	// Make sure the page is readable
-	if !readability.Check(tee) {
+	if !readability.Check(bytes.NewReader(content)) {
 		return "", fmt.Errorf("failed to parse page: the page is not readable")
 	}
 
-	// Get readable content from the reader
-	article, err := readability.FromReader(buf, pageURL)
+	// Get readable content from the page
+	article, err := readability.FromBytes(content, pageURL)
 	if err != nil {
 		return "", fmt.Errorf("failed to parse page: %v", err)
 	}
 
 	// Return the article (or its metadata)
-	if metadataOnly {
+	if opts.metadataOnly {
 		metadata := map[string]interface{}{
 			"title":   article.Title,
 			"byline":  article.Byline,
@@ -156,6 +221,19 @@ func getContent(srcPath string, metadataOnly bool) (string, error) {
 		return string(prettyJSON), nil
 	}
 
+	switch opts.format {
+	case "text":
+		return article.FormattedText(), nil
+	case "markdown":
+		return article.Markdown(), nil
+	case "json":
+		prettyJSON, err := json.MarshalIndent(&article, "", "    ")
+		if err != nil {
+			return "", fmt.Errorf("failed to encode article: %v", err)
+		}
+		return string(prettyJSON), nil
+	}
+
 	return article.Content, nil
 }
 
@@ -0,0 +1,40 @@
+package readability
+
+import (
+	"strings"
+
+	"github.com/go-shiori/dom"
+	"golang.org/x/net/html"
+)
+
+// isCodeHeavy checks if most of the text in node is code. The <code>
+// inside <pre> is skipped, so the code is not counted twice.
+func (ps *Parser) isCodeHeavy(node *html.Node) bool {
+	textLength := ps.getTextLength(node)
+	if textLength == 0 {
+		return false
+	}
+
+	var codeLength int
+	for _, code := range ps.getAllNodesWithTag(node, "pre", "code") {
+		if dom.TagName(code) == "code" && ps.hasAncestorTag(code, "pre", -1, nil) {
+			continue
+		}
+		codeLength += ps.getTextLength(code)
+	}
+
+	return float64(codeLength)/float64(textLength) > 0.5
+}
+
+// isCodeLanguageClass checks if class specifies the language of code,
+// e.g. "language-go" or "lang-js" in <pre> or <code>.
+func isCodeLanguageClass(node *html.Node, class string) bool {
+	switch dom.TagName(node) {
+	case "pre", "code":
+	default:
+		return false
+	}
+
+	class = strings.ToLower(class)
+	return strings.HasPrefix(class, "language-") || strings.HasPrefix(class, "lang-")
+}
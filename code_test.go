@@ -0,0 +1,37 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_Parser_PreserveCode(t *testing.T) {
+	paragraph := articleParagraph(10)
+	code := `<pre class="language-go"><code class="language-go hljs">func main() {<br>` +
+		"\n\tfmt.Println(\"hello\")<br><br>\n}</code></pre>"
+	codeSection := `<div class="snippet"><div class="gutter"><span>1</span><span>2</span><span>3</span></div>` +
+		`<pre>x := 1` + "\n" + `y := 2` + "\n" + `z := x + y</pre></div>`
+	input := "<html><body><article>" + paragraph + code + codeSection + paragraph + "</article></body></html>"
+
+	scenarios := map[bool][]string{
+		false: {`<pre><code>func main() {`},
+		true: {
+			`<pre class="language-go"><code class="language-go">func main() {<br/>` + "\n\tfmt.Println(&#34;hello&#34;)<br/><br/>\n}</code></pre>",
+			"<pre>x := 1\ny := 2\nz := x + y</pre>",
+		},
+	}
+
+	for preserve, expectations := range scenarios {
+		ps := NewParser(WithPreserveCode(preserve))
+		article, err := ps.Parse(strings.NewReader(input), fakeHostURL)
+		if err != nil {
+			t.Fatalf("failed to parse: %v", err)
+		}
+
+		for _, expected := range expectations {
+			if !strings.Contains(article.Content, expected) {
+				t.Errorf("preserve %v, content should contain %q\ngot: %s", preserve, expected, article.Content)
+			}
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package readability
+
+import (
+	"strings"
+	"time"
+
+	"github.com/go-shiori/dom"
+	"golang.org/x/net/html"
+)
+
+// Comment is a comment in the discussion of the article. Replies have
+// depth more than 0.
+type Comment struct {
+	ID     string     `json:"id,omitempty"`
+	Author string     `json:"author,omitempty"`
+	Time   *time.Time `json:"time,omitempty"`
+	HTML   string     `json:"html"`
+	Depth  int        `json:"depth"`
+}
+
+// commentStructure is the markup of comments in a known platform, which
+// is described using CSS selectors. Except for item, the selectors in a
+// list are tried in order of priority.
+type commentStructure struct {
+	item    string
+	author  string
+	time    string
+	content string
+	// depth returns the depth of comment that can't be inferred from
+	// the nesting of items, e.g. in table-based thread.
+	depth func(item *html.Node) int
+}
+
+// commentStructures is the known comment structures, in order of checking.
+var commentStructures = []commentStructure{{
+	// WordPress comment list
+	item:    ".commentlist .comment, .comment-list .comment",
+	author:  ".comment-author .fn, .comment-author cite, .comment-author",
+	time:    ".comment-metadata time, .comment-meta time, .comment-meta a, time",
+	content: ".comment-content, .comment-text, .comment-body",
+}, {
+	// Disqus static fallback
+	item:    "#dsq-comments .dsq-comment, #dsq-comments li.comment",
+	author:  ".dsq-comment-header cite, .dsq-comment-author",
+	time:    ".dsq-comment-meta time, .dsq-comment-meta a",
+	content: ".dsq-comment-message, .dsq-comment-body",
+}, {
+	// Hacker News style thread
+	item:    "tr.comtr",
+	author:  ".hnuser",
+	time:    ".age",
+	content: ".commtext",
+	depth: func(item *html.Node) int {
+		if indent := dom.QuerySelector(item, "td.ind"); indent != nil {
+			if depth := imageDimension(dom.GetAttribute(indent, "indent")); depth > 0 {
+				return depth
+			}
+			if img := dom.QuerySelector(indent, "img"); img != nil {
+				return imageDimension(dom.GetAttribute(img, "width")) / 40
+			}
+		}
+		return 0
+	},
+}, {
+	// Schema.org microdata
+	item:    `[itemtype$="schema.org/Comment"], [itemtype$="schema.org/Answer"]`,
+	author:  `[itemprop="author"] [itemprop="name"], [itemprop="author"]`,
+	time:    `[itemprop="dateCreated"], [itemprop="datePublished"]`,
+	content: `[itemprop="text"]`,
+}}
+
+// getComments extracts the comments from the first known structure that
+// found in the document. The comments are in document order, with the
+// URLs in their content converted to absolute.
+func (ps *Parser) getComments() []Comment {
+	for _, structure := range commentStructures {
+		items := dom.QuerySelectorAll(ps.doc, structure.item)
+		if len(items) == 0 {
+			continue
+		}
+
+		itemSet := make(map[*html.Node]struct{}, len(items))
+		for _, item := range items {
+			itemSet[item] = struct{}{}
+		}
+
+		// parentItem returns the nearest comment that contains node
+		parentItem := func(node *html.Node) *html.Node {
+			for parent := node.Parent; parent != nil; parent = parent.Parent {
+				if _, isItem := itemSet[parent]; isItem {
+					return parent
+				}
+			}
+			return nil
+		}
+
+		// own returns the first node in the item itself, not in its
+		// replies, that matches the selectors in order of priority
+		own := func(item *html.Node, selectors string) *html.Node {
+			for _, selector := range strings.Split(selectors, ",") {
+				for _, node := range dom.QuerySelectorAll(item, selector) {
+					if parentItem(node) == item {
+						return node
+					}
+				}
+			}
+			return nil
+		}
+
+		var comments []Comment
+		for _, item := range items {
+			content := own(item, structure.content)
+			if content == nil {
+				continue
+			}
+
+			content = dom.Clone(content, true)
+			ps.fixRelativeURIs(content)
+			comment := Comment{
+				ID:   dom.ID(item),
+				HTML: strings.TrimSpace(dom.InnerHTML(content)),
+			}
+
+			if comment.HTML == "" {
+				continue
+			}
+
+			if author := own(item, structure.author); author != nil {
+				comment.Author = strings.Join(strings.Fields(dom.TextContent(author)), " ")
+			}
+
+			if timeNode := own(item, structure.time); timeNode != nil {
+				date := strOr(
+					strings.TrimSpace(dom.GetAttribute(timeNode, "datetime")),
+					strings.TrimSpace(dom.GetAttribute(timeNode, "content")),
+					strings.TrimSpace(dom.GetAttribute(timeNode, "title")),
+					strings.TrimSpace(dom.TextContent(timeNode)))
+				comment.Time = parseDate(date)
+			}
+
+			if structure.depth != nil {
+				comment.Depth = structure.depth(item)
+			} else {
+				for parent := parentItem(item); parent != nil; parent = parentItem(parent) {
+					comment.Depth++
+				}
+			}
+
+			comments = append(comments, comment)
+		}
+
+		if len(comments) > 0 {
+			return comments
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,100 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_Parser_Comments(t *testing.T) {
+	paragraph := articleParagraph(10)
+	article := `<article>` + paragraph + `</article>`
+
+	type expectedComment struct {
+		author string
+		time   string
+		html   string
+		depth  int
+	}
+
+	scenarios := map[string][]expectedComment{
+		// WordPress
+		`<ol class="comment-list"><li id="comment-1" class="comment"><article class="comment-body">` +
+			`<footer><div class="comment-author"><b class="fn">Alice</b> says:</div>` +
+			`<div class="comment-metadata"><a href="#comment-1"><time datetime="2021-06-27T10:00:00+00:00">June 27</time></a></div></footer>` +
+			`<div class="comment-content"><p>First <a href="/link">comment</a></p></div></article>` +
+			`<ol class="children"><li id="comment-2" class="comment"><article class="comment-body">` +
+			`<div class="comment-author"><b class="fn">Bob</b></div>` +
+			`<div class="comment-content"><p>Reply</p></div></article></li></ol></li></ol>`: {
+			{"Alice", "2021-06-27", `<p>First <a href="http://fakehost/link">comment</a></p>`, 0},
+			{"Bob", "", "<p>Reply</p>", 1},
+		},
+		// Disqus
+		`<div id="dsq-content"><ul id="dsq-comments"><li class="comment" id="dsq-comment-1">` +
+			`<div class="dsq-comment-header"><cite><a href="/user">Carol</a></cite></div>` +
+			`<div class="dsq-comment-message"><p>Static comment</p></div></li></ul></div>`: {
+			{"Carol", "", "<p>Static comment</p>", 0},
+		},
+		// Hacker News
+		`<table><tr class="athing comtr" id="1"><td class="ind" indent="0"></td><td>` +
+			`<a class="hnuser">dave</a> <span class="age" title="2021-06-27T10:00:00">1 hour ago</span>` +
+			`<div class="commtext">Top level</div></td></tr>` +
+			`<tr class="athing comtr" id="2"><td class="ind" indent="1"></td><td>` +
+			`<a class="hnuser">erin</a><div class="commtext">Nested</div></td></tr></table>`: {
+			{"dave", "2021-06-27", "Top level", 0},
+			{"erin", "", "Nested", 1},
+		},
+		// Schema.org microdata
+		`<div itemscope itemtype="https://schema.org/Comment"><span itemprop="author">Frank</span>` +
+			`<meta itemprop="dateCreated" content="2021-06-27"><div itemprop="text">Microdata comment</div></div>`: {
+			{"Frank", "2021-06-27", "Microdata comment", 0},
+		},
+		`<div class="comments">Not a known structure</div>`: nil,
+	}
+
+	for comments, expected := range scenarios {
+		input := `<html><body>` + article + comments + `</body></html>`
+		ps := NewParser(WithComments(true))
+		result, err := ps.Parse(strings.NewReader(input), fakeHostURL)
+		if err != nil {
+			t.Fatalf("failed to parse: %v", err)
+		}
+
+		if len(result.Comments) != len(expected) {
+			t.Errorf("\n"+
+				"html : %q\n"+
+				"want : %d comments\n"+
+				"got  : %+v", comments, len(expected), result.Comments)
+			continue
+		}
+
+		for i, comment := range result.Comments {
+			date := ""
+			if comment.Time != nil {
+				date = comment.Time.Format("2006-01-02")
+			}
+
+			got := expectedComment{comment.Author, date, comment.HTML, comment.Depth}
+			if got != expected[i] {
+				t.Errorf("\n"+
+					"html : %q\n"+
+					"want : %+v\n"+
+					"got  : %+v", comments, expected[i], got)
+			}
+		}
+
+		if strings.Contains(result.Content, "Reply") || strings.Contains(result.Content, "Nested") {
+			t.Errorf("comments should not be in the content: %s", result.Content)
+		}
+	}
+
+	// Comments are not extracted by default
+	result, err := FromReader(strings.NewReader(`<html><body>`+article+
+		`<ol class="commentlist"><li class="comment"><div class="comment-content">Hi</div></li></ol></body></html>`), fakeHostURL)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	if result.Comments != nil {
+		t.Errorf("comments should be nil, got %+v", result.Comments)
+	}
+}
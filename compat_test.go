@@ -0,0 +1,282 @@
+package readability
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	fp "path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-shiori/dom"
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// Environment variables of the Readability.js compatibility harness. The
+// harness runs against the test pages of Readability.js, which are not
+// vendored here since they keep changing as Readability.js evolves, e.g.
+// from a checkout of https://github.com/mozilla/readability:
+//
+//	READABILITY_JS_TEST_PAGES=../readability/test/test-pages \
+//	READABILITY_JS_REPORT=compat.json \
+//	go test -run Test_ReadabilityJSFixtures -v
+const (
+	// envCompatTestPages is the directory of Readability.js test pages.
+	// The harness is skipped when it's empty.
+	envCompatTestPages = "READABILITY_JS_TEST_PAGES"
+	// envCompatReport is the path of JSON report, which has the result of
+	// each case so the parity drift can be tracked over time.
+	envCompatReport = "READABILITY_JS_REPORT"
+	// envCompatMinParity is the min percentage of cases that must fully
+	// match for the harness to pass. Default: 0 (only report).
+	envCompatMinParity = "READABILITY_JS_MIN_PARITY"
+)
+
+// compatMaxDiffLength is the max length of content diff in the report,
+// since the diff of text has the whole text of node.
+const compatMaxDiffLength = 1000
+
+// compatMetadata is the expected metadata in Readability.js test page.
+// Language is stored as "lang" there, while it's "language" in the test
+// pages of this package. PublishedTime is only compared when it exists,
+// since it's missing in the older test pages.
+type compatMetadata struct {
+	ExpectedMetadata
+	Lang          string  `json:"lang,omitempty"`
+	PublishedTime *string `json:"publishedTime,omitempty"`
+}
+
+// compatResult is the result of a Readability.js test page.
+type compatResult struct {
+	Name string `json:"name"`
+	// Error is the error that prevents the case from being compared, e.g.
+	// the missing file or the failed parse.
+	Error string `json:"error,omitempty"`
+	// Similarity is the similarity of article text with the expected one,
+	// from 0 to 1, so the content that's close enough is still scored.
+	Similarity float64 `json:"similarity"`
+	// Diffs is the differences from the expected result, keyed by field.
+	Diffs map[string]string `json:"diffs,omitempty"`
+}
+
+// passed checks if the case fully matches the expected result.
+func (r compatResult) passed() bool {
+	return r.Error == "" && len(r.Diffs) == 0
+}
+
+func Test_ReadabilityJSFixtures(t *testing.T) {
+	testDir := os.Getenv(envCompatTestPages)
+	if testDir == "" {
+		t.Skipf("%s is not set", envCompatTestPages)
+	}
+
+	names, err := compatCaseNames(testDir)
+	if err != nil {
+		t.Fatalf("failed to list test pages: %v", err)
+	}
+
+	var results []compatResult
+	fieldFailures := make(map[string]int)
+	for _, name := range names {
+		result := runCompatCase(fp.Join(testDir, name))
+		result.Name = name
+		results = append(results, result)
+
+		switch {
+		case result.Error != "":
+			t.Logf("%s: ERROR %s", name, result.Error)
+		case !result.passed():
+			fields := make([]string, 0, len(result.Diffs))
+			for field := range result.Diffs {
+				fields = append(fields, field)
+				fieldFailures[field]++
+			}
+			sort.Strings(fields)
+
+			t.Logf("%s: FAIL (similarity %.3f)", name, result.Similarity)
+			for _, field := range fields {
+				t.Logf("    %s: %s", field, strings.ReplaceAll(result.Diffs[field], "\n", "\n        "))
+			}
+		}
+	}
+
+	passed := 0
+	for _, result := range results {
+		if result.passed() {
+			passed++
+		}
+	}
+
+	parity := 100 * float64(passed) / float64(len(results))
+	t.Logf("parity: %d/%d cases (%.1f%%), failures by field: %v", passed, len(results), parity, fieldFailures)
+
+	if path := os.Getenv(envCompatReport); path != "" {
+		report, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			t.Fatalf("failed to encode report: %v", err)
+		}
+
+		if err = os.WriteFile(path, report, 0o644); err != nil {
+			t.Fatalf("failed to write report: %v", err)
+		}
+	}
+
+	if minParity := os.Getenv(envCompatMinParity); minParity != "" {
+		min, err := strconv.ParseFloat(minParity, 64)
+		if err != nil {
+			t.Fatalf("invalid %s: %v", envCompatMinParity, err)
+		}
+
+		if parity < min {
+			t.Errorf("parity %.1f%% is below %.1f%%", parity, min)
+		}
+	}
+}
+
+// compatCaseNames returns the names of test pages in the directory.
+func compatCaseNames(testDir string) ([]string, error) {
+	items, err := os.ReadDir(testDir)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(items) == 0 {
+		return nil, fmt.Errorf("no test pages in %s", testDir)
+	}
+
+	var names []string
+	for _, item := range items {
+		if item.IsDir() {
+			names = append(names, item.Name())
+		}
+	}
+	return names, nil
+}
+
+// runCompatCase extracts the source of test page, and compares the result
+// with what Readability.js returns.
+func runCompatCase(caseDir string) compatResult {
+	article, originalDoc, extractedDoc, err := extractSourceFile(fp.Join(caseDir, "source.html"))
+	if err != nil {
+		return compatResult{Error: err.Error()}
+	}
+
+	expectedDoc, err := decodeExpectedFile(fp.Join(caseDir, "expected.html"))
+	if err != nil {
+		return compatResult{Error: err.Error()}
+	}
+
+	metadata, err := decodeCompatMetadata(fp.Join(caseDir, "expected-metadata.json"))
+	if err != nil {
+		return compatResult{Error: err.Error()}
+	}
+
+	result := compatResult{
+		Similarity: textSimilarity(dom.TextContent(extractedDoc), dom.TextContent(expectedDoc)),
+		Diffs:      make(map[string]string),
+	}
+
+	if err := compareArticleContent(extractedDoc, expectedDoc); err != nil {
+		diff := err.Error()
+		if len(diff) > compatMaxDiffLength {
+			diff = diff[:compatMaxDiffLength] + "..."
+		}
+		result.Diffs["content"] = diff
+	}
+
+	language := metadata.Language
+	if metadata.Lang != "" {
+		language = metadata.Lang
+	}
+
+	compareField := func(field, expected, actual string) {
+		if expected != actual {
+			result.Diffs[field] = fmt.Sprintf("want %q got %q", expected, actual)
+		}
+	}
+
+	compareField("title", metadata.Title, article.Title)
+	compareField("byline", metadata.Byline, article.Byline)
+	compareField("excerpt", metadata.Excerpt, article.Excerpt)
+	compareField("siteName", metadata.SiteName, article.SiteName)
+	compareField("language", language, article.Language)
+	compareField("dir", metadata.Dir, article.Dir)
+	if metadata.PublishedTime != nil {
+		compareField("publishedTime", formatCompatTime(parseDate(*metadata.PublishedTime)),
+			formatCompatTime(article.PublishedTime))
+	}
+
+	if isReaderable := CheckDocument(originalDoc); metadata.Readerable != isReaderable {
+		result.Diffs["readerable"] = fmt.Sprintf("want %v got %v", metadata.Readerable, isReaderable)
+	}
+
+	return result
+}
+
+// decodeCompatMetadata decodes the expected metadata of test page.
+func decodeCompatMetadata(path string) (compatMetadata, error) {
+	var result compatMetadata
+
+	f, err := os.Open(path)
+	if err != nil {
+		return result, fmt.Errorf("failed to open metadata: %v", err)
+	}
+	defer f.Close()
+
+	err = json.NewDecoder(f).Decode(&result)
+	return result, err
+}
+
+// formatCompatTime formats the published time so the missing time can be
+// compared as well.
+func formatCompatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// textSimilarity returns the similarity of both text ignoring whitespaces,
+// which is based on the Levenshtein distance of their characters.
+func textSimilarity(result, expected string) float64 {
+	result = strings.Join(strings.Fields(result), " ")
+	expected = strings.Join(strings.Fields(expected), " ")
+	if result == expected {
+		return 1
+	}
+
+	total := charCount(result)
+	if n := charCount(expected); n > total {
+		total = n
+	}
+
+	comparator := diffmatchpatch.New()
+	diffs := comparator.DiffMain(result, expected, false)
+	return 1 - float64(comparator.DiffLevenshtein(diffs))/float64(total)
+}
+
+func Test_runCompatCase(t *testing.T) {
+	// The harness must pass for the test pages of this package, which
+	// are taken from Readability.js
+	result := runCompatCase(fp.Join("test-pages", "001"))
+	if !result.passed() || result.Similarity != 1 {
+		t.Errorf("want passed, got %+v", result)
+	}
+
+	scenarios := map[[2]string]float64{
+		{"the quick brown fox", "the quick brown fox"}:   1,
+		{"the quick brown fox", "the  quick\nbrown fox"}: 1,
+		{"the quick brown fox", "the quick brown cat"}:   1 - 3.0/19,
+		{"", "the quick brown fox"}:                      0,
+	}
+
+	for texts, expected := range scenarios {
+		if similarity := textSimilarity(texts[0], texts[1]); math.Abs(similarity-expected) > 1e-9 {
+			t.Errorf("similarity of %q, want %v got %v", texts, expected, similarity)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package readability
+
+import (
+	"math"
+	"strings"
+
+	"github.com/go-shiori/dom"
+	"golang.org/x/net/html"
+)
+
+// The weight of each signal in Article.Confidence. They add up to 1.
+const (
+	confidenceScoreWeight  = 0.35
+	confidenceLengthWeight = 0.25
+	confidenceLinkWeight   = 0.2
+	confidenceTitleWeight  = 0.2
+)
+
+// confidenceScoreScale is the candidate score where the score signal
+// reaches 0.5. A few paragraphs of plain article usually score above it.
+const confidenceScoreScale = 50.0
+
+// getConfidence estimates how likely the article content is extracted
+// correctly, from 0 to 1. It's derived from the score of the winning
+// candidate, the length and link density of the content, and whether
+// the title in metadata agrees with the title found in the document.
+func (ps *Parser) getConfidence(articleContent *html.Node, textLength int) float64 {
+	if articleContent == nil || textLength == 0 {
+		return 0
+	}
+
+	// Site rule is written by hand so it's trusted, while the density
+	// doesn't have any score so it's treated as a coin flip
+	var score float64
+	switch ps.strategy {
+	case StrategySiteRule:
+		score = 1
+	case StrategyDensity:
+		score = 0.5
+	case StrategyReadability, StrategyRelaxed:
+		if ps.topCandidateScore > 0 {
+			score = ps.topCandidateScore / (ps.topCandidateScore + confidenceScoreScale)
+		}
+		if ps.strategy == StrategyRelaxed {
+			score *= 0.75
+		}
+	}
+
+	length := float64(textLength) / float64(textLength+ps.CharThresholds)
+	links := 1 - math.Min(ps.getLinkDensity(articleContent), 1)
+	title := ps.titleAgreement()
+
+	confidence := score*confidenceScoreWeight +
+		length*confidenceLengthWeight +
+		links*confidenceLinkWeight +
+		title*confidenceTitleWeight
+	return math.Round(confidence*100) / 100
+}
+
+// titleAgreement compares the article title from metadata with the first
+// h1 in the document, or with the title element if there are no h1.
+func (ps *Parser) titleAgreement() float64 {
+	if strings.TrimSpace(ps.articleTitle) == "" {
+		return 0
+	}
+
+	extractedTitle := ps.getArticleTitle()
+	if h1s := dom.GetElementsByTagName(ps.doc, "h1"); len(h1s) > 0 {
+		extractedTitle = ps.getInnerText(h1s[0], true)
+	}
+	if strings.TrimSpace(extractedTitle) == "" {
+		return 0
+	}
+
+	return math.Max(ps.textSimilarity(extractedTitle, ps.articleTitle),
+		ps.textSimilarity(ps.articleTitle, extractedTitle))
+}
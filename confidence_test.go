@@ -0,0 +1,45 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_Parser_Confidence(t *testing.T) {
+	paragraph := articleParagraph(10)
+	links := `<p><a href="/a">First related link</a>, <a href="/b">second related link</a>, ` +
+		`<a href="/c">third related link</a></p>`
+
+	good := `<html><head><title>Moon landing anniversary | News</title>` +
+		`<meta property="og:title" content="Moon landing anniversary"></head><body>` +
+		`<article><h1>Moon landing anniversary</h1>` + strings.Repeat(paragraph, 5) + `</article></body></html>`
+	poor := `<html><head><title>Home</title>` +
+		`<meta property="og:title" content="Latest stories from us"></head><body>` +
+		`<div><h1>Welcome</h1>` + links + links + `<p>Short text only.</p></div></body></html>`
+
+	goodArticle, err := FromReader(strings.NewReader(good), fakeHostURL)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	poorArticle, err := FromReader(strings.NewReader(poor), fakeHostURL)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	if goodArticle.Confidence < 0.7 || goodArticle.Confidence > 1 {
+		t.Errorf("want high confidence for good article, got %v", goodArticle.Confidence)
+	}
+	if poorArticle.Confidence > 0.4 || poorArticle.Confidence < 0 {
+		t.Errorf("want low confidence for poor article, got %v", poorArticle.Confidence)
+	}
+
+	empty, err := FromReader(strings.NewReader(`<html><body></body></html>`), fakeHostURL)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	if empty.Confidence != 0 {
+		t.Errorf("want zero confidence for empty article, got %v", empty.Confidence)
+	}
+}
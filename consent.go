@@ -0,0 +1,99 @@
+package readability
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/go-shiori/dom"
+	"golang.org/x/net/html"
+)
+
+// DefaultConsentPatterns is the class names and ids that used by the known
+// consent management platforms, e.g. OneTrust, Cookiebot and Didomi.
+// Pattern that ends with "*" matches by prefix.
+var DefaultConsentPatterns = []string{
+	"onetrust*", "ot-sdk-*", "optanon*", "cybotcookiebot*", "cookiebot*", "didomi*", "qc-cmp*",
+	"truste", "truste-*", "truste_*", "trustarc*", "osano-cm-*", "iubenda-cs-*", "usercentrics*", "uc-banner*", "sp_message*",
+	"fc-consent-*", "cky-consent*", "cc-window", "cc-banner", "cookie-law-info*", "cli-modal*",
+	"cmplz-*", "moove_gdpr*", "gdpr-cookie-*", "cookie-notice*", "cookie-consent*", "cookie-banner*",
+	"consent-banner*", "evidon-*",
+}
+
+// consentMaxLength is the max length of text in consent banner. Longer
+// element is never removed, even when it's matched by the patterns, so the
+// article that talks about cookie or wrapped in a similarly named element
+// won't be removed by mistake.
+const consentMaxLength = 1500
+
+var (
+	rxConsentClass = newKeywordMatcher(`(?i)cookie|consent|gdpr|ccpa|privacy`)
+	rxConsentText  = regexp.MustCompile(`(?i)\b(we|this (web)?site) (uses?|serves?) cookies\b|` +
+		`\bcookie (policy|settings|preferences|notice|consent)\b|` +
+		`\baccept (all )?cookies\b|` +
+		`\b(manage|customi[sz]e) (your )?(cookie |privacy |consent )?(preferences|settings|choices)\b|` +
+		`\b(your|privacy) choices\b|` +
+		`\bconsent to (the )?(use|processing|storing)\b`)
+	rxFixedPosition = regexp.MustCompile(`(?i)position\s*:\s*(fixed|sticky)`)
+)
+
+// removeConsentBanners removes the cookie banners and consent overlays,
+// which may survive into the content or even displace the real candidate
+// since they are often in their own container at the end of body. The
+// banner is detected from the class names of consent platforms, or from
+// the consent text in element with cookie-like class or fixed position.
+func (ps *Parser) removeConsentBanners(doc *html.Node) {
+	walkNodes(doc, ps.consentBannerVisitor())
+}
+
+// consentBannerVisitor returns the visitor of removeConsentBanners, which
+// removes the consent banners inside body.
+func (ps *Parser) consentBannerVisitor() nodeVisitor {
+	patterns := ps.ConsentPatterns
+	if patterns == nil {
+		patterns = DefaultConsentPatterns
+	}
+
+	return func(node *html.Node) visitResult {
+		if node.Type != html.ElementNode {
+			return visitChildren
+		}
+
+		switch dom.TagName(node) {
+		case "head":
+			return visitSkipChildren
+		case "html", "body":
+			return visitChildren
+		}
+
+		if node.Parent != nil && ps.isConsentBanner(node, patterns) {
+			ps.logDebug("removing consent banner", "match", dom.ClassName(node)+" "+dom.ID(node))
+			ps.diagnoseRemoval(node, RemovedConsentBanner)
+			node.Parent.RemoveChild(node)
+			return visitRemoved
+		}
+		return visitChildren
+	}
+}
+
+// isConsentBanner checks if node is a cookie banner or consent overlay.
+func (ps *Parser) isConsentBanner(node *html.Node, patterns []string) bool {
+	matchPattern := false
+	for _, name := range append(strings.Fields(dom.ClassName(node)), dom.ID(node)) {
+		if name != "" && matchNamePattern(name, patterns) {
+			matchPattern = true
+			break
+		}
+	}
+
+	matchString := dom.ClassName(node) + " " + dom.ID(node)
+	if !matchPattern && !rxConsentClass.MatchString(matchString) &&
+		!rxFixedPosition.MatchString(dom.GetAttribute(node, "style")) {
+		return false
+	}
+
+	text := ps.getInnerText(node, true)
+	if charCount(text) > consentMaxLength {
+		return false
+	}
+	return matchPattern || rxConsentText.MatchString(text)
+}
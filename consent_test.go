@@ -0,0 +1,79 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_Parser_removeConsentBanners(t *testing.T) {
+	paragraph := articleParagraph(10)
+	recipe := "<p>" + strings.Repeat("Bake the cookie until golden, then accept all cookies from the oven. ", 25) + "</p>"
+	input := `<html><body><article>` + paragraph +
+		`<div id="onetrust-consent-sdk"><p>Vendor banner that has no consent text at all.</p></div>` +
+		`<div class="cookie-bar"><p>We use cookies to improve your experience. Read our cookie policy.</p></div>` +
+		`<div style="position: fixed; bottom: 0"><p>By clicking accept, you agree. Manage your preferences.</p></div>` +
+		`<div class="site-cmp"><p>Custom platform banner.</p></div>` +
+		`<div class="cookie-recipe">` + recipe + `</div>` + paragraph + `</article></body></html>`
+
+	tests := []struct {
+		name       string
+		opts       []Option
+		expected   []string
+		unexpected []string
+	}{{
+		name:       "default",
+		expected:   []string{"Bake the cookie", "Custom platform banner"},
+		unexpected: []string{"Vendor banner", "We use cookies", "Manage your preferences"},
+	}, {
+		name:       "custom patterns",
+		opts:       []Option{WithConsentPatterns(append(DefaultConsentPatterns, "site-cmp")...)},
+		expected:   []string{"Bake the cookie"},
+		unexpected: []string{"Vendor banner", "Custom platform banner"},
+	}, {
+		name:     "keep consent banners",
+		opts:     []Option{WithKeepConsentBanners(true)},
+		expected: []string{"Bake the cookie", "Vendor banner", "We use cookies", "Manage your preferences"},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ps := NewParser(test.opts...)
+			article, err := ps.Parse(strings.NewReader(input), fakeHostURL)
+			if err != nil {
+				t.Fatalf("failed to parse: %v", err)
+			}
+
+			for _, expected := range test.expected {
+				if !strings.Contains(article.TextContent, expected) {
+					t.Errorf("content should contain %q: %s", expected, article.TextContent)
+				}
+			}
+
+			for _, unexpected := range test.unexpected {
+				if strings.Contains(article.TextContent, unexpected) {
+					t.Errorf("content should not contain %q: %s", unexpected, article.TextContent)
+				}
+			}
+		})
+	}
+}
+
+func Test_Parser_removeConsentBanners_articleWrapper(t *testing.T) {
+	// The wrapper that merely looks like a consent platform must not take
+	// the article with it
+	paragraph := articleParagraph(10)
+	for _, wrapper := range []string{`class="trusted-reporting"`, `class="trustee-notes"`, `id="onetrust-wrapper"`} {
+		input := `<html><body><div ` + wrapper + `><article>` + paragraph + paragraph + paragraph +
+			`</article></div></body></html>`
+
+		ps := NewParser()
+		article, err := ps.Parse(strings.NewReader(input), fakeHostURL)
+		if err != nil {
+			t.Fatalf("%s: failed to parse: %v", wrapper, err)
+		}
+
+		if article.Length < 3*len(paragraph)/2 {
+			t.Errorf("%s: article is removed, length %d: %s", wrapper, article.Length, article.TextContent)
+		}
+	}
+}
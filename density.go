@@ -0,0 +1,241 @@
+package readability
+
+import (
+	"unicode"
+
+	"github.com/go-shiori/dom"
+	"golang.org/x/net/html"
+)
+
+// Algorithm is the algorithm that used to find the article content.
+type Algorithm int
+
+const (
+	// AlgorithmReadability finds the article by scoring the candidates,
+	// just like Readability.js.
+	AlgorithmReadability Algorithm = iota
+	// AlgorithmDensity finds the article by its text density, i.e. the
+	// number of characters per tag, which works on pages where candidate
+	// scoring fails like documentation portal and forum. It's based on
+	// Content Extraction via Text Density (CETD).
+	AlgorithmDensity
+	// AlgorithmHybrid finds the article using AlgorithmReadability, then
+	// falls back to AlgorithmDensity when the content is still shorter
+	// than CharThresholds after all retries with less strict flags.
+	AlgorithmHybrid
+)
+
+// Strategy is the strategy that found the article content.
+type Strategy string
+
+// Strategies that recorded in Article.Strategy, so the caller knows which
+// one found the content.
+const (
+	// StrategyReadability is the candidate scoring with all flags.
+	StrategyReadability Strategy = "readability"
+	// StrategyRelaxed is the candidate scoring with less strict flags,
+	// e.g. without removing the unlikely candidates.
+	StrategyRelaxed Strategy = "relaxed"
+	// StrategyDensity is the text density algorithm.
+	StrategyDensity Strategy = "density"
+	// StrategySiteRule is the content selectors of site rule.
+	StrategySiteRule Strategy = "site rule"
+)
+
+// densityBoilerplateTags is the tags that never contain the article, so
+// they are removed before the density is measured.
+var densityBoilerplateTags = []string{"nav", "aside", "footer", "form", "button", "select", "noscript", "iframe"}
+
+// densityTextBlocks is the tags of text blocks that kept even when they
+// are short, as long as they are not mostly links.
+var densityTextBlocks = sliceToMap("p", "h1", "h2", "h3", "h4", "h5", "h6", "blockquote", "pre",
+	"ul", "ol", "dl", "table", "figcaption")
+
+// densityMediaTags is the tags of media, which don't have text but still
+// part of the article.
+var densityMediaTags = sliceToMap("img", "picture", "figure", "video", "audio")
+
+// densityStats is the statistics of a subtree that used to measure its
+// text density.
+type densityStats struct {
+	chars     int
+	tags      int
+	linkChars int
+	// density is the number of non-link characters per tag.
+	density float64
+	// densitySum is the sum of density of the element children.
+	densitySum float64
+	// maxDensity is the highest density inside the subtree.
+	maxDensity float64
+}
+
+// grabContent finds the article content using the algorithm of parser,
+// and records the strategy that found it.
+func (ps *Parser) grabContent() (*html.Node, error) {
+	if ps.Algorithm == AlgorithmDensity {
+		return ps.grabDensityArticle()
+	}
+
+	articleContent, err := ps.grabArticle()
+	if err != nil || ps.Algorithm != AlgorithmHybrid {
+		return articleContent, err
+	}
+
+	textLength := 0
+	if articleContent != nil {
+		textLength = ps.getTextLength(articleContent)
+	}
+	if textLength >= ps.CharThresholds {
+		return articleContent, nil
+	}
+
+	// Candidate scoring failed even with the less strict flags, so try
+	// the density which is used only if it finds more content
+	strategy, dir := ps.strategy, ps.articleDir
+	densityContent, err := ps.grabDensityArticle()
+	if err != nil {
+		return nil, err
+	}
+
+	if densityContent == nil || ps.getTextLength(densityContent) <= textLength {
+		ps.strategy, ps.articleDir = strategy, dir
+		return articleContent, nil
+	}
+
+	ps.logInfo("article grabbed using density fallback", "readability length", textLength)
+	return densityContent, nil
+}
+
+// grabDensityArticle finds the article content by its text density. The
+// element whose children have the highest total density is picked, then
+// its children that are sparser than the whole page are removed. The
+// content is wrapped the same way as the one from grabArticle.
+func (ps *Parser) grabDensityArticle() (*html.Node, error) {
+	if err := ps.ctxErr(); err != nil {
+		return nil, err
+	}
+
+	doc := dom.Clone(ps.doc, true)
+	body := dom.QuerySelector(doc, "body")
+	if body == nil {
+		ps.logWarn("no body found in document, abort")
+		return nil, nil
+	}
+
+	ps.removeNodes(ps.getAllNodesWithTag(body, densityBoilerplateTags...), nil)
+
+	stats := make(map[*html.Node]*densityStats)
+	bodyStats := measureDensity(body, false, stats)
+	if bodyStats.chars == 0 {
+		return nil, nil
+	}
+
+	// Pick the element with the highest density sum. Elements are checked
+	// in document order, so the ancestor wins when they are tied.
+	best, bestSum := body, bodyStats.densitySum
+	for _, node := range dom.QuerySelectorAll(body, "*") {
+		if nodeStats := stats[node]; nodeStats != nil && nodeStats.densitySum > bestSum {
+			best, bestSum = node, nodeStats.densitySum
+		}
+	}
+
+	ps.pruneSparseNodes(best, bodyStats.density, stats)
+	dir := getNodeDir(best)
+	ps.logInfo("article grabbed using text density", "tag", dom.TagName(best),
+		"density sum", bestSum, "threshold", bodyStats.density)
+
+	page := dom.CreateElement("div")
+	dom.SetAttribute(page, "id", "readability-page-1")
+	dom.SetAttribute(page, "class", "page")
+	if dom.TagName(best) == "body" {
+		for best.FirstChild != nil {
+			dom.AppendChild(page, best.FirstChild)
+		}
+	} else {
+		dom.AppendChild(page, best)
+	}
+
+	articleContent := dom.CreateElement("div")
+	dom.AppendChild(articleContent, page)
+
+	// Clean up for presentation. The sections are already filtered by
+	// their density, so they are not cleaned conditionally.
+	flags := ps.flags
+	ps.flags.cleanConditionally = false
+	ps.prepArticle(articleContent)
+	ps.flags = flags
+
+	if ps.getTextLength(articleContent) == 0 {
+		return nil, nil
+	}
+
+	ps.strategy = StrategyDensity
+	ps.setArticleDir(articleContent, dir)
+	return articleContent, nil
+}
+
+// measureDensity counts the characters and tags inside node, then saves
+// the statistics of each element into stats.
+func measureDensity(node *html.Node, inLink bool, stats map[*html.Node]*densityStats) densityStats {
+	var result densityStats
+	if node.Type == html.TextNode {
+		for _, r := range node.Data {
+			if !unicode.IsSpace(r) {
+				result.chars++
+			}
+		}
+		if inLink {
+			result.linkChars = result.chars
+		}
+		return result
+	}
+
+	if node.Type != html.ElementNode {
+		return result
+	}
+
+	result.tags = 1
+	inLink = inLink || dom.TagName(node) == "a"
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		childStats := measureDensity(child, inLink, stats)
+		result.chars += childStats.chars
+		result.tags += childStats.tags
+		result.linkChars += childStats.linkChars
+		result.densitySum += childStats.density
+		if childStats.maxDensity > result.maxDensity {
+			result.maxDensity = childStats.maxDensity
+		}
+	}
+
+	result.density = float64(result.chars-result.linkChars) / float64(result.tags)
+	if result.density > result.maxDensity {
+		result.maxDensity = result.density
+	}
+
+	stats[node] = &result
+	return result
+}
+
+// pruneSparseNodes removes the element children of node whose subtree
+// doesn't have any part that reaches the density threshold. Short text
+// blocks like paragraph and heading are kept unless they are mostly
+// links, and so are the media that don't have text at all.
+func (ps *Parser) pruneSparseNodes(node *html.Node, threshold float64, stats map[*html.Node]*densityStats) {
+	for _, child := range dom.Children(node) {
+		childStats := stats[child]
+		_, isTextBlock := densityTextBlocks[dom.TagName(child)]
+		_, isMedia := densityMediaTags[dom.TagName(child)]
+
+		switch {
+		case childStats == nil || isMedia || ps.hasPlayableMedia(child):
+			continue
+		case childStats.maxDensity >= threshold:
+			ps.pruneSparseNodes(child, threshold, stats)
+		case isTextBlock && childStats.linkChars*2 <= childStats.chars:
+			continue
+		default:
+			ps.diagnoseRemoval(child, RemovedLowDensity)
+			node.RemoveChild(child)
+		}
+	}
+}
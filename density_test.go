@@ -0,0 +1,95 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_Parser_AlgorithmDensity(t *testing.T) {
+	paragraph := "<p>" + strings.Repeat("This is a sentence of the post, long enough to be dense. ", 8) + "</p>"
+	links := `<ul><li><a href="/a">First link</a></li><li><a href="/b">Second link</a></li>` +
+		`<li><a href="/c">Third link</a></li></ul>`
+	input := `<html><body>` +
+		`<div class="top"><a href="/">Home</a> <a href="/forum">Forum</a></div>` +
+		`<table><tr><td class="menu">` + links + `</td>` +
+		`<td class="thread"><h2>Thread title</h2>` + paragraph + `<p>Short reply.</p>` + paragraph +
+		`<div class="signature"><a href="/u/1">User</a> | <a href="/u/1/posts">Posts</a></div>` + paragraph +
+		`<img src="/photo.jpg" alt="Photo"></td></tr></table>` +
+		`<nav>` + links + `</nav></body></html>`
+
+	ps := NewParser(WithAlgorithm(AlgorithmDensity))
+	article, err := ps.Parse(strings.NewReader(input), fakeHostURL)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	for _, expected := range []string{"Thread title", "long enough to be dense", "Short reply."} {
+		if !strings.Contains(article.TextContent, expected) {
+			t.Errorf("content should contain %q: %q", expected, article.TextContent)
+		}
+	}
+
+	for _, unexpected := range []string{"Home", "First link", "Posts"} {
+		if strings.Contains(article.TextContent, unexpected) {
+			t.Errorf("content should not contain %q: %q", unexpected, article.TextContent)
+		}
+	}
+
+	if !strings.Contains(article.Content, `<img src="http://fakehost/photo.jpg"`) {
+		t.Errorf("image should be kept: %s", article.Content)
+	}
+
+	// Page without text has no content
+	article, err = ps.Parse(strings.NewReader(`<html><body><div><img src="a.png"></div></body></html>`), fakeHostURL)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	if article.Node != nil {
+		t.Errorf("want no content, got %q", article.Content)
+	}
+}
+
+func Test_Parser_AlgorithmHybrid(t *testing.T) {
+	// Readability.js doesn't score list item, so only the introduction is
+	// found even after all retries with less strict flags.
+	item := "<li>" + strings.Repeat("This is an answer of the question, long enough to be dense. ", 2) + "</li>"
+	input := `<html><body><div><div><p>` + strings.Repeat("This is the introduction of the page. ", 2) +
+		`</p></div></div><div><ul>` + strings.Repeat(item, 4) + `</ul></div></body></html>`
+
+	tests := []struct {
+		algorithm Algorithm
+		strategy  Strategy
+		expected  string
+	}{
+		{AlgorithmReadability, StrategyReadability, "introduction of the page"},
+		{AlgorithmHybrid, StrategyDensity, "answer of the question"},
+	}
+
+	for _, test := range tests {
+		ps := NewParser(WithAlgorithm(test.algorithm))
+		article, err := ps.Parse(strings.NewReader(input), fakeHostURL)
+		if err != nil {
+			t.Fatalf("failed to parse: %v", err)
+		}
+
+		if article.Strategy != test.strategy {
+			t.Errorf("algorithm %d: want strategy %q, got %q", test.algorithm, test.strategy, article.Strategy)
+		}
+		if !strings.Contains(article.TextContent, test.expected) {
+			t.Errorf("algorithm %d: content should contain %q: %q", test.algorithm, test.expected, article.TextContent)
+		}
+	}
+
+	// Readability result is kept when it's long enough
+	paragraph := articleParagraph(10)
+	input = `<html><body><article>` + paragraph + paragraph + `</article></body></html>`
+	ps := NewParser(WithAlgorithm(AlgorithmHybrid))
+	article, err := ps.Parse(strings.NewReader(input), fakeHostURL)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	if article.Strategy != StrategyReadability {
+		t.Errorf("want strategy %q, got %q", StrategyReadability, article.Strategy)
+	}
+}
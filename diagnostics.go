@@ -0,0 +1,111 @@
+package readability
+
+import (
+	"strings"
+	"time"
+
+	"github.com/go-shiori/dom"
+	"golang.org/x/net/html"
+)
+
+// RemovalReason is the reason why a node is removed from the article.
+type RemovalReason string
+
+// Reasons of node removal that recorded in Diagnostics.
+const (
+	RemovedHidden          RemovalReason = "hidden"
+	RemovedModalDialog     RemovalReason = "modal dialog"
+	RemovedByline          RemovalReason = "byline"
+	RemovedDuplicateTitle  RemovalReason = "duplicate title"
+	RemovedUnlikely        RemovalReason = "unlikely candidate"
+	RemovedUnlikelyRole    RemovalReason = "unlikely role"
+	RemovedEmpty           RemovalReason = "without content"
+	RemovedTag             RemovalReason = "unwanted tag"
+	RemovedShareElement    RemovalReason = "share element"
+	RemovedNegativeWeight  RemovalReason = "negative class weight"
+	RemovedTooManyImages   RemovalReason = "too many images"
+	RemovedTooManyListItem RemovalReason = "too many list items"
+	RemovedTooManyInputs   RemovalReason = "too many inputs"
+	RemovedTooShort        RemovalReason = "too short"
+	RemovedLinkDensity     RemovalReason = "high link density"
+	RemovedTooManyEmbeds   RemovalReason = "too many embeds"
+	RemovedSiteRule        RemovalReason = "site rule"
+	RemovedLowDensity      RemovalReason = "low text density"
+	RemovedConsentBanner   RemovalReason = "consent banner"
+	RemovedPromo           RemovalReason = "newsletter or call to action"
+)
+
+// maxRemovedTextLength is the max length of text that kept for each
+// removed node in Diagnostics.
+const maxRemovedTextLength = 100
+
+// RemovedNode is the summary of a subtree that removed from the article.
+type RemovedNode struct {
+	Tag    string        `json:"tag"`
+	ID     string        `json:"id"`
+	Class  string        `json:"class"`
+	Text   string        `json:"text"`
+	Reason RemovalReason `json:"reason"`
+	// Attempt is the number of grab attempt where the node is removed,
+	// starting from 1. It's 0 if the node is removed before the article
+	// is grabbed.
+	Attempt int `json:"attempt"`
+}
+
+// PhaseTiming is the time spent in a phase of the extraction.
+type PhaseTiming struct {
+	Phase    string        `json:"phase"`
+	Duration time.Duration `json:"duration"`
+}
+
+// Diagnostics is the report of the extraction, which is useful to find out
+// why the article is empty or missing some of its content.
+type Diagnostics struct {
+	// Removed is the subtrees that removed, in the order of removal.
+	Removed []RemovedNode `json:"removed"`
+	// Attempts is the number of attempts to grab the article. Each attempt
+	// is less strict than the previous one.
+	Attempts int `json:"attempts"`
+	// Attempt is the number of attempt whose result is used.
+	Attempt int `json:"attempt"`
+	// TopCandidateScore is the score of the winning candidate.
+	TopCandidateScore float64 `json:"top_candidate_score"`
+	// Timings is the time spent in each phase of the extraction, which are
+	// "preprocess", "inspect", "prepare", "metadata", "grab" and
+	// "post-process". The last one is missing when there are no content.
+	Timings []PhaseTiming `json:"timings"`
+}
+
+// diagnoseRemoval records the removal of node, if diagnostics is enabled.
+func (ps *Parser) diagnoseRemoval(node *html.Node, reason RemovalReason) {
+	if ps.diagnostics == nil || node.Type != html.ElementNode {
+		return
+	}
+
+	text := strings.Join(strings.Fields(dom.TextContent(node)), " ")
+	if runes := []rune(text); len(runes) > maxRemovedTextLength {
+		text = string(runes[:maxRemovedTextLength]) + "..."
+	}
+
+	ps.diagnostics.Removed = append(ps.diagnostics.Removed, RemovedNode{
+		Tag:     dom.TagName(node),
+		ID:      dom.ID(node),
+		Class:   dom.ClassName(node),
+		Text:    text,
+		Reason:  reason,
+		Attempt: ps.diagnostics.Attempts,
+	})
+}
+
+// diagnosePhase records the time spent in a phase since start, and returns
+// the start time of the next phase.
+func (ps *Parser) diagnosePhase(phase string, start time.Time) time.Time {
+	now := time.Now()
+	if ps.diagnostics != nil {
+		ps.diagnostics.Timings = append(ps.diagnostics.Timings, PhaseTiming{
+			Phase:    phase,
+			Duration: now.Sub(start),
+		})
+	}
+	return now
+}
@@ -0,0 +1,71 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_Parser_Diagnostics(t *testing.T) {
+	paragraph := articleParagraph(10)
+	input := `<html><body><article>` + paragraph + paragraph +
+		`<div id="hidden" style="display: none">Hidden text</div>` +
+		`<div class="comments">Unlikely comments</div>` +
+		`<div><a href="/a">First link of the navigation</a> <a href="/b">Second link</a></div>` +
+		`</article></body></html>`
+
+	ps := NewParser()
+	article, err := ps.Parse(strings.NewReader(input), fakeHostURL)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	if article.Diagnostics != nil {
+		t.Errorf("diagnostics should be nil when not requested")
+	}
+
+	ps = NewParser(WithDiagnostics(true))
+	article, err = ps.Parse(strings.NewReader(input), fakeHostURL)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	diagnostics := article.Diagnostics
+	if diagnostics == nil {
+		t.Fatalf("diagnostics should be returned")
+	}
+
+	reasons := make(map[RemovalReason]RemovedNode)
+	for _, removed := range diagnostics.Removed {
+		reasons[removed.Reason] = removed
+	}
+
+	expected := map[RemovalReason]string{
+		RemovedHidden:      "Hidden text",
+		RemovedUnlikely:    "Unlikely comments",
+		RemovedLinkDensity: "First link of the navigation Second link",
+	}
+
+	for reason, text := range expected {
+		if removed, exist := reasons[reason]; !exist || removed.Text != text {
+			t.Errorf("reason %q, want text %q got %+v", reason, text, removed)
+		}
+	}
+
+	if diagnostics.Attempts != 1 || diagnostics.Attempt != 1 {
+		t.Errorf("want 1 attempt, got %d and use attempt %d", diagnostics.Attempts, diagnostics.Attempt)
+	}
+
+	if diagnostics.TopCandidateScore <= 0 {
+		t.Errorf("top candidate score should be positive, got %v", diagnostics.TopCandidateScore)
+	}
+
+	var phases []string
+	for _, timing := range diagnostics.Timings {
+		phases = append(phases, timing.Phase)
+	}
+
+	expectedPhases := "preprocess,inspect,prepare,metadata,grab,post-process"
+	if got := strings.Join(phases, ","); got != expectedPhases {
+		t.Errorf("phases, want %q got %q", expectedPhases, got)
+	}
+}
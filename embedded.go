@@ -0,0 +1,301 @@
+package readability
+
+import (
+	"encoding/json"
+	nurl "net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-shiori/dom"
+	"golang.org/x/net/html"
+)
+
+var (
+	rxISODuration = regexp.MustCompile(`(?i)^P(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+(?:\.\d+)?)S)?)?$`)
+	rxYouTubeID   = regexp.MustCompile(`^/(?:embed|v)/([\w-]{11})`)
+)
+
+// embedProviders maps the host of embed URL into its provider name.
+var embedProviders = map[string]string{
+	"youtube.com":          "youtube",
+	"youtube-nocookie.com": "youtube",
+	"youtu.be":             "youtube",
+	"player.vimeo.com":     "vimeo",
+	"vimeo.com":            "vimeo",
+	"dailymotion.com":      "dailymotion",
+	"player.twitch.tv":     "twitch",
+	"v.qq.com":             "qq",
+	"w.soundcloud.com":     "soundcloud",
+	"soundcloud.com":       "soundcloud",
+	"open.spotify.com":     "spotify",
+}
+
+// audioProviders is the providers whose embeds are audio.
+var audioProviders = []string{"soundcloud", "spotify"}
+
+// EmbeddedMedia is a video or audio in the article, either embedded from
+// a provider like YouTube, or hosted by the site itself in which case
+// the provider is empty. When encoded to JSON, the duration is written
+// in seconds.
+type EmbeddedMedia struct {
+	Provider  string        `json:"provider,omitempty"`
+	Title     string        `json:"title,omitempty"`
+	URL       string        `json:"url,omitempty"`
+	EmbedURL  string        `json:"embed_url,omitempty"`
+	Thumbnail string        `json:"thumbnail,omitempty"`
+	Duration  time.Duration `json:"-"`
+}
+
+// plainEmbeddedMedia has the same fields as EmbeddedMedia, but without its
+// JSON methods so it can be encoded without infinite recursion.
+type plainEmbeddedMedia EmbeddedMedia
+
+// embeddedMediaJSON is the JSON representation of EmbeddedMedia.
+type embeddedMediaJSON struct {
+	plainEmbeddedMedia
+	Duration int `json:"duration,omitempty"`
+}
+
+// MarshalJSON encodes media into JSON object, with duration in seconds.
+func (media EmbeddedMedia) MarshalJSON() ([]byte, error) {
+	return json.Marshal(embeddedMediaJSON{
+		plainEmbeddedMedia: plainEmbeddedMedia(media),
+		Duration:           int(media.Duration / time.Second),
+	})
+}
+
+// UnmarshalJSON decodes JSON object that created by MarshalJSON.
+func (media *EmbeddedMedia) UnmarshalJSON(data []byte) error {
+	var decoded embeddedMediaJSON
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	*media = EmbeddedMedia(decoded.plainEmbeddedMedia)
+	media.Duration = time.Duration(decoded.Duration) * time.Second
+	return nil
+}
+
+// getJSONLDMedia returns the VideoObject and AudioObject in JSON-LD, which
+// may be put anywhere, e.g. in @graph or as video of the article.
+func (ps *Parser) getJSONLDMedia() (videos, audios []EmbeddedMedia) {
+	var walk func(interface{})
+	walk = func(value interface{}) {
+		switch val := value.(type) {
+		case []interface{}:
+			for _, item := range val {
+				walk(item)
+			}
+		case map[string]interface{}:
+			switch strType, _ := val["@type"].(string); strType {
+			case "VideoObject":
+				videos = append(videos, ps.jsonLDMedia(val))
+				return
+			case "AudioObject":
+				audios = append(audios, ps.jsonLDMedia(val))
+				return
+			}
+
+			for _, item := range val {
+				walk(item)
+			}
+		}
+	}
+
+	for _, script := range dom.QuerySelectorAll(ps.doc, `script[type="application/ld+json"]`) {
+		var parsed interface{}
+		content := rxCDATA.ReplaceAllString(dom.TextContent(script), "")
+		if err := json.Unmarshal([]byte(content), &parsed); err == nil {
+			walk(parsed)
+		}
+	}
+
+	return videos, audios
+}
+
+// jsonLDMedia converts VideoObject or AudioObject in JSON-LD into media.
+func (ps *Parser) jsonLDMedia(obj map[string]interface{}) EmbeddedMedia {
+	str := func(key string) string {
+		value, _ := obj[key].(string)
+		return strings.TrimSpace(value)
+	}
+
+	media := EmbeddedMedia{
+		Title:    str("name"),
+		Duration: parseISODuration(str("duration")),
+	}
+
+	if contentURL := str("contentUrl"); contentURL != "" {
+		media.URL = toAbsoluteURI(contentURL, ps.documentURI)
+	}
+
+	if embedURL := str("embedUrl"); embedURL != "" {
+		media.EmbedURL = toAbsoluteURI(embedURL, ps.documentURI)
+		media.Provider = embedProvider(media.EmbedURL)
+	}
+
+	// Thumbnail may be written as URL, ImageObject or list of them
+	thumbnail := obj["thumbnailUrl"]
+	if thumbnail == nil {
+		thumbnail = obj["thumbnail"]
+	}
+	if thumbnails, isArray := thumbnail.([]interface{}); isArray && len(thumbnails) > 0 {
+		thumbnail = thumbnails[0]
+	}
+
+	switch val := thumbnail.(type) {
+	case string:
+		media.Thumbnail = toAbsoluteURI(strings.TrimSpace(val), ps.documentURI)
+	case map[string]interface{}:
+		if url, isString := val["url"].(string); isString {
+			media.Thumbnail = toAbsoluteURI(strings.TrimSpace(url), ps.documentURI)
+		}
+	}
+
+	return media
+}
+
+// getEmbeddedMedia returns the videos and audios in article content, then
+// merges them with the ones in JSON-LD which usually have more details.
+// It must be called after the URLs are converted to absolute.
+func (ps *Parser) getEmbeddedMedia(articleContent *html.Node, jsonLdVideos, jsonLdAudios []EmbeddedMedia) (videos, audios []EmbeddedMedia) {
+	if articleContent != nil {
+		for _, node := range dom.QuerySelectorAll(articleContent, "iframe, embed, video, audio") {
+			tagName := dom.TagName(node)
+			media := EmbeddedMedia{Title: strings.TrimSpace(dom.GetAttribute(node, "title"))}
+
+			switch tagName {
+			case "video", "audio":
+				media.URL = dom.GetAttribute(node, "src")
+				if media.URL == "" {
+					if sources := dom.GetElementsByTagName(node, "source"); len(sources) > 0 {
+						media.URL = dom.GetAttribute(sources[0], "src")
+					}
+				}
+				media.Thumbnail = dom.GetAttribute(node, "poster")
+			default:
+				media.EmbedURL = dom.GetAttribute(node, "src")
+				media.Provider = embedProvider(media.EmbedURL)
+				media.Thumbnail = embedThumbnail(media.Provider, media.EmbedURL)
+			}
+
+			if media.URL == "" && media.EmbedURL == "" {
+				continue
+			}
+
+			if tagName == "audio" || indexOf(audioProviders, media.Provider) >= 0 {
+				audios = append(audios, media)
+			} else {
+				videos = append(videos, media)
+			}
+		}
+	}
+
+	return mergeEmbeddedMedia(videos, jsonLdVideos), mergeEmbeddedMedia(audios, jsonLdAudios)
+}
+
+// mergeEmbeddedMedia fills the missing details of media in content using the
+// JSON-LD media with the same URL. The unmatched JSON-LD media are added
+// at the end.
+func mergeEmbeddedMedia(medias, jsonLdMedias []EmbeddedMedia) []EmbeddedMedia {
+	for _, ldMedia := range jsonLdMedias {
+		merged := false
+		for i := range medias {
+			media := &medias[i]
+			if !sameMediaURL(media.EmbedURL, ldMedia.EmbedURL) && !sameMediaURL(media.URL, ldMedia.URL) {
+				continue
+			}
+
+			media.Provider = strOr(media.Provider, ldMedia.Provider)
+			media.Title = strOr(media.Title, ldMedia.Title)
+			media.URL = strOr(media.URL, ldMedia.URL)
+			media.EmbedURL = strOr(media.EmbedURL, ldMedia.EmbedURL)
+			media.Thumbnail = strOr(ldMedia.Thumbnail, media.Thumbnail)
+			if media.Duration == 0 {
+				media.Duration = ldMedia.Duration
+			}
+
+			merged = true
+			break
+		}
+
+		if !merged {
+			medias = append(medias, ldMedia)
+		}
+	}
+	return medias
+}
+
+// sameMediaURL checks if both URLs point to the same media, ignoring the
+// scheme and query.
+func sameMediaURL(a, b string) bool {
+	if a == "" || b == "" {
+		return false
+	}
+
+	urlA, errA := nurl.Parse(a)
+	urlB, errB := nurl.Parse(b)
+	if errA != nil || errB != nil {
+		return a == b
+	}
+
+	return strings.EqualFold(urlA.Host, urlB.Host) &&
+		strings.TrimSuffix(urlA.Path, "/") == strings.TrimSuffix(urlB.Path, "/")
+}
+
+// embedProvider returns the name of provider of the embed URL, or its
+// host name if the provider is unknown.
+func embedProvider(embedURL string) string {
+	parsedURL, err := nurl.Parse(embedURL)
+	if err != nil || parsedURL.Hostname() == "" {
+		return ""
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(parsedURL.Hostname()), "www.")
+	if provider, exist := embedProviders[host]; exist {
+		return provider
+	}
+	return host
+}
+
+// embedThumbnail returns the thumbnail of the embed whose thumbnail can be
+// derived from its URL, e.g. YouTube video.
+func embedThumbnail(provider, embedURL string) string {
+	if provider != "youtube" {
+		return ""
+	}
+
+	parsedURL, err := nurl.Parse(embedURL)
+	if err != nil {
+		return ""
+	}
+
+	if parts := rxYouTubeID.FindStringSubmatch(parsedURL.Path); len(parts) == 2 {
+		return "https://i.ytimg.com/vi/" + parts[1] + "/hqdefault.jpg"
+	}
+	return ""
+}
+
+// parseISODuration parses duration in ISO 8601 format, e.g. "PT1H2M3S".
+// Returns 0 if it's not valid.
+func parseISODuration(value string) time.Duration {
+	parts := rxISODuration.FindStringSubmatch(strings.TrimSpace(value))
+	if len(parts) != 5 || value == "P" || value == "PT" {
+		return 0
+	}
+
+	var duration time.Duration
+	units := []time.Duration{24 * time.Hour, time.Hour, time.Minute}
+	for i, unit := range units {
+		if number, err := strconv.Atoi(parts[i+1]); err == nil {
+			duration += time.Duration(number) * unit
+		}
+	}
+
+	if seconds, err := strconv.ParseFloat(parts[4], 64); err == nil {
+		duration += time.Duration(seconds * float64(time.Second))
+	}
+	return duration
+}
@@ -0,0 +1,72 @@
+package readability
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_Parser_EmbeddedMedia(t *testing.T) {
+	paragraph := articleParagraph(10)
+	input := `<html><head><script type="application/ld+json">{"@context":"https://schema.org","@graph":[` +
+		`{"@type":"NewsArticle","headline":"Title","video":{"@type":"VideoObject","name":"The Video",` +
+		`"embedUrl":"https://www.youtube.com/embed/dQw4w9WgXcQ","thumbnailUrl":["https://fakehost/thumb.jpg"],"duration":"PT1M30S"}},` +
+		`{"@type":"AudioObject","name":"The Podcast","contentUrl":"/podcast.mp3","duration":"PT1H"}]}</script></head>` +
+		`<body><article>` + paragraph +
+		`<iframe src="https://www.youtube.com/embed/dQw4w9WgXcQ?rel=0"></iframe>` + paragraph +
+		`<video src="/clip.mp4" poster="/poster.jpg" controls></video>` + paragraph +
+		`</article></body></html>`
+
+	article, err := FromReader(strings.NewReader(input), fakeHostURL)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	expectedVideos := []EmbeddedMedia{{
+		Provider:  "youtube",
+		Title:     "The Video",
+		EmbedURL:  "https://www.youtube.com/embed/dQw4w9WgXcQ?rel=0",
+		Thumbnail: "https://fakehost/thumb.jpg",
+		Duration:  90 * time.Second,
+	}, {
+		URL:       "http://fakehost/clip.mp4",
+		Thumbnail: "http://fakehost/poster.jpg",
+	}}
+
+	expectedAudios := []EmbeddedMedia{{
+		Title:    "The Podcast",
+		URL:      "http://fakehost/podcast.mp3",
+		Duration: time.Hour,
+	}}
+
+	if !reflect.DeepEqual(article.Videos, expectedVideos) {
+		t.Errorf("\n"+
+			"want : %+v\n"+
+			"got  : %+v", expectedVideos, article.Videos)
+	}
+
+	if !reflect.DeepEqual(article.Audios, expectedAudios) {
+		t.Errorf("\n"+
+			"want : %+v\n"+
+			"got  : %+v", expectedAudios, article.Audios)
+	}
+}
+
+func Test_parseISODuration(t *testing.T) {
+	scenarios := map[string]time.Duration{
+		"PT1H2M3S": time.Hour + 2*time.Minute + 3*time.Second,
+		"PT90S":    90 * time.Second,
+		"P1DT1H":   25 * time.Hour,
+		"PT1.5S":   1500 * time.Millisecond,
+		"PT":       0,
+		"1:30":     0,
+		"":         0,
+	}
+
+	for value, expected := range scenarios {
+		if duration := parseISODuration(value); duration != expected {
+			t.Errorf("duration %q, want %v got %v", value, expected, duration)
+		}
+	}
+}
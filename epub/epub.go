@@ -0,0 +1,448 @@
+// Package epub creates EPUB 3 e-book from one or more readable article,
+// e.g. to send the articles to an e-reader.
+package epub
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha1"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/go-shiori/dom"
+	readability "github.com/go-shiori/go-readability"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Image is an image that embedded inside the e-book.
+type Image struct {
+	// MediaType is the MIME type of the image, e.g. "image/png".
+	MediaType string
+	// Data is the content of the image.
+	Data []byte
+}
+
+// ImageLoader loads the image from specified URL, so it can be embedded
+// inside the e-book.
+type ImageLoader func(src string) (*Image, error)
+
+// Book is the e-book that will be created.
+type Book struct {
+	// Title is the title of the book. If empty, the title of the first
+	// chapter will be used.
+	Title string
+	// Author is the author of the book. If empty, the byline of the
+	// first chapter will be used.
+	Author string
+	// Language is the language of the book. If empty, the language of
+	// the first chapter will be used, or "en" if it's not available.
+	Language string
+	// Identifier is the unique identifier of the book. If empty, an UUID
+	// will be generated from the content of the book.
+	Identifier string
+	// Modified is the last time the book is modified. If zero, the
+	// current time will be used.
+	Modified time.Time
+	// Cover is the cover image of the book. Optional.
+	Cover *Image
+	// Chapters is the articles that become the chapters of the book.
+	Chapters []readability.Article
+	// LoadImage is used to load the images inside chapters. EPUB doesn't
+	// allow remote images, so if LoadImage is nil or it fails to load
+	// the image, the image will be replaced by its alternative text. The
+	// audio and video are never embedded, they are replaced by the link
+	// to their source instead.
+	LoadImage ImageLoader
+}
+
+// manifestItem is an item inside the manifest of the package document.
+type manifestItem struct {
+	id         string
+	href       string
+	mediaType  string
+	properties string
+}
+
+// bookWriter writes the content of a book.
+type bookWriter struct {
+	book     Book
+	zip      *zip.Writer
+	manifest []manifestItem
+	images   map[string]string
+}
+
+// Write writes the book as EPUB 3 file into w.
+func Write(w io.Writer, book Book) error {
+	if len(book.Chapters) == 0 {
+		return fmt.Errorf("book doesn't have any chapter")
+	}
+
+	bw := &bookWriter{
+		book:   book,
+		zip:    zip.NewWriter(w),
+		images: make(map[string]string),
+	}
+
+	bw.fillMetadata()
+	if err := bw.writeBook(); err != nil {
+		return err
+	}
+
+	return bw.zip.Close()
+}
+
+// fillMetadata fills the empty metadata of the book.
+func (bw *bookWriter) fillMetadata() {
+	book := &bw.book
+	first := book.Chapters[0]
+
+	if book.Title == "" {
+		book.Title = first.Title
+	}
+
+	if book.Author == "" {
+		book.Author = first.Byline
+	}
+
+	if book.Language == "" {
+		book.Language = first.Language
+	}
+
+	if book.Language == "" {
+		book.Language = "en"
+	}
+
+	if book.Modified.IsZero() {
+		book.Modified = time.Now()
+	}
+
+	if book.Identifier == "" {
+		hash := sha1.New()
+		for _, chapter := range book.Chapters {
+			io.WriteString(hash, chapter.Title)
+			io.WriteString(hash, chapter.Content)
+		}
+
+		// Use the hash as version 5 UUID
+		sum := hash.Sum(nil)
+		sum[6] = (sum[6] & 0x0f) | 0x50
+		sum[8] = (sum[8] & 0x3f) | 0x80
+		book.Identifier = fmt.Sprintf("urn:uuid:%x-%x-%x-%x-%x",
+			sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16])
+	}
+}
+
+// writeBook writes all files of the book into the zip archive.
+func (bw *bookWriter) writeBook() error {
+	// The mimetype must be the first file and it must not be compressed.
+	mimeWriter, err := bw.zip.CreateHeader(&zip.FileHeader{
+		Name:   "mimetype",
+		Method: zip.Store,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write mimetype: %v", err)
+	}
+	io.WriteString(mimeWriter, "application/epub+zip")
+
+	if err = bw.writeFile("META-INF/container.xml", []byte(containerXML)); err != nil {
+		return err
+	}
+
+	// Write cover
+	if bw.book.Cover != nil {
+		href, err := bw.writeImage("cover", bw.book.Cover)
+		if err != nil {
+			return err
+		}
+		bw.manifest[len(bw.manifest)-1].properties = "cover-image"
+
+		cover := fmt.Sprintf(`<div class="cover"><img src="%s" alt="%s"/></div>`,
+			xmlEscape(href), xmlEscape(bw.book.Title))
+		if err = bw.writeXHTML("cover.xhtml", "cover", bw.book.Title, cover, ""); err != nil {
+			return err
+		}
+	}
+
+	// Write chapters
+	var navItems strings.Builder
+	for i, chapter := range bw.book.Chapters {
+		name := fmt.Sprintf("chapter-%03d.xhtml", i+1)
+		id := fmt.Sprintf("chapter-%03d", i+1)
+
+		title := chapter.Title
+		if title == "" {
+			title = fmt.Sprintf("Chapter %d", i+1)
+		}
+
+		body, properties, err := bw.chapterBody(chapter)
+		if err != nil {
+			return err
+		}
+
+		content := "<h1>" + xmlEscape(title) + "</h1>\n" + body
+		if err = bw.writeXHTML(name, id, title, content, properties); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(&navItems, "<li><a href=\"%s\">%s</a></li>\n", name, xmlEscape(title))
+	}
+
+	// Write navigation document
+	nav := "<nav epub:type=\"toc\" id=\"toc\">\n<h1>Contents</h1>\n<ol>\n" + navItems.String() + "</ol>\n</nav>"
+	if err = bw.writeXHTML("nav.xhtml", "nav", "Contents", nav, "nav"); err != nil {
+		return err
+	}
+
+	return bw.writePackage()
+}
+
+// chapterBody converts the article content into XHTML. It also returns
+// the manifest properties of the chapter, which must declare the inline
+// SVG and MathML inside it.
+func (bw *bookWriter) chapterBody(chapter readability.Article) (string, string, error) {
+	context := &html.Node{Type: html.ElementNode, Data: "div", DataAtom: atom.Div}
+	nodes, err := html.ParseFragment(strings.NewReader(chapter.Content), context)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to parse chapter %q: %v", chapter.Title, err)
+	}
+
+	root := dom.CreateElement("div")
+	for _, node := range nodes {
+		dom.AppendChild(root, node)
+	}
+
+	// Embed the images, or replace it with its alt text
+	for _, img := range dom.GetElementsByTagName(root, "img") {
+		src := dom.GetAttribute(img, "src")
+		if href, err := bw.embedImage(src); err == nil && href != "" {
+			dom.SetAttribute(img, "src", href)
+			dom.RemoveAttribute(img, "srcset")
+			if !dom.HasAttribute(img, "alt") {
+				dom.SetAttribute(img, "alt", "")
+			}
+			continue
+		}
+
+		alt := strings.TrimSpace(dom.GetAttribute(img, "alt"))
+		if alt == "" {
+			img.Parent.RemoveChild(img)
+			continue
+		}
+		dom.ReplaceChild(img.Parent, dom.CreateTextNode("["+alt+"]"), img)
+	}
+
+	// Picture's sources point to remote images as well
+	for _, source := range dom.QuerySelectorAll(root, "picture > source") {
+		source.Parent.RemoveChild(source)
+	}
+
+	// Audio and video are not embedded since they may be huge, so they are
+	// replaced with the link to their source
+	for _, media := range dom.QuerySelectorAll(root, "video, audio") {
+		src := dom.GetAttribute(media, "src")
+		if source := dom.QuerySelector(media, "source[src]"); src == "" && source != nil {
+			src = dom.GetAttribute(source, "src")
+		}
+
+		if src == "" {
+			media.Parent.RemoveChild(media)
+			continue
+		}
+
+		label := "[Video]"
+		if dom.TagName(media) == "audio" {
+			label = "[Audio]"
+		}
+
+		link := dom.CreateElement("a")
+		dom.SetAttribute(link, "href", src)
+		dom.AppendChild(link, dom.CreateTextNode(label))
+		dom.ReplaceChild(media.Parent, link, media)
+	}
+
+	var buf bytes.Buffer
+	for child := root.FirstChild; child != nil; child = child.NextSibling {
+		renderXHTML(&buf, child)
+	}
+
+	var properties []string
+	if len(dom.GetElementsByTagName(root, "math")) > 0 {
+		properties = append(properties, "mathml")
+	}
+	if len(dom.GetElementsByTagName(root, "svg")) > 0 {
+		properties = append(properties, "svg")
+	}
+	return buf.String(), strings.Join(properties, " "), nil
+}
+
+// embedImage loads image from src and writes it into the book. Returns
+// the path of the image inside the book.
+func (bw *bookWriter) embedImage(src string) (string, error) {
+	if href, exist := bw.images[src]; exist {
+		return href, nil
+	}
+
+	if src == "" || bw.book.LoadImage == nil {
+		return "", nil
+	}
+
+	img, err := bw.book.LoadImage(src)
+	if err != nil || img == nil {
+		return "", err
+	}
+
+	href, err := bw.writeImage(fmt.Sprintf("image-%03d", len(bw.images)+1), img)
+	if err != nil {
+		return "", err
+	}
+
+	bw.images[src] = href
+	return href, nil
+}
+
+// writeImage writes image into the book and registers it in manifest.
+func (bw *bookWriter) writeImage(id string, img *Image) (string, error) {
+	ext, supported := imageExtensions[img.MediaType]
+	if !supported {
+		return "", fmt.Errorf("image type %q is not supported", img.MediaType)
+	}
+
+	href := "images/" + id + ext
+	if err := bw.writeFile(path.Join("OEBPS", href), img.Data); err != nil {
+		return "", err
+	}
+
+	bw.manifest = append(bw.manifest, manifestItem{
+		id:        id,
+		href:      href,
+		mediaType: img.MediaType,
+	})
+	return href, nil
+}
+
+// writeXHTML writes an XHTML content document and registers it in manifest.
+func (bw *bookWriter) writeXHTML(name, id, title, body, properties string) error {
+	content := fmt.Sprintf(xhtmlTemplate, xmlEscape(bw.book.Language), xmlEscape(title), body)
+	if err := bw.writeFile(path.Join("OEBPS", name), []byte(content)); err != nil {
+		return err
+	}
+
+	bw.manifest = append(bw.manifest, manifestItem{
+		id:         id,
+		href:       name,
+		mediaType:  "application/xhtml+xml",
+		properties: properties,
+	})
+	return nil
+}
+
+// writePackage writes the package document of the book.
+func (bw *bookWriter) writePackage() error {
+	book := bw.book
+
+	var metadata strings.Builder
+	fmt.Fprintf(&metadata, "<dc:identifier id=\"book-id\">%s</dc:identifier>\n", xmlEscape(book.Identifier))
+	fmt.Fprintf(&metadata, "<dc:title>%s</dc:title>\n", xmlEscape(book.Title))
+	fmt.Fprintf(&metadata, "<dc:language>%s</dc:language>\n", xmlEscape(book.Language))
+	if book.Author != "" {
+		fmt.Fprintf(&metadata, "<dc:creator>%s</dc:creator>\n", xmlEscape(book.Author))
+	}
+	fmt.Fprintf(&metadata, "<meta property=\"dcterms:modified\">%s</meta>\n",
+		book.Modified.UTC().Format("2006-01-02T15:04:05Z"))
+	if book.Cover != nil {
+		metadata.WriteString("<meta name=\"cover\" content=\"cover\"/>\n")
+	}
+
+	var manifest, spine strings.Builder
+	for _, item := range bw.manifest {
+		fmt.Fprintf(&manifest, "<item id=\"%s\" href=\"%s\" media-type=\"%s\"", item.id, item.href, item.mediaType)
+		if item.properties != "" {
+			fmt.Fprintf(&manifest, " properties=\"%s\"", item.properties)
+		}
+		manifest.WriteString("/>\n")
+
+		if item.mediaType == "application/xhtml+xml" && item.id != "nav" {
+			fmt.Fprintf(&spine, "<itemref idref=\"%s\"/>\n", item.id)
+		}
+	}
+
+	content := fmt.Sprintf(packageTemplate, metadata.String(), manifest.String(), spine.String())
+	return bw.writeFile("OEBPS/content.opf", []byte(content))
+}
+
+// writeFile writes a compressed file into the zip archive.
+func (bw *bookWriter) writeFile(name string, content []byte) error {
+	fileWriter, err := bw.zip.Create(name)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %v", name, err)
+	}
+
+	if _, err = fileWriter.Write(content); err != nil {
+		return fmt.Errorf("failed to write %s: %v", name, err)
+	}
+
+	return nil
+}
+
+// xmlEscape escapes s so it can be used as XML text or attribute value.
+// Characters that are not allowed in XML are removed.
+func xmlEscape(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if r == '\t' || r == '\n' || r == '\r' || (r >= 0x20 && r != 0xFFFE && r != 0xFFFF) {
+			return r
+		}
+		return -1
+	}, s)
+	return xmlReplacer.Replace(s)
+}
+
+var xmlReplacer = strings.NewReplacer(
+	"&", "&amp;",
+	"<", "&lt;",
+	">", "&gt;",
+	`"`, "&quot;",
+	"'", "&apos;")
+
+var imageExtensions = map[string]string{
+	"image/gif":     ".gif",
+	"image/jpeg":    ".jpg",
+	"image/png":     ".png",
+	"image/svg+xml": ".svg",
+	"image/webp":    ".webp",
+}
+
+const containerXML = `<?xml version="1.0" encoding="UTF-8"?>
+<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">
+<rootfiles>
+<rootfile full-path="OEBPS/content.opf" media-type="application/oebps-package+xml"/>
+</rootfiles>
+</container>
+`
+
+const packageTemplate = `<?xml version="1.0" encoding="UTF-8"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="3.0" unique-identifier="book-id">
+<metadata xmlns:dc="http://purl.org/dc/elements/1.1/">
+%s</metadata>
+<manifest>
+%s</manifest>
+<spine>
+%s</spine>
+</package>
+`
+
+const xhtmlTemplate = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE html>
+<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops" xml:lang="%s">
+<head>
+<meta charset="UTF-8"/>
+<title>%s</title>
+</head>
+<body>
+%s
+</body>
+</html>
+`
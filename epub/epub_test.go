@@ -0,0 +1,161 @@
+package epub
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	readability "github.com/go-shiori/go-readability"
+)
+
+func Test_Write(t *testing.T) {
+	book := Book{
+		Cover: &Image{MediaType: "image/png", Data: []byte("fake png")},
+		Chapters: []readability.Article{{
+			Title:    "First Article",
+			Byline:   "John Doe",
+			Language: "en",
+			Content:  `<div id="readability-page-1" class="page"><p>Hello<br>world &amp; friends</p><img src="http://example.com/a.png" alt="An image"><img src="http://example.com/b.png"></div>`,
+		}, {
+			Title: "Second Article",
+			Content: `<p>Second <b>chapter</b></p><script>alert(1)</script>` +
+				`<video poster="http://example.com/poster.png"><source src="http://example.com/v.mp4"></video>` +
+				`<audio src="http://example.com/a.mp3"></audio><video></video>`,
+		}, {
+			Title: "Third Article",
+			Content: `<p>Chart <svg viewBox="0 0 10 10" preserveAspectRatio="none"><foreignObject width="10" height="10">` +
+				`<DIV Class="label">x</DIV></foreignObject></svg> and <math><mi>x</mi></math></p>`,
+		}},
+		LoadImage: func(src string) (*Image, error) {
+			if strings.HasSuffix(src, "a.png") {
+				return &Image{MediaType: "image/png", Data: []byte("a")}, nil
+			}
+			return nil, nil
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, book); err != nil {
+		t.Fatalf("failed to write book: %v", err)
+	}
+
+	reader, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("failed to open book: %v", err)
+	}
+
+	// The mimetype must be the first, uncompressed file
+	if first := reader.File[0]; first.Name != "mimetype" || first.Method != zip.Store {
+		t.Errorf("first file must be uncompressed mimetype, got %q", first.Name)
+	}
+
+	files := make(map[string]string)
+	for _, f := range reader.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("failed to open %s: %v", f.Name, err)
+		}
+		content, _ := io.ReadAll(rc)
+		rc.Close()
+		files[f.Name] = string(content)
+	}
+
+	expectedFiles := []string{
+		"META-INF/container.xml",
+		"OEBPS/content.opf",
+		"OEBPS/nav.xhtml",
+		"OEBPS/cover.xhtml",
+		"OEBPS/images/cover.png",
+		"OEBPS/images/image-001.png",
+		"OEBPS/chapter-001.xhtml",
+		"OEBPS/chapter-002.xhtml",
+		"OEBPS/chapter-003.xhtml",
+	}
+
+	for _, name := range expectedFiles {
+		content, exist := files[name]
+		if !exist {
+			t.Errorf("file %s doesn't exist", name)
+			continue
+		}
+
+		// Make sure all XML files are well-formed
+		if strings.HasSuffix(name, ".xml") || strings.HasSuffix(name, ".opf") || strings.HasSuffix(name, ".xhtml") {
+			decoder := xml.NewDecoder(strings.NewReader(content))
+			decoder.Strict = true
+			for {
+				if _, err := decoder.Token(); err == io.EOF {
+					break
+				} else if err != nil {
+					t.Errorf("%s is not well-formed: %v", name, err)
+					break
+				}
+			}
+		}
+	}
+
+	opf := files["OEBPS/content.opf"]
+	for _, expected := range []string{
+		"<dc:title>First Article</dc:title>",
+		"<dc:creator>John Doe</dc:creator>",
+		`properties="cover-image"`,
+		`<itemref idref="chapter-002"/>`,
+	} {
+		if !strings.Contains(opf, expected) {
+			t.Errorf("package document doesn't contain %q", expected)
+		}
+	}
+
+	chapter := files["OEBPS/chapter-001.xhtml"]
+	if !strings.Contains(chapter, `<img src="images/image-001.png" alt="An image"/>`) {
+		t.Errorf("loaded image is not embedded:\n%s", chapter)
+	}
+
+	if strings.Contains(chapter, "b.png") {
+		t.Errorf("remote image should be removed:\n%s", chapter)
+	}
+
+	if strings.Contains(files["OEBPS/chapter-002.xhtml"], "alert") {
+		t.Errorf("script should be removed")
+	}
+
+	// Remote audio and video are linked instead of embedded
+	for _, expected := range []string{
+		`<a href="http://example.com/v.mp4">[Video]</a>`,
+		`<a href="http://example.com/a.mp3">[Audio]</a>`,
+	} {
+		if !strings.Contains(files["OEBPS/chapter-002.xhtml"], expected) {
+			t.Errorf("chapter doesn't contain %q:\n%s", expected, files["OEBPS/chapter-002.xhtml"])
+		}
+	}
+
+	for _, unexpected := range []string{"<video", "<audio", "<source", "poster.png"} {
+		if strings.Contains(files["OEBPS/chapter-002.xhtml"], unexpected) {
+			t.Errorf("remote media should be removed:\n%s", files["OEBPS/chapter-002.xhtml"])
+		}
+	}
+
+	// SVG and MathML names are case sensitive, and their chapter must be
+	// declared in manifest
+	for _, expected := range []string{
+		`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 10 10"`,
+		`preserveAspectRatio="none"`,
+		`<foreignObject width="10" height="10"><div xmlns="http://www.w3.org/1999/xhtml" class="label">x</div></foreignObject>`,
+		`<math xmlns="http://www.w3.org/1998/Math/MathML"><mi>x</mi></math>`,
+	} {
+		if !strings.Contains(files["OEBPS/chapter-003.xhtml"], expected) {
+			t.Errorf("chapter doesn't contain %q:\n%s", expected, files["OEBPS/chapter-003.xhtml"])
+		}
+	}
+
+	if !strings.Contains(opf, `href="chapter-003.xhtml" media-type="application/xhtml+xml" properties="mathml svg"`) {
+		t.Errorf("chapter with SVG and MathML must have properties:\n%s", opf)
+	}
+
+	if strings.Contains(opf, `href="chapter-001.xhtml" media-type="application/xhtml+xml" properties`) {
+		t.Errorf("chapter without SVG and MathML must not have properties:\n%s", opf)
+	}
+}
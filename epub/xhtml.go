@@ -0,0 +1,100 @@
+package epub
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+
+	"github.com/go-shiori/dom"
+	"golang.org/x/net/html"
+)
+
+var rxXMLName = regexp.MustCompile(`^[A-Za-z_][-A-Za-z0-9_.]*$`)
+
+// xmlNamespaces is the XML namespace of elements, keyed by their namespace
+// in the HTML parser.
+var xmlNamespaces = map[string]string{
+	"":     "http://www.w3.org/1999/xhtml",
+	"svg":  "http://www.w3.org/2000/svg",
+	"math": "http://www.w3.org/1998/Math/MathML",
+}
+
+// skippedElems are elements that are not rendered into XHTML, including
+// their children.
+var skippedElems = map[string]struct{}{
+	"script":   {},
+	"style":    {},
+	"noscript": {},
+	"template": {},
+	"iframe":   {},
+	"object":   {},
+	"embed":    {},
+}
+
+// renderXHTML renders node as well-formed XHTML into buf. Unlike HTML,
+// void elements are self closed, and the element or attribute whose name
+// is not a valid XML name is dropped. The names are lowercased only for
+// HTML elements, since SVG and MathML names like viewBox are case
+// sensitive in XML. The namespace is declared wherever it changes, e.g.
+// on the svg element, or on the HTML element inside foreignObject.
+func renderXHTML(buf *bytes.Buffer, node *html.Node) {
+	switch node.Type {
+	case html.TextNode:
+		buf.WriteString(xmlEscape(node.Data))
+		return
+	case html.ElementNode:
+	default:
+		return
+	}
+
+	isHTML := node.Namespace == ""
+	tagName := node.Data
+	if isHTML {
+		tagName = strings.ToLower(tagName)
+	}
+
+	if _, skipped := skippedElems[tagName]; skipped {
+		return
+	}
+
+	// If tag name is not valid, just render its children
+	if !rxXMLName.MatchString(tagName) {
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			renderXHTML(buf, child)
+		}
+		return
+	}
+
+	buf.WriteString("<" + tagName)
+	if namespace, known := xmlNamespaces[node.Namespace]; known &&
+		node.Parent != nil && node.Parent.Namespace != node.Namespace {
+		buf.WriteString(` xmlns="` + namespace + `"`)
+	}
+
+	written := make(map[string]struct{})
+	for _, attr := range node.Attr {
+		key := attr.Key
+		if isHTML {
+			key = strings.ToLower(key)
+		}
+
+		if _, exist := written[key]; exist || attr.Namespace != "" ||
+			key == "xmlns" || !rxXMLName.MatchString(key) {
+			continue
+		}
+
+		written[key] = struct{}{}
+		buf.WriteString(" " + key + `="` + xmlEscape(attr.Val) + `"`)
+	}
+
+	if dom.IsVoidElement(node) {
+		buf.WriteString("/>")
+		return
+	}
+
+	buf.WriteString(">")
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		renderXHTML(buf, child)
+	}
+	buf.WriteString("</" + tagName + ">")
+}
@@ -0,0 +1,40 @@
+package readability
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrBodyTooLarge is returned by Fetcher when the size of the response
+	// body exceeds Fetcher.MaxBodyBytes.
+	ErrBodyTooLarge = errors.New("response body is too large")
+	// ErrTooManyElements is returned by Parser when the number of elements
+	// in the document exceeds Parser.MaxElemsToParse.
+	ErrTooManyElements = errors.New("document has too many elements")
+	// ErrTooDeep is returned by Parser when the elements in the document
+	// are nested deeper than Parser.MaxElementDepth.
+	ErrTooDeep = errors.New("document is nested too deeply")
+	// ErrParseTimeout is returned by Parser when the parse takes longer
+	// than Parser.MaxParseDuration.
+	ErrParseTimeout = errors.New("parse takes too long")
+	// ErrPanic is returned by Parser when it panics while parsing, e.g.
+	// because of malformed input, instead of crashing the process.
+	ErrPanic = errors.New("parser panicked")
+)
+
+// StatusError is returned by Fetcher when the server responds with status
+// that's not 2xx, including the retryable status once the retries are
+// exhausted.
+type StatusError struct {
+	// URL is the URL that responded with the status.
+	URL string
+	// StatusCode is the HTTP status code of response, e.g. 404.
+	StatusCode int
+	// Status is the HTTP status of response, e.g. "404 Not Found".
+	Status string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s responded with status %s", e.URL, e.Status)
+}
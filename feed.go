@@ -0,0 +1,54 @@
+package readability
+
+import (
+	"strings"
+
+	"github.com/go-shiori/dom"
+)
+
+// feedTypes is the MIME types of feeds, mapped to their short name.
+var feedTypes = map[string]string{
+	"application/rss+xml":   "rss",
+	"application/atom+xml":  "atom",
+	"application/feed+json": "json",
+}
+
+// Feed is the RSS, Atom or JSON feed of the site where the article is
+// published.
+type Feed struct {
+	URL   string `json:"url"`
+	Type  string `json:"type"`
+	Title string `json:"title,omitempty"`
+}
+
+// getFeeds returns the feeds that advertised in the page using
+// <link rel="alternate">, in document order.
+func (ps *Parser) getFeeds() []Feed {
+	var feeds []Feed
+	seen := make(map[string]struct{})
+	for _, link := range dom.QuerySelectorAll(ps.doc, `link[rel][href][type]`) {
+		if indexOf(strings.Fields(strings.ToLower(dom.GetAttribute(link, "rel"))), "alternate") < 0 {
+			continue
+		}
+
+		mimeType := strings.ToLower(strings.TrimSpace(dom.GetAttribute(link, "type")))
+		feedType, isFeed := feedTypes[mimeType]
+		href := strings.TrimSpace(dom.GetAttribute(link, "href"))
+		if !isFeed || href == "" {
+			continue
+		}
+
+		feedURL := toAbsoluteURI(href, ps.documentURI)
+		if _, exist := seen[feedURL]; exist {
+			continue
+		}
+
+		seen[feedURL] = struct{}{}
+		feeds = append(feeds, Feed{
+			URL:   feedURL,
+			Type:  feedType,
+			Title: strings.TrimSpace(dom.GetAttribute(link, "title")),
+		})
+	}
+	return feeds
+}
@@ -0,0 +1,36 @@
+package readability
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_Parser_Feeds(t *testing.T) {
+	paragraph := articleParagraph(10)
+	input := `<html><head>` +
+		`<link rel="alternate" type="application/rss+xml" title="RSS" href="/feed.xml">` +
+		`<link rel="alternate" type="application/atom+xml" href="https://fakehost/atom.xml">` +
+		`<link rel="alternate" type="application/feed+json" title="JSON" href="feed.json">` +
+		`<link rel="alternate" type="application/rss+xml" href="/feed.xml">` +
+		`<link rel="alternate" hreflang="id" href="/id/page.html">` +
+		`<link rel="stylesheet" type="text/css" href="/style.css">` +
+		`</head><body><article>` + paragraph + `</article></body></html>`
+
+	article, err := FromReader(strings.NewReader(input), fakeHostURL)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	expected := []Feed{
+		{URL: "http://fakehost/feed.xml", Type: "rss", Title: "RSS"},
+		{URL: "https://fakehost/atom.xml", Type: "atom"},
+		{URL: "http://fakehost/test/feed.json", Type: "json", Title: "JSON"},
+	}
+
+	if !reflect.DeepEqual(article.Feeds, expected) {
+		t.Errorf("\n"+
+			"want : %+v\n"+
+			"got  : %+v", expected, article.Feeds)
+	}
+}
@@ -0,0 +1,340 @@
+package readability
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	nurl "net/url"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+)
+
+// acceptEncoding is the content encodings that can be decoded by Fetcher.
+const acceptEncoding = "gzip, deflate, br"
+
+// Fetcher fetches a web page over HTTP then parses it to find the readable
+// content. The zero value is ready to use.
+type Fetcher struct {
+	// Client is the HTTP client that used to fetch the web page. Use it to
+	// set custom transport, proxy, timeout, etc. If nil, http.DefaultClient
+	// will be used.
+	Client *http.Client
+	// Parser is the parser that used to parse the fetched web page. If nil,
+	// a parser with default value will be used.
+	Parser *Parser
+	// UserAgent is the value of User-Agent header that sent to the server.
+	// If empty, the default user agent of Go HTTP client will be used.
+	UserAgent string
+	// Header is the additional headers that sent to the server, e.g.
+	// Accept-Language or Referer.
+	Header http.Header
+	// Jar is the cookie jar that used instead of the jar of Client, e.g.
+	// to keep the session or consent cookie. Default: nil (use the jar
+	// of Client, if any).
+	Jar http.CookieJar
+	// Cookies is the additional cookies that sent to the server.
+	Cookies []*http.Cookie
+	// MaxBodyBytes is the max size of the response body after it's decoded,
+	// which protects from huge page and decompression bomb. When the body
+	// exceeds this limit, ErrBodyTooLarge will be returned unless
+	// ParseTruncated is true. Default: 0 (no limit).
+	MaxBodyBytes int64
+	// ParseTruncated determines whether the body that exceeds MaxBodyBytes
+	// should be truncated and parsed instead of returning error.
+	ParseTruncated bool
+	// Retry is the policy for retrying the failed requests. Default: no retry.
+	Retry RetryPolicy
+	// Cache is used to store the fetched pages, so the page that not
+	// modified doesn't need to be downloaded again. Default: nil (no cache).
+	Cache Cache
+	// MaxPages is the max number of pages that fetched for a paginated
+	// article. The subsequent pages are found using Article.NextPageURL,
+	// then merged into the content of the first page. Default: 0 (only
+	// fetch the first page).
+	MaxPages int
+	// AMPFallbackLength is the min length of the article content. When the
+	// content is shorter than this and the page has AMP version, the AMP
+	// version will be fetched and used instead since it's usually much
+	// cleaner. Default: 0 (never use AMP version).
+	AMPFallbackLength int
+	// AMPFallbackConfidence is the min confidence of the article. When
+	// the article is extracted with lower confidence than this and the
+	// page has AMP version, the AMP version will be fetched and used
+	// instead if it's extracted with higher confidence. Default: 0 (never
+	// use AMP version).
+	AMPFallbackConfidence float64
+	// FollowSinglePage determines if the single page version of the
+	// article, which is specified by the site rule, should be fetched and
+	// used instead of the page itself. Default: false.
+	FollowSinglePage bool
+}
+
+// fetchedPage is the web page that fetched by Fetcher.
+type fetchedPage struct {
+	url  *nurl.URL
+	body []byte
+}
+
+// Fetch fetches the web page from specified url then parses the response
+// to find the readable content. When one of the subsequent pages can't be
+// fetched, the pages that already merged are returned along with the
+// error, and NextPageURL is kept pointing to the failed page.
+func (f *Fetcher) Fetch(ctx context.Context, pageURL string) (Article, error) {
+	article, pageURL, err := f.fetchArticle(ctx, pageURL)
+	if err != nil {
+		return Article{}, err
+	}
+
+	// Use the single page version if the article has one, otherwise use
+	// the AMP version if the extracted content is too short or unsure
+	if err = f.fetchSinglePage(ctx, &article); err != nil {
+		return Article{}, err
+	}
+
+	if err = f.fetchAMP(ctx, &article); err != nil {
+		return Article{}, err
+	}
+
+	// Fetch the rest of pages of the article
+	parser := f.parser()
+	visited := map[string]struct{}{pageURL: {}}
+	for number := 2; number <= f.MaxPages && article.NextPageURL != ""; number++ {
+		if _, seen := visited[article.NextPageURL]; seen {
+			break
+		}
+		visited[article.NextPageURL] = struct{}{}
+
+		next, _, err := f.fetchArticle(ctx, article.NextPageURL)
+		if err != nil {
+			return article, fmt.Errorf("failed to fetch page %d: %w", number, err)
+		}
+
+		if err = parser.appendPage(&article, next, number); err != nil {
+			return Article{}, err
+		}
+	}
+
+	return article, nil
+}
+
+// FetchPage fetches the web page from specified url without parsing it.
+// It returns the decoded body and the final URL of the page after
+// redirects, e.g. to check the page before parsing it.
+func (f *Fetcher) FetchPage(ctx context.Context, pageURL string) ([]byte, *nurl.URL, error) {
+	parsedURL, err := nurl.ParseRequestURI(pageURL)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse URL: %v", err)
+	}
+
+	page, err := f.fetch(ctx, parsedURL)
+	if err != nil {
+		return nil, nil, err
+	}
+	return page.body, page.url, nil
+}
+
+// fetchArticle fetches and parses a single web page. It also returns the
+// final URL of the page after redirects.
+func (f *Fetcher) fetchArticle(ctx context.Context, pageURL string) (Article, string, error) {
+	// Make sure URL is valid
+	parsedURL, err := nurl.ParseRequestURI(pageURL)
+	if err != nil {
+		return Article{}, "", fmt.Errorf("failed to parse URL: %v", err)
+	}
+
+	page, err := f.fetch(ctx, parsedURL)
+	if err != nil {
+		return Article{}, "", err
+	}
+
+	// Parse content
+	parser := f.parser()
+	article, err := parser.ParseWithContext(ctx, bytes.NewReader(page.body), page.url)
+	return article, page.url.String(), err
+}
+
+// fetch downloads the web page from pageURL and returns its decoded body.
+func (f *Fetcher) fetch(ctx context.Context, pageURL *nurl.URL) (*fetchedPage, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", pageURL.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+
+	// Set the custom headers
+	for key, values := range f.Header {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+
+	if f.UserAgent != "" {
+		req.Header.Set("User-Agent", f.UserAgent)
+	}
+
+	for _, cookie := range f.Cookies {
+		req.AddCookie(cookie)
+	}
+
+	// Set Accept-Encoding header to indicate the encodings we can decode
+	if req.Header.Get("Accept-Encoding") == "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+
+	// If the page is cached, only download it if it's modified
+	cacheKey := pageURL.String()
+	var cached *CacheEntry
+	if f.Cache != nil {
+		if entry, exist := f.Cache.Get(cacheKey); exist && entry != nil {
+			cached = entry
+			if entry.ETag != "" {
+				req.Header.Set("If-None-Match", entry.ETag)
+			}
+			if entry.LastModified != "" {
+				req.Header.Set("If-Modified-Since", entry.LastModified)
+			}
+		}
+	}
+
+	resp, err := f.Retry.do(ctx, f.client(), req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch the page: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Use the final URL after redirects, so the relative links are resolved
+	// against the page where the content actually is.
+	finalURL := pageURL
+	if resp.Request != nil && resp.Request.URL != nil {
+		finalURL = resp.Request.URL
+	}
+
+	if cached != nil && resp.StatusCode == http.StatusNotModified {
+		return &fetchedPage{url: finalURL, body: cached.Body}, nil
+	}
+
+	// The error page is not the article, even when it's HTML
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, &StatusError{URL: finalURL.String(), StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+
+	// Decode the content based on its encoding
+	reader, err := decodeContent(resp.Body, resp.Header.Get("Content-Encoding"))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	// Make sure content type is HTML
+	cp := resp.Header.Get("Content-Type")
+	if !strings.Contains(cp, "text/html") {
+		return nil, fmt.Errorf("URL is not a HTML document")
+	}
+
+	body, err := f.readBody(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	// Save the page to cache
+	etag := resp.Header.Get("ETag")
+	lastModified := resp.Header.Get("Last-Modified")
+	if f.Cache != nil && resp.StatusCode == http.StatusOK && (etag != "" || lastModified != "") {
+		f.Cache.Set(cacheKey, &CacheEntry{
+			ETag:         etag,
+			LastModified: lastModified,
+			Body:         body,
+		})
+	}
+
+	return &fetchedPage{url: finalURL, body: body}, nil
+}
+
+// readBody reads the decoded response body while respecting MaxBodyBytes.
+func (f *Fetcher) readBody(reader io.Reader) ([]byte, error) {
+	if f.MaxBodyBytes > 0 {
+		reader = io.LimitReader(reader, f.MaxBodyBytes+1)
+	}
+
+	body, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the page: %w", err)
+	}
+
+	if f.MaxBodyBytes > 0 && int64(len(body)) > f.MaxBodyBytes {
+		if !f.ParseTruncated {
+			return nil, fmt.Errorf("%w: exceeds %d bytes", ErrBodyTooLarge, f.MaxBodyBytes)
+		}
+		body = body[:f.MaxBodyBytes]
+	}
+
+	return body, nil
+}
+
+// decodeContent returns reader that decodes body which encoded using the
+// specified content encoding. If there are several encodings, they are
+// decoded in the reverse order they were applied.
+func decodeContent(body io.Reader, contentEncoding string) (io.ReadCloser, error) {
+	reader := io.NopCloser(body)
+	encodings := strings.Split(contentEncoding, ",")
+	for i := len(encodings) - 1; i >= 0; i-- {
+		var err error
+		switch encoding := strings.ToLower(strings.TrimSpace(encodings[i])); encoding {
+		case "gzip", "x-gzip":
+			reader, err = gzip.NewReader(reader)
+		case "deflate":
+			// Deflate should be wrapped in zlib format, however some
+			// servers send the raw deflate stream instead.
+			buffered := bufio.NewReader(reader)
+			if header, _ := buffered.Peek(2); len(header) == 2 && header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0 {
+				reader, err = zlib.NewReader(buffered)
+			} else {
+				reader = flate.NewReader(buffered)
+			}
+		case "br":
+			reader = io.NopCloser(brotli.NewReader(reader))
+		case "", "identity":
+		default:
+			err = fmt.Errorf("content encoding %q is not supported", encoding)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode content: %v", err)
+		}
+	}
+
+	return reader, nil
+}
+
+// client returns the HTTP client that used by fetcher. If fetcher has
+// its own cookie jar, a copy of the client is returned so the original
+// client is not modified.
+func (f *Fetcher) client() *http.Client {
+	client := f.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	if f.Jar != nil {
+		clientWithJar := *client
+		clientWithJar.Jar = f.Jar
+		client = &clientWithJar
+	}
+
+	return client
+}
+
+// parser returns a new copy of the parser that used by fetcher, so
+// the same fetcher can be used to fetch several pages at once.
+func (f *Fetcher) parser() Parser {
+	if f.Parser != nil {
+		return *f.Parser
+	}
+	return NewParser()
+}
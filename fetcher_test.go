@@ -0,0 +1,550 @@
+package readability
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	nurl "net/url"
+	"os"
+	fp "path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andybalholm/brotli"
+)
+
+// countingTransport is a RoundTripper that counts how many requests
+// passed through it.
+type countingTransport struct {
+	count int
+}
+
+func (ct *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ct.count++
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func newTestPageServer(t *testing.T, testName string) *httptest.Server {
+	source, err := os.ReadFile(fp.Join("test-pages", testName, "source.html"))
+	if err != nil {
+		t.Fatalf("failed to read source: %v", err)
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write(source)
+	}))
+}
+
+func Test_Fetcher_Client(t *testing.T) {
+	server := newTestPageServer(t, "001")
+	defer server.Close()
+
+	transport := &countingTransport{}
+	fetcher := Fetcher{Client: &http.Client{Transport: transport}}
+
+	article, err := fetcher.Fetch(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("failed to fetch: %v", err)
+	}
+
+	if article.Title == "" {
+		t.Errorf("title should not be empty")
+	}
+
+	if transport.count != 1 {
+		t.Errorf("requests through custom transport, want %d got %d", 1, transport.count)
+	}
+}
+
+func Test_Fetcher_ContentEncoding(t *testing.T) {
+	source, err := os.ReadFile(fp.Join("test-pages", "001", "source.html"))
+	if err != nil {
+		t.Fatalf("failed to read source: %v", err)
+	}
+
+	encoders := map[string]func(io.Writer) io.WriteCloser{
+		"gzip":    func(w io.Writer) io.WriteCloser { return gzip.NewWriter(w) },
+		"deflate": func(w io.Writer) io.WriteCloser { return zlib.NewWriter(w) },
+		"br":      func(w io.Writer) io.WriteCloser { return brotli.NewWriter(w) },
+		"deflate, gzip": func(w io.Writer) io.WriteCloser {
+			gz := gzip.NewWriter(w)
+			fl, _ := flate.NewWriter(gz, flate.DefaultCompression)
+			return multiCloser{fl, gz}
+		},
+	}
+
+	for encoding, newEncoder := range encoders {
+		var buf bytes.Buffer
+		encoder := newEncoder(&buf)
+		encoder.Write(source)
+		encoder.Close()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/html")
+			w.Header().Set("Content-Encoding", encoding)
+			w.Write(buf.Bytes())
+		}))
+
+		fetcher := Fetcher{}
+		article, err := fetcher.Fetch(context.Background(), server.URL)
+		server.Close()
+
+		if err != nil {
+			t.Errorf("%s: failed to fetch: %v", encoding, err)
+		} else if article.Title == "" {
+			t.Errorf("%s: title should not be empty", encoding)
+		}
+	}
+}
+
+// multiCloser is a writer that closes several writers in order.
+type multiCloser []io.WriteCloser
+
+func (mc multiCloser) Write(p []byte) (int, error) {
+	return mc[0].Write(p)
+}
+
+func (mc multiCloser) Close() error {
+	for _, c := range mc {
+		if err := c.Close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func Test_Fetcher_MaxBodyBytes(t *testing.T) {
+	// Gzip bomb: small compressed body which decoded into huge page
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	gz.Write([]byte("<html><body><p>"))
+	gz.Write(bytes.Repeat([]byte("lorem ipsum "), 1<<20))
+	gz.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Write(buf.Bytes())
+	}))
+	defer server.Close()
+
+	fetcher := Fetcher{MaxBodyBytes: 1 << 16}
+	_, err := fetcher.Fetch(context.Background(), server.URL)
+	if !errors.Is(err, ErrBodyTooLarge) {
+		t.Errorf("error, want %v got %v", ErrBodyTooLarge, err)
+	}
+
+	fetcher.ParseTruncated = true
+	article, err := fetcher.Fetch(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("failed to parse truncated page: %v", err)
+	}
+
+	if article.Length == 0 || article.Length > 1<<16 {
+		t.Errorf("length of truncated article should be within limit, got %d", article.Length)
+	}
+}
+
+func Test_Fetcher_Retry(t *testing.T) {
+	source, err := os.ReadFile(fp.Join("test-pages", "001", "source.html"))
+	if err != nil {
+		t.Fatalf("failed to read source: %v", err)
+	}
+
+	nRequests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		nRequests++
+		switch nRequests {
+		case 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case 2:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			w.Header().Set("Content-Type", "text/html")
+			w.Write(source)
+		}
+	}))
+	defer server.Close()
+
+	fetcher := Fetcher{Retry: RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond}}
+	article, err := fetcher.Fetch(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("failed to fetch: %v", err)
+	}
+
+	if nRequests != 3 {
+		t.Errorf("number of requests, want %d got %d", 3, nRequests)
+	}
+
+	if article.Title == "" {
+		t.Errorf("title should not be empty")
+	}
+}
+
+func Test_Fetcher_StatusError(t *testing.T) {
+	source, err := os.ReadFile(fp.Join("test-pages", "001", "source.html"))
+	if err != nil {
+		t.Fatalf("failed to read source: %v", err)
+	}
+
+	nRequests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		nRequests++
+		w.Header().Set("Content-Type", "text/html")
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		w.Write(source)
+	}))
+	defer server.Close()
+
+	// The error page is never parsed, even once the retries are exhausted
+	fetcher := Fetcher{Retry: RetryPolicy{MaxAttempts: 2, MinBackoff: time.Millisecond}}
+	scenarios := map[string]int{
+		"/missing": http.StatusNotFound,
+		"/broken":  http.StatusInternalServerError,
+	}
+
+	for path, expected := range scenarios {
+		nRequests = 0
+		_, err := fetcher.Fetch(context.Background(), server.URL+path)
+
+		var statusErr *StatusError
+		if !errors.As(err, &statusErr) || statusErr.StatusCode != expected || statusErr.URL != server.URL+path {
+			t.Errorf("%s: want status error %d, got %v", path, expected, err)
+		}
+
+		// Only the retryable status is retried
+		expectedRequests := 1
+		if expected == http.StatusInternalServerError {
+			expectedRequests = 2
+		}
+
+		if nRequests != expectedRequests {
+			t.Errorf("%s: number of requests, want %d got %d", path, expectedRequests, nRequests)
+		}
+	}
+}
+
+func Test_Fetcher_Cache(t *testing.T) {
+	source, err := os.ReadFile(fp.Join("test-pages", "001", "source.html"))
+	if err != nil {
+		t.Fatalf("failed to read source: %v", err)
+	}
+
+	nNotModified := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			nNotModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/html")
+		w.Header().Set("ETag", `"v1"`)
+		w.Write(source)
+	}))
+	defer server.Close()
+
+	fetcher := Fetcher{Cache: NewMemoryCache()}
+	first, err := fetcher.Fetch(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("failed to fetch: %v", err)
+	}
+
+	second, err := fetcher.Fetch(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("failed to fetch from cache: %v", err)
+	}
+
+	if nNotModified != 1 {
+		t.Errorf("number of not modified response, want %d got %d", 1, nNotModified)
+	}
+
+	if first.Content != second.Content {
+		t.Errorf("cached content is different")
+	}
+}
+
+func Test_Fetcher_Header(t *testing.T) {
+	source, err := os.ReadFile(fp.Join("test-pages", "001", "source.html"))
+	if err != nil {
+		t.Fatalf("failed to read source: %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("User-Agent") != "test-agent" || r.Header.Get("Accept-Language") != "en-US" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/html")
+		w.Write(source)
+	}))
+	defer server.Close()
+
+	fetcher := Fetcher{
+		UserAgent: "test-agent",
+		Header:    http.Header{"Accept-Language": {"en-US"}},
+	}
+
+	if _, err := fetcher.Fetch(context.Background(), server.URL); err != nil {
+		t.Errorf("failed to fetch: %v", err)
+	}
+}
+
+func Test_Fetcher_Cookies(t *testing.T) {
+	source, err := os.ReadFile(fp.Join("test-pages", "001", "source.html"))
+	if err != nil {
+		t.Fatalf("failed to read source: %v", err)
+	}
+
+	// The server requires consent cookie, and set session cookie
+	// by redirecting the first request.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := r.Cookie("consent"); err != nil {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		if _, err := r.Cookie("session"); err != nil {
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc"})
+			http.Redirect(w, r, r.URL.String(), http.StatusFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/html")
+		w.Write(source)
+	}))
+	defer server.Close()
+
+	jar, _ := cookiejar.New(nil)
+	fetcher := Fetcher{
+		Jar:     jar,
+		Cookies: []*http.Cookie{{Name: "consent", Value: "yes"}},
+	}
+
+	if _, err := fetcher.Fetch(context.Background(), server.URL); err != nil {
+		t.Errorf("failed to fetch: %v", err)
+	}
+
+	if http.DefaultClient.Jar != nil {
+		t.Errorf("default client should not be modified")
+	}
+}
+
+func Test_Fetcher_MaxPages(t *testing.T) {
+	paragraph := strings.Repeat("This is a sentence of the paginated article, long enough to be scored. ", 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := pageNumber(r.URL)
+		if page == 4 {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		next := fmt.Sprintf(`<link rel="next" href="/article?page=%d">`, page+1)
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprintf(w, `<html><head><title>Paginated</title>%s</head><body><article>`+
+			`<p>Content of page %d. %s</p><p>%s</p></article></body></html>`, next, page, paragraph, paragraph)
+	}))
+	defer server.Close()
+
+	fetcher := Fetcher{MaxPages: 2}
+	article, err := fetcher.Fetch(context.Background(), server.URL+"/article")
+	if err != nil {
+		t.Fatalf("failed to fetch: %v", err)
+	}
+
+	for _, expected := range []string{"Content of page 1.", "Content of page 2.", `id="readability-page-2"`} {
+		if !strings.Contains(article.Content, expected) {
+			t.Errorf("content should contain %q", expected)
+		}
+	}
+
+	if strings.Contains(article.Content, "Content of page 3.") {
+		t.Errorf("content should not contain page 3 which exceeds max pages")
+	}
+
+	if expected := server.URL + "/article?page=3"; article.NextPageURL != expected {
+		t.Errorf("next page URL, want %q got %q", expected, article.NextPageURL)
+	}
+
+	if !strings.Contains(article.Markdown(), "Content of page 2.") {
+		t.Errorf("markdown should contain all pages")
+	}
+
+	// The pages that already merged are kept when the next one fails
+	fetcher = Fetcher{MaxPages: 5}
+	article, err = fetcher.Fetch(context.Background(), server.URL+"/article")
+
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusNotFound {
+		t.Errorf("want status error of page 4, got %v", err)
+	}
+
+	if !strings.Contains(article.Content, "Content of page 3.") {
+		t.Errorf("content of merged pages should be kept: %s", article.Content)
+	}
+
+	if expected := server.URL + "/article?page=4"; article.NextPageURL != expected {
+		t.Errorf("next page URL, want %q got %q", expected, article.NextPageURL)
+	}
+}
+
+func Test_Fetcher_AMPFallbackLength(t *testing.T) {
+	paragraph := strings.Repeat("This is a sentence of the AMP article, long enough to be scored. ", 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		if r.URL.Path == "/amp" {
+			fmt.Fprintf(w, `<html><head><title>AMP</title></head><body><article>`+
+				`<p>Full content. %s</p><p>%s</p></article></body></html>`, paragraph, paragraph)
+			return
+		}
+
+		fmt.Fprint(w, `<html><head><title>Canonical</title><link rel="amphtml" href="/amp"></head>`+
+			`<body><article><p>Please enable JavaScript to read this article.</p></article></body></html>`)
+	}))
+	defer server.Close()
+
+	scenarios := map[string]struct {
+		fetcher Fetcher
+		usesAMP bool
+	}{
+		"disabled":   {Fetcher{}, false},
+		"length":     {Fetcher{AMPFallbackLength: 500}, true},
+		"confidence": {Fetcher{AMPFallbackConfidence: 0.99}, true},
+	}
+
+	for name, scenario := range scenarios {
+		article, err := scenario.fetcher.Fetch(context.Background(), server.URL+"/article")
+		if err != nil {
+			t.Fatalf("failed to fetch: %v", err)
+		}
+
+		if article.AMPURL != server.URL+"/amp" {
+			t.Errorf("AMP URL, want %q got %q", server.URL+"/amp", article.AMPURL)
+		}
+
+		if article.Title != "Canonical" {
+			t.Errorf("metadata should be taken from canonical page, got title %q", article.Title)
+		}
+
+		usesAMP := strings.Contains(article.TextContent, "Full content.")
+		if usesAMP != scenario.usesAMP {
+			t.Errorf("%s: want AMP content %v got %v", name, scenario.usesAMP, usesAMP)
+		}
+		checkDerivedFields(t, article)
+	}
+}
+
+// checkDerivedFields makes sure the fields that derived from the content
+// of article describe its current content.
+func checkDerivedFields(t *testing.T, article Article) {
+	t.Helper()
+
+	parser := NewParser()
+	text := article.TextContent
+	if article.WordCount != articleWordCount(text) || article.CharCount != nonSpaceCharCount(text) {
+		t.Errorf("counts don't match content, got %d words and %d chars", article.WordCount, article.CharCount)
+	}
+
+	if article.ReadingTime != parser.estimateReadingTime(text) {
+		t.Errorf("reading time doesn't match content, got %v", article.ReadingTime)
+	}
+
+	if expected := strings.Count(article.Content, "<p>"); article.ParagraphCount != expected {
+		t.Errorf("paragraph count, want %d got %d", expected, article.ParagraphCount)
+	}
+}
+
+func Test_Fetcher_FinalURL(t *testing.T) {
+	paragraph := articleParagraph(10)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/old", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/new/article", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/new/article", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<html><body><article>`+paragraph+`<img src="image.png"></article></body></html>`)
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	var fetcher Fetcher
+	article, err := fetcher.Fetch(context.Background(), server.URL+"/old")
+	if err != nil {
+		t.Fatalf("failed to fetch: %v", err)
+	}
+
+	if article.URL != server.URL+"/new/article" {
+		t.Errorf("want final URL %q, got %q", server.URL+"/new/article", article.URL)
+	}
+
+	// Relative URL is resolved against the final URL
+	if !strings.Contains(article.Content, server.URL+"/new/image.png") {
+		t.Errorf("image is not resolved against final URL: %s", article.Content)
+	}
+
+	// The page can be fetched without parsing it
+	body, pageURL, err := fetcher.FetchPage(context.Background(), server.URL+"/old")
+	if err != nil {
+		t.Fatalf("failed to fetch page: %v", err)
+	}
+
+	if pageURL.String() != server.URL+"/new/article" || !bytes.Contains(body, []byte("<article>")) {
+		t.Errorf("want body of final URL, got %s %q", pageURL, body)
+	}
+}
+
+func Test_Fetcher_FollowSinglePage(t *testing.T) {
+	paragraph := strings.Repeat(articleSentence, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		if r.URL.Path == "/print" {
+			fmt.Fprintf(w, `<html><body><article><p>Page 1. %s</p><p>Page 2. %s</p></article></body></html>`,
+				paragraph, paragraph)
+			return
+		}
+
+		fmt.Fprintf(w, `<html><head><title>Article</title></head><body><article><p>Page 1. %s</p>`+
+			`<p>%s</p></article><a class="print" href="/print">Print</a></body></html>`, paragraph, paragraph)
+	}))
+	defer server.Close()
+
+	serverURL, _ := nurl.Parse(server.URL)
+	rules := NewRules()
+	rules.Register(serverURL.Hostname(), Rule{SinglePageLink: []string{"a.print"}})
+	parser := NewParser(WithRules(rules))
+
+	for _, follow := range []bool{false, true} {
+		fetcher := Fetcher{Parser: &parser, FollowSinglePage: follow}
+		article, err := fetcher.Fetch(context.Background(), server.URL+"/article")
+		if err != nil {
+			t.Fatalf("failed to fetch: %v", err)
+		}
+
+		if article.Title != "Article" {
+			t.Errorf("metadata should be taken from the page itself, got title %q", article.Title)
+		}
+
+		usesSinglePage := strings.Contains(article.TextContent, "Page 2.")
+		if usesSinglePage != follow {
+			t.Errorf("with follow %v, got single page content %v", follow, usesSinglePage)
+		}
+		checkDerivedFields(t, article)
+	}
+}
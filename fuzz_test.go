@@ -0,0 +1,88 @@
+package readability
+
+import (
+	"errors"
+	"os"
+	fp "path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fuzzSeeds is the inputs that used as the seed corpus of fuzz targets, on
+// top of the small test pages. Each of them is a known edge case of HTML.
+var fuzzSeeds = []string{
+	"",
+	"<",
+	"<html>",
+	"<p>text",
+	"<div><p>para<div>block</div></p></div>",
+	"<table><tr><td>cell<td>cell<tr><td>row</table>",
+	"<ul><li>item<li><p>para</ul>",
+	"<svg><p/><math><mi/></math></svg>",
+	"<template><p>inside template</p></template>",
+	"<noscript><img src=a.jpg></noscript>",
+	"<a href='javascript:alert(1)'>x</a><img srcset=', ,'>",
+	`<script type="application/ld+json">{"@type":"Article","author":[{}]}</script>`,
+	`<script type="application/ld+json">[[[[{"@graph":null}]]]]</script>`,
+	"<meta property='og:title'><title></title><h1></h1>",
+	"<div>" + strings.Repeat("<b>", 100) + "text",
+	articleParagraph(10),
+	"<html\x00><body\xff>\u00ad\u200b</body>",
+}
+
+// addFuzzSeeds adds the seed corpus into f.
+func addFuzzSeeds(f *testing.F) {
+	for _, seed := range fuzzSeeds {
+		f.Add(seed)
+	}
+
+	paths, err := fp.Glob(fp.Join("test-pages", "*", "source.html"))
+	if err != nil {
+		f.Fatalf("failed to list test pages: %v", err)
+	}
+
+	for _, path := range paths {
+		if info, err := os.Stat(path); err != nil || info.Size() > 12*1024 {
+			continue
+		}
+
+		source, err := os.ReadFile(path)
+		if err != nil {
+			f.Fatalf("failed to read %s: %v", path, err)
+		}
+		f.Add(string(source))
+	}
+}
+
+func Fuzz_Parse(f *testing.F) {
+	addFuzzSeeds(f)
+	f.Fuzz(func(t *testing.T, input string) {
+		logger := &testLogger{}
+		ps := NewParser(WithLogger(logger), WithMaxParseDuration(10*time.Second))
+
+		_, err := ps.Parse(strings.NewReader(input), fakeHostURL)
+		if errors.Is(err, ErrPanic) {
+			t.Fatalf("%v\n%s", err, strings.Join(logger.records, "\n"))
+		}
+	})
+}
+
+func Fuzz_Check(f *testing.F) {
+	addFuzzSeeds(f)
+	f.Fuzz(func(t *testing.T, input string) {
+		logger := &testLogger{}
+		ps := NewParser(WithLogger(logger))
+
+		if _, err := ps.CheckScore(strings.NewReader(input)); errors.Is(err, ErrPanic) {
+			t.Fatalf("%v\n%s", err, strings.Join(logger.records, "\n"))
+		}
+
+		ps.CheckStream(strings.NewReader(input))
+		for _, record := range logger.records {
+			if strings.HasPrefix(record, "WARN recovered from panic") {
+				t.Fatalf("stream check panicked\n%s", record)
+			}
+		}
+	})
+}
@@ -3,16 +3,19 @@ module github.com/go-shiori/go-readability
 go 1.20
 
 require (
+	github.com/PuerkitoBio/goquery v1.8.1
+	github.com/andybalholm/brotli v1.0.5
+	github.com/andybalholm/cascadia v1.3.2
 	github.com/go-shiori/dom v0.0.0-20210627111528-4e4722cd0d65
+	github.com/gogs/chardet v0.0.0-20211120154057-b7413eaefb8f
 	github.com/sergi/go-diff v1.1.0
 	github.com/spf13/cobra v1.0.0
 	golang.org/x/net v0.9.0
+	golang.org/x/text v0.9.0
+	gopkg.in/yaml.v2 v2.2.4
 )
 
 require (
-	github.com/andybalholm/cascadia v1.3.2 // indirect
-	github.com/gogs/chardet v0.0.0-20211120154057-b7413eaefb8f // indirect
 	github.com/inconshreveable/mousetrap v1.0.0 // indirect
 	github.com/spf13/pflag v1.0.5 // indirect
-	golang.org/x/text v0.9.0 // indirect
 )
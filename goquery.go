@@ -0,0 +1,39 @@
+package readability
+
+import (
+	"errors"
+	nurl "net/url"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// FromGoqueryDocument parses a goquery document and returns the readable content.
+// It's the wrapper for `Parser.ParseGoqueryDocument()` and useful if you only
+// want to use the default parser.
+func FromGoqueryDocument(doc *goquery.Document, pageURL *nurl.URL) (Article, error) {
+	parser := NewParser()
+	return parser.ParseGoqueryDocument(doc, pageURL)
+}
+
+// ParseGoqueryDocument parses the specified goquery document and find the main
+// readable content. Just like ParseDocument, the document is kept untouched.
+func (ps *Parser) ParseGoqueryDocument(doc *goquery.Document, pageURL *nurl.URL) (Article, error) {
+	if doc == nil || doc.Selection == nil || len(doc.Nodes) == 0 {
+		return Article{}, errors.New("goquery document is empty")
+	}
+
+	return ps.ParseDocument(doc.Nodes[0], pageURL)
+}
+
+// Selection returns the cleaned article content as goquery selection. The
+// selection contains the root node of article content, i.e. the one that
+// returned by ContentNode, so modifying it changes Node as well.
+func (article Article) Selection() *goquery.Selection {
+	root := article.ContentNode()
+	if root == nil {
+		return &goquery.Selection{Nodes: []*html.Node{}}
+	}
+
+	return goquery.NewDocumentFromNode(root).Selection
+}
@@ -0,0 +1,51 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func Test_FromGoqueryDocument(t *testing.T) {
+	paragraph := articleParagraph(10)
+	input := `<html><head><title>Goquery Article</title></head><body><article>` +
+		paragraph + `<p><a href="image.png">Image</a></p>` + paragraph +
+		`</article></body></html>`
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("failed to create goquery document: %v", err)
+	}
+
+	article, err := FromGoqueryDocument(doc, fakeHostURL)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	if article.Title != "Goquery Article" {
+		t.Errorf("title, want %q got %q", "Goquery Article", article.Title)
+	}
+
+	// Original document should be untouched
+	if href, _ := doc.Find("a").Attr("href"); href != "image.png" {
+		t.Errorf("original document is modified, got href %q", href)
+	}
+
+	selection := article.Selection()
+	if n := selection.Find("p").Length(); n != 3 {
+		t.Errorf("want 3 paragraphs, got %d", n)
+	}
+
+	if href, _ := selection.Find("a").Attr("href"); href != "http://fakehost/test/image.png" {
+		t.Errorf("href, want %q got %q", "http://fakehost/test/image.png", href)
+	}
+
+	if _, err = FromGoqueryDocument(&goquery.Document{}, fakeHostURL); err == nil {
+		t.Errorf("empty document should return error")
+	}
+
+	if n := (Article{}).Selection().Length(); n != 0 {
+		t.Errorf("empty article should have empty selection, got %d nodes", n)
+	}
+}
@@ -0,0 +1,13 @@
+package readability
+
+import "strings"
+
+// articleSentence is the sentence of article in the test documents, which
+// is long enough to be scored.
+const articleSentence = "This is a sentence of the article, long enough to be scored. "
+
+// articleParagraph returns the paragraph of n article sentences. With 10
+// sentences, it's long enough to be the content of article by itself.
+func articleParagraph(n int) string {
+	return "<p>" + strings.Repeat(articleSentence, n) + "</p>"
+}
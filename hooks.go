@@ -0,0 +1,15 @@
+package readability
+
+import "golang.org/x/net/html"
+
+// AddPreprocessor appends fn to the preprocessors, which are called with
+// the whole document before it's prepared and scored.
+func (ps *Parser) AddPreprocessor(fn func(*html.Node)) {
+	ps.Preprocessors = append(ps.Preprocessors, fn)
+}
+
+// AddPostprocessor appends fn to the postprocessors, which are called with
+// the container of article content after it's cleaned up.
+func (ps *Parser) AddPostprocessor(fn func(*html.Node)) {
+	ps.Postprocessors = append(ps.Postprocessors, fn)
+}
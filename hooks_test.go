@@ -0,0 +1,56 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-shiori/dom"
+	"golang.org/x/net/html"
+)
+
+func Test_Parser_Processors(t *testing.T) {
+	paragraph := articleParagraph(10)
+	input := `<html><body><div class="comment">` + paragraph + paragraph + `</div></body></html>`
+
+	var calls []string
+	ps := NewParser(WithPreprocessors(func(doc *html.Node) {
+		calls = append(calls, "pre 1")
+
+		// Rename the class, so the article is not removed as comment
+		for _, node := range dom.QuerySelectorAll(doc, ".comment") {
+			dom.SetAttribute(node, "class", "story")
+		}
+	}))
+	ps.AddPreprocessor(func(doc *html.Node) {
+		calls = append(calls, "pre 2")
+		if len(dom.QuerySelectorAll(doc, ".comment")) != 0 {
+			t.Error("preprocessors are not called in order")
+		}
+	})
+	ps.AddPostprocessor(func(content *html.Node) {
+		calls = append(calls, "post")
+		for _, p := range dom.GetElementsByTagName(content, "p") {
+			dom.SetAttribute(p, "data-checked", "true")
+		}
+	})
+
+	doc, err := dom.Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("failed to parse input: %v", err)
+	}
+
+	article, err := ps.ParseDocument(doc, fakeHostURL)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	if strings.Join(calls, ",") != "pre 1,pre 2,post" {
+		t.Errorf("unexpected calls: %v", calls)
+	}
+	if !strings.Contains(article.Content, `<p data-checked="true">`) {
+		t.Errorf("postprocessor changes are not in content: %s", article.Content)
+	}
+	if len(dom.QuerySelectorAll(doc, ".comment")) != 1 {
+		t.Error("preprocessor changed the original document")
+	}
+}
@@ -0,0 +1,241 @@
+package readability
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-shiori/dom"
+	"golang.org/x/net/html"
+)
+
+// Sources of the lead image candidates.
+const (
+	ImageSourceJSONLD      = "jsonld"
+	ImageSourceOpenGraph   = "opengraph"
+	ImageSourceTwitterCard = "twitter"
+	ImageSourceMicrodata   = "microdata"
+	ImageSourceMetaTag     = "meta"
+	ImageSourceContent     = "content"
+)
+
+// maxContentImageCandidates is the number of images in article content
+// that considered as lead image, counted from the top.
+const maxContentImageCandidates = 5
+
+var (
+	rxImageDimension = regexp.MustCompile(`^\s*(\d+)`)
+	rxImageClutter   = regexp.MustCompile(`(?i)logo|icon|avatar|sprite|pixel|spacer|blank|badge|placeholder|1x1|gravatar`)
+)
+
+// imageSourceWeights is the base score of the lead image candidates,
+// based on where they come from.
+var imageSourceWeights = map[string]float64{
+	ImageSourceJSONLD:      3,
+	ImageSourceOpenGraph:   3,
+	ImageSourceTwitterCard: 2,
+	ImageSourceMicrodata:   2,
+	ImageSourceMetaTag:     1,
+	ImageSourceContent:     2,
+}
+
+// imageMetadataSources is the metadata source of the lead image candidates,
+// which must be listed in Parser.MetadataPrecedence to be used.
+var imageMetadataSources = map[string]MetadataSource{
+	ImageSourceJSONLD:      MetadataJSONLD,
+	ImageSourceOpenGraph:   MetadataOpenGraph,
+	ImageSourceTwitterCard: MetadataTwitterCard,
+	ImageSourceMicrodata:   MetadataMicrodata,
+	ImageSourceMetaTag:     MetadataMetaTag,
+}
+
+// ImageCandidate is a candidate of the lead image of the article. The
+// dimensions are 0 when they are unknown.
+type ImageCandidate struct {
+	URL     string   `json:"url"`
+	Sources []string `json:"sources"`
+	Width   int      `json:"width,omitempty"`
+	Height  int      `json:"height,omitempty"`
+	Score   float64  `json:"score"`
+}
+
+// getImageCandidates collects the lead image candidates from metadata and
+// the top of article content, then ranks them by their score. The image
+// that found in several sources is merged into a single candidate. Only the
+// metadata sources in MetadataPrecedence are used.
+func (ps *Parser) getImageCandidates(jsonLd map[string]string, metaTags map[string][]string,
+	openGraph *OpenGraph, twitterCard *TwitterCard, microdata *Microdata, articleContent *html.Node) []ImageCandidate {
+	var candidates []*ImageCandidate
+	byURL := make(map[string]*ImageCandidate)
+	precedence := ps.metadataPrecedence()
+
+	add := func(src, source string, width, height int, weight float64) {
+		src = strings.TrimSpace(src)
+		if src == "" || strings.HasPrefix(src, "data:") {
+			return
+		}
+
+		if metadataSource, isMetadata := imageMetadataSources[source]; isMetadata {
+			if !containsMetadataSource(precedence, metadataSource) {
+				return
+			}
+		}
+
+		imageURL := toAbsoluteURI(src, ps.documentURI)
+		candidate, exist := byURL[imageURL]
+		if !exist {
+			candidate = &ImageCandidate{URL: imageURL}
+			byURL[imageURL] = candidate
+			candidates = append(candidates, candidate)
+		} else {
+			// Image that used in several places is more likely to be the lead
+			weight = math.Max(weight, 0) + 1
+		}
+
+		if candidate.Width == 0 && candidate.Height == 0 {
+			candidate.Width, candidate.Height = width, height
+		}
+
+		if indexOf(candidate.Sources, source) < 0 {
+			candidate.Sources = append(candidate.Sources, source)
+		}
+		candidate.Score += weight
+	}
+
+	add(jsonLd["image"], ImageSourceJSONLD, imageDimension(jsonLd["imageWidth"]),
+		imageDimension(jsonLd["imageHeight"]), imageSourceWeights[ImageSourceJSONLD])
+
+	if openGraph != nil {
+		for _, image := range openGraph.Images {
+			add(strOr(image.SecureURL, image.URL), ImageSourceOpenGraph, image.Width, image.Height,
+				imageSourceWeights[ImageSourceOpenGraph])
+		}
+	}
+
+	if twitterCard != nil {
+		add(twitterCard.Image, ImageSourceTwitterCard, 0, 0, imageSourceWeights[ImageSourceTwitterCard])
+	}
+
+	if microdata != nil {
+		add(microdata.Image, ImageSourceMicrodata, 0, 0, imageSourceWeights[ImageSourceMicrodata])
+	}
+
+	add(strOr(metaTags["image"]...), ImageSourceMetaTag, 0, 0, imageSourceWeights[ImageSourceMetaTag])
+
+	// Images at the top of the content are more likely to be the lead
+	if articleContent != nil {
+		for i, img := range dom.GetElementsByTagName(articleContent, "img") {
+			if i >= maxContentImageCandidates {
+				break
+			}
+
+			weight := imageSourceWeights[ImageSourceContent] - 0.4*float64(i)
+			add(dom.GetAttribute(img, "src"), ImageSourceContent, imageDimension(dom.GetAttribute(img, "width")),
+				imageDimension(dom.GetAttribute(img, "height")), weight)
+		}
+	}
+
+	result := make([]ImageCandidate, len(candidates))
+	for i, candidate := range candidates {
+		candidate.Score += imageDimensionScore(candidate.Width, candidate.Height)
+		if rxImageClutter.MatchString(candidate.URL) {
+			candidate.Score -= 5
+		}
+		result[i] = *candidate
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Score > result[j].Score
+	})
+
+	return result
+}
+
+// imageDimensionScore scores the image by its dimensions. Large image is
+// preferred, while tiny image like tracking pixel and icon, and extremely
+// wide or tall image like banner and logo are penalized.
+func imageDimensionScore(width, height int) float64 {
+	if width <= 0 || height <= 0 {
+		return 0
+	}
+
+	if width < 100 || height < 100 {
+		return -10
+	}
+
+	score := math.Min(float64(width*height)/(400*300), 4)
+	if ratio := float64(width) / float64(height); ratio > 3 || ratio < 1.0/3 {
+		score -= 3
+	}
+	return score
+}
+
+// imageDimension parses the width or height of image, e.g. "800" or
+// "800px". Returns 0 if it's not valid.
+func imageDimension(value string) int {
+	parts := rxImageDimension.FindStringSubmatch(value)
+	if len(parts) < 2 {
+		return 0
+	}
+
+	dimension, _ := strconv.Atoi(parts[1])
+	return dimension
+}
+
+// ImageInfo is an image that found in the article content.
+type ImageInfo struct {
+	URL     string `json:"url"`
+	Alt     string `json:"alt,omitempty"`
+	Caption string `json:"caption,omitempty"`
+	Width   int    `json:"width,omitempty"`
+	Height  int    `json:"height,omitempty"`
+}
+
+// getArticleImages returns the images in the article content, in document
+// order. The caption is taken from the <figcaption> of the figure that
+// contains the image. It must be called after the URLs are converted to
+// absolute.
+func (ps *Parser) getArticleImages(articleContent *html.Node) []ImageInfo {
+	var images []ImageInfo
+	seen := make(map[string]struct{})
+	for _, img := range dom.QuerySelectorAll(articleContent, "img") {
+		src := strings.TrimSpace(dom.GetAttribute(img, "src"))
+		if src == "" || strings.HasPrefix(src, "data:") {
+			continue
+		}
+
+		if _, exist := seen[src]; exist {
+			continue
+		}
+		seen[src] = struct{}{}
+
+		image := ImageInfo{
+			URL:    src,
+			Alt:    strings.TrimSpace(dom.GetAttribute(img, "alt")),
+			Width:  imageDimension(dom.GetAttribute(img, "width")),
+			Height: imageDimension(dom.GetAttribute(img, "height")),
+		}
+
+		if figure := closestAncestor(img, "figure"); figure != nil {
+			if captions := dom.GetElementsByTagName(figure, "figcaption"); len(captions) > 0 {
+				image.Caption = strings.Join(strings.Fields(dom.TextContent(captions[0])), " ")
+			}
+		}
+
+		images = append(images, image)
+	}
+	return images
+}
+
+// closestAncestor returns the nearest ancestor of node with the tag name,
+// or nil if there are none.
+func closestAncestor(node *html.Node, tagName string) *html.Node {
+	for parent := node.Parent; parent != nil; parent = parent.Parent {
+		if dom.TagName(parent) == tagName {
+			return parent
+		}
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package readability
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_Parser_ImageCandidates(t *testing.T) {
+	paragraph := articleParagraph(10)
+	scenarios := []struct {
+		name     string
+		opts     []Option
+		head     string
+		content  string
+		expected string
+	}{{
+		name:     "single metadata image",
+		head:     `<meta property="og:image" content="/lead.jpg">`,
+		expected: "http://fakehost/lead.jpg",
+	}, {
+		name: "logo in metadata",
+		head: `<meta property="og:image" content="/site-logo.png">` +
+			`<meta property="og:image:width" content="400"><meta property="og:image:height" content="100">`,
+		content:  `<img src="/photo.jpg" width="800" height="600">`,
+		expected: "http://fakehost/photo.jpg",
+	}, {
+		name:     "tracking pixel in content",
+		head:     `<meta name="twitter:image" content="/lead.jpg">`,
+		content:  `<img src="/track.gif" width="1" height="1">`,
+		expected: "http://fakehost/lead.jpg",
+	}, {
+		name: "image in several sources",
+		head: `<script type="application/ld+json">{"@context":"https://schema.org","@type":"Article",` +
+			`"image":"/jsonld.jpg"}</script>` +
+			`<meta property="og:image" content="/shared.jpg">`,
+		content:  `<img src="/shared.jpg">`,
+		expected: "http://fakehost/shared.jpg",
+	}, {
+		name:     "large JSON-LD image",
+		head:     `<meta property="og:image" content="/og.jpg"><script type="application/ld+json">{"@context":"https://schema.org","@type":"Article","image":[{"url":"/jsonld.jpg","width":"1200","height":"800"}]}</script>`,
+		expected: "http://fakehost/jsonld.jpg",
+	}, {
+		name:     "metadata precedence",
+		opts:     []Option{WithMetadataPrecedence(MetadataMetaTag)},
+		head:     `<meta property="og:image" content="/og.jpg"><meta name="image" content="/meta.jpg">`,
+		expected: "http://fakehost/meta.jpg",
+	}, {
+		name:     "metadata precedence with content image",
+		opts:     []Option{WithMetadataPrecedence(MetadataJSONLD)},
+		head:     `<meta property="og:image" content="/og.jpg">`,
+		content:  `<img src="/photo.jpg" width="800" height="600">`,
+		expected: "http://fakehost/photo.jpg",
+	}}
+
+	for _, scenario := range scenarios {
+		input := `<html><head>` + scenario.head + `</head><body><article>` +
+			scenario.content + paragraph + paragraph + `</article></body></html>`
+		parser := NewParser(scenario.opts...)
+		article, err := parser.Parse(strings.NewReader(input), fakeHostURL)
+		if err != nil {
+			t.Fatalf("%s: failed to parse: %v", scenario.name, err)
+		}
+
+		if article.Image != scenario.expected {
+			t.Errorf("%s: want image %q, got %q (candidates %+v)",
+				scenario.name, scenario.expected, article.Image, article.ImageCandidates)
+		}
+
+		for _, candidate := range article.ImageCandidates {
+			if candidate.URL == "http://fakehost/og.jpg" && scenario.opts != nil {
+				t.Errorf("%s: image from source that not in precedence: %+v", scenario.name, candidate)
+			}
+		}
+	}
+}
+
+func Test_imageDimensionScore(t *testing.T) {
+	scenarios := map[[2]int]float64{
+		{0, 0}:       0,
+		{1, 1}:       -10,
+		{400, 300}:   1,
+		{1600, 1200}: 4,
+		{1200, 200}:  -1,
+	}
+
+	for dimension, expected := range scenarios {
+		if score := imageDimensionScore(dimension[0], dimension[1]); score != expected {
+			t.Errorf("dimension %v, want score %v got %v", dimension, expected, score)
+		}
+	}
+}
+
+func Test_Parser_Images(t *testing.T) {
+	paragraph := articleParagraph(10)
+	input := `<html><body><article>` + paragraph +
+		`<figure><img src="/first.jpg" alt="First" width="800" height="600px">` +
+		`<figcaption>The <b>first</b> image</figcaption></figure>` + paragraph +
+		`<p><img src="second.png"> <img src="/first.jpg"></p>` + paragraph +
+		`</article></body></html>`
+
+	article, err := FromReader(strings.NewReader(input), fakeHostURL)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	expected := []ImageInfo{
+		{URL: "http://fakehost/first.jpg", Alt: "First", Caption: "The first image", Width: 800, Height: 600},
+		{URL: "http://fakehost/test/second.png"},
+	}
+
+	if !reflect.DeepEqual(article.Images, expected) {
+		t.Errorf("\n"+
+			"want : %+v\n"+
+			"got  : %+v", expected, article.Images)
+	}
+}
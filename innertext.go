@@ -0,0 +1,154 @@
+package readability
+
+import (
+	"bytes"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+)
+
+// maxPooledBufferSize is the max capacity of buffer that returned to the
+// pool, so a huge page doesn't keep its buffer alive forever.
+const maxPooledBufferSize = 1 << 20
+
+// bufferPool is the pool of buffers that used to collect the text of
+// nodes, so the buffer doesn't have to grow from scratch for each node.
+var bufferPool = sync.Pool{
+	New: func() interface{} { return new(bytes.Buffer) },
+}
+
+// textContent is like dom.TextContent, but the text is collected in a
+// pooled buffer, so the only allocation is the returned string.
+func textContent(node *html.Node) string {
+	if node.Type == html.TextNode {
+		return node.Data
+	}
+
+	buffer := bufferPool.Get().(*bytes.Buffer)
+	buffer.Reset()
+	walkTextNodes(node, func(text string) bool {
+		buffer.WriteString(text)
+		return true
+	})
+
+	text := buffer.String()
+	if buffer.Cap() <= maxPooledBufferSize {
+		bufferPool.Put(buffer)
+	}
+	return text
+}
+
+// hasTextContent checks if node has any text other than whitespace, i.e.
+// it's the same as strings.TrimSpace(dom.TextContent(node)) != "".
+func hasTextContent(node *html.Node) bool {
+	found := false
+	walkTextNodes(node, func(text string) bool {
+		for _, r := range text {
+			if !unicode.IsSpace(r) {
+				found = true
+				return false
+			}
+		}
+		return true
+	})
+	return found
+}
+
+// textLength returns the length of node's inner text without building
+// the text. It's the same as ps.getTextLength(node): the
+// leading and trailing whitespaces are not counted, and each sequence of
+// whitespaces that collapsed by getInnerText is counted as one character.
+func textLength(node *html.Node) int {
+	var length, pending int
+	var started, inSpaces bool
+
+	walkTextNodes(node, func(text string) bool {
+		for i := 0; i < len(text); {
+			r, size := utf8.DecodeRuneInString(text[i:])
+			i += size
+
+			switch {
+			case !unicode.IsSpace(r):
+				started = true
+				length += pending + 1
+				pending, inSpaces = 0, false
+			case !started:
+			case isNormalizedSpace(r):
+				if !inSpaces {
+					pending++
+					inSpaces = true
+				}
+			default:
+				pending++
+				inSpaces = false
+			}
+		}
+		return true
+	})
+
+	return length
+}
+
+// isNormalizedSpace checks if r is matched by `\s` in regex, which is
+// collapsed by getInnerText. Other Unicode spaces are only trimmed.
+func isNormalizedSpace(r rune) bool {
+	switch r {
+	case '\t', '\n', '\f', '\r', ' ':
+		return true
+	default:
+		return false
+	}
+}
+
+// walkTextNodes calls fn for the data of each text node inside node, in
+// document order, until fn returns false.
+func walkTextNodes(node *html.Node, fn func(text string) bool) bool {
+	if node.Type == html.TextNode {
+		return fn(node.Data)
+	}
+
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		if !walkTextNodes(child, fn) {
+			return false
+		}
+	}
+	return true
+}
+
+// getTextLength returns the length of node's inner text. While the text
+// length cache is enabled, the length of each node is only counted once.
+func (ps *Parser) getTextLength(node *html.Node) int {
+	if length, cached := ps.textLengths[node]; cached {
+		return length
+	}
+
+	length := textLength(node)
+	if ps.textLengths != nil {
+		ps.textLengths[node] = length
+	}
+	return length
+}
+
+// cacheTextLengths enables the text length cache until the returned
+// function is called. Since the cache is never invalidated, it must only
+// be enabled while the document is not modified, e.g. while the same node
+// is measured several times to decide whether it should be removed.
+func (ps *Parser) cacheTextLengths() (release func()) {
+	if ps.textLengths != nil {
+		return func() {}
+	}
+
+	if ps.textLengthCache == nil {
+		ps.textLengthCache = make(map[*html.Node]int)
+	}
+	ps.textLengths = ps.textLengthCache
+
+	return func() {
+		for node := range ps.textLengths {
+			delete(ps.textLengths, node)
+		}
+		ps.textLengths = nil
+	}
+}
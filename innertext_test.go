@@ -0,0 +1,68 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-shiori/dom"
+)
+
+func Test_textLength(t *testing.T) {
+	ps := NewParser()
+	scenarios := []string{
+		`<div></div>`,
+		`<div>   </div>`,
+		`<div>Hello world</div>`,
+		`<div>  Hello   <b>big</b>  world  </div>`,
+		`<div>Hello<span> </span><span> </span>world</div>`,
+		"<div>\n\tHello\n\n\tworld\n</div>",
+		"<div>Hello \u00a0 world\u00a0 </div>",
+		"<div>\u00a0 \u3000Hello\u2003</div>",
+		"<div>Héllo wörld, 你好</div>",
+		"<div>Hello\vworld \v </div>",
+		`<div><p>First paragraph.</p>   <p>Second   paragraph.</p></div>`,
+	}
+
+	for _, scenario := range scenarios {
+		doc, err := dom.FastParse(strings.NewReader(scenario))
+		if err != nil {
+			t.Fatalf("failed to parse %q: %v", scenario, err)
+		}
+
+		div := dom.QuerySelector(doc, "div")
+		expected := charCount(ps.getInnerText(div, true))
+		if length := textLength(div); length != expected {
+			t.Errorf("text length of %q is %d, want %d", scenario, length, expected)
+		}
+
+		hasText := strings.TrimSpace(dom.TextContent(div)) != ""
+		if hasTextContent(div) != hasText {
+			t.Errorf("hasTextContent of %q is %v, want %v", scenario, !hasText, hasText)
+		}
+	}
+}
+
+func Test_Parser_cacheTextLengths(t *testing.T) {
+	doc, err := dom.FastParse(strings.NewReader(`<div><p>Hello</p><p>world</p></div>`))
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	ps := NewParser()
+	div := dom.QuerySelector(doc, "div")
+	release := ps.cacheTextLengths()
+	if length := ps.getTextLength(div); length != 10 {
+		t.Errorf("text length is %d, want 10", length)
+	}
+
+	// The cached length is used until the cache is released
+	div.RemoveChild(div.LastChild)
+	if length := ps.getTextLength(div); length != 10 {
+		t.Errorf("cached text length is %d, want 10", length)
+	}
+
+	release()
+	if length := ps.getTextLength(div); length != 5 {
+		t.Errorf("text length after release is %d, want 5", length)
+	}
+}
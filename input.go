@@ -0,0 +1,153 @@
+package readability
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	nurl "net/url"
+	"os"
+	fp "path/filepath"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+var (
+	rxSavedFromURL  = regexp.MustCompile(`(?i)saved from url=\(\d+\)(\S+)`)
+	rxSingleFileURL = regexp.MustCompile(`(?im)^\s*url:\s*(\S+)`)
+)
+
+// ParseString parses the HTML in string. When pageURL is nil, it's guessed
+// from the page as explained in GuessPageURL.
+func (ps *Parser) ParseString(content string, pageURL *nurl.URL) (Article, error) {
+	if pageURL == nil {
+		pageURL = GuessPageURL(strings.NewReader(content))
+	}
+	return ps.Parse(strings.NewReader(content), pageURL)
+}
+
+// ParseBytes is like ParseString, but the HTML is in bytes. The content is
+// parsed as it is without copying, so it must not be modified while parsing.
+func (ps *Parser) ParseBytes(content []byte, pageURL *nurl.URL) (Article, error) {
+	if pageURL == nil {
+		pageURL = GuessPageURL(bytes.NewReader(content))
+	}
+	return ps.parseContent(context.Background(), content, pageURL)
+}
+
+// ParseFile parses the web page that saved in path. When pageURL is nil,
+// it's guessed from the page as explained in GuessPageURL, or else the
+// file URL of path is used, so the relative URLs point to the files that
+// saved along with the page. The file with .mht or .mhtml extension is
+// parsed as MHTML archive using ParseMHTML.
+func (ps *Parser) ParseFile(path string, pageURL *nurl.URL) (Article, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return Article{}, fmt.Errorf("failed to read file: %v", err)
+	}
+
+	switch strings.ToLower(fp.Ext(path)) {
+	case ".mht", ".mhtml":
+		return ps.ParseMHTML(bytes.NewReader(content), pageURL)
+	}
+
+	if pageURL == nil {
+		pageURL = GuessPageURL(bytes.NewReader(content))
+	}
+
+	if pageURL == nil {
+		if pageURL, err = fileURL(path); err != nil {
+			return Article{}, err
+		}
+	}
+
+	return ps.parseContent(context.Background(), content, pageURL)
+}
+
+// GuessPageURL guesses the original URL of page from its head, which is
+// taken from the first one of:
+//   - the "saved from url" comment that added by browsers once the page
+//     is saved, or the URL comment that added by SingleFile;
+//   - the canonical link;
+//   - the "og:url" meta.
+//
+// It only accepts the absolute HTTP and HTTPS URLs, and returns nil if
+// there are none. Only the head is read, without parsing the document.
+func GuessPageURL(input io.Reader) *nurl.URL {
+	var commentURL, canonicalURL, ogURL string
+	tokenizer := html.NewTokenizer(input)
+
+tokens:
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			break tokens
+		case html.CommentToken:
+			if commentURL == "" {
+				commentURL = savedPageURL(string(tokenizer.Text()))
+			}
+		case html.StartTagToken, html.SelfClosingTagToken:
+			token := tokenizer.Token()
+			switch token.Data {
+			case "body":
+				break tokens
+			case "link":
+				if canonicalURL == "" && strings.EqualFold(tokenAttr(token, "rel"), "canonical") {
+					canonicalURL = tokenAttr(token, "href")
+				}
+			case "meta":
+				if ogURL == "" && strings.EqualFold(tokenAttr(token, "property"), "og:url") {
+					ogURL = tokenAttr(token, "content")
+				}
+			}
+		case html.EndTagToken:
+			if name, _ := tokenizer.TagName(); string(name) == "head" {
+				break tokens
+			}
+		}
+	}
+
+	for _, candidate := range []string{commentURL, canonicalURL, ogURL} {
+		parsedURL, err := nurl.ParseRequestURI(strings.TrimSpace(candidate))
+		if err == nil && (parsedURL.Scheme == "http" || parsedURL.Scheme == "https") && parsedURL.Host != "" {
+			return parsedURL
+		}
+	}
+	return nil
+}
+
+// savedPageURL returns the URL in the comment that added when the page is
+// saved, or empty string if the comment doesn't have any.
+func savedPageURL(comment string) string {
+	if match := rxSavedFromURL.FindStringSubmatch(comment); match != nil {
+		return match[1]
+	}
+
+	if strings.Contains(comment, "SingleFile") {
+		if match := rxSingleFileURL.FindStringSubmatch(comment); match != nil {
+			return match[1]
+		}
+	}
+	return ""
+}
+
+// tokenAttr returns the value of attribute in token.
+func tokenAttr(token html.Token, key string) string {
+	for _, attr := range token.Attr {
+		if attr.Key == key {
+			return attr.Val
+		}
+	}
+	return ""
+}
+
+// fileURL returns the file URL of path.
+func fileURL(path string) (*nurl.URL, error) {
+	absPath, err := fp.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get absolute path: %v", err)
+	}
+	return &nurl.URL{Scheme: "file", Path: fp.ToSlash(absPath)}, nil
+}
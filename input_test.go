@@ -0,0 +1,106 @@
+package readability
+
+import (
+	"os"
+	fp "path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_GuessPageURL(t *testing.T) {
+	scenarios := map[string]string{
+		"<!-- saved from url=(0032)https://example.com/saved.html -->\n" +
+			`<html><head><link rel="canonical" href="https://example.com/canonical"></head></html>`: "https://example.com/saved.html",
+		"<!--\n Page saved with SingleFile \n url: https://example.com/single \n saved date: Mon Jan 01 2024\n-->" +
+			`<html><head></head></html>`: "https://example.com/single",
+		`<html><head><link rel="Canonical" href="https://example.com/canonical">` +
+			`<meta property="og:url" content="https://example.com/og"></head></html>`: "https://example.com/canonical",
+		`<html><head><link rel="canonical" href="/relative">` +
+			`<meta property="og:url" content="https://example.com/og"></head></html>`: "https://example.com/og",
+		`<html><head><meta property="og:url" content="ftp://example.com/og"></head></html>`:             "",
+		`<html><head></head><body><link rel="canonical" href="https://example.com/body"></body></html>`: "",
+		`<html><head><!-- url: https://example.com/not-single-file --></head></html>`:                   "",
+		`<html><head><title>No URL</title></head><body><p>Text</p></body></html>`:                       "",
+	}
+
+	for input, expected := range scenarios {
+		result := ""
+		if pageURL := GuessPageURL(strings.NewReader(input)); pageURL != nil {
+			result = pageURL.String()
+		}
+
+		if result != expected {
+			t.Errorf("%s\nwant %q got %q", input, expected, result)
+		}
+	}
+}
+
+func Test_Parser_ParseFile(t *testing.T) {
+	paragraph := articleParagraph(10)
+	input := `<html><head><title>Saved</title></head><body><article>` +
+		paragraph + `<p><a href="images/photo.html">Photo</a></p></article></body></html>`
+
+	dir := t.TempDir()
+	path := fp.Join(dir, "page.html")
+	if err := os.WriteFile(path, []byte(input), 0o644); err != nil {
+		t.Fatalf("failed to write page: %v", err)
+	}
+
+	parser := NewParser()
+
+	// Without URL, the relative URLs point to the files next to the page
+	article, err := parser.ParseFile(path, nil)
+	if err != nil {
+		t.Fatalf("failed to parse file: %v", err)
+	}
+
+	expected := `href="file://` + fp.ToSlash(dir) + `/images/photo.html"`
+	if !strings.Contains(article.Content, expected) {
+		t.Errorf("want %s in content, got %s", expected, article.Content)
+	}
+
+	// The given URL is used as it is
+	article, err = parser.ParseFile(path, fakeHostURL)
+	if err != nil {
+		t.Fatalf("failed to parse file: %v", err)
+	}
+
+	if !strings.Contains(article.Content, `href="http://fakehost/test/images/photo.html"`) {
+		t.Errorf("want fakehost URL in content, got %s", article.Content)
+	}
+
+	if _, err = parser.ParseFile(fp.Join(dir, "missing.html"), nil); err == nil {
+		t.Errorf("want error for missing file")
+	}
+}
+
+func Test_Parser_ParseString(t *testing.T) {
+	paragraph := articleParagraph(10)
+	input := `<html><head><link rel="canonical" href="https://example.com/posts/1">` +
+		`<title>Saved</title></head><body><article>` + paragraph +
+		`<p><a href="../about">About</a></p></article></body></html>`
+
+	parser := NewParser()
+	fromString, err := parser.ParseString(input, nil)
+	if err != nil {
+		t.Fatalf("failed to parse string: %v", err)
+	}
+
+	if !strings.Contains(fromString.Content, `href="https://example.com/about"`) {
+		t.Errorf("want URL resolved against canonical URL, got %s", fromString.Content)
+	}
+
+	fromBytes, err := parser.ParseBytes([]byte(input), nil)
+	if err != nil {
+		t.Fatalf("failed to parse bytes: %v", err)
+	}
+
+	fromReader, err := parser.Parse(strings.NewReader(input), GuessPageURL(strings.NewReader(input)))
+	if err != nil {
+		t.Fatalf("failed to parse reader: %v", err)
+	}
+
+	if fromString.Content != fromReader.Content || fromBytes.Content != fromReader.Content {
+		t.Errorf("want same content from string, bytes and reader")
+	}
+}
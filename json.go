@@ -0,0 +1,51 @@
+package readability
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// plainArticle has the same fields as Article, but without its JSON
+// methods so it can be encoded without infinite recursion.
+type plainArticle Article
+
+// articleJSON is the JSON representation of Article.
+type articleJSON struct {
+	plainArticle
+	PublishedTime string `json:"published_time,omitempty"`
+	ModifiedTime  string `json:"modified_time,omitempty"`
+	ReadingTime   int    `json:"reading_time"`
+}
+
+// MarshalJSON encodes article into JSON object with the field names that
+// written in the struct tags. Time is encoded in RFC 3339 format, and
+// omitted when it's not available. Reading time is encoded in seconds.
+func (article Article) MarshalJSON() ([]byte, error) {
+	data := articleJSON{
+		plainArticle: plainArticle(article),
+		ReadingTime:  int(article.ReadingTime / time.Second),
+	}
+	if article.PublishedTime != nil {
+		data.PublishedTime = article.PublishedTime.Format(time.RFC3339)
+	}
+	if article.ModifiedTime != nil {
+		data.ModifiedTime = article.ModifiedTime.Format(time.RFC3339)
+	}
+
+	return json.Marshal(data)
+}
+
+// UnmarshalJSON decodes JSON object that created by MarshalJSON. Beside
+// RFC 3339, the time may be written in other common date formats.
+func (article *Article) UnmarshalJSON(data []byte) error {
+	var decoded articleJSON
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	*article = Article(decoded.plainArticle)
+	article.PublishedTime = parseDate(decoded.PublishedTime)
+	article.ModifiedTime = parseDate(decoded.ModifiedTime)
+	article.ReadingTime = time.Duration(decoded.ReadingTime) * time.Second
+	return nil
+}
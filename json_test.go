@@ -0,0 +1,107 @@
+package readability
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_Article_JSON(t *testing.T) {
+	publishedTime := time.Date(2021, 6, 27, 11, 15, 28, 0, time.UTC)
+	modifiedTime := time.Date(2021, 6, 28, 8, 0, 0, 0, time.UTC)
+	article := Article{
+		Title:           "Title",
+		Byline:          "Byline",
+		Authors:         []Author{{Name: "Byline", URL: "http://example.com/byline"}},
+		Content:         "<p>Content</p>",
+		TextContent:     "Content",
+		Length:          7,
+		Excerpt:         "Excerpt",
+		SiteName:        "Site",
+		Image:           "http://example.com/image.png",
+		ImageCandidates: []ImageCandidate{{URL: "http://example.com/image.png", Sources: []string{ImageSourceOpenGraph}, Score: 3}},
+		Favicon:         "http://example.com/favicon.png",
+		Language:        "en",
+		Dir:             "ltr",
+		PublishedTime:   &publishedTime,
+		ModifiedTime:    &modifiedTime,
+		NextPageURL:     "http://example.com/article?page=2",
+		AMPURL:          "http://example.com/article/amp",
+		Truncated:       true,
+		URL:             "http://example.com/article",
+		CanonicalURL:    "http://example.com/article",
+		Feeds:           []Feed{{URL: "http://example.com/feed.xml", Type: "rss"}},
+		Tags:            []string{"go", "readability"},
+		Comments:        []Comment{{Author: "John", HTML: "<p>Nice</p>"}},
+		Links:           []LinkInfo{{URL: "http://example.com/source", AnchorText: "Source", InContent: true, Context: LinkContextContent}},
+		Media: []Media{{
+			Type:    "video",
+			Poster:  "http://example.com/poster.png",
+			Sources: []MediaSource{{Src: "http://example.com/video.mp4", Type: "video/mp4"}},
+			Tracks:  []MediaTrack{{Src: "http://example.com/en.vtt", Kind: "subtitles", SrcLang: "en", Label: "English"}},
+		}},
+		Images:         []ImageInfo{{URL: "http://example.com/image.png", Caption: "Caption"}},
+		Videos:         []EmbeddedMedia{{Provider: "youtube", EmbedURL: "https://www.youtube.com/embed/abc", Duration: 90 * time.Second}},
+		Audios:         []EmbeddedMedia{{URL: "http://example.com/podcast.mp3"}},
+		ReadingTime:    3 * time.Minute,
+		WordCount:      1,
+		CharCount:      7,
+		ParagraphCount: 1,
+		OpenGraph:      &OpenGraph{Title: "Title", Images: []OpenGraphImage{{URL: "http://example.com/image.png", Width: 800}}},
+		TwitterCard:    &TwitterCard{Card: "summary", Creator: "@byline"},
+		DublinCore:     &DublinCore{Title: "Title", Creators: []string{"Byline"}},
+		HEntry:         &HEntry{Name: "Title", Author: &HCard{Name: "Byline"}},
+		Microdata:      &Microdata{Type: "NewsArticle", Headline: "Title"},
+		Sections:       []Section{{HeadingLevel: 2, HeadingText: "Heading", AnchorID: "heading", HTML: "<p>Content</p>"}},
+		Meta:           map[string]string{"description": "Excerpt"},
+		Description:    "Excerpt",
+		MetaKeywords:   []string{"go", "readability"},
+		Readability:    &ReadabilityMetrics{Sentences: 1, Words: 2},
+		Keywords:       []Keyword{{Text: "go readability", Score: 4}},
+	}
+
+	encoded, err := json.Marshal(article)
+	if err != nil {
+		t.Fatalf("failed to encode article: %v", err)
+	}
+
+	var fields map[string]interface{}
+	if err = json.Unmarshal(encoded, &fields); err != nil {
+		t.Fatalf("failed to decode fields: %v", err)
+	}
+
+	expectedFields := []string{"title", "byline", "authors", "content", "text_content", "length", "excerpt",
+		"site_name", "image", "image_candidates", "favicon", "language", "dir", "published_time",
+		"modified_time", "next_page_url", "amp_url", "single_page_url", "url", "canonical_url", "feeds",
+		"tags", "comments", "links", "truncated", "media", "images", "videos", "audios", "open_graph",
+		"twitter_card", "dublin_core", "h_entry", "microdata", "sections", "reading_time", "word_count",
+		"char_count", "paragraph_count", "meta", "description", "meta_keywords",
+		"keywords", "readability", "strategy", "confidence", "removed_regions"}
+	for _, field := range expectedFields {
+		if _, exist := fields[field]; !exist {
+			t.Errorf("field %q doesn't exist in %s", field, encoded)
+		}
+	}
+
+	if len(fields) != len(expectedFields) {
+		t.Errorf("number of fields, want %d got %d", len(expectedFields), len(fields))
+	}
+
+	if fields["published_time"] != "2021-06-27T11:15:28Z" {
+		t.Errorf("published time, want %q got %q", "2021-06-27T11:15:28Z", fields["published_time"])
+	}
+
+	if fields["reading_time"] != float64(180) {
+		t.Errorf("reading time, want %v got %v", 180, fields["reading_time"])
+	}
+
+	var decoded Article
+	if err = json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatalf("failed to decode article: %v", err)
+	}
+
+	if !reflect.DeepEqual(article, decoded) {
+		t.Errorf("decoded article is different\nwant: %+v\ngot : %+v", article, decoded)
+	}
+}
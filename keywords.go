@@ -0,0 +1,172 @@
+package readability
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// DefaultMaxKeywords is the default number of keywords that are extracted
+// from the article.
+const DefaultMaxKeywords = 10
+
+// maxKeywordWords is the max number of words in a key phrase.
+const maxKeywordWords = 4
+
+// englishStopWords is the English words that don't carry meaning on their
+// own, which are used to split the text into key phrases. The stop words
+// of other languages are taken from their common words.
+var englishStopWords = []string{
+	"a", "about", "above", "after", "again", "against", "all", "also", "am", "an", "and", "any",
+	"are", "as", "at", "be", "because", "been", "before", "being", "below", "between", "both",
+	"but", "by", "can", "could", "did", "do", "does", "doing", "down", "during", "each", "even",
+	"few", "for", "from", "further", "get", "got", "had", "has", "have", "having", "he", "her",
+	"here", "hers", "herself", "him", "himself", "his", "how", "i", "if", "in", "into", "is",
+	"it", "its", "itself", "just", "like", "many", "may", "me", "might", "more", "most", "much",
+	"must", "my", "myself", "new", "no", "nor", "not", "now", "of", "off", "on", "once", "one",
+	"only", "or", "other", "our", "ours", "ourselves", "out", "over", "own", "said", "same",
+	"say", "says", "she", "should", "so", "some", "such", "than", "that", "the", "their",
+	"theirs", "them", "themselves", "then", "there", "these", "they", "this", "those",
+	"through", "to", "too", "under", "until", "up", "us", "very", "was", "we", "were", "what",
+	"when", "where", "which", "while", "who", "whom", "why", "will", "with", "would", "you",
+	"your", "yours", "yourself", "yourselves",
+}
+
+// Keyword is a key phrase of the article, scored using RAKE (Rapid
+// Automatic Keyword Extraction) algorithm.
+type Keyword struct {
+	Text  string  `json:"text"`
+	Score float64 `json:"score"`
+}
+
+// getKeywords returns the key phrases of the article text, if the
+// keyword extraction is enabled.
+func (ps *Parser) getKeywords(text, language string) []Keyword {
+	if !ps.ExtractKeywords {
+		return nil
+	}
+
+	max := ps.MaxKeywords
+	if max <= 0 {
+		max = DefaultMaxKeywords
+	}
+	return extractKeywords(text, language, max)
+}
+
+// extractKeywords extracts the top key phrases from text using RAKE. The
+// text is split into candidate phrases by punctuations and stop words,
+// their words are scored by degree divided by frequency, then each phrase
+// is scored by the sum of its word scores. CJK text is skipped since its
+// words are not delimited by space.
+func extractKeywords(text, language string, max int) []Keyword {
+	if max <= 0 {
+		return nil
+	}
+
+	stopWords := make(map[string]struct{})
+	for _, word := range englishStopWords {
+		stopWords[word] = struct{}{}
+	}
+
+	language = strings.ToLower(strings.SplitN(language, "-", 2)[0])
+	for _, word := range commonWords[language] {
+		stopWords[word] = struct{}{}
+	}
+
+	// Split text into candidate phrases
+	var phrases [][]string
+	var current []string
+	closePhrase := func() {
+		if len(current) > 0 && len(current) <= maxKeywordWords {
+			phrases = append(phrases, current)
+		}
+		current = nil
+	}
+
+	var word strings.Builder
+	closeWord := func(endsPhrase bool) {
+		if word.Len() > 0 {
+			w := strings.Trim(strings.ToLower(word.String()), "'’-")
+			word.Reset()
+
+			_, isStopWord := stopWords[w]
+			switch {
+			case isStopWord || charCount(w) < 2 || isNumeric(w):
+				closePhrase()
+			default:
+				current = append(current, w)
+			}
+		}
+
+		if endsPhrase {
+			closePhrase()
+		}
+	}
+
+	for _, r := range text {
+		switch {
+		case isCJK(r):
+			closeWord(true)
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r) ||
+			((r == '\'' || r == '’' || r == '-') && word.Len() > 0):
+			word.WriteRune(r)
+		case unicode.IsSpace(r):
+			closeWord(false)
+		default:
+			closeWord(true)
+		}
+	}
+	closeWord(true)
+
+	// Score each word by its degree and frequency
+	frequency := make(map[string]float64)
+	degree := make(map[string]float64)
+	for _, phrase := range phrases {
+		for _, w := range phrase {
+			frequency[w]++
+			degree[w] += float64(len(phrase))
+		}
+	}
+
+	// Score the unique phrases
+	scores := make(map[string]float64)
+	for _, phrase := range phrases {
+		key := strings.Join(phrase, " ")
+		if _, exist := scores[key]; exist {
+			continue
+		}
+
+		score := 0.0
+		for _, w := range phrase {
+			score += degree[w] / frequency[w]
+		}
+		scores[key] = score
+	}
+
+	keywords := make([]Keyword, 0, len(scores))
+	for key, score := range scores {
+		keywords = append(keywords, Keyword{Text: key, Score: score})
+	}
+
+	sort.Slice(keywords, func(i, j int) bool {
+		if keywords[i].Score != keywords[j].Score {
+			return keywords[i].Score > keywords[j].Score
+		}
+		return keywords[i].Text < keywords[j].Text
+	})
+
+	if len(keywords) > max {
+		keywords = keywords[:max]
+	}
+	return keywords
+}
+
+// isNumeric checks if the word only consists of digits and punctuations.
+func isNumeric(word string) bool {
+	for _, r := range word {
+		if unicode.IsLetter(r) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,71 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_extractKeywords(t *testing.T) {
+	text := "Compatibility of systems of linear constraints over the set of natural numbers. " +
+		"Criteria of compatibility of a system of linear Diophantine equations, strict inequations, " +
+		"and nonstrict inequations are considered. Upper bounds for components of a minimal set of " +
+		"solutions and algorithms of construction of minimal generating sets of solutions for all " +
+		"types of systems are given."
+
+	keywords := extractKeywords(text, "en", 3)
+	expected := []string{"linear diophantine equations", "minimal generating sets", "linear constraints"}
+	if len(keywords) != len(expected) {
+		t.Fatalf("want %d keywords, got %+v", len(expected), keywords)
+	}
+
+	for i, keyword := range keywords {
+		if keyword.Text != expected[i] {
+			t.Errorf("keyword %d: want %q, got %q", i, expected[i], keyword.Text)
+		}
+		if i > 0 && keyword.Score > keywords[i-1].Score {
+			t.Errorf("keywords are not sorted by score: %+v", keywords)
+		}
+	}
+}
+
+func Test_extractKeywords_StopWords(t *testing.T) {
+	// German stop words are taken from the common words of the language
+	keywords := extractKeywords("Der schnelle Fuchs und der faule Hund.", "de-DE", DefaultMaxKeywords)
+	for _, keyword := range keywords {
+		if strings.HasPrefix(keyword.Text, "der ") || strings.Contains(keyword.Text, " und ") {
+			t.Errorf("stop word in keyword %q", keyword.Text)
+		}
+	}
+
+	// Numbers and CJK text are not keywords
+	if keywords := extractKeywords("2024 12.5 中文文本", "en", DefaultMaxKeywords); len(keywords) != 0 {
+		t.Errorf("want no keywords, got %+v", keywords)
+	}
+}
+
+func Test_Parser_Keywords(t *testing.T) {
+	paragraph := "<p>" + strings.Repeat("The solar panel installation has reduced the energy costs of the small village. ", 10) + "</p>"
+	input := "<html><body><article>" + paragraph + paragraph + "</article></body></html>"
+
+	article, err := FromReader(strings.NewReader(input), fakeHostURL)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	if len(article.Keywords) != 0 {
+		t.Errorf("keywords extracted without being enabled: %+v", article.Keywords)
+	}
+
+	ps := NewParser()
+	WithKeywords(2)(&ps)
+	article, err = ps.Parse(strings.NewReader(input), fakeHostURL)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	if len(article.Keywords) != 2 {
+		t.Fatalf("want 2 keywords, got %+v", article.Keywords)
+	}
+	if article.Keywords[0].Text != "solar panel installation" {
+		t.Errorf("want top keyword %q, got %+v", "solar panel installation", article.Keywords)
+	}
+}
@@ -0,0 +1,155 @@
+package readability
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/go-shiori/dom"
+)
+
+// minDetectionWords is the min number of common words that must be found
+// in the text before its language is detected from them.
+const minDetectionWords = 5
+
+// scriptLanguages is the languages that can be detected from the script of
+// the text alone, since the script is mostly used by a single language.
+var scriptLanguages = []struct {
+	script   *unicode.RangeTable
+	language string
+}{
+	{unicode.Hiragana, "ja"},
+	{unicode.Katakana, "ja"},
+	{unicode.Hangul, "ko"},
+	{unicode.Han, "zh"},
+	{unicode.Thai, "th"},
+	{unicode.Hebrew, "he"},
+	{unicode.Arabic, "ar"},
+	{unicode.Greek, "el"},
+	{unicode.Devanagari, "hi"},
+	{unicode.Cyrillic, "ru"},
+}
+
+// commonWords is the most common words of languages that written in Latin
+// script, which used to tell them apart.
+var commonWords = map[string][]string{
+	"en": {"the", "and", "of", "to", "is", "in", "that", "it", "was", "for", "with", "as", "on", "are", "this", "be", "by", "have", "from", "not"},
+	"fr": {"le", "la", "les", "de", "des", "et", "est", "une", "un", "du", "que", "qui", "dans", "pour", "pas", "sur", "au", "avec", "il", "sont"},
+	"de": {"der", "die", "das", "und", "ist", "nicht", "ein", "eine", "zu", "den", "von", "mit", "sich", "des", "auf", "für", "ich", "dem", "auch", "wird"},
+	"es": {"el", "los", "las", "de", "que", "y", "en", "un", "una", "es", "por", "con", "para", "del", "se", "no", "como", "más", "pero", "su"},
+	"it": {"il", "di", "che", "e", "la", "per", "un", "una", "non", "sono", "del", "della", "con", "gli", "è", "nel", "anche", "come", "le", "si"},
+	"pt": {"o", "os", "as", "de", "que", "e", "do", "da", "em", "um", "uma", "para", "com", "não", "dos", "das", "por", "mais", "se", "é"},
+	"nl": {"de", "het", "een", "en", "van", "is", "dat", "niet", "op", "te", "zijn", "met", "voor", "die", "ook", "er", "maar", "aan", "wordt", "bij"},
+	"id": {"yang", "dan", "di", "ini", "itu", "dengan", "untuk", "tidak", "dari", "dalam", "akan", "pada", "juga", "ke", "adalah", "bisa", "ada", "kami", "oleh", "atau"},
+}
+
+// getMetaLanguage returns the language that specified in meta tags or
+// JSON-LD, normalized as BCP 47 tag.
+func (ps *Parser) getMetaLanguage(jsonLd map[string]string) string {
+	if language := jsonLd["inLanguage"]; language != "" {
+		return normalizeLanguageTag(language)
+	}
+
+	for _, meta := range dom.GetElementsByTagName(ps.doc, "meta") {
+		content := strings.TrimSpace(dom.GetAttribute(meta, "content"))
+		if content == "" {
+			continue
+		}
+
+		httpEquiv := strings.ToLower(dom.GetAttribute(meta, "http-equiv"))
+		name := strings.ToLower(dom.GetAttribute(meta, "name"))
+		property := strings.ToLower(dom.GetAttribute(meta, "property"))
+		if httpEquiv == "content-language" || name == "language" ||
+			name == "dc.language" || name == "dcterms.language" || property == "og:locale" {
+			// Content-Language may contain several languages
+			content = strings.TrimSpace(strings.Split(content, ",")[0])
+			return normalizeLanguageTag(content)
+		}
+	}
+
+	return ""
+}
+
+// normalizeLanguageTag converts locale like "en_US" into BCP 47 tag "en-US".
+func normalizeLanguageTag(tag string) string {
+	parts := strings.FieldsFunc(tag, func(r rune) bool { return r == '_' || r == '-' })
+	if len(parts) == 0 {
+		return ""
+	}
+
+	parts[0] = strings.ToLower(parts[0])
+	for i := 1; i < len(parts); i++ {
+		if len(parts[i]) == 2 {
+			parts[i] = strings.ToUpper(parts[i])
+		}
+	}
+	return strings.Join(parts, "-")
+}
+
+// detectLanguage guesses the language of the text. It first looks at the
+// dominant script of the text, then for Latin script, compares the words
+// with the common words of each language. Returns empty string if the
+// language can't be detected reliably.
+func detectLanguage(text string) string {
+	scriptCounts := make(map[string]int)
+	letters := 0
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+
+		letters++
+		for _, sl := range scriptLanguages {
+			if unicode.Is(sl.script, r) {
+				scriptCounts[sl.language]++
+				break
+			}
+		}
+	}
+
+	if letters == 0 {
+		return ""
+	}
+
+	// Japanese is written with mix of kana and kanji, so any kana
+	// means Japanese instead of Chinese.
+	if scriptCounts["ja"] > 0 && scriptCounts["ja"]+scriptCounts["zh"] > letters/2 {
+		return "ja"
+	}
+
+	for _, sl := range scriptLanguages {
+		if scriptCounts[sl.language] > letters/2 {
+			return sl.language
+		}
+	}
+
+	// Count the common words of each language
+	wordCounts := make(map[string]int)
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\''
+	})
+
+	lookup := make(map[string][]string)
+	for language, list := range commonWords {
+		for _, word := range list {
+			lookup[word] = append(lookup[word], language)
+		}
+	}
+
+	for _, word := range words {
+		for _, language := range lookup[word] {
+			wordCounts[language]++
+		}
+	}
+
+	bestLanguage, bestCount := "", 0
+	for language, count := range wordCounts {
+		if count > bestCount || (count == bestCount && language < bestLanguage) {
+			bestLanguage, bestCount = language, count
+		}
+	}
+
+	if bestCount < minDetectionWords {
+		return ""
+	}
+	return bestLanguage
+}
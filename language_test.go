@@ -0,0 +1,65 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_detectLanguage(t *testing.T) {
+	scenarios := map[string]string{
+		"":       "",
+		"12345":  "",
+		"Hello!": "",
+		"The quick brown fox jumps over the lazy dog, and it is known that this is the best test of the keyboard.": "en",
+		"Le renard brun saute par-dessus le chien paresseux, et il est connu que c'est une phrase pour les tests.": "fr",
+		"Der schnelle braune Fuchs springt über den faulen Hund, und das ist nicht die erste Version des Satzes.":  "de",
+		"El rápido zorro marrón salta sobre el perro perezoso, y es una frase que se usa para las pruebas.":        "es",
+		"这是一个中文句子，用于测试语言检测。":                                                                                       "zh",
+		"これは日本語の文章で、言語の検出をテストするためのものです。":                                                                           "ja",
+		"이것은 언어 감지를 테스트하기 위한 한국어 문장입니다.":                                                                           "ko",
+		"Это русское предложение для проверки определения языка.":                                                  "ru",
+		"هذه جملة عربية لاختبار اكتشاف اللغة.":                                                                     "ar",
+	}
+
+	for text, expected := range scenarios {
+		if language := detectLanguage(text); language != expected {
+			t.Errorf("\n"+
+				"text : %q\n"+
+				"want : %q\n"+
+				"got  : %q", text, expected, language)
+		}
+	}
+}
+
+func Test_Parser_DetectLanguage(t *testing.T) {
+	paragraph := articleParagraph(10)
+	article := "<body><article>" + paragraph + paragraph + "</article></body>"
+
+	scenarios := []struct {
+		html     string
+		detect   bool
+		expected string
+	}{
+		{`<html>` + article + `</html>`, false, ""},
+		{`<html>` + article + `</html>`, true, "en"},
+		{`<html lang="en-GB">` + article + `</html>`, true, "en-GB"},
+		{`<html><head><meta property="og:locale" content="id_ID"></head>` + article + `</html>`, true, "id-ID"},
+		{`<html><head><meta http-equiv="Content-Language" content="fr, en"></head>` + article + `</html>`, true, "fr"},
+		{`<html><head><script type="application/ld+json">{"@context": "https://schema.org", "@type": "Article", "inLanguage": "de"}</script></head>` + article + `</html>`, true, "de"},
+	}
+
+	for _, scenario := range scenarios {
+		ps := NewParser(WithDetectLanguage(scenario.detect))
+		result, err := ps.Parse(strings.NewReader(scenario.html), fakeHostURL)
+		if err != nil {
+			t.Fatalf("failed to parse: %v", err)
+		}
+
+		if result.Language != scenario.expected {
+			t.Errorf("\n"+
+				"html : %q\n"+
+				"want : %q\n"+
+				"got  : %q", scenario.html[:80], scenario.expected, result.Language)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package readability
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/gogs/chardet"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/charset"
+	xunicode "golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// DefaultMaxElementDepth is the default max depth of nested elements. It's
+// far deeper than what the real pages need, while it still keeps the
+// recursive walks over the document, e.g. while sanitizing or rendering
+// the content, well within the stack.
+const DefaultMaxElementDepth = 512
+
+// checkDocumentLimits checks the number of elements and the depth of nested
+// elements in the document, which are not checked when the max is zero. It
+// walks the document without recursion, and stops as soon as any limit is
+// exceeded.
+func checkDocumentLimits(doc *html.Node, maxElems, maxDepth int) error {
+	count, depth := 0, 0
+	for node := doc; node != nil; {
+		if node.Type == html.ElementNode {
+			count++
+			if maxElems > 0 && count > maxElems {
+				return fmt.Errorf("%w: more than %d elements", ErrTooManyElements, maxElems)
+			}
+
+			if maxDepth > 0 && depth > maxDepth {
+				return fmt.Errorf("%w: more than %d levels", ErrTooDeep, maxDepth)
+			}
+		}
+
+		// Move to the next node in depth-first order
+		if node.FirstChild != nil {
+			node = node.FirstChild
+			depth++
+			continue
+		}
+
+		for node != doc && node.NextSibling == nil {
+			node = node.Parent
+			depth--
+		}
+
+		if node == doc {
+			break
+		}
+		node = node.NextSibling
+	}
+	return nil
+}
+
+// limitParseDuration returns the copy of ctx that's done once the parse
+// exceeds MaxParseDuration, along with the function that must be called
+// with the result of parse. The function releases the context, and
+// replaces the error with ErrParseTimeout if it's caused by the limit.
+func (ps *Parser) limitParseDuration(ctx context.Context) (context.Context, func(error) error) {
+	if ps.MaxParseDuration <= 0 {
+		return ctx, func(err error) error { return err }
+	}
+
+	parent := ctx
+	ctx, cancel := context.WithTimeout(parent, ps.MaxParseDuration)
+	return ctx, func(err error) error {
+		defer cancel()
+		if err != nil && ctx.Err() != nil && parent.Err() == nil {
+			return fmt.Errorf("%w: longer than %v", ErrParseTimeout, ps.MaxParseDuration)
+		}
+		return err
+	}
+}
+
+// parseInput parses the input the same way as dom.Parse, but the HTML
+// parser reads the input through ctx, so it's aborted once ctx is done.
+// It matters since the HTML parser may take quadratic time for the
+// deeply nested elements.
+func parseInput(ctx context.Context, input io.Reader) (*html.Node, error) {
+	content, err := io.ReadAll(input)
+	if err != nil {
+		return nil, err
+	}
+	return parseContent(ctx, content)
+}
+
+// parseContent is like parseInput, but the whole input is already read
+// into content, which is never modified.
+func parseContent(ctx context.Context, content []byte) (*html.Node, error) {
+	// Detect page encoding
+	res, err := chardet.NewHtmlDetector().DetectBest(content)
+	if err != nil {
+		return nil, err
+	}
+
+	pageEncoding, _ := charset.Lookup(res.Charset)
+	if pageEncoding == nil {
+		pageEncoding = xunicode.UTF8
+	}
+
+	// Convert to UTF-8 in NFC, and remove the soft hyphens
+	softHyphen := runes.Predicate(func(r rune) bool { return r == '\u00AD' })
+	normalizer := transform.Chain(norm.NFD, runes.Remove(softHyphen), norm.NFC)
+
+	var r io.Reader = bytes.NewReader(content)
+	r = transform.NewReader(r, pageEncoding.NewDecoder())
+	r = transform.NewReader(r, normalizer)
+	return html.Parse(contextReader{ctx: ctx, reader: r})
+}
+
+// contextReader is the reader that fails with the error of ctx once it's
+// done.
+type contextReader struct {
+	ctx    context.Context
+	reader io.Reader
+}
+
+func (r contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.reader.Read(p)
+}
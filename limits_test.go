@@ -0,0 +1,95 @@
+package readability
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-shiori/dom"
+)
+
+func Test_checkDocumentLimits(t *testing.T) {
+	// The document has html, head, body and 3 nested div, so the deepest
+	// div is in the 5th level
+	input := "<html><body>" + strings.Repeat("<div>", 3) + "text" + strings.Repeat("</div>", 3) + "</body></html>"
+	scenarios := []struct {
+		maxElems int
+		maxDepth int
+		expected error
+	}{
+		{0, 0, nil},
+		{6, 5, nil},
+		{5, 0, ErrTooManyElements},
+		{0, 4, ErrTooDeep},
+	}
+
+	doc, err := dom.FastParse(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("failed to parse input: %v", err)
+	}
+
+	for _, scenario := range scenarios {
+		err := checkDocumentLimits(doc, scenario.maxElems, scenario.maxDepth)
+		if !errors.Is(err, scenario.expected) {
+			t.Errorf("max elems %d depth %d, want %v got %v",
+				scenario.maxElems, scenario.maxDepth, scenario.expected, err)
+		}
+	}
+}
+
+func Test_Parser_MaxElementDepth(t *testing.T) {
+	paragraph := articleParagraph(10)
+	nested := strings.Repeat("<div>", 600) + paragraph + strings.Repeat("</div>", 600)
+
+	doc, err := dom.FastParse(strings.NewReader(nested))
+	if err != nil {
+		t.Fatalf("failed to parse input: %v", err)
+	}
+
+	scenarios := map[int]bool{
+		DefaultMaxElementDepth: true,
+		700:                    false,
+		0:                      false,
+	}
+
+	for max, expectError := range scenarios {
+		ps := NewParser(WithMaxElementDepth(max))
+		_, err := ps.Parse(strings.NewReader(nested), fakeHostURL)
+		if tooDeep := errors.Is(err, ErrTooDeep); tooDeep != expectError {
+			t.Errorf("max %d, want too deep %v got error %v", max, expectError, err)
+		}
+
+		_, err = ps.Candidates(doc)
+		if tooDeep := errors.Is(err, ErrTooDeep); tooDeep != expectError {
+			t.Errorf("candidates with max %d, want too deep %v got error %v", max, expectError, err)
+		}
+	}
+}
+
+func Test_Parser_MaxParseDuration(t *testing.T) {
+	// The HTML parser takes quadratic time for the deeply nested elements,
+	// so the limit must also apply while parsing the input
+	input := strings.Repeat("<div>", 50000) + "text"
+	ps := NewParser(WithMaxElementDepth(0), WithMaxParseDuration(50*time.Millisecond))
+
+	start := time.Now()
+	_, err := ps.Parse(strings.NewReader(input), fakeHostURL)
+	if !errors.Is(err, ErrParseTimeout) {
+		t.Errorf("want %v got %v", ErrParseTimeout, err)
+	}
+
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("parse is not aborted, it takes %v", elapsed)
+	}
+
+	// Error from the context of caller is kept as it is
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = ps.ParseWithContext(ctx, strings.NewReader(input), fakeHostURL)
+	if !errors.Is(err, context.Canceled) || errors.Is(err, ErrParseTimeout) {
+		t.Errorf("want %v got %v", context.Canceled, err)
+	}
+}
@@ -0,0 +1,119 @@
+package readability
+
+import (
+	nurl "net/url"
+	"regexp"
+	"strings"
+
+	"github.com/go-shiori/dom"
+	"golang.org/x/net/html"
+)
+
+// Contexts of the links in the page.
+const (
+	LinkContextContent    = "content"
+	LinkContextRelated    = "related"
+	LinkContextNavigation = "navigation"
+	LinkContextSidebar    = "sidebar"
+	LinkContextFooter     = "footer"
+	LinkContextOther      = "other"
+)
+
+var (
+	rxRelatedLinks    = regexp.MustCompile(`(?i)related|recommend|read-?next|more-?stories|you-?may|also-?like|popular|trending|outbrain|taboola`)
+	rxNavigationLinks = regexp.MustCompile(`(?i)(^|[\s_-])(nav|navbar|navigation|menu|breadcrumbs?|pagination|pager)($|[\s_-])`)
+	rxSidebarLinks    = regexp.MustCompile(`(?i)sidebar|widget`)
+	rxFooterLinks     = regexp.MustCompile(`(?i)footer|colophon`)
+)
+
+// LinkInfo is a link in the page. InContent marks the link that kept in
+// the article content, e.g. citation, while Context tells where the link
+// is found in the page.
+type LinkInfo struct {
+	URL        string `json:"url"`
+	AnchorText string `json:"anchor_text"`
+	Rel        string `json:"rel,omitempty"`
+	InContent  bool   `json:"in_content"`
+	Context    string `json:"context"`
+}
+
+// getLinks returns the links in the document, in document order. Each URL
+// is only listed once, using its first occurrence in the article content
+// or the page. It must be called after the URLs in article content are
+// converted to absolute.
+func (ps *Parser) getLinks(articleContent *html.Node) []LinkInfo {
+	contentLinks := make(map[string]*html.Node)
+	if articleContent != nil {
+		for _, a := range dom.QuerySelectorAll(articleContent, "a[href]") {
+			if href := dom.GetAttribute(a, "href"); contentLinks[href] == nil {
+				contentLinks[href] = a
+			}
+		}
+	}
+
+	var links []LinkInfo
+	indexes := make(map[string]int)
+	for _, a := range dom.QuerySelectorAll(ps.doc, "a[href]") {
+		href := strings.TrimSpace(dom.GetAttribute(a, "href"))
+		if href == "" || strings.HasPrefix(href, "#") {
+			continue
+		}
+
+		linkURL, err := nurl.Parse(toAbsoluteURI(href, ps.documentURI))
+		if err != nil || (linkURL.Scheme != "http" && linkURL.Scheme != "https") {
+			continue
+		}
+
+		absoluteURL := linkURL.String()
+		_, inContent := contentLinks[absoluteURL]
+		link := LinkInfo{
+			URL:        absoluteURL,
+			AnchorText: strings.Join(strings.Fields(dom.TextContent(a)), " "),
+			Rel:        strings.TrimSpace(dom.GetAttribute(a, "rel")),
+			InContent:  inContent,
+			Context:    linkContext(a),
+		}
+
+		if inContent {
+			link.Context = LinkContextContent
+		}
+
+		// Prefer the occurrence that has anchor text
+		if i, exist := indexes[absoluteURL]; exist {
+			if links[i].AnchorText == "" {
+				links[i].AnchorText = link.AnchorText
+			}
+			continue
+		}
+
+		indexes[absoluteURL] = len(links)
+		links = append(links, link)
+	}
+
+	return links
+}
+
+// linkContext returns where the link is found in the page, based on the
+// tag, role, class and id of its ancestors.
+func linkContext(link *html.Node) string {
+	for node := link.Parent; node != nil; node = node.Parent {
+		if node.Type != html.ElementNode {
+			continue
+		}
+
+		matchString := dom.ClassName(node) + " " + dom.ID(node)
+		tagName := dom.TagName(node)
+		role := dom.GetAttribute(node, "role")
+		switch {
+		case rxRelatedLinks.MatchString(matchString):
+			return LinkContextRelated
+		case tagName == "nav" || role == "navigation" || rxNavigationLinks.MatchString(matchString):
+			return LinkContextNavigation
+		case tagName == "aside" || role == "complementary" || rxSidebarLinks.MatchString(matchString):
+			return LinkContextSidebar
+		case tagName == "footer" || role == "contentinfo" || rxFooterLinks.MatchString(matchString):
+			return LinkContextFooter
+		}
+	}
+	return LinkContextOther
+}
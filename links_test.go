@@ -0,0 +1,50 @@
+package readability
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_Parser_Links(t *testing.T) {
+	paragraph := articleParagraph(10)
+	input := `<html><body>` +
+		`<nav><a href="/">Home</a> <a href="/about">About</a></nav>` +
+		`<article>` + paragraph +
+		`<p>According to <a href="https://example.com/study" rel="nofollow">the study</a>, it works.</p>` +
+		paragraph + `</article>` +
+		`<div class="related-posts"><a href="/other-post">Other post</a></div>` +
+		`<footer><a href="/privacy">Privacy</a> <a href="#top">Top</a> <a href="mailto:me@fakehost">Mail</a></footer>` +
+		`<a href="/about"><img src="/about.png"></a>` +
+		`</body></html>`
+
+	// Links are not listed by default
+	article, err := FromReader(strings.NewReader(input), fakeHostURL)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	if article.Links != nil {
+		t.Errorf("links should be nil, got %+v", article.Links)
+	}
+
+	ps := NewParser(WithLinks(true))
+	article, err = ps.Parse(strings.NewReader(input), fakeHostURL)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	expected := []LinkInfo{
+		{URL: "http://fakehost/", AnchorText: "Home", Context: LinkContextNavigation},
+		{URL: "http://fakehost/about", AnchorText: "About", Context: LinkContextNavigation},
+		{URL: "https://example.com/study", AnchorText: "the study", Rel: "nofollow", InContent: true, Context: LinkContextContent},
+		{URL: "http://fakehost/other-post", AnchorText: "Other post", Context: LinkContextRelated},
+		{URL: "http://fakehost/privacy", AnchorText: "Privacy", Context: LinkContextFooter},
+	}
+
+	if !reflect.DeepEqual(article.Links, expected) {
+		t.Errorf("\n"+
+			"want : %+v\n"+
+			"got  : %+v", expected, article.Links)
+	}
+}
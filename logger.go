@@ -0,0 +1,68 @@
+package readability
+
+import (
+	"fmt"
+	"log"
+	"strings"
+)
+
+// Logger is the interface for the structured, leveled logging of the decisions
+// that made by the parser. The args are alternating keys and values, so
+// *slog.Logger can be used directly as Logger.
+type Logger interface {
+	Debug(msg string, args ...interface{})
+	Info(msg string, args ...interface{})
+	Warn(msg string, args ...interface{})
+}
+
+// stdLogger is the logger that used in debug mode when there are no
+// logger specified. It prints all levels using the standard logger.
+type stdLogger struct{}
+
+func (stdLogger) Debug(msg string, args ...interface{}) { stdLog("DEBUG", msg, args) }
+func (stdLogger) Info(msg string, args ...interface{})  { stdLog("INFO", msg, args) }
+func (stdLogger) Warn(msg string, args ...interface{})  { stdLog("WARN", msg, args) }
+
+func stdLog(level, msg string, args []interface{}) {
+	var sb strings.Builder
+	sb.WriteString(level + " " + msg)
+	for i := 0; i < len(args); i += 2 {
+		if i+1 < len(args) {
+			fmt.Fprintf(&sb, " %v=%q", args[i], fmt.Sprint(args[i+1]))
+		} else {
+			fmt.Fprintf(&sb, " %q", fmt.Sprint(args[i]))
+		}
+	}
+	log.Println(sb.String())
+}
+
+// logger returns the logger that used by parser, or nil if logging is
+// disabled.
+func (ps *Parser) logger() Logger {
+	switch {
+	case ps.Logger != nil:
+		return ps.Logger
+	case ps.Debug:
+		return stdLogger{}
+	default:
+		return nil
+	}
+}
+
+func (ps *Parser) logDebug(msg string, args ...interface{}) {
+	if logger := ps.logger(); logger != nil {
+		logger.Debug(msg, args...)
+	}
+}
+
+func (ps *Parser) logInfo(msg string, args ...interface{}) {
+	if logger := ps.logger(); logger != nil {
+		logger.Info(msg, args...)
+	}
+}
+
+func (ps *Parser) logWarn(msg string, args ...interface{}) {
+	if logger := ps.logger(); logger != nil {
+		logger.Warn(msg, args...)
+	}
+}
@@ -0,0 +1,44 @@
+package readability
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type testLogger struct {
+	records []string
+}
+
+func (l *testLogger) record(level, msg string, args []interface{}) {
+	l.records = append(l.records, fmt.Sprint(level, " ", msg, " ", args))
+}
+
+func (l *testLogger) Debug(msg string, args ...interface{}) { l.record("DEBUG", msg, args) }
+func (l *testLogger) Info(msg string, args ...interface{})  { l.record("INFO", msg, args) }
+func (l *testLogger) Warn(msg string, args ...interface{})  { l.record("WARN", msg, args) }
+
+func Test_Parser_Logger(t *testing.T) {
+	paragraph := articleParagraph(10)
+	input := `<html><head><script type="application/ld+json">{invalid</script></head>` +
+		`<body><div class="comments">Comments</div>` + paragraph + paragraph + `</body></html>`
+
+	logger := &testLogger{}
+	ps := NewParser(WithLogger(logger))
+	if _, err := ps.Parse(strings.NewReader(input), fakeHostURL); err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	expected := []string{
+		"WARN failed to decode JSON-LD",
+		"DEBUG removing unlikely candidate [match comments ]",
+		"INFO article grabbed [attempt 1",
+	}
+
+	logs := strings.Join(logger.records, "\n")
+	for _, record := range expected {
+		if !strings.Contains(logs, record) {
+			t.Errorf("log %q not found in:\n%s", record, logs)
+		}
+	}
+}
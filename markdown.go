@@ -0,0 +1,366 @@
+package readability
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/go-shiori/dom"
+	"golang.org/x/net/html"
+)
+
+var (
+	rxMarkdownEscape     = regexp.MustCompile("([\\\\`*_\\[\\]<>])")
+	rxMarkdownLineStart  = regexp.MustCompile(`(?m)^(\s*)([#+\-=]|\d+[.)])(\s|$)`)
+	rxRenderSpaces       = regexp.MustCompile(`[ \t\r\n\f]+`)
+	rxMarkdownBacktick   = regexp.MustCompile("`+")
+	rxCodeLanguage       = regexp.MustCompile(`(?i)(?:^|\s)(?:lang|language)-(\S+)`)
+	rxMarkdownURLUnsafe  = regexp.MustCompile(`[\s()<>]`)
+	renderBlockElems     = sliceToMap("address", "article", "aside", "blockquote", "dd", "details", "dialog", "div", "dl", "dt", "fieldset", "figcaption", "figure", "footer", "form", "h1", "h2", "h3", "h4", "h5", "h6", "header", "hr", "li", "main", "nav", "ol", "p", "pre", "section", "summary", "table", "ul")
+	markdownRawHTMLElems = sliceToMap("table", "iframe", "video", "audio", "object", "embed", "svg", "math")
+)
+
+// Markdown renders the content of the article as CommonMark. Headings,
+// links, images, code blocks, blockquotes and lists are converted to
+// their Markdown counterparts, while elements that can't be expressed
+// in Markdown (e.g. table and embedded video) are kept as raw HTML.
+func (article Article) Markdown() string {
+	root := article.ContentNode()
+	if root == nil {
+		return ""
+	}
+
+	var mr markdownRenderer
+	return strings.Join(mr.blocks(root), "\n\n")
+}
+
+// markdownRenderer converts HTML node into CommonMark.
+type markdownRenderer struct{}
+
+// blocks renders the children of node as a list of Markdown blocks.
+// Phrasing content that located between block elements is wrapped
+// into its own paragraph.
+func (mr *markdownRenderer) blocks(node *html.Node) []string {
+	var blocks []string
+	var inlines strings.Builder
+
+	flushInlines := func() {
+		paragraph := mr.cleanInline(inlines.String())
+		if paragraph != "" {
+			blocks = append(blocks, paragraph)
+		}
+		inlines.Reset()
+	}
+
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		if !mr.isBlock(child) {
+			inlines.WriteString(mr.inline(child))
+			continue
+		}
+
+		flushInlines()
+		if block := mr.block(child); block != "" {
+			blocks = append(blocks, block)
+		}
+	}
+
+	flushInlines()
+	return blocks
+}
+
+// block renders a single block element.
+func (mr *markdownRenderer) block(node *html.Node) string {
+	tagName := dom.TagName(node)
+	if _, isRaw := markdownRawHTMLElems[tagName]; isRaw {
+		return mr.rawHTML(node)
+	}
+
+	switch tagName {
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		text := mr.cleanInline(mr.inlineChildren(node))
+		if text == "" {
+			return ""
+		}
+		text = strings.ReplaceAll(text, "\\\n", " ")
+		return strings.Repeat("#", int(tagName[1]-'0')) + " " + text
+
+	case "p", "dt", "summary", "figcaption":
+		return mr.cleanInline(mr.inlineChildren(node))
+
+	case "hr":
+		return "---"
+
+	case "pre":
+		return mr.codeBlock(node)
+
+	case "blockquote":
+		content := strings.Join(mr.blocks(node), "\n\n")
+		if content == "" {
+			return ""
+		}
+		return prefixLines(content, "> ", "> ")
+
+	case "ul", "ol":
+		return mr.list(node)
+
+	case "dd":
+		content := strings.Join(mr.blocks(node), "\n\n")
+		return prefixLines(content, "    ", "    ")
+	}
+
+	return strings.Join(mr.blocks(node), "\n\n")
+}
+
+// list renders <ul> or <ol> element. The list is rendered tightly
+// unless one of its item contains more than one block.
+func (mr *markdownRenderer) list(node *html.Node) string {
+	ordered := dom.TagName(node) == "ol"
+	number := 1
+	if ordered {
+		fmt.Sscanf(dom.GetAttribute(node, "start"), "%d", &number)
+	}
+
+	loose := false
+	var items []string
+	for _, child := range dom.Children(node) {
+		if dom.TagName(child) != "li" {
+			continue
+		}
+
+		marker := "- "
+		if ordered {
+			marker = fmt.Sprintf("%d. ", number)
+			number++
+		}
+
+		// Nested list doesn't make the item loose
+		blocks := mr.blocks(child)
+		nestedLists := 0
+		for _, grandChild := range dom.Children(child) {
+			if tag := dom.TagName(grandChild); tag == "ul" || tag == "ol" {
+				nestedLists++
+			}
+		}
+
+		itemLoose := len(blocks)-nestedLists > 1
+		loose = loose || itemLoose
+
+		separator := "\n"
+		if itemLoose {
+			separator = "\n\n"
+		}
+
+		content := strings.Join(blocks, separator)
+		indent := strings.Repeat(" ", len(marker))
+		items = append(items, prefixLines(content, marker, indent))
+	}
+
+	if loose {
+		return strings.Join(items, "\n\n")
+	}
+	return strings.Join(items, "\n")
+}
+
+// codeBlock renders <pre> element as fenced code block. The language
+// of the code is taken from "language-*" class of <pre> or <code>.
+func (mr *markdownRenderer) codeBlock(node *html.Node) string {
+	code := strings.TrimSuffix(dom.TextContent(node), "\n")
+	code = strings.TrimPrefix(code, "\n")
+
+	language := ""
+	codeNode := node
+	if codes := dom.GetElementsByTagName(node, "code"); len(codes) == 1 {
+		codeNode = codes[0]
+	}
+
+	for _, n := range []*html.Node{codeNode, node} {
+		if parts := rxCodeLanguage.FindStringSubmatch(dom.ClassName(n)); len(parts) == 2 {
+			language = parts[1]
+			break
+		}
+	}
+
+	// The fence must be longer than any backtick run inside the code
+	fenceLength := 3
+	for _, run := range rxMarkdownBacktick.FindAllString(code, -1) {
+		if len(run) >= fenceLength {
+			fenceLength = len(run) + 1
+		}
+	}
+
+	fence := strings.Repeat("`", fenceLength)
+	return fence + language + "\n" + code + "\n" + fence
+}
+
+// rawHTML renders node as raw HTML block. Blank lines are removed
+// since in CommonMark they end the HTML block.
+func (mr *markdownRenderer) rawHTML(node *html.Node) string {
+	var lines []string
+	for _, line := range strings.Split(dom.OuterHTML(node), "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// inline renders a phrasing node.
+func (mr *markdownRenderer) inline(node *html.Node) string {
+	switch node.Type {
+	case html.TextNode:
+		return mr.escape(rxRenderSpaces.ReplaceAllString(node.Data, " "))
+	case html.ElementNode:
+	default:
+		return ""
+	}
+
+	tagName := dom.TagName(node)
+	if _, isRaw := markdownRawHTMLElems[tagName]; isRaw {
+		return dom.OuterHTML(node)
+	}
+
+	switch tagName {
+	case "br":
+		return "\\\n"
+
+	case "img":
+		src := dom.GetAttribute(node, "src")
+		if src == "" {
+			return ""
+		}
+		alt := mr.escape(rxRenderSpaces.ReplaceAllString(dom.GetAttribute(node, "alt"), " "))
+		return "![" + alt + "](" + mr.destination(src, dom.GetAttribute(node, "title")) + ")"
+
+	case "a":
+		text := mr.inlineChildren(node)
+		href := dom.GetAttribute(node, "href")
+		if href == "" || strings.TrimSpace(text) == "" {
+			return text
+		}
+		return mr.wrap(text, "[", "]("+mr.destination(href, dom.GetAttribute(node, "title"))+")")
+
+	case "strong", "b":
+		return mr.wrap(mr.inlineChildren(node), "**", "**")
+
+	case "em", "i", "cite":
+		return mr.wrap(mr.inlineChildren(node), "*", "*")
+
+	case "code", "kbd", "samp", "tt":
+		return mr.codeSpan(dom.TextContent(node))
+	}
+
+	return mr.inlineChildren(node)
+}
+
+// inlineChildren renders all children of node as phrasing content.
+func (mr *markdownRenderer) inlineChildren(node *html.Node) string {
+	var sb strings.Builder
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		sb.WriteString(mr.inline(child))
+	}
+	return sb.String()
+}
+
+// wrap surrounds text with prefix and suffix. Leading and trailing
+// whitespaces are moved outside, since emphasis delimiter that is
+// followed by whitespace is not recognized by CommonMark.
+func (mr *markdownRenderer) wrap(text, prefix, suffix string) string {
+	trimmed := strings.TrimSpace(text)
+	if trimmed == "" {
+		return text
+	}
+
+	leading := text[:strings.Index(text, trimmed)]
+	trailing := text[len(leading)+len(trimmed):]
+	return leading + prefix + trimmed + suffix + trailing
+}
+
+// codeSpan renders code as inline code span.
+func (mr *markdownRenderer) codeSpan(code string) string {
+	code = rxRenderSpaces.ReplaceAllString(code, " ")
+	if strings.TrimSpace(code) == "" {
+		return code
+	}
+
+	fenceLength := 1
+	for _, run := range rxMarkdownBacktick.FindAllString(code, -1) {
+		if len(run) >= fenceLength {
+			fenceLength = len(run) + 1
+		}
+	}
+
+	if strings.HasPrefix(code, "`") || strings.HasSuffix(code, "`") {
+		code = " " + code + " "
+	}
+
+	fence := strings.Repeat("`", fenceLength)
+	return fence + code + fence
+}
+
+// destination formats url and its optional title as link destination.
+func (mr *markdownRenderer) destination(url, title string) string {
+	if rxMarkdownURLUnsafe.MatchString(url) {
+		url = "<" + strings.NewReplacer("<", "%3C", ">", "%3E", "\n", "").Replace(url) + ">"
+	}
+
+	if title = strings.TrimSpace(title); title != "" {
+		url += ` "` + strings.ReplaceAll(title, `"`, `\"`) + `"`
+	}
+
+	return url
+}
+
+// escape escapes characters that have special meaning in Markdown.
+func (mr *markdownRenderer) escape(text string) string {
+	return rxMarkdownEscape.ReplaceAllString(text, `\$1`)
+}
+
+// cleanInline normalizes the rendered phrasing content: removes the
+// excess whitespaces around line breaks, and escapes characters that
+// will be treated as block marker at the start of a line.
+func (mr *markdownRenderer) cleanInline(text string) string {
+	lines := strings.Split(text, "\n")
+	for i := range lines {
+		lines[i] = strings.TrimSpace(lines[i])
+	}
+
+	text = strings.Join(lines, "\n")
+	text = strings.TrimSuffix(strings.TrimSpace(text), "\\")
+	text = strings.TrimSpace(text)
+	return rxMarkdownLineStart.ReplaceAllStringFunc(text, func(s string) string {
+		// Backslash only escapes punctuation, so for ordered list marker
+		// the escape is put before the dot or parenthesis.
+		idx := strings.IndexAny(s, "#+-=.)")
+		return s[:idx] + "\\" + s[idx:]
+	})
+}
+
+// isBlock determines if node should be rendered as Markdown block.
+func (mr *markdownRenderer) isBlock(node *html.Node) bool {
+	if node.Type != html.ElementNode {
+		return false
+	}
+
+	_, isBlock := renderBlockElems[dom.TagName(node)]
+	return isBlock
+}
+
+// prefixLines prefixes the first line of text with firstPrefix, and the
+// rest of the lines with prefix. Empty lines are only prefixed with the
+// trailing-space-trimmed prefix.
+func prefixLines(text, firstPrefix, prefix string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		p := prefix
+		if i == 0 {
+			p = firstPrefix
+		}
+
+		if line == "" {
+			lines[i] = strings.TrimRight(p, " ")
+		} else {
+			lines[i] = p + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,45 @@
+package readability
+
+import (
+	"testing"
+)
+
+func Test_Article_Markdown(t *testing.T) {
+	scenarios := map[string]string{
+		`<h2>Title</h2><p>Hello <b>bold</b> and <em>italic </em>world.</p>`: "" +
+			"## Title\n\n" +
+			"Hello **bold** and *italic* world.",
+		`<p>Visit <a href="http://example.com/a b" title="Ex">the site</a>.</p>`: "" +
+			"Visit [the site](<http://example.com/a b> \"Ex\").",
+		`<p><img src="http://example.com/a.png" alt="An image"></p>`: "" +
+			"![An image](http://example.com/a.png)",
+		`<ul><li>One</li><li>Two<ol><li>Nested</li></ol></li></ul>`: "" +
+			"- One\n" +
+			"- Two\n" +
+			"  1. Nested",
+		`<blockquote><p>Quoted</p><p>Twice</p></blockquote>`: "" +
+			"> Quoted\n" +
+			">\n" +
+			"> Twice",
+		"<pre><code class=\"language-go\">fmt.Println(\"```\")\n</code></pre>": "" +
+			"````go\n" +
+			"fmt.Println(\"```\")\n" +
+			"````",
+		`<p>Use <code>a*b</code> not a*b.</p>`: "" +
+			"Use `a*b` not a\\*b.",
+		`<p>1. not a list</p><p>First<br>Second</p>`: "" +
+			"1\\. not a list\n\n" +
+			"First\\\n" +
+			"Second",
+	}
+
+	for content, expected := range scenarios {
+		article := Article{Content: content}
+		if result := article.Markdown(); result != expected {
+			t.Errorf("\n"+
+				"html : %q\n"+
+				"want : %q\n"+
+				"got  : %q", content, expected, result)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package readability
+
+import (
+	"strings"
+
+	"github.com/go-shiori/dom"
+	"golang.org/x/net/html"
+)
+
+// mathTeXClass is the class of element that contains the TeX source of
+// the math that converted by parser.
+const mathTeXClass = "math-tex"
+
+// convertMath converts the math that rendered by MathJax or KaTeX into
+// its TeX source, which wrapped in element with class "math-tex". The
+// inline math is delimited by \( and \), while the display math is
+// delimited by \[ and \]. MathML nodes are kept as it is.
+func (ps *Parser) convertMath(doc *html.Node) {
+	// MathJax v2 keeps the TeX source in script tag, right after the
+	// rendered output which is useless without its style.
+	mathScripts := dom.QuerySelectorAll(doc, `script[type^="math/tex"]`)
+	ps.forEachNode(mathScripts, func(script *html.Node, _ int) {
+		display := strings.Contains(dom.GetAttribute(script, "type"), "mode=display")
+		for i := 0; i < 2; i++ {
+			prev := dom.PreviousElementSibling(script)
+			if prev == nil || !strings.HasPrefix(dom.ClassName(prev), "MathJax") {
+				break
+			}
+			prev.Parent.RemoveChild(prev)
+		}
+		ps.replaceWithTeX(script, dom.TextContent(script), display)
+	})
+
+	// KaTeX and MathJax v3 keep the TeX source as annotation in MathML
+	ps.forEachNode(dom.QuerySelectorAll(doc, ".katex-display, .katex, mjx-container"), func(container *html.Node, _ int) {
+		if container.Parent == nil {
+			return
+		}
+
+		annotation := dom.QuerySelector(container, `annotation[encoding="application/x-tex"]`)
+		if annotation != nil {
+			display := indexOf(strings.Fields(dom.ClassName(container)), "katex-display") != -1 || dom.GetAttribute(container, "display") == "true"
+			ps.replaceWithTeX(container, dom.TextContent(annotation), display)
+			return
+		}
+
+		// If there are no TeX source, just use the MathML
+		if math := dom.QuerySelector(container, "math"); math != nil {
+			dom.ReplaceChild(container.Parent, dom.Clone(math, true), container)
+		}
+	})
+}
+
+// replaceWithTeX replaces node with element that contains the TeX source.
+func (ps *Parser) replaceWithTeX(node *html.Node, tex string, display bool) {
+	tex = strings.TrimSpace(tex)
+	if node.Parent == nil || tex == "" {
+		return
+	}
+
+	// Display math is put in paragraph, unless it's inside one already.
+	// Div is not used here since later it will be converted to paragraph
+	// anyway, without its class.
+	tagName, text := "span", `\(`+tex+`\)`
+	if display {
+		text = `\[` + tex + `\]`
+		if parentTag := dom.TagName(node.Parent); parentTag != "p" && !ps.isPhrasingContent(node.Parent) {
+			tagName = "p"
+		}
+	}
+
+	elem := dom.CreateElement(tagName)
+	dom.SetAttribute(elem, "class", mathTeXClass)
+	dom.AppendChild(elem, dom.CreateTextNode(text))
+	dom.ReplaceChild(node.Parent, elem, node)
+}
@@ -0,0 +1,35 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_Parser_PreserveMath(t *testing.T) {
+	paragraph := articleParagraph(10)
+	scenarios := map[string]string{
+		`<p>Inline <span class="MathJax_Preview"></span><span class="MathJax">rendered</span>` +
+			`<script type="math/tex">x^2</script> math.</p>`: `<p>Inline <span class="math-tex">\(x^2\)</span> math.</p>`,
+		`<div class="MathJax_Display">rendered</div><script type="math/tex; mode=display">\sum_{i=1}^n i</script>`: `<p class="math-tex">\[\sum_{i=1}^n i\]</p>`,
+		`<p>KaTeX <span class="katex"><span class="katex-mathml"><math><semantics><mi>y</mi>` +
+			`<annotation encoding="application/x-tex">y</annotation></semantics></math></span>` +
+			`<span class="katex-html" aria-hidden="true">y</span></span> math.</p>`: `<p>KaTeX <span class="math-tex">\(y\)</span> math.</p>`,
+		`<p>MathML <math><mi>z</mi></math> math.</p>`: `<p>MathML <math><mi>z</mi></math> math.</p>`,
+	}
+
+	for input, expected := range scenarios {
+		html := "<html><body><article>" + paragraph + input + paragraph + "</article></body></html>"
+		ps := NewParser(WithPreserveMath(true))
+		article, err := ps.Parse(strings.NewReader(html), fakeHostURL)
+		if err != nil {
+			t.Fatalf("failed to parse %q: %v", input, err)
+		}
+
+		if !strings.Contains(article.Content, expected) {
+			t.Errorf("\n"+
+				"html : %q\n"+
+				"want : %q\n"+
+				"got  : %q", input, expected, article.Content)
+		}
+	}
+}
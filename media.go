@@ -0,0 +1,83 @@
+package readability
+
+import (
+	"github.com/go-shiori/dom"
+	"golang.org/x/net/html"
+)
+
+// Media is an audio or video that found in the article content.
+type Media struct {
+	Type    string        `json:"type"`
+	Src     string        `json:"src"`
+	Poster  string        `json:"poster"`
+	Sources []MediaSource `json:"sources"`
+	Tracks  []MediaTrack  `json:"tracks"`
+}
+
+// MediaSource is the alternative source of media, from <source> element.
+type MediaSource struct {
+	Src  string `json:"src"`
+	Type string `json:"type"`
+}
+
+// MediaTrack is the timed text track of media, e.g. subtitles, from
+// <track> element.
+type MediaTrack struct {
+	Src     string `json:"src"`
+	Kind    string `json:"kind"`
+	SrcLang string `json:"srclang"`
+	Label   string `json:"label"`
+}
+
+// hasPlayableMedia checks if node contains audio or video that can be
+// played by user, i.e. it has controls and a source.
+func (ps *Parser) hasPlayableMedia(node *html.Node) bool {
+	for _, media := range ps.getAllNodesWithTag(node, "audio", "video") {
+		if !dom.HasAttribute(media, "controls") {
+			continue
+		}
+
+		if dom.GetAttribute(media, "src") != "" || len(dom.GetElementsByTagName(media, "source")) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// getArticleMedia returns the list of audio and video in the article
+// content. It must be called after the URLs are converted to absolute.
+func (ps *Parser) getArticleMedia(articleContent *html.Node) []Media {
+	var medias []Media
+	for _, node := range dom.QuerySelectorAll(articleContent, "audio, video") {
+		media := Media{
+			Type:   dom.TagName(node),
+			Src:    dom.GetAttribute(node, "src"),
+			Poster: dom.GetAttribute(node, "poster"),
+		}
+
+		for _, source := range dom.GetElementsByTagName(node, "source") {
+			if src := dom.GetAttribute(source, "src"); src != "" {
+				media.Sources = append(media.Sources, MediaSource{
+					Src:  src,
+					Type: dom.GetAttribute(source, "type"),
+				})
+			}
+		}
+
+		for _, track := range dom.GetElementsByTagName(node, "track") {
+			if src := dom.GetAttribute(track, "src"); src != "" {
+				media.Tracks = append(media.Tracks, MediaTrack{
+					Src:     src,
+					Kind:    dom.GetAttribute(track, "kind"),
+					SrcLang: dom.GetAttribute(track, "srclang"),
+					Label:   dom.GetAttribute(track, "label"),
+				})
+			}
+		}
+
+		if media.Src != "" || len(media.Sources) > 0 {
+			medias = append(medias, media)
+		}
+	}
+	return medias
+}
@@ -0,0 +1,46 @@
+package readability
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_Article_Media(t *testing.T) {
+	paragraph := articleParagraph(10)
+	player := `<div class="player"><video controls poster="/poster.jpg"><source src="/video.mp4" type="video/mp4">` +
+		`<track src="/subs.vtt" kind="subtitles" srclang="en" label="English"></video></div>` +
+		`<audio controls src="podcast/episode.mp3"></audio>`
+	input := "<html><body><article>" + paragraph + player + paragraph + "</article></body></html>"
+
+	expected := []Media{{
+		Type:    "video",
+		Poster:  "http://fakehost/poster.jpg",
+		Sources: []MediaSource{{Src: "http://fakehost/video.mp4", Type: "video/mp4"}},
+		Tracks:  []MediaTrack{{Src: "http://fakehost/subs.vtt", Kind: "subtitles", SrcLang: "en", Label: "English"}},
+	}, {
+		Type: "audio",
+		Src:  "http://fakehost/test/podcast/episode.mp3",
+	}}
+
+	ps := NewParser(WithPreserveMedia(true))
+	article, err := ps.Parse(strings.NewReader(input), fakeHostURL)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	if !reflect.DeepEqual(article.Media, expected) {
+		t.Errorf("media is different\nwant: %+v\ngot : %+v", expected, article.Media)
+	}
+
+	// Without the option, the video in clutter-like section is removed
+	ps = NewParser()
+	article, err = ps.Parse(strings.NewReader(input), fakeHostURL)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	if len(article.Media) != 1 || article.Media[0].Type != "audio" {
+		t.Errorf("without preserve media, want only audio got %+v", article.Media)
+	}
+}
@@ -0,0 +1,145 @@
+package readability
+
+import (
+	"strings"
+
+	"github.com/go-shiori/dom"
+)
+
+// dublinCorePrefixes is the prefixes of Dublin Core meta tags, both for
+// the elements and the terms.
+var dublinCorePrefixes = []string{"dcterms.", "dcterms:", "dcterm.", "dcterm:", "dc.", "dc:"}
+
+// DublinCore is the Dublin Core metadata of the page. Element refinements
+// like "DC.date.issued" are merged into their element.
+type DublinCore struct {
+	Title       string   `json:"title,omitempty"`
+	Creators    []string `json:"creators,omitempty"`
+	Subject     string   `json:"subject,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Publisher   string   `json:"publisher,omitempty"`
+	Date        string   `json:"date,omitempty"`
+	Type        string   `json:"type,omitempty"`
+	Format      string   `json:"format,omitempty"`
+	Identifier  string   `json:"identifier,omitempty"`
+	Source      string   `json:"source,omitempty"`
+	Language    string   `json:"language,omitempty"`
+	Rights      string   `json:"rights,omitempty"`
+}
+
+// getMetaTags collects the content of meta tags, keyed by their name or
+// property in lowercase. Meta tags that occur several times keep all of
+// their values, in document order.
+func (ps *Parser) getMetaTags() map[string][]string {
+	metaTags := make(map[string][]string)
+	for _, meta := range dom.GetElementsByTagName(ps.doc, "meta") {
+		content := strings.TrimSpace(dom.GetAttribute(meta, "content"))
+		if content == "" {
+			continue
+		}
+
+		for _, attrName := range []string{"name", "property", "itemprop", "http-equiv"} {
+			key := strings.ToLower(strings.TrimSpace(dom.GetAttribute(meta, attrName)))
+			if key != "" {
+				metaTags[key] = append(metaTags[key], content)
+				break
+			}
+		}
+	}
+	return metaTags
+}
+
+// flattenMetaTags returns the first value of each meta tag, or nil if
+// there are no meta tags.
+func flattenMetaTags(metaTags map[string][]string) map[string]string {
+	if len(metaTags) == 0 {
+		return nil
+	}
+
+	flat := make(map[string]string, len(metaTags))
+	for key, values := range metaTags {
+		flat[key] = values[0]
+	}
+	return flat
+}
+
+// getDublinCore extracts the Dublin Core metadata from meta tags. Returns
+// nil if the page doesn't have any Dublin Core metadata.
+func getDublinCore(metaTags map[string][]string) *DublinCore {
+	// Normalize the keys, so "DC.Date.Issued" and "dcterms:issued" can be
+	// looked up simply as "date.issued" and "issued".
+	elements := make(map[string][]string)
+	for key, values := range metaTags {
+		for _, prefix := range dublinCorePrefixes {
+			if strings.HasPrefix(key, prefix) {
+				element := strings.ReplaceAll(key[len(prefix):], ":", ".")
+				elements[element] = append(elements[element], values...)
+				break
+			}
+		}
+	}
+
+	if len(elements) == 0 {
+		return nil
+	}
+
+	first := func(names ...string) string {
+		for _, name := range names {
+			if values := elements[name]; len(values) > 0 {
+				return values[0]
+			}
+		}
+		return ""
+	}
+
+	var creators []string
+	for _, name := range []string{"creator", "creator.personalname", "contributor.author"} {
+		creators = appendUnique(creators, elements[name]...)
+	}
+
+	return &DublinCore{
+		Title:       first("title"),
+		Creators:    creators,
+		Subject:     first("subject"),
+		Description: first("description", "abstract"),
+		Publisher:   first("publisher"),
+		Date:        first("date.issued", "issued", "date", "date.created", "created"),
+		Type:        first("type"),
+		Format:      first("format"),
+		Identifier:  first("identifier"),
+		Source:      first("source"),
+		Language:    first("language"),
+		Rights:      first("rights"),
+	}
+}
+
+// getMetaKeywords returns the keywords in meta tags. The keywords are
+// separated by comma or semicolon, and the duplicates are removed.
+func getMetaKeywords(metaTags map[string][]string) []string {
+	var keywords []string
+	seen := make(map[string]struct{})
+	for _, name := range []string{"keywords", "news_keywords"} {
+		for _, value := range metaTags[name] {
+			for _, keyword := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ';' }) {
+				keyword = strings.Join(strings.Fields(keyword), " ")
+				if _, exist := seen[strings.ToLower(keyword)]; keyword == "" || exist {
+					continue
+				}
+
+				seen[strings.ToLower(keyword)] = struct{}{}
+				keywords = append(keywords, keyword)
+			}
+		}
+	}
+	return keywords
+}
+
+// appendUnique appends the values that not exist yet in slice.
+func appendUnique(slice []string, values ...string) []string {
+	for _, value := range values {
+		if indexOf(slice, value) < 0 {
+			slice = append(slice, value)
+		}
+	}
+	return slice
+}
@@ -0,0 +1,76 @@
+package readability
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_Parser_DublinCore(t *testing.T) {
+	paragraph := articleParagraph(10)
+	input := `<html><head>` +
+		`<meta name="DC.Title" content="The Title">` +
+		`<meta name="DC.Creator" content="Jane Doe">` +
+		`<meta name="DC.creator" content="John Doe">` +
+		`<meta name="DC.Date.Issued" content="2014-12-15">` +
+		`<meta name="dcterms.publisher" content="The Publisher">` +
+		`<meta name="DC.Language" content="en">` +
+		`<meta name="description" content="The description">` +
+		`<meta name="keywords" content="Go, readability; parser, go">` +
+		`<meta name="news_keywords" content="article">` +
+		`</head><body><article>` + paragraph + `</article></body></html>`
+
+	ps := NewParser()
+	article, err := ps.Parse(strings.NewReader(input), fakeHostURL)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	expected := &DublinCore{
+		Title:     "The Title",
+		Creators:  []string{"Jane Doe", "John Doe"},
+		Publisher: "The Publisher",
+		Date:      "2014-12-15",
+		Language:  "en",
+	}
+
+	if !reflect.DeepEqual(article.DublinCore, expected) {
+		t.Errorf("\n"+
+			"want : %+v\n"+
+			"got  : %+v", expected, article.DublinCore)
+	}
+
+	if article.Title != "The Title" {
+		t.Errorf("title should be taken from Dublin Core, got %q", article.Title)
+	}
+
+	if article.PublishedTime == nil || !article.PublishedTime.Equal(time.Date(2014, 12, 15, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("published time should be taken from Dublin Core, got %v", article.PublishedTime)
+	}
+
+	if article.Description != "The description" {
+		t.Errorf("want description %q, got %q", "The description", article.Description)
+	}
+
+	if article.Meta["dc.creator"] != "Jane Doe" || article.Meta["keywords"] != "Go, readability; parser, go" {
+		t.Errorf("unexpected meta: %v", article.Meta)
+	}
+
+	expectedKeywords := []string{"Go", "readability", "parser", "article"}
+	if !reflect.DeepEqual(article.MetaKeywords, expectedKeywords) {
+		t.Errorf("\n"+
+			"want : %q\n"+
+			"got  : %q", expectedKeywords, article.MetaKeywords)
+	}
+
+	// Page without Dublin Core doesn't have the metadata
+	article, err = ps.Parse(strings.NewReader(`<html><body><article>`+paragraph+`</article></body></html>`), fakeHostURL)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	if article.DublinCore != nil || article.Meta != nil {
+		t.Errorf("dublin core and meta should be nil, got %+v and %v", article.DublinCore, article.Meta)
+	}
+}
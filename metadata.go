@@ -0,0 +1,67 @@
+package readability
+
+// MetadataSource is the source of article metadata.
+type MetadataSource int
+
+const (
+	// MetadataJSONLD is the Schema.org object in JSON-LD script.
+	MetadataJSONLD MetadataSource = iota
+	// MetadataDublinCore is the Dublin Core meta tags, e.g. "dc:title".
+	MetadataDublinCore
+	// MetadataOpenGraph is the OpenGraph meta tags, e.g. "og:title".
+	MetadataOpenGraph
+	// MetadataMetaTag is the common meta tags, e.g. "description" and
+	// "author", including the ones used by Weibo and Parse.ly.
+	MetadataMetaTag
+	// MetadataTwitterCard is the Twitter card meta tags, e.g. "twitter:title".
+	MetadataTwitterCard
+	// MetadataMicroformats is the microformats2 h-entry, which is only
+	// parsed when Parser.UseMicroformats is enabled.
+	MetadataMicroformats
+	// MetadataMicrodata is the Schema.org article in microdata, which is
+	// only parsed when Parser.UseMicrodata is enabled.
+	MetadataMicrodata
+)
+
+// DefaultMetadataPrecedence is the default order of metadata sources,
+// which is the same as Readability.js with microformats and microdata as
+// the last resort.
+var DefaultMetadataPrecedence = []MetadataSource{
+	MetadataJSONLD,
+	MetadataDublinCore,
+	MetadataOpenGraph,
+	MetadataMetaTag,
+	MetadataTwitterCard,
+	MetadataMicroformats,
+	MetadataMicrodata,
+}
+
+// pickMetadata returns the first not empty value from the candidates,
+// following the order of metadata precedence.
+func (ps *Parser) pickMetadata(candidates map[MetadataSource][]string) string {
+	for _, source := range ps.metadataPrecedence() {
+		if value := strOr(candidates[source]...); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// containsMetadataSource checks if source is in the list of sources.
+func containsMetadataSource(sources []MetadataSource, source MetadataSource) bool {
+	for _, s := range sources {
+		if s == source {
+			return true
+		}
+	}
+	return false
+}
+
+// metadataPrecedence returns the order of metadata sources that used by
+// parser.
+func (ps *Parser) metadataPrecedence() []MetadataSource {
+	if ps.MetadataPrecedence == nil {
+		return DefaultMetadataPrecedence
+	}
+	return ps.MetadataPrecedence
+}
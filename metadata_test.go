@@ -0,0 +1,111 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_Parser_MetadataPrecedence(t *testing.T) {
+	paragraph := articleParagraph(10)
+	input := `<html><head><title>Page title</title>` +
+		`<script type="application/ld+json">{"@context":"https://schema.org","@type":"Article","headline":"JSON-LD title"}</script>` +
+		`<meta property="og:title" content="OpenGraph title">` +
+		`<meta name="twitter:title" content="Twitter title">` +
+		`</head><body><article>` + paragraph + `</article></body></html>`
+
+	scenarios := []struct {
+		opts     []Option
+		expected string
+	}{
+		{nil, "JSON-LD title"},
+		{[]Option{WithDisableJSONLD(true)}, "OpenGraph title"},
+		{[]Option{WithMetadataPrecedence(MetadataTwitterCard, MetadataOpenGraph)}, "Twitter title"},
+		{[]Option{WithMetadataPrecedence(MetadataMetaTag)}, "Page title"},
+	}
+
+	for _, scenario := range scenarios {
+		ps := NewParser(scenario.opts...)
+		article, err := ps.Parse(strings.NewReader(input), fakeHostURL)
+		if err != nil {
+			t.Fatalf("failed to parse: %v", err)
+		}
+
+		if article.Title != scenario.expected {
+			t.Errorf("precedence %v disable JSON-LD %v, want title %q got %q",
+				ps.MetadataPrecedence, ps.DisableJSONLD, scenario.expected, article.Title)
+		}
+	}
+}
+
+func Test_Parser_ModifiedTime(t *testing.T) {
+	paragraph := articleParagraph(10)
+	article := `<body><article>` + paragraph + `</article></body>`
+
+	scenarios := map[string][2]string{
+		`<html><head><meta property="article:published_time" content="2021-06-27T11:15:28Z">` +
+			`<meta property="article:modified_time" content="2021-06-28T08:00:00+02:00"></head>` + article + `</html>`: {
+			"2021-06-27T11:15:28Z", "2021-06-28T06:00:00Z"},
+		`<html><head><script type="application/ld+json">{"@context": "https://schema.org", "@type": "Article", ` +
+			`"datePublished": "2021-06-27", "dateModified": "2021-06-29"}</script></head>` + article + `</html>`: {
+			"2021-06-27T00:00:00Z", "2021-06-29T00:00:00Z"},
+		`<html><body><time pubdate datetime="2021-06-27T11:15:28Z">27 June</time>` +
+			`<time itemprop="dateModified">June 30, 2021</time><article>` + paragraph + `</article></body></html>`: {
+			"2021-06-27T11:15:28Z", "2021-06-30T00:00:00Z"},
+		`<html><body><time pubdate datetime="20210627">27 June</time>` +
+			`<time itemprop="dateModified">20210630</time><article>` + paragraph + `</article></body></html>`: {
+			"2021-06-27T00:00:00Z", ""},
+		`<html>` + article + `</html>`: {"", ""},
+	}
+
+	format := func(date *time.Time) string {
+		if date == nil {
+			return ""
+		}
+		return date.UTC().Format(time.RFC3339)
+	}
+
+	for input, expected := range scenarios {
+		ps := NewParser()
+		result, err := ps.Parse(strings.NewReader(input), fakeHostURL)
+		if err != nil {
+			t.Fatalf("failed to parse: %v", err)
+		}
+
+		got := [2]string{format(result.PublishedTime), format(result.ModifiedTime)}
+		if got != expected {
+			t.Errorf("\n"+
+				"html : %q\n"+
+				"want : %q\n"+
+				"got  : %q", input, expected, got)
+		}
+	}
+}
+
+func Test_Parser_Favicon(t *testing.T) {
+	paragraph := articleParagraph(10)
+	scenarios := map[string]string{
+		`<link rel="icon" href="/favicon.ico">`: "http://fakehost/favicon.ico",
+		`<link rel="icon" href="/favicon.ico"><link rel="icon" type="image/png" sizes="32x32" href="/icon-32.png">` +
+			`<link rel="icon" sizes="16x16 96x96" href="/icon.png">`: "http://fakehost/icon.png",
+		`<link rel="icon" sizes="32x32" href="/icon-32.png"><link rel="apple-touch-icon" href="/touch.png">`:                     "http://fakehost/touch.png",
+		`<link rel="apple-touch-icon" sizes="192x192" href="/touch.png"><link rel="icon" type="image/svg+xml" href="/icon.svg">`: "http://fakehost/icon.svg",
+		`<link rel="shortcut icon" href="icon-64x64.png"><link rel="mask-icon" href="/mask.svg">`:                                "http://fakehost/test/icon-64x64.png",
+		`<link rel="icon" href="data:image/png;base64,AAAA">`:                                                                    "",
+	}
+
+	for head, expected := range scenarios {
+		input := `<html><head>` + head + `</head><body><article>` + paragraph + `</article></body></html>`
+		article, err := FromReader(strings.NewReader(input), fakeHostURL)
+		if err != nil {
+			t.Fatalf("failed to parse: %v", err)
+		}
+
+		if article.Favicon != expected {
+			t.Errorf("\n"+
+				"html : %q\n"+
+				"want : %q\n"+
+				"got  : %q", head, expected, article.Favicon)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package readability
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+// minSMOGSentences is the number of sentences that SMOG index is
+// calibrated for. Text with fewer sentences is scaled to it.
+const minSMOGSentences = 30
+
+// vowels is the vowels whose groups are counted as syllables, including
+// the accented ones that used by other languages in Latin script.
+const vowels = "aeiouyáéíóúàèìòùâêîôûäëïöüãõåæø"
+
+// ReadabilityMetrics is the readability scores of the article text. The
+// formulas are designed for English, so the scores of other languages
+// are only rough estimations.
+type ReadabilityMetrics struct {
+	Sentences     int `json:"sentences"`
+	Words         int `json:"words"`
+	Syllables     int `json:"syllables"`
+	Letters       int `json:"letters"`
+	PolySyllables int `json:"poly_syllables"`
+
+	// FleschReadingEase is mostly between 0 and 100, where higher score
+	// means easier to read.
+	FleschReadingEase float64 `json:"flesch_reading_ease"`
+	// FleschKincaidGrade is the U.S. school grade that needed to
+	// understand the text.
+	FleschKincaidGrade float64 `json:"flesch_kincaid_grade"`
+	// SMOGIndex is the years of education that needed to understand the
+	// text, estimated from the number of words with 3 or more syllables.
+	SMOGIndex float64 `json:"smog_index"`
+	// ColemanLiauIndex is the U.S. school grade estimated from the number
+	// of letters instead of syllables.
+	ColemanLiauIndex float64 `json:"coleman_liau_index"`
+	// AutomatedReadabilityIndex is the U.S. school grade estimated from
+	// the number of characters.
+	AutomatedReadabilityIndex float64 `json:"automated_readability_index"`
+}
+
+// getReadabilityMetrics returns the readability scores of the article text,
+// if the readability metrics are enabled.
+func (ps *Parser) getReadabilityMetrics(text, language string) *ReadabilityMetrics {
+	if !ps.ExtractReadabilityMetrics {
+		return nil
+	}
+	return readabilityMetrics(text, language)
+}
+
+// readabilityMetrics computes the readability scores of text. Returns nil
+// if there are no words, or if the text is mostly CJK whose words can't
+// be told apart.
+func readabilityMetrics(text, language string) *ReadabilityMetrics {
+	words, cjkChars := countWords(text)
+	if words == 0 || cjkChars > words {
+		return nil
+	}
+
+	metrics := ReadabilityMetrics{}
+	for _, sentence := range SplitSentences(text, language) {
+		sentenceWords := strings.FieldsFunc(sentence.Text, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\'' && r != '’'
+		})
+		if len(sentenceWords) == 0 {
+			continue
+		}
+
+		metrics.Sentences++
+		metrics.Words += len(sentenceWords)
+		for _, word := range sentenceWords {
+			syllables := countSyllables(word)
+			metrics.Syllables += syllables
+			if syllables >= 3 {
+				metrics.PolySyllables++
+			}
+
+			for _, r := range word {
+				if unicode.IsLetter(r) || unicode.IsDigit(r) {
+					metrics.Letters++
+				}
+			}
+		}
+	}
+
+	if metrics.Words == 0 {
+		return nil
+	}
+
+	wordsPerSentence := float64(metrics.Words) / float64(metrics.Sentences)
+	syllablesPerWord := float64(metrics.Syllables) / float64(metrics.Words)
+	lettersPerWord := float64(metrics.Letters) / float64(metrics.Words)
+
+	metrics.FleschReadingEase = 206.835 - 1.015*wordsPerSentence - 84.6*syllablesPerWord
+	metrics.FleschKincaidGrade = 0.39*wordsPerSentence + 11.8*syllablesPerWord - 15.59
+	metrics.SMOGIndex = 1.043*math.Sqrt(float64(metrics.PolySyllables)*minSMOGSentences/float64(metrics.Sentences)) + 3.1291
+	metrics.ColemanLiauIndex = 0.0588*lettersPerWord*100 - 0.296*100/wordsPerSentence - 15.8
+	metrics.AutomatedReadabilityIndex = 4.71*lettersPerWord + 0.5*wordsPerSentence - 21.43
+	return &metrics
+}
+
+// countSyllables estimates the number of syllables in word by counting
+// its groups of vowels, ignoring the silent "e" in English.
+func countSyllables(word string) int {
+	word = strings.ToLower(strings.TrimFunc(word, func(r rune) bool { return !unicode.IsLetter(r) }))
+	runes := []rune(word)
+	if len(runes) == 0 {
+		return 0
+	}
+	if len(runes) <= 3 {
+		return 1
+	}
+
+	// Remove the endings that usually don't add syllable, e.g. "-es" in
+	// "makes" and "-ed" in "jumped", but not in "wanted" or "loses"
+	switch {
+	case strings.HasSuffix(word, "ed") && !strings.HasSuffix(word, "ted") && !strings.HasSuffix(word, "ded"):
+		runes = runes[:len(runes)-2]
+	case strings.HasSuffix(word, "es") && !strings.HasSuffix(word, "ses") && !strings.HasSuffix(word, "zes") &&
+		!strings.HasSuffix(word, "ces") && !strings.HasSuffix(word, "ges") && !strings.HasSuffix(word, "xes"):
+		runes = runes[:len(runes)-2]
+	case strings.HasSuffix(word, "e") && !strings.HasSuffix(word, "le"):
+		runes = runes[:len(runes)-1]
+	}
+
+	syllables := 0
+	inVowel := false
+	for i, r := range runes {
+		isVowel := strings.ContainsRune(vowels, r) && !(i == 0 && r == 'y')
+		if isVowel && !inVowel {
+			syllables++
+		}
+		inVowel = isVowel
+	}
+
+	if syllables == 0 {
+		return 1
+	}
+	return syllables
+}
@@ -0,0 +1,81 @@
+package readability
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func Test_countSyllables(t *testing.T) {
+	scenarios := map[string]int{
+		"cat":         1,
+		"make":        1,
+		"makes":       1,
+		"jumped":      1,
+		"wanted":      2,
+		"table":       2,
+		"yellow":      2,
+		"readability": 5,
+		"beautiful":   3,
+		"café":        2,
+		"42":          0,
+	}
+
+	for word, expected := range scenarios {
+		if got := countSyllables(word); got != expected {
+			t.Errorf("%q: want %d syllables, got %d", word, expected, got)
+		}
+	}
+}
+
+func Test_readabilityMetrics(t *testing.T) {
+	text := "The cat sat on the mat. The dog ran to the park."
+	metrics := readabilityMetrics(text, "en")
+	if metrics == nil {
+		t.Fatal("want metrics, got nil")
+	}
+
+	if metrics.Sentences != 2 || metrics.Words != 12 || metrics.Syllables != 12 || metrics.PolySyllables != 0 {
+		t.Errorf("unexpected counts: %+v", metrics)
+	}
+
+	// 206.835 - 1.015*6 - 84.6*1
+	if math.Abs(metrics.FleschReadingEase-116.145) > 0.001 {
+		t.Errorf("want Flesch reading ease 116.145, got %f", metrics.FleschReadingEase)
+	}
+
+	// Complex text must be harder to read than the simple one
+	complexText := strings.Repeat("Institutional considerations necessitate comprehensive evaluation of organizational "+
+		"responsibilities, particularly regarding environmental sustainability. ", 3)
+	complexMetrics := readabilityMetrics(complexText, "en")
+	if complexMetrics.FleschReadingEase >= metrics.FleschReadingEase ||
+		complexMetrics.FleschKincaidGrade <= metrics.FleschKincaidGrade ||
+		complexMetrics.SMOGIndex <= metrics.SMOGIndex {
+		t.Errorf("complex text is not harder to read: %+v", complexMetrics)
+	}
+
+	if metrics := readabilityMetrics("", "en"); metrics != nil {
+		t.Errorf("want nil metrics of empty text, got %+v", metrics)
+	}
+
+	if metrics := readabilityMetrics("今日は晴れです。明日は雨です。", "ja"); metrics != nil {
+		t.Errorf("want nil metrics of CJK text, got %+v", metrics)
+	}
+}
+
+func Test_Parser_ReadabilityMetrics(t *testing.T) {
+	paragraph := articleParagraph(10)
+	input := `<html><body><article>` + paragraph + paragraph + `</article></body></html>`
+
+	for _, extract := range []bool{false, true} {
+		ps := NewParser(WithReadabilityMetrics(extract))
+		article, err := ps.Parse(strings.NewReader(input), fakeHostURL)
+		if err != nil {
+			t.Fatalf("failed to parse: %v", err)
+		}
+
+		if (article.Readability != nil) != extract {
+			t.Errorf("extract %v, got metrics %+v", extract, article.Readability)
+		}
+	}
+}
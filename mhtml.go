@@ -0,0 +1,229 @@
+package readability
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	nurl "net/url"
+	"regexp"
+	"strings"
+
+	"github.com/go-shiori/dom"
+	"golang.org/x/net/html"
+)
+
+var rxContentIDURL = regexp.MustCompile(`(?i)cid:[^\s,"'()<>]+`)
+
+// mhtmlPart is a resource in MHTML archive.
+type mhtmlPart struct {
+	contentType string
+	contentID   string
+	location    string
+	content     []byte
+}
+
+// ParseMHTML parses the MHTML archive, which is the multipart/related
+// message that saved by browsers as "webpage, single file". The resources
+// referenced by cid: URL are embedded into the article as data URL, since
+// they only exist inside the archive. When pageURL is nil, the location of
+// the snapshot in archive is used, or else it's guessed from the page as
+// explained in GuessPageURL.
+func (ps *Parser) ParseMHTML(input io.Reader, pageURL *nurl.URL) (_ Article, err error) {
+	defer ps.recoverPanic(&err)
+
+	parts, err := readMHTML(input)
+	if err != nil {
+		return Article{}, fmt.Errorf("failed to read MHTML: %w", err)
+	}
+
+	root := parts[0]
+	if pageURL == nil {
+		pageURL = mhtmlPageURL(root)
+	}
+
+	if pageURL == nil {
+		pageURL = GuessPageURL(bytes.NewReader(root.content))
+	}
+
+	ctx, finish := ps.limitParseDuration(context.Background())
+	doc, err := parseContent(ctx, root.content)
+	if err != nil {
+		return Article{}, finish(fmt.Errorf("failed to parse input: %w", err))
+	}
+
+	resolveContentIDs(doc, parts[1:])
+	article, err := ps.ParseDocumentWithContext(ctx, doc, pageURL)
+	return article, finish(err)
+}
+
+// readMHTML reads the parts of MHTML archive. The HTML part that holds the
+// page is returned first.
+func readMHTML(input io.Reader) ([]mhtmlPart, error) {
+	msg, err := mail.ReadMessage(input)
+	if err != nil {
+		return nil, err
+	}
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid content type: %v", err)
+	}
+
+	location := msg.Header.Get("Snapshot-Content-Location")
+
+	// The archive may only have the HTML page, without any resources
+	if mediaType == "text/html" {
+		part, err := readMHTMLPart(msg.Body, msg.Header)
+		if err != nil {
+			return nil, err
+		}
+
+		if part.location == "" {
+			part.location = location
+		}
+		return []mhtmlPart{part}, nil
+	}
+
+	if !strings.HasPrefix(mediaType, "multipart/") || params["boundary"] == "" {
+		return nil, fmt.Errorf("content type %s is not multipart", mediaType)
+	}
+
+	var parts []mhtmlPart
+	rootIdx := -1
+	startID := strings.Trim(params["start"], "<>")
+	reader := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		p, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		part, err := readMHTMLPart(p, p.Header)
+		if err != nil {
+			return nil, err
+		}
+
+		// The page is the start part, or else the first HTML part
+		isHTML := part.contentType == "text/html"
+		switch {
+		case startID != "" && part.contentID == startID:
+			rootIdx = len(parts)
+		case startID == "" && rootIdx < 0 && isHTML:
+			rootIdx = len(parts)
+		}
+		parts = append(parts, part)
+	}
+
+	if rootIdx < 0 {
+		return nil, errors.New("no HTML page in archive")
+	}
+
+	// Move the page to the front
+	root := parts[rootIdx]
+	if root.location == "" {
+		root.location = location
+	}
+
+	parts = append(parts[:rootIdx], parts[rootIdx+1:]...)
+	return append([]mhtmlPart{root}, parts...), nil
+}
+
+// readMHTMLPart reads the content of a part in MHTML archive. The quoted
+// printable content in multipart is already decoded by multipart reader,
+// so it's only decoded here for the archive that only has the page.
+func readMHTMLPart(r io.Reader, header map[string][]string) (mhtmlPart, error) {
+	get := func(key string) string {
+		if values := header[key]; len(values) > 0 {
+			return strings.TrimSpace(values[0])
+		}
+		return ""
+	}
+
+	mediaType, _, err := mime.ParseMediaType(get("Content-Type"))
+	if err != nil {
+		mediaType = "application/octet-stream"
+	}
+
+	switch strings.ToLower(get("Content-Transfer-Encoding")) {
+	case "base64":
+		r = base64.NewDecoder(base64.StdEncoding, r)
+	case "quoted-printable":
+		r = quotedprintable.NewReader(r)
+	}
+
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return mhtmlPart{}, fmt.Errorf("failed to read part: %v", err)
+	}
+
+	return mhtmlPart{
+		contentType: mediaType,
+		contentID:   strings.Trim(get("Content-Id"), "<>"),
+		location:    get("Content-Location"),
+		content:     content,
+	}, nil
+}
+
+// mhtmlPageURL returns the location of page in archive, if it's a valid
+// HTTP or HTTPS URL.
+func mhtmlPageURL(root mhtmlPart) *nurl.URL {
+	parsedURL, err := nurl.ParseRequestURI(root.location)
+	if err != nil || (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") {
+		return nil
+	}
+	return parsedURL
+}
+
+// resolveContentIDs replaces the cid: URLs in attributes of the document,
+// including srcset and inline style, with the data URL of their parts.
+// The URL of missing parts is kept as it is.
+func resolveContentIDs(doc *html.Node, parts []mhtmlPart) {
+	if len(parts) == 0 {
+		return
+	}
+
+	partsByID := make(map[string]mhtmlPart)
+	for _, part := range parts {
+		if part.contentID != "" {
+			partsByID[part.contentID] = part
+		}
+	}
+
+	// The part is only encoded once it's actually referenced
+	dataURLs := make(map[string]string)
+	resolve := func(cidURL string) string {
+		id := cidURL[len("cid:"):]
+		if unescaped, err := nurl.PathUnescape(id); err == nil {
+			id = unescaped
+		}
+
+		part, exist := partsByID[id]
+		if !exist {
+			return cidURL
+		}
+
+		if _, encoded := dataURLs[id]; !encoded {
+			dataURLs[id] = "data:" + part.contentType + ";base64," +
+				base64.StdEncoding.EncodeToString(part.content)
+		}
+		return dataURLs[id]
+	}
+
+	for _, node := range dom.GetElementsByTagName(doc, "*") {
+		for i, attr := range node.Attr {
+			if strings.Contains(strings.ToLower(attr.Val), "cid:") {
+				node.Attr[i].Val = rxContentIDURL.ReplaceAllStringFunc(attr.Val, resolve)
+			}
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package readability
+
+import (
+	"encoding/base64"
+	"errors"
+	"os"
+	fp "path/filepath"
+	"strings"
+	"testing"
+)
+
+// testMHTML returns the MHTML archive in the same format as Chromium, with
+// an image that's referenced by cid: URL.
+func testMHTML(image []byte) string {
+	paragraph := articleParagraph(10)
+	page := `<html><head><meta charset=3D"utf-8"><title>Saved</title></head><body><article>` +
+		paragraph + `<p><img src=3D"cid:image-1@mhtml.blink" alt=3D"Photo"></p>` +
+		`<p><a href=3D"../about">About</a></p></article></body></html>`
+
+	return "From: <Saved by Blink>\r\n" +
+		"Snapshot-Content-Location: https://example.com/posts/1\r\n" +
+		"Subject: Saved\r\n" +
+		"MIME-Version: 1.0\r\n" +
+		"Content-Type: multipart/related;\r\n" +
+		"\ttype=\"text/html\";\r\n" +
+		"\tboundary=\"----MultipartBoundary--abc----\"\r\n" +
+		"\r\n" +
+		"------MultipartBoundary--abc----\r\n" +
+		"Content-Type: text/html\r\n" +
+		"Content-ID: <frame-1@mhtml.blink>\r\n" +
+		"Content-Transfer-Encoding: quoted-printable\r\n" +
+		"Content-Location: https://example.com/posts/1\r\n" +
+		"\r\n" +
+		page + "\r\n" +
+		"\r\n" +
+		"------MultipartBoundary--abc----\r\n" +
+		"Content-Type: image/png\r\n" +
+		"Content-Transfer-Encoding: base64\r\n" +
+		"Content-ID: <image-1@mhtml.blink>\r\n" +
+		"\r\n" +
+		base64.StdEncoding.EncodeToString(image) + "\r\n" +
+		"\r\n" +
+		"------MultipartBoundary--abc------\r\n"
+}
+
+func Test_Parser_ParseMHTML(t *testing.T) {
+	image := []byte("\x89PNG\r\n\x1a\nfake image content")
+	parser := NewParser()
+
+	article, err := parser.ParseMHTML(strings.NewReader(testMHTML(image)), nil)
+	if err != nil {
+		t.Fatalf("failed to parse MHTML: %v", err)
+	}
+
+	if article.Title != "Saved" {
+		t.Errorf("want title Saved, got %q", article.Title)
+	}
+
+	dataURL := "data:image/png;base64," + base64.StdEncoding.EncodeToString(image)
+	if !strings.Contains(article.Content, `src="`+dataURL+`"`) {
+		t.Errorf("want cid: image resolved to data URL, got %s", article.Content)
+	}
+
+	// The snapshot location is used as the page URL
+	if !strings.Contains(article.Content, `href="https://example.com/about"`) {
+		t.Errorf("want URL resolved against snapshot location, got %s", article.Content)
+	}
+
+	// The given URL is used as it is
+	article, err = parser.ParseMHTML(strings.NewReader(testMHTML(image)), fakeHostURL)
+	if err != nil {
+		t.Fatalf("failed to parse MHTML: %v", err)
+	}
+
+	if !strings.Contains(article.Content, `href="http://fakehost/about"`) {
+		t.Errorf("want URL resolved against fakehost, got %s", article.Content)
+	}
+
+	// MHTML file is detected from its extension
+	path := fp.Join(t.TempDir(), "page.mhtml")
+	if err = os.WriteFile(path, []byte(testMHTML(image)), 0o644); err != nil {
+		t.Fatalf("failed to write archive: %v", err)
+	}
+
+	fromFile, err := parser.ParseFile(path, nil)
+	if err != nil {
+		t.Fatalf("failed to parse file: %v", err)
+	}
+
+	if !strings.Contains(fromFile.Content, dataURL) {
+		t.Errorf("want MHTML file parsed as archive, got %s", fromFile.Content)
+	}
+}
+
+func Test_readMHTML(t *testing.T) {
+	// The start part is the page, even when it's not the first one
+	archive := "Content-Type: multipart/related; boundary=b; start=\"<page>\"\r\n" +
+		"\r\n" +
+		"--b\r\n" +
+		"Content-Type: text/html\r\n" +
+		"Content-ID: <frame>\r\n" +
+		"\r\n" +
+		"<p>frame</p>\r\n" +
+		"--b\r\n" +
+		"Content-Type: text/html; charset=utf-8\r\n" +
+		"Content-ID: <page>\r\n" +
+		"Content-Location: https://example.com/\r\n" +
+		"\r\n" +
+		"<p>page</p>\r\n" +
+		"--b--\r\n"
+
+	parts, err := readMHTML(strings.NewReader(archive))
+	if err != nil {
+		t.Fatalf("failed to read archive: %v", err)
+	}
+
+	if len(parts) != 2 || string(parts[0].content) != "<p>page</p>" || parts[1].contentID != "frame" {
+		t.Errorf("want page part first, got %+v", parts)
+	}
+
+	// The archive may only have the page
+	single := "Content-Type: text/html\r\n" +
+		"Snapshot-Content-Location: https://example.com/single\r\n" +
+		"Content-Transfer-Encoding: quoted-printable\r\n" +
+		"\r\n" +
+		"<p class=3D\"page\">single</p>"
+
+	parts, err = readMHTML(strings.NewReader(single))
+	if err != nil {
+		t.Fatalf("failed to read archive: %v", err)
+	}
+
+	if len(parts) != 1 || string(parts[0].content) != `<p class="page">single</p>` ||
+		parts[0].location != "https://example.com/single" {
+		t.Errorf("want single page part, got %+v", parts)
+	}
+
+	invalids := []string{
+		"not an archive",
+		"Content-Type: image/png\r\n\r\nimage",
+		"Content-Type: multipart/related; boundary=b\r\n\r\n--b\r\nContent-Type: image/png\r\n\r\nimage\r\n--b--\r\n",
+	}
+
+	parser := NewParser()
+	for _, invalid := range invalids {
+		if _, err := parser.ParseMHTML(strings.NewReader(invalid), nil); err == nil || errors.Is(err, ErrPanic) {
+			t.Errorf("%q: want read error, got %v", invalid, err)
+		}
+	}
+}
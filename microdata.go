@@ -0,0 +1,170 @@
+package readability
+
+import (
+	"strings"
+
+	"github.com/go-shiori/dom"
+	"golang.org/x/net/html"
+)
+
+// Microdata is the Schema.org article that marked up with microdata, i.e.
+// itemscope and itemprop attributes.
+type Microdata struct {
+	Type          string   `json:"type,omitempty"`
+	Headline      string   `json:"headline,omitempty"`
+	Description   string   `json:"description,omitempty"`
+	Image         string   `json:"image,omitempty"`
+	DatePublished string   `json:"date_published,omitempty"`
+	DateModified  string   `json:"date_modified,omitempty"`
+	Authors       []Author `json:"authors,omitempty"`
+	Publisher     string   `json:"publisher,omitempty"`
+}
+
+// getMicrodata parses the first Schema.org article in the microdata of
+// the document. Returns nil if there are no article in it.
+func (ps *Parser) getMicrodata() *Microdata {
+	for _, item := range dom.QuerySelectorAll(ps.doc, "[itemscope][itemtype]") {
+		itemType := microdataType(item)
+		if itemType == "" || !rxJsonLdArticleTypes.MatchString(itemType) {
+			continue
+		}
+
+		props := microdataProperties(item)
+		first := func(names ...string) string {
+			for _, name := range names {
+				for _, node := range props[name] {
+					if value := ps.microdataValue(node); value != "" {
+						return value
+					}
+				}
+			}
+			return ""
+		}
+
+		md := &Microdata{
+			Type:          itemType,
+			Headline:      first("headline", "name"),
+			Description:   first("description"),
+			Image:         first("image", "thumbnailUrl"),
+			DatePublished: first("datePublished", "dateCreated"),
+			DateModified:  first("dateModified"),
+		}
+
+		if publishers := props["publisher"]; len(publishers) > 0 {
+			md.Publisher = ps.microdataItemName(publishers[0])
+		}
+
+		for _, node := range append(props["author"], props["creator"]...) {
+			author := Author{Name: ps.microdataItemName(node)}
+			if isMicrodataItem(node) {
+				if urls := microdataProperties(node)["url"]; len(urls) > 0 {
+					author.URL = ps.microdataValue(urls[0])
+				}
+			} else if dom.TagName(node) == "a" {
+				author.URL = ps.microdataValue(node)
+			}
+
+			if author.Name != "" {
+				md.Authors = append(md.Authors, author)
+			}
+		}
+
+		return md
+	}
+
+	return nil
+}
+
+// byline returns the names of microdata authors as byline.
+func (md *Microdata) byline() string {
+	if md == nil {
+		return ""
+	}
+
+	names := make([]string, len(md.Authors))
+	for i, author := range md.Authors {
+		names[i] = author.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+// microdataType returns the type of item without its vocabulary, e.g.
+// "NewsArticle" for "https://schema.org/NewsArticle".
+func microdataType(item *html.Node) string {
+	itemTypes := strings.Fields(dom.GetAttribute(item, "itemtype"))
+	if len(itemTypes) == 0 {
+		return ""
+	}
+
+	itemType := strings.TrimSuffix(itemTypes[0], "/")
+	return itemType[strings.LastIndex(itemType, "/")+1:]
+}
+
+// microdataProperties returns the properties of item, keyed by the
+// property names. The properties of nested items are not included.
+func microdataProperties(item *html.Node) map[string][]*html.Node {
+	props := make(map[string][]*html.Node)
+	var walk func(*html.Node)
+	walk = func(node *html.Node) {
+		for _, child := range dom.Children(node) {
+			for _, name := range strings.Fields(dom.GetAttribute(child, "itemprop")) {
+				props[name] = append(props[name], child)
+			}
+
+			if !isMicrodataItem(child) {
+				walk(child)
+			}
+		}
+	}
+
+	walk(item)
+	return props
+}
+
+// microdataItemName returns the name of property that may be an item,
+// e.g. author which is usually a Person.
+func (ps *Parser) microdataItemName(node *html.Node) string {
+	if !isMicrodataItem(node) {
+		return ps.microdataValue(node)
+	}
+
+	if names := microdataProperties(node)["name"]; len(names) > 0 {
+		return ps.microdataValue(names[0])
+	}
+	return ""
+}
+
+// microdataValue returns the value of property node, following the rule
+// in HTML specification.
+func (ps *Parser) microdataValue(node *html.Node) string {
+	var value string
+	switch dom.TagName(node) {
+	case "meta":
+		return strings.TrimSpace(dom.GetAttribute(node, "content"))
+	case "audio", "embed", "iframe", "img", "source", "track", "video":
+		value = dom.GetAttribute(node, "src")
+	case "a", "area", "link":
+		value = dom.GetAttribute(node, "href")
+	case "object":
+		value = dom.GetAttribute(node, "data")
+	case "data", "meter":
+		return strings.TrimSpace(dom.GetAttribute(node, "value"))
+	case "time":
+		if datetime := strings.TrimSpace(dom.GetAttribute(node, "datetime")); datetime != "" {
+			return datetime
+		}
+		fallthrough
+	default:
+		return strings.Join(strings.Fields(dom.TextContent(node)), " ")
+	}
+
+	if value = strings.TrimSpace(value); value == "" {
+		return ""
+	}
+	return toAbsoluteURI(value, ps.documentURI)
+}
+
+// isMicrodataItem checks if node is a microdata item.
+func isMicrodataItem(node *html.Node) bool {
+	return dom.HasAttribute(node, "itemscope")
+}
@@ -0,0 +1,103 @@
+package readability
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-shiori/dom"
+)
+
+func Test_Parser_Microdata(t *testing.T) {
+	paragraph := articleParagraph(10)
+	input := `<html><head><title>Site Title</title></head><body>` +
+		`<div itemscope itemtype="https://schema.org/NewsArticle">` +
+		`<h1 itemprop="headline">The Headline</h1>` +
+		`<span itemprop="author" itemscope itemtype="https://schema.org/Person">` +
+		`<a itemprop="url" href="/janedoe"><span itemprop="name">Jane Doe</span></a></span>` +
+		`<time itemprop="datePublished" datetime="2021-06-27">June 27</time>` +
+		`<meta itemprop="dateModified" content="2021-06-28">` +
+		`<img itemprop="image" src="/image.png">` +
+		`<div itemprop="publisher" itemscope itemtype="https://schema.org/Organization">` +
+		`<meta itemprop="name" content="The Publisher"></div>` +
+		`<div itemprop="articleBody">` + paragraph + paragraph + `</div>` +
+		`</div></body></html>`
+
+	// Microdata is disabled by default
+	ps := NewParser()
+	article, err := ps.Parse(strings.NewReader(input), fakeHostURL)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	if article.Microdata != nil || article.Title != "Site Title" {
+		t.Errorf("microdata should be ignored, got %+v and title %q", article.Microdata, article.Title)
+	}
+
+	ps = NewParser(WithMicrodata(true))
+	article, err = ps.Parse(strings.NewReader(input), fakeHostURL)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	expected := &Microdata{
+		Type:          "NewsArticle",
+		Headline:      "The Headline",
+		Image:         "http://fakehost/image.png",
+		DatePublished: "2021-06-27",
+		DateModified:  "2021-06-28",
+		Authors:       []Author{{Name: "Jane Doe", URL: "http://fakehost/janedoe"}},
+		Publisher:     "The Publisher",
+	}
+
+	if !reflect.DeepEqual(article.Microdata, expected) {
+		t.Errorf("\n"+
+			"want : %+v\n"+
+			"got  : %+v", expected, article.Microdata)
+	}
+
+	if article.Title != "The Headline" || article.Byline != "Jane Doe" {
+		t.Errorf("metadata should be taken from microdata, got %q and %q", article.Title, article.Byline)
+	}
+
+	if article.ModifiedTime == nil || article.ModifiedTime.Format("2006-01-02") != "2021-06-28" {
+		t.Errorf("modified time should be taken from microdata, got %v", article.ModifiedTime)
+	}
+
+	if !reflect.DeepEqual(article.Authors, expected.Authors) {
+		t.Errorf("want authors %+v, got %+v", expected.Authors, article.Authors)
+	}
+}
+
+func Test_Parser_MicrodataContentHint(t *testing.T) {
+	paragraph := articleParagraph(10)
+	input := `<html><body><div itemscope itemtype="https://schema.org/Article">` +
+		`<div itemprop="articleBody">` + paragraph + paragraph + `</div></div></body></html>`
+
+	contentScore := func(ps Parser) float64 {
+		doc, err := dom.Parse(strings.NewReader(input))
+		if err != nil {
+			t.Fatalf("failed to parse input: %v", err)
+		}
+
+		candidates, err := ps.Candidates(doc)
+		if err != nil {
+			t.Fatalf("failed to get candidates: %v", err)
+		}
+
+		for _, candidate := range candidates {
+			if dom.GetAttribute(candidate.Node, "itemprop") == "articleBody" {
+				return candidate.Score
+			}
+		}
+
+		t.Fatalf("articleBody is not a candidate")
+		return 0
+	}
+
+	withoutHint := contentScore(NewParser())
+	withHint := contentScore(NewParser(WithMicrodata(true)))
+	if withHint-withoutHint != contentHintWeight {
+		t.Errorf("want score bonus %d, got %v", contentHintWeight, withHint-withoutHint)
+	}
+}
@@ -0,0 +1,191 @@
+package readability
+
+import (
+	"strings"
+
+	"github.com/go-shiori/dom"
+	"golang.org/x/net/html"
+)
+
+// contentHintWeight is the score bonus for the node that marked as the
+// article content by the page, e.g. e-content of h-entry.
+const contentHintWeight = 25
+
+// HEntry is the microformats2 h-entry of the page, which is used by
+// IndieWeb blogs to mark up their posts.
+type HEntry struct {
+	Name       string   `json:"name,omitempty"`
+	Summary    string   `json:"summary,omitempty"`
+	URL        string   `json:"url,omitempty"`
+	Author     *HCard   `json:"author,omitempty"`
+	Published  string   `json:"published,omitempty"`
+	Updated    string   `json:"updated,omitempty"`
+	Categories []string `json:"categories,omitempty"`
+}
+
+// HCard is the microformats2 h-card, which is used for the author of
+// h-entry.
+type HCard struct {
+	Name  string `json:"name,omitempty"`
+	URL   string `json:"url,omitempty"`
+	Photo string `json:"photo,omitempty"`
+}
+
+// getHEntry parses the first h-entry in the document. Returns nil if the
+// document doesn't have any h-entry.
+func (ps *Parser) getHEntry() *HEntry {
+	root := dom.QuerySelector(ps.doc, ".h-entry")
+	if root == nil {
+		return nil
+	}
+
+	entry := &HEntry{
+		Name:      ps.mfFirstValue(root, "p-name"),
+		Summary:   ps.mfFirstValue(root, "p-summary"),
+		URL:       ps.mfFirstValue(root, "u-url"),
+		Published: ps.mfFirstValue(root, "dt-published"),
+		Updated:   ps.mfFirstValue(root, "dt-updated"),
+	}
+
+	for _, node := range mfProperties(root, "p-category") {
+		if category := ps.mfValue(node, "p-category"); category != "" {
+			entry.Categories = appendUnique(entry.Categories, category)
+		}
+	}
+
+	if nodes := mfProperties(root, "p-author"); len(nodes) > 0 {
+		author := nodes[0]
+		card := &HCard{Name: ps.mfValue(author, "p-author")}
+		if hasClass(author, "h-card") {
+			card.Name = strOr(ps.mfFirstValue(author, "p-name"), card.Name)
+			card.URL = ps.mfFirstValue(author, "u-url")
+			card.Photo = ps.mfFirstValue(author, "u-photo")
+			if card.URL == "" && dom.TagName(author) == "a" {
+				card.URL = ps.mfValue(author, "u-url")
+			}
+		}
+
+		if card.Name != "" {
+			entry.Author = card
+		}
+	}
+
+	return entry
+}
+
+// isContentHint checks if the page marked node as the article content,
+// either as e-content of h-entry or articleBody in microdata.
+func (ps *Parser) isContentHint(node *html.Node) bool {
+	if ps.UseMicroformats && hasClass(node, "e-content") &&
+		(hasClass(node, "h-entry") || hasAncestorClass(node, "h-entry")) {
+		return true
+	}
+
+	return ps.UseMicrodata &&
+		indexOf(strings.Fields(dom.GetAttribute(node, "itemprop")), "articleBody") >= 0
+}
+
+// mfProperties returns the descendants of root that have the property
+// class, without entering the nested microformats.
+func mfProperties(root *html.Node, class string) []*html.Node {
+	var nodes []*html.Node
+	var walk func(*html.Node)
+	walk = func(node *html.Node) {
+		for _, child := range dom.Children(node) {
+			if hasClass(child, class) {
+				nodes = append(nodes, child)
+			}
+
+			if !isMicroformatRoot(child) {
+				walk(child)
+			}
+		}
+	}
+
+	walk(root)
+	return nodes
+}
+
+// mfFirstValue returns the value of the first property in root.
+func (ps *Parser) mfFirstValue(root *html.Node, class string) string {
+	if nodes := mfProperties(root, class); len(nodes) > 0 {
+		return ps.mfValue(nodes[0], class)
+	}
+	return ""
+}
+
+// mfValue returns the value of property node, following the parsing
+// rule for its prefix: "u-" for URL, "dt-" for date time, and "p-" for
+// plain text.
+func (ps *Parser) mfValue(node *html.Node, class string) string {
+	var attrs []string
+	tagName := dom.TagName(node)
+	switch {
+	case strings.HasPrefix(class, "u-"):
+		switch tagName {
+		case "a", "area", "link":
+			attrs = []string{"href"}
+		case "img", "audio", "video", "source", "iframe":
+			attrs = []string{"src"}
+		case "object":
+			attrs = []string{"data"}
+		}
+	case strings.HasPrefix(class, "dt-"):
+		switch tagName {
+		case "time", "ins", "del":
+			attrs = []string{"datetime"}
+		}
+	}
+
+	switch tagName {
+	case "abbr":
+		attrs = append(attrs, "title")
+	case "data", "input":
+		attrs = append(attrs, "value")
+	case "img", "area":
+		attrs = append(attrs, "alt")
+	}
+
+	value := ""
+	for _, attr := range attrs {
+		if value = strings.TrimSpace(dom.GetAttribute(node, attr)); value != "" {
+			break
+		}
+	}
+
+	if value == "" {
+		value = strings.Join(strings.Fields(dom.TextContent(node)), " ")
+	}
+
+	if strings.HasPrefix(class, "u-") && value != "" {
+		value = toAbsoluteURI(value, ps.documentURI)
+	}
+
+	return value
+}
+
+// isMicroformatRoot checks if node is root of microformats, i.e. it has
+// class prefixed with "h-".
+func isMicroformatRoot(node *html.Node) bool {
+	for _, class := range strings.Fields(dom.ClassName(node)) {
+		if strings.HasPrefix(class, "h-") {
+			return true
+		}
+	}
+	return false
+}
+
+// hasClass checks if node has the specified class.
+func hasClass(node *html.Node, class string) bool {
+	return indexOf(strings.Fields(dom.ClassName(node)), class) >= 0
+}
+
+// hasAncestorClass checks if any ancestor of node has the class.
+func hasAncestorClass(node *html.Node, class string) bool {
+	for parent := node.Parent; parent != nil; parent = parent.Parent {
+		if hasClass(parent, class) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,102 @@
+package readability
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-shiori/dom"
+)
+
+func Test_Parser_Microformats(t *testing.T) {
+	paragraph := articleParagraph(10)
+	input := `<html><head><title>Site Title</title></head><body>` +
+		`<article class="h-entry">` +
+		`<h1 class="p-name">The Post</h1>` +
+		`<a class="p-author h-card" href="/johndoe"><img class="u-photo" src="/john.png" alt="">John Doe</a>` +
+		`<time class="dt-published" datetime="2021-06-27T11:15:28Z">June 27</time>` +
+		`<p class="p-summary">The summary</p>` +
+		`<a class="p-category" href="/tag/go">Go</a>` +
+		`<div class="e-content">` + paragraph + `</div>` +
+		`</article></body></html>`
+
+	// Microformats is disabled by default
+	ps := NewParser()
+	article, err := ps.Parse(strings.NewReader(input), fakeHostURL)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	if article.HEntry != nil || article.Title != "Site Title" {
+		t.Errorf("h-entry should be ignored, got %+v and title %q", article.HEntry, article.Title)
+	}
+
+	ps = NewParser(WithMicroformats(true))
+	article, err = ps.Parse(strings.NewReader(input), fakeHostURL)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	expected := &HEntry{
+		Name:      "The Post",
+		Summary:   "The summary",
+		Published: "2021-06-27T11:15:28Z",
+		Author: &HCard{
+			Name:  "John Doe",
+			URL:   "http://fakehost/johndoe",
+			Photo: "http://fakehost/john.png",
+		},
+		Categories: []string{"Go"},
+	}
+
+	if !reflect.DeepEqual(article.HEntry, expected) {
+		t.Errorf("\n"+
+			"want : %+v\n"+
+			"got  : %+v", expected, article.HEntry)
+	}
+
+	if article.Title != "The Post" || article.Byline != "John Doe" || article.Excerpt != "The summary" {
+		t.Errorf("metadata should be taken from h-entry, got %q, %q and %q", article.Title, article.Byline, article.Excerpt)
+	}
+
+	if article.PublishedTime == nil || article.PublishedTime.Format("2006-01-02") != "2021-06-27" {
+		t.Errorf("published time should be taken from h-entry, got %v", article.PublishedTime)
+	}
+
+	expectedAuthors := []Author{{Name: "John Doe", URL: "http://fakehost/johndoe"}}
+	if !reflect.DeepEqual(article.Authors, expectedAuthors) {
+		t.Errorf("want authors %+v, got %+v", expectedAuthors, article.Authors)
+	}
+}
+
+func Test_Parser_MicroformatsContentHint(t *testing.T) {
+	paragraph := articleParagraph(10)
+	input := `<html><body><div class="h-entry"><div class="e-content">` + paragraph + paragraph + `</div></div></body></html>`
+
+	contentScore := func(ps Parser) float64 {
+		doc, err := dom.Parse(strings.NewReader(input))
+		if err != nil {
+			t.Fatalf("failed to parse input: %v", err)
+		}
+
+		candidates, err := ps.Candidates(doc)
+		if err != nil {
+			t.Fatalf("failed to get candidates: %v", err)
+		}
+
+		for _, candidate := range candidates {
+			if strings.Contains(dom.ClassName(candidate.Node), "e-content") {
+				return candidate.Score
+			}
+		}
+
+		t.Fatalf("e-content is not a candidate")
+		return 0
+	}
+
+	withoutHint := contentScore(NewParser())
+	withHint := contentScore(NewParser(WithMicroformats(true)))
+	if withHint-withoutHint != contentHintWeight {
+		t.Errorf("want score bonus %d, got %v", contentHintWeight, withHint-withoutHint)
+	}
+}
@@ -0,0 +1,124 @@
+package readability
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/go-shiori/dom"
+)
+
+// OpenGraph is the Open Graph metadata of the page, including the
+// properties of "article" object type.
+type OpenGraph struct {
+	Title       string           `json:"title,omitempty"`
+	Description string           `json:"description,omitempty"`
+	Type        string           `json:"type,omitempty"`
+	URL         string           `json:"url,omitempty"`
+	Locale      string           `json:"locale,omitempty"`
+	SiteName    string           `json:"site_name,omitempty"`
+	Images      []OpenGraphImage `json:"images,omitempty"`
+	Article     OpenGraphArticle `json:"article"`
+}
+
+// OpenGraphImage is an image in Open Graph metadata.
+type OpenGraphImage struct {
+	URL       string `json:"url"`
+	SecureURL string `json:"secure_url,omitempty"`
+	Type      string `json:"type,omitempty"`
+	Width     int    `json:"width,omitempty"`
+	Height    int    `json:"height,omitempty"`
+	Alt       string `json:"alt,omitempty"`
+}
+
+// OpenGraphArticle is the article:* properties in Open Graph metadata.
+// The times are kept as written, use PublishedTime and ModifiedTime of
+// Article for the parsed ones.
+type OpenGraphArticle struct {
+	PublishedTime  string   `json:"published_time,omitempty"`
+	ModifiedTime   string   `json:"modified_time,omitempty"`
+	ExpirationTime string   `json:"expiration_time,omitempty"`
+	Authors        []string `json:"authors,omitempty"`
+	Section        string   `json:"section,omitempty"`
+	Tags           []string `json:"tags,omitempty"`
+}
+
+// getOpenGraph collects the Open Graph metadata from meta tags. Structured
+// properties like og:image:width are applied to the last image before it.
+// Returns nil if the page doesn't have any Open Graph metadata.
+func (ps *Parser) getOpenGraph() *OpenGraph {
+	var og OpenGraph
+	found := false
+
+	for _, meta := range dom.GetElementsByTagName(ps.doc, "meta") {
+		property := strings.ToLower(strings.TrimSpace(dom.GetAttribute(meta, "property")))
+		if property == "" {
+			// Some sites put Open Graph property in name attribute
+			property = strings.ToLower(strings.TrimSpace(dom.GetAttribute(meta, "name")))
+		}
+
+		if !strings.HasPrefix(property, "og:") && !strings.HasPrefix(property, "article:") {
+			continue
+		}
+
+		content := strings.TrimSpace(dom.GetAttribute(meta, "content"))
+		if content == "" {
+			continue
+		}
+
+		found = true
+		lastImage := func() *OpenGraphImage {
+			if len(og.Images) == 0 {
+				og.Images = append(og.Images, OpenGraphImage{})
+			}
+			return &og.Images[len(og.Images)-1]
+		}
+
+		switch property {
+		case "og:title":
+			og.Title = content
+		case "og:description":
+			og.Description = content
+		case "og:type":
+			og.Type = content
+		case "og:url":
+			og.URL = toAbsoluteURI(content, ps.documentURI)
+		case "og:locale":
+			og.Locale = content
+		case "og:site_name":
+			og.SiteName = content
+		case "og:image", "og:image:url":
+			if len(og.Images) > 0 && og.Images[len(og.Images)-1].URL == "" {
+				og.Images[len(og.Images)-1].URL = toAbsoluteURI(content, ps.documentURI)
+			} else {
+				og.Images = append(og.Images, OpenGraphImage{URL: toAbsoluteURI(content, ps.documentURI)})
+			}
+		case "og:image:secure_url":
+			lastImage().SecureURL = toAbsoluteURI(content, ps.documentURI)
+		case "og:image:type":
+			lastImage().Type = content
+		case "og:image:width":
+			lastImage().Width, _ = strconv.Atoi(content)
+		case "og:image:height":
+			lastImage().Height, _ = strconv.Atoi(content)
+		case "og:image:alt":
+			lastImage().Alt = content
+		case "article:published_time":
+			og.Article.PublishedTime = content
+		case "article:modified_time":
+			og.Article.ModifiedTime = content
+		case "article:expiration_time":
+			og.Article.ExpirationTime = content
+		case "article:author":
+			og.Article.Authors = append(og.Article.Authors, content)
+		case "article:section":
+			og.Article.Section = content
+		case "article:tag":
+			og.Article.Tags = append(og.Article.Tags, content)
+		}
+	}
+
+	if !found {
+		return nil
+	}
+	return &og
+}
@@ -0,0 +1,68 @@
+package readability
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_Parser_OpenGraph(t *testing.T) {
+	paragraph := articleParagraph(10)
+	input := `<html><head>` +
+		`<meta property="og:title" content="The Title">` +
+		`<meta property="og:type" content="article">` +
+		`<meta property="og:url" content="/test/page.html">` +
+		`<meta property="og:locale" content="en_US">` +
+		`<meta property="og:image" content="/first.png">` +
+		`<meta property="og:image:width" content="800">` +
+		`<meta property="og:image:height" content="600">` +
+		`<meta property="og:image:alt" content="First image">` +
+		`<meta property="og:image" content="http://cdn.fakehost/second.png">` +
+		`<meta property="og:image:type" content="image/png">` +
+		`<meta property="article:published_time" content="2021-06-27T11:15:28Z">` +
+		`<meta property="article:author" content="John Doe">` +
+		`<meta property="article:author" content="Jane Roe">` +
+		`<meta property="article:section" content="Technology">` +
+		`<meta property="article:tag" content="go">` +
+		`<meta property="article:tag" content="readability">` +
+		`</head><body><article>` + paragraph + `</article></body></html>`
+
+	ps := NewParser()
+	article, err := ps.Parse(strings.NewReader(input), fakeHostURL)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	expected := &OpenGraph{
+		Title:  "The Title",
+		Type:   "article",
+		URL:    "http://fakehost/test/page.html",
+		Locale: "en_US",
+		Images: []OpenGraphImage{
+			{URL: "http://fakehost/first.png", Width: 800, Height: 600, Alt: "First image"},
+			{URL: "http://cdn.fakehost/second.png", Type: "image/png"},
+		},
+		Article: OpenGraphArticle{
+			PublishedTime: "2021-06-27T11:15:28Z",
+			Authors:       []string{"John Doe", "Jane Roe"},
+			Section:       "Technology",
+			Tags:          []string{"go", "readability"},
+		},
+	}
+
+	if !reflect.DeepEqual(article.OpenGraph, expected) {
+		t.Errorf("\n"+
+			"want : %+v\n"+
+			"got  : %+v", expected, article.OpenGraph)
+	}
+
+	// Page without Open Graph doesn't have the metadata
+	article, err = ps.Parse(strings.NewReader(`<html><body><article>`+paragraph+`</article></body></html>`), fakeHostURL)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	if article.OpenGraph != nil {
+		t.Errorf("open graph should be nil, got %+v", article.OpenGraph)
+	}
+}
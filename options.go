@@ -0,0 +1,472 @@
+package readability
+
+import (
+	nurl "net/url"
+	"regexp"
+	"time"
+
+	"golang.org/x/net/html"
+)
+
+// Option is a functional option that configures a Parser. Options are
+// applied in order by NewParser, after the default values are set.
+type Option func(*Parser)
+
+// WithCharThresholds sets the number of chars an article must have in
+// order to return a result.
+func WithCharThresholds(n int) Option {
+	return func(ps *Parser) {
+		ps.CharThresholds = n
+	}
+}
+
+// WithNTopCandidates sets the number of top candidates to consider when
+// analysing how tight the competition is among candidates.
+func WithNTopCandidates(n int) Option {
+	return func(ps *Parser) {
+		ps.NTopCandidates = n
+	}
+}
+
+// WithTagsToScore sets the element tags that will be scored.
+func WithTagsToScore(tags ...string) Option {
+	return func(ps *Parser) {
+		ps.TagsToScore = tags
+	}
+}
+
+// WithClassesToPreserve sets the classes that will be kept when the
+// classes are stripped from the article content. The "page" class that
+// set by readability is always kept.
+func WithClassesToPreserve(classes ...string) Option {
+	return func(ps *Parser) {
+		ps.ClassesToPreserve = classes
+	}
+}
+
+// WithKeepClasses specifies whether the classes should be kept in the
+// article content or not.
+func WithKeepClasses(keep bool) Option {
+	return func(ps *Parser) {
+		ps.KeepClasses = keep
+	}
+}
+
+// WithDebug specifies whether the log should be printed or not.
+func WithDebug(debug bool) Option {
+	return func(ps *Parser) {
+		ps.Debug = debug
+	}
+}
+
+// WithLazyImageAttributes sets the attributes that contain the real
+// URL of lazy-loaded image, e.g. "data-src", "data-original" and
+// "data-srcset". They are promoted in the order they are specified.
+func WithLazyImageAttributes(attrs ...string) Option {
+	return func(ps *Parser) {
+		ps.LazyImageAttributes = attrs
+	}
+}
+
+// WithCollapsePictures specifies whether each <picture> element should
+// be collapsed into its best single <img>.
+func WithCollapsePictures(collapse bool) Option {
+	return func(ps *Parser) {
+		ps.CollapsePictures = collapse
+	}
+}
+
+// WithDisableNoscriptUnwrap specifies whether the fallback images inside
+// <noscript> should not be unwrapped.
+func WithDisableNoscriptUnwrap(disable bool) Option {
+	return func(ps *Parser) {
+		ps.DisableNoscriptUnwrap = disable
+	}
+}
+
+// WithAllowedVideoRegex sets the regular expression that matches the URL
+// of embedded videos that should be kept in the article content.
+func WithAllowedVideoRegex(rx *regexp.Regexp) Option {
+	return func(ps *Parser) {
+		ps.AllowedVideoRegex = rx
+	}
+}
+
+// WithConvertSocialEmbeds specifies whether the embedded social media
+// posts should be converted into static blockquotes. The resolver is
+// optional, and used when the post content can't be extracted from
+// the embed markup.
+func WithConvertSocialEmbeds(convert bool, resolver SocialEmbedResolver) Option {
+	return func(ps *Parser) {
+		ps.ConvertSocialEmbeds = convert
+		ps.SocialEmbedResolver = resolver
+	}
+}
+
+// WithPreserveMath specifies whether the math that rendered by MathJax
+// or KaTeX should be preserved as its TeX source.
+func WithPreserveMath(preserve bool) Option {
+	return func(ps *Parser) {
+		ps.PreserveMath = preserve
+	}
+}
+
+// WithPreserveCode specifies whether the code fidelity mode is enabled,
+// which keeps <pre> and <code> blocks exactly as they are.
+func WithPreserveCode(preserve bool) Option {
+	return func(ps *Parser) {
+		ps.PreserveCode = preserve
+	}
+}
+
+// WithDataTableThresholds sets the thresholds for detecting data table.
+// Use the thresholds of NewParser as the base to only change some of it.
+func WithDataTableThresholds(thresholds DataTableThresholds) Option {
+	return func(ps *Parser) {
+		ps.DataTableThresholds = thresholds
+	}
+}
+
+// WithPreserveDetails specifies whether the <details> disclosure elements
+// should be kept in the article content.
+func WithPreserveDetails(preserve bool) Option {
+	return func(ps *Parser) {
+		ps.PreserveDetails = preserve
+	}
+}
+
+// WithPreserveMedia specifies whether the audio and video with controls
+// should be kept in the article content.
+func WithPreserveMedia(preserve bool) Option {
+	return func(ps *Parser) {
+		ps.PreserveMedia = preserve
+	}
+}
+
+// WithSVGPolicy sets the policy for handling inline SVG. The min size is
+// only used by SVGKeepLarge, use 0 for the default size.
+func WithSVGPolicy(policy SVGPolicy, minSize float64) Option {
+	return func(ps *Parser) {
+		ps.SVGPolicy = policy
+		ps.SVGMinSize = minSize
+	}
+}
+
+// WithMaxElemsToParse sets the max number of elements in the document.
+// When the document has more elements, ErrTooManyElements is returned.
+func WithMaxElemsToParse(n int) Option {
+	return func(ps *Parser) {
+		ps.MaxElemsToParse = n
+	}
+}
+
+// WithMaxElementDepth sets the max depth of nested elements. When the
+// document is deeper, ErrTooDeep is returned. Use 0 for no limit.
+func WithMaxElementDepth(n int) Option {
+	return func(ps *Parser) {
+		ps.MaxElementDepth = n
+	}
+}
+
+// WithMaxParseDuration sets the max duration of each parse. When the parse
+// takes longer, it's aborted with ErrParseTimeout.
+func WithMaxParseDuration(d time.Duration) Option {
+	return func(ps *Parser) {
+		ps.MaxParseDuration = d
+	}
+}
+
+// WithDisableJSONLD specifies whether the metadata in JSON-LD should not
+// be extracted.
+func WithDisableJSONLD(disable bool) Option {
+	return func(ps *Parser) {
+		ps.DisableJSONLD = disable
+	}
+}
+
+// WithMetadataPrecedence sets the order of sources that used when they
+// have different value of the same metadata.
+func WithMetadataPrecedence(sources ...MetadataSource) Option {
+	return func(ps *Parser) {
+		ps.MetadataPrecedence = sources
+	}
+}
+
+// WithSerializer sets the function that used to convert the article
+// content into HTML string.
+func WithSerializer(serializer func(*html.Node) (string, error)) Option {
+	return func(ps *Parser) {
+		ps.Serializer = serializer
+	}
+}
+
+// WithLinkDensityModifier sets the value that added to the link density
+// thresholds when checking the link-heavy sections.
+func WithLinkDensityModifier(modifier float64) Option {
+	return func(ps *Parser) {
+		ps.LinkDensityModifier = modifier
+	}
+}
+
+// WithDiagnostics specifies whether the report of the extraction should
+// be returned in Article.Diagnostics.
+func WithDiagnostics(collect bool) Option {
+	return func(ps *Parser) {
+		ps.CollectDiagnostics = collect
+	}
+}
+
+// WithLogger sets the logger that used to log the decisions that made by
+// the parser.
+func WithLogger(logger Logger) Option {
+	return func(ps *Parser) {
+		ps.Logger = logger
+	}
+}
+
+// WithSections specifies whether the article content should be split into
+// sections by its headings.
+func WithSections(extract bool) Option {
+	return func(ps *Parser) {
+		ps.ExtractSections = extract
+	}
+}
+
+// WithNormalizeHeadings sets the level of the top headings in the article
+// content, and enables the normalization of heading hierarchy.
+func WithNormalizeHeadings(baseLevel int) Option {
+	return func(ps *Parser) {
+		ps.HeadingBaseLevel = baseLevel
+	}
+}
+
+// WithReadingSpeed sets the reading speed that used to estimate the reading
+// time, in words per minute and CJK characters per minute.
+func WithReadingSpeed(wordsPerMinute, cjkCharsPerMinute int) Option {
+	return func(ps *Parser) {
+		ps.WordsPerMinute = wordsPerMinute
+		ps.CJKCharsPerMinute = cjkCharsPerMinute
+	}
+}
+
+// WithDetectLanguage specifies whether the language of the article should
+// be taken from meta tags or detected from its text, when the document
+// doesn't specify it in <html lang>.
+func WithDetectLanguage(detect bool) Option {
+	return func(ps *Parser) {
+		ps.DetectLanguage = detect
+	}
+}
+
+// WithPropagateDir specifies whether the text direction of the article
+// should be set as dir attribute in the article content.
+func WithPropagateDir(propagate bool) Option {
+	return func(ps *Parser) {
+		ps.PropagateDir = propagate
+	}
+}
+
+// WithMicroformats specifies whether the microformats2 h-entry should be
+// used for the metadata and as hint of the article content.
+func WithMicroformats(use bool) Option {
+	return func(ps *Parser) {
+		ps.UseMicroformats = use
+	}
+}
+
+// WithMicrodata specifies whether the Schema.org article in microdata should
+// be used for the metadata and as hint of the article content.
+func WithMicrodata(use bool) Option {
+	return func(ps *Parser) {
+		ps.UseMicrodata = use
+	}
+}
+
+// WithComments specifies whether the comments of the article should be
+// extracted into Article.Comments.
+func WithComments(extract bool) Option {
+	return func(ps *Parser) {
+		ps.ExtractComments = extract
+	}
+}
+
+// WithReadabilityMetrics specifies whether the readability scores of the
+// article should be computed into Article.Readability.
+func WithReadabilityMetrics(extract bool) Option {
+	return func(ps *Parser) {
+		ps.ExtractReadabilityMetrics = extract
+	}
+}
+
+// WithLinks specifies whether the links in the page should be listed in
+// Article.Links.
+func WithLinks(extract bool) Option {
+	return func(ps *Parser) {
+		ps.ExtractLinks = extract
+	}
+}
+
+// WithStripTrackingParams enables the removal of tracking parameters from
+// the URLs in the article content. If params is specified, it's used
+// instead of DefaultTrackingParams.
+func WithStripTrackingParams(params ...string) Option {
+	return func(ps *Parser) {
+		ps.StripTrackingParams = true
+		if len(params) > 0 {
+			ps.TrackingParams = params
+		}
+	}
+}
+
+// WithAbsoluteURLs specifies whether the relative URLs in the article
+// content should be converted into absolute URLs.
+func WithAbsoluteURLs(absolute bool) Option {
+	return func(ps *Parser) {
+		ps.DisableAbsoluteURLs = !absolute
+	}
+}
+
+// WithBaseURL sets the URL that used to resolve the relative URLs in the
+// article content, instead of the page URL.
+func WithBaseURL(baseURL *nurl.URL) Option {
+	return func(ps *Parser) {
+		ps.BaseURL = baseURL
+	}
+}
+
+// WithURLRewriter sets the function that used to rewrite each URL in the
+// article content.
+func WithURLRewriter(rewriter URLRewriter) Option {
+	return func(ps *Parser) {
+		ps.URLRewriter = rewriter
+	}
+}
+
+// WithKeywords enables the extraction of key phrases into Article.Keywords.
+// If max is positive, it's used instead of DefaultMaxKeywords.
+func WithKeywords(max int) Option {
+	return func(ps *Parser) {
+		ps.ExtractKeywords = true
+		if max > 0 {
+			ps.MaxKeywords = max
+		}
+	}
+}
+
+// WithRules sets the registry of site rules that used by the parser.
+func WithRules(rules *Rules) Option {
+	return func(ps *Parser) {
+		ps.Rules = rules
+	}
+}
+
+// WithPreprocessors appends the functions that called with the whole
+// document before it's prepared and scored.
+func WithPreprocessors(fns ...func(*html.Node)) Option {
+	return func(ps *Parser) {
+		ps.Preprocessors = append(ps.Preprocessors, fns...)
+	}
+}
+
+// WithPostprocessors appends the functions that called with the container
+// of article content after it's cleaned up.
+func WithPostprocessors(fns ...func(*html.Node)) Option {
+	return func(ps *Parser) {
+		ps.Postprocessors = append(ps.Postprocessors, fns...)
+	}
+}
+
+// WithScoringWeights sets the class patterns and tag scores that used to
+// score the candidates.
+func WithScoringWeights(weights ScoringWeights) Option {
+	return func(ps *Parser) {
+		ps.ScoringWeights = weights
+	}
+}
+
+// WithAlgorithm sets the algorithm that used to find the article content.
+func WithAlgorithm(algorithm Algorithm) Option {
+	return func(ps *Parser) {
+		ps.Algorithm = algorithm
+	}
+}
+
+// WithSanitize enables the strict sanitizer using the specified policy.
+// If the policy is nil, DefaultSanitizePolicy will be used.
+func WithSanitize(policy *SanitizePolicy) Option {
+	return func(ps *Parser) {
+		ps.Sanitize = true
+		ps.SanitizePolicy = policy
+	}
+}
+
+// WithKeepAttributes sets the attributes that kept when the article content
+// is cleaned up. Attribute that ends with "*" matches by prefix.
+func WithKeepAttributes(attributes ...string) Option {
+	return func(ps *Parser) {
+		ps.KeepAttributes = attributes
+	}
+}
+
+// WithKeepStyles sets the CSS properties that kept in the style attribute.
+func WithKeepStyles(properties ...string) Option {
+	return func(ps *Parser) {
+		ps.KeepStyles = properties
+	}
+}
+
+// WithAttributeFilter sets the function that decides whether each attribute
+// is kept when the article content is cleaned up.
+func WithAttributeFilter(filter AttributeFilter) Option {
+	return func(ps *Parser) {
+		ps.AttributeFilter = filter
+	}
+}
+
+// WithConsentPatterns sets the class names and ids of consent banners that
+// removed before scoring.
+func WithConsentPatterns(patterns ...string) Option {
+	return func(ps *Parser) {
+		ps.ConsentPatterns = patterns
+	}
+}
+
+// WithKeepConsentBanners specifies whether the cookie banners and consent
+// overlays should be kept.
+func WithKeepConsentBanners(keep bool) Option {
+	return func(ps *Parser) {
+		ps.KeepConsentBanners = keep
+	}
+}
+
+// WithDisablePromoRemoval specifies whether the newsletter signup boxes and
+// call to action cards should be kept in the article content.
+func WithDisablePromoRemoval(disable bool) Option {
+	return func(ps *Parser) {
+		ps.DisablePromoRemoval = disable
+	}
+}
+
+// WithSharePatterns sets the class names and ids of share buttons and
+// social widgets that removed from the article content.
+func WithSharePatterns(patterns ...string) Option {
+	return func(ps *Parser) {
+		ps.SharePatterns = patterns
+	}
+}
+
+// WithDisableShareBarRemoval specifies whether the share bars that only
+// identified by their links should be kept in the article content.
+func WithDisableShareBarRemoval(disable bool) Option {
+	return func(ps *Parser) {
+		ps.DisableShareBarRemoval = disable
+	}
+}
+
+// WithRemovedRegions specifies whether the regions that not included in the
+// article content should be listed in Article.RemovedRegions.
+func WithRemovedRegions(extract bool) Option {
+	return func(ps *Parser) {
+		ps.ExtractRemovedRegions = extract
+	}
+}
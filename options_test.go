@@ -0,0 +1,41 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_NewParser(t *testing.T) {
+	ps := NewParser(
+		WithCharThresholds(100),
+		WithNTopCandidates(10),
+		WithTagsToScore("p", "pre"),
+		WithClassesToPreserve("page", "highlight"),
+		WithKeepClasses(true),
+	)
+
+	if ps.CharThresholds != 100 {
+		t.Errorf("char thresholds, want %d got %d", 100, ps.CharThresholds)
+	}
+
+	if ps.NTopCandidates != 10 {
+		t.Errorf("top candidates, want %d got %d", 10, ps.NTopCandidates)
+	}
+
+	if tags := strings.Join(ps.TagsToScore, ","); tags != "p,pre" {
+		t.Errorf("tags to score, want %q got %q", "p,pre", tags)
+	}
+
+	if classes := strings.Join(ps.ClassesToPreserve, ","); classes != "page,highlight" {
+		t.Errorf("classes to preserve, want %q got %q", "page,highlight", classes)
+	}
+
+	if !ps.KeepClasses {
+		t.Errorf("keep classes, want %v got %v", true, ps.KeepClasses)
+	}
+
+	// Options that are not specified should keep their default value
+	if ps.MaxElemsToParse != 0 || ps.Debug {
+		t.Errorf("unspecified options should keep default value")
+	}
+}
@@ -0,0 +1,243 @@
+package readability
+
+import (
+	"fmt"
+	nurl "net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-shiori/dom"
+	"golang.org/x/net/html"
+)
+
+var (
+	rxNextLinkText = regexp.MustCompile(`(?i)^(next|next page|weiter|suivant|siguiente)?\s*[›»→>]*$`)
+	rxPageOfText   = regexp.MustCompile(`(?i)\bpage\s+(\d+)(\s+of\s+\d+)?$`)
+	rxPageQuery    = regexp.MustCompile(`(?i)^(page|p|pg|paged)$`)
+	rxPagePath     = regexp.MustCompile(`(?i)/page/(\d+)/?$`)
+)
+
+// getNextPageURL returns the absolute URL of the next page when the
+// article is paginated. The next page is detected from rel="next" link,
+// "next" or "page N" anchor, and "?page=N" or "/page/N" pattern in URL.
+func (ps *Parser) getNextPageURL() string {
+	// rel="next" is the most reliable, so use it whenever it exists
+	for _, link := range dom.QuerySelectorAll(ps.doc, `link[rel][href], a[rel][href]`) {
+		for _, rel := range strings.Fields(dom.GetAttribute(link, "rel")) {
+			if strings.EqualFold(rel, "next") {
+				if nextURL := ps.validNextPageURL(dom.GetAttribute(link, "href")); nextURL != "" {
+					return nextURL
+				}
+			}
+		}
+	}
+
+	currentPage := pageNumber(ps.documentURI)
+	for _, a := range dom.GetElementsByTagName(ps.doc, "a") {
+		href := dom.GetAttribute(a, "href")
+		nextURL := ps.validNextPageURL(href)
+		if nextURL == "" {
+			continue
+		}
+
+		// Link to the next page usually has the page number in its URL,
+		// which separates it from link to the next post in a blog.
+		parsedURL, err := nurl.Parse(nextURL)
+		if err != nil || !ps.isSubsequentPage(parsedURL, currentPage) {
+			continue
+		}
+
+		text := strings.TrimSpace(rxRenderSpaces.ReplaceAllString(dom.TextContent(a), " "))
+		if text == "" {
+			continue
+		}
+
+		if rxNextLinkText.MatchString(text) || text == strconv.Itoa(currentPage+1) {
+			return nextURL
+		}
+
+		if parts := rxPageOfText.FindStringSubmatch(text); len(parts) > 1 && parts[1] == strconv.Itoa(currentPage+1) {
+			return nextURL
+		}
+	}
+
+	return ""
+}
+
+// validNextPageURL converts href into absolute URL, then makes sure it
+// points to another page in the same site. Returns empty string if it's
+// not a valid next page.
+func (ps *Parser) validNextPageURL(href string) string {
+	href = strings.TrimSpace(href)
+	if href == "" || strings.HasPrefix(href, "#") || strings.HasPrefix(href, "javascript:") {
+		return ""
+	}
+
+	nextURL, err := nurl.Parse(toAbsoluteURI(href, ps.documentURI))
+	if err != nil || (nextURL.Scheme != "http" && nextURL.Scheme != "https") {
+		return ""
+	}
+	nextURL.Fragment = ""
+
+	if ps.documentURI != nil {
+		if !strings.EqualFold(nextURL.Hostname(), ps.documentURI.Hostname()) {
+			return ""
+		}
+
+		currentURL := *ps.documentURI
+		currentURL.Fragment = ""
+		if nextURL.String() == currentURL.String() {
+			return ""
+		}
+	}
+
+	return nextURL.String()
+}
+
+// isSubsequentPage checks if nextURL looks like the page after the
+// current page, i.e. it has the next page number in its query or path,
+// or its path is the current path followed by a number.
+func (ps *Parser) isSubsequentPage(nextURL *nurl.URL, currentPage int) bool {
+	if pageNumber(nextURL) == currentPage+1 {
+		return true
+	}
+
+	if ps.documentURI == nil {
+		return false
+	}
+
+	currentPath := strings.TrimSuffix(ps.documentURI.Path, "/")
+	nextPath := strings.TrimSuffix(nextURL.Path, "/")
+	if !strings.HasPrefix(nextPath, currentPath) || len(nextPath) == len(currentPath) {
+		return false
+	}
+
+	suffix := strings.TrimLeft(nextPath[len(currentPath):], "/-_")
+	_, err := strconv.Atoi(suffix)
+	return err == nil
+}
+
+// pageNumber returns the page number that specified in URL, either in
+// query like "?page=N" or in path like "/page/N". Returns 1 if there
+// are no page number.
+func pageNumber(pageURL *nurl.URL) int {
+	if pageURL == nil {
+		return 1
+	}
+
+	for key, values := range pageURL.Query() {
+		if rxPageQuery.MatchString(key) && len(values) > 0 {
+			if number, err := strconv.Atoi(values[0]); err == nil {
+				return number
+			}
+		}
+	}
+
+	if parts := rxPagePath.FindStringSubmatch(pageURL.Path); len(parts) == 2 {
+		if number, err := strconv.Atoi(parts[1]); err == nil {
+			return number
+		}
+	}
+
+	return 1
+}
+
+// appendPage merges the content of the next page into article. Each page
+// is kept in its own div with id "readability-page-N". The merged content
+// is serialized using the serializer of parser.
+func (ps *Parser) appendPage(article *Article, next Article, number int) error {
+	article.NextPageURL = next.NextPageURL
+	article.Truncated = article.Truncated || next.Truncated
+	article.Media = append(article.Media, next.Media...)
+	article.Images = append(article.Images, next.Images...)
+	article.Videos = append(article.Videos, next.Videos...)
+	article.Audios = append(article.Audios, next.Audios...)
+	article.RemovedRegions = append(article.RemovedRegions, next.RemovedRegions...)
+	if next.Node == nil {
+		return nil
+	}
+
+	if article.Node == nil {
+		*article = next
+		return nil
+	}
+
+	// The container of the pages is the parent of the first page. Make
+	// sure it exists, since the node may be created manually.
+	container := article.Node.Parent
+	if container == nil {
+		container = dom.CreateElement("div")
+		dom.AppendChild(container, article.Node)
+	}
+
+	page := dom.Clone(next.Node, true)
+	dom.SetAttribute(page, "id", fmt.Sprintf("readability-page-%d", number))
+	dom.AppendChild(container, page)
+
+	return ps.updateContent(article, container)
+}
+
+// replaceContent replaces the content of article with the content of
+// replacement, which is another version of the same article, e.g. its
+// AMP or single page version. The fields that derived from the content
+// are replaced as well, while the metadata of article is kept as it is.
+// The article is not modified if the content can't be serialized.
+func (ps *Parser) replaceContent(article *Article, replacement Article) error {
+	updated := *article
+	updated.Node = replacement.Node
+	updated.Truncated = replacement.Truncated
+	updated.Media = replacement.Media
+	updated.Images = replacement.Images
+	updated.Videos = replacement.Videos
+	updated.Audios = replacement.Audios
+	updated.Strategy = replacement.Strategy
+	updated.Confidence = replacement.Confidence
+	updated.RemovedRegions = replacement.RemovedRegions
+	if updated.Excerpt == "" {
+		updated.Excerpt = replacement.Excerpt
+	}
+
+	if err := ps.updateContent(&updated, pagesContainer(replacement.Node)); err != nil {
+		return err
+	}
+
+	*article = updated
+	return nil
+}
+
+// updateContent serializes container as the content of article, then
+// recomputes all fields that derived from the content.
+func (ps *Parser) updateContent(article *Article, container *html.Node) error {
+	content, err := ps.serialize(container)
+	if err != nil {
+		return err
+	}
+
+	if ps.ExtractSections {
+		article.Sections = ps.getSections(container)
+	}
+
+	if ps.ExtractLinks {
+		article.Links = ps.getLinks(container)
+	}
+
+	article.Content = content
+	article.TextContent = strings.TrimSpace(dom.TextContent(container))
+	article.Length = charCount(article.TextContent)
+	article.ReadingTime = ps.estimateReadingTime(article.TextContent)
+	article.WordCount = articleWordCount(article.TextContent)
+	article.CharCount = nonSpaceCharCount(article.TextContent)
+	article.ParagraphCount = paragraphCount(container)
+	article.Readability = ps.getReadabilityMetrics(article.TextContent, article.Language)
+	article.Keywords = ps.getKeywords(article.TextContent, article.Language)
+	return nil
+}
+
+// pagesContainer returns the node that contains all pages of the article.
+func pagesContainer(node *html.Node) *html.Node {
+	if node != nil && node.Parent != nil {
+		return node.Parent
+	}
+	return node
+}
@@ -0,0 +1,41 @@
+package readability
+
+import (
+	nurl "net/url"
+	"strings"
+	"testing"
+
+	"github.com/go-shiori/dom"
+)
+
+func Test_getNextPageURL(t *testing.T) {
+	scenarios := map[string]string{
+		`<link rel="next" href="/article?page=2">`:                     "http://example.com/article?page=2",
+		`<a rel="prev next" href="http://example.com/article/2">2</a>`: "http://example.com/article/2",
+		`<a href="/article?page=2">Next »</a>`:                         "http://example.com/article?page=2",
+		`<a href="/article/page/2/">Page 2 of 3</a>`:                   "http://example.com/article/page/2/",
+		`<a href="/article-2">2</a>`:                                   "http://example.com/article-2",
+		`<a href="/another-article">Next »</a>`:                        "",
+		`<a href="http://other.com/article?page=2">Next</a>`:           "",
+		`<a href="/article?page=3">3</a>`:                              "",
+		`<a href="/article#page-2">Next</a>`:                           "",
+	}
+
+	pageURL, _ := nurl.ParseRequestURI("http://example.com/article")
+	for input, expected := range scenarios {
+		doc, err := dom.Parse(strings.NewReader(input))
+		if err != nil {
+			t.Fatalf("failed to parse %q: %v", input, err)
+		}
+
+		ps := NewParser()
+		ps.doc = doc
+		ps.documentURI = pageURL
+		if result := ps.getNextPageURL(); result != expected {
+			t.Errorf("\n"+
+				"html : %q\n"+
+				"want : %q\n"+
+				"got  : %q", input, expected, result)
+		}
+	}
+}
@@ -0,0 +1,287 @@
+package readability
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"unicode"
+
+	"github.com/go-shiori/dom"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+	"golang.org/x/net/html/charset"
+)
+
+// CheckStream is like Check, but the input is scanned by the HTML tokenizer
+// instead of being parsed into DOM, and the scan stops as soon as the score
+// is enough. Only the open elements are kept in memory, so it's cheap to
+// pre-filter the pages of large crawl. Since the tree is never built, the
+// implied end tags are only guessed for the common cases, so the result
+// may be different with Check for the badly broken markup.
+func (ps *Parser) CheckStream(input io.Reader) bool {
+	return ps.CheckStreamWithOptions(input, CheckOptions{})
+}
+
+// CheckStreamWithOptions is like CheckStream, but uses the specified options.
+// The nodes that passed to VisibilityChecker only have the tag name and the
+// attributes, without parent and children.
+func (ps *Parser) CheckStreamWithOptions(input io.Reader, opts CheckOptions) (readable bool) {
+	defer ps.recoverPanic(nil)
+
+	minScore := opts.MinScore
+	if minScore <= 0 {
+		minScore = DefaultCheckMinScore
+	}
+
+	minContentLength := opts.MinContentLength
+	if minContentLength <= 0 {
+		minContentLength = DefaultCheckMinContentLength
+	}
+
+	isVisible := opts.VisibilityChecker
+	if isVisible == nil {
+		isVisible = ps.isProbablyVisible
+	}
+
+	reader, err := charset.NewReader(input, "")
+	if err != nil {
+		return false
+	}
+
+	s := checkScanner{
+		parser:           ps,
+		tokenizer:        html.NewTokenizer(reader),
+		isVisible:        isVisible,
+		minContentLength: minContentLength,
+	}
+
+	for s.score <= minScore {
+		switch tokenType := s.tokenizer.Next(); tokenType {
+		case html.ErrorToken:
+			// Either EOF or broken input, in both cases the open elements
+			// are closed, just like the end of document
+			s.closeElements(0)
+			return s.score > minScore
+		case html.TextToken:
+			s.text(s.tokenizer.Text())
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, hasAttr := s.tokenizer.TagName()
+			s.startTag(tagString(name), hasAttr, tokenType == html.SelfClosingTagToken)
+		case html.EndTagToken:
+			name, _ := s.tokenizer.TagName()
+			s.endTag(tagString(name))
+		}
+	}
+
+	return true
+}
+
+// checkElement is an open element in checkScanner.
+type checkElement struct {
+	tag string
+	// counted is true if the element is scored once it's closed, i.e. it's
+	// visible and likely candidate, and it's not paragraph in list item.
+	counted bool
+	// hasBR is true if the element has <br> as its direct child, which is
+	// required for <div> to be scored.
+	hasBR bool
+	// length is the length of the trimmed text content, while pending is
+	// the length of whitespaces after it, which are only counted once it's
+	// followed by another text.
+	length  int
+	pending int
+	started bool
+}
+
+// checkScanner scores the tokens of document the same way as checkScore
+// does with the nodes.
+type checkScanner struct {
+	parser           *Parser
+	tokenizer        *html.Tokenizer
+	isVisible        func(*html.Node) bool
+	minContentLength int
+
+	stack []checkElement
+	score float64
+}
+
+// checkVoidTags is the tags that never have any content, so they are never
+// pushed into the stack.
+var checkVoidTags = sliceToMap("area", "base", "br", "col", "embed", "hr", "img", "input",
+	"keygen", "link", "meta", "param", "source", "track", "wbr")
+
+// checkParagraphClosers is the tags whose start tag implies the end of the
+// open paragraph.
+var checkParagraphClosers = sliceToMap("address", "article", "aside", "blockquote", "details",
+	"div", "dl", "fieldset", "figcaption", "figure", "footer", "form", "h1", "h2", "h3", "h4",
+	"h5", "h6", "header", "hgroup", "hr", "main", "menu", "nav", "ol", "p", "pre", "section",
+	"table", "ul")
+
+// checkScopeTags is the tags that stop the search of implicitly closed
+// element, since the element outside of them is not affected.
+var checkScopeTags = sliceToMap("applet", "button", "caption", "html", "marquee", "object",
+	"table", "td", "th", "template")
+
+// startTag handles the start tag, whose attributes are only read when
+// it's candidate.
+func (s *checkScanner) startTag(tag string, hasAttr, selfClosing bool) {
+	if _, isCloser := checkParagraphClosers[tag]; isCloser {
+		s.closeImplied("p", nil)
+	}
+
+	switch tag {
+	case "li":
+		s.closeImplied("li", []string{"ul", "ol"})
+	case "dd", "dt":
+		s.closeImplied("dd", []string{"dl"})
+		s.closeImplied("dt", []string{"dl"})
+	}
+
+	if _, isVoid := checkVoidTags[tag]; isVoid {
+		if tag == "br" && len(s.stack) > 0 {
+			s.stack[len(s.stack)-1].hasBR = true
+		}
+		return
+	}
+
+	// Self closing syntax is ignored by HTML elements, so it's only
+	// respected in foreign content like SVG and MathML
+	if selfClosing && s.inForeignContent() {
+		return
+	}
+
+	element := checkElement{tag: tag}
+	switch tag {
+	case "p", "pre", "article", "div":
+		element.counted = s.isCandidate(tag, hasAttr)
+	}
+	s.stack = append(s.stack, element)
+}
+
+// endTag handles the end tag of name, which closes the matched element and
+// any element inside of it.
+func (s *checkScanner) endTag(name string) {
+	if name == "br" {
+		// </br> is parsed as <br>
+		if len(s.stack) > 0 {
+			s.stack[len(s.stack)-1].hasBR = true
+		}
+		return
+	}
+
+	for i := len(s.stack) - 1; i >= 0; i-- {
+		if s.stack[i].tag == name {
+			s.closeElements(i)
+			return
+		}
+	}
+}
+
+// closeImplied closes the open element of tag whose end tag is implied,
+// unless there is a scope tag or one of the stoppers in between.
+func (s *checkScanner) closeImplied(tag string, stoppers []string) {
+	for i := len(s.stack) - 1; i >= 0; i-- {
+		current := s.stack[i].tag
+		if current == tag {
+			s.closeElements(i)
+			return
+		}
+
+		if _, isScope := checkScopeTags[current]; isScope || indexOf(stoppers, current) >= 0 {
+			return
+		}
+	}
+}
+
+// closeElements closes the open elements from index start to the top of
+// stack, and scores them.
+func (s *checkScanner) closeElements(start int) {
+	for i := len(s.stack) - 1; i >= start; i-- {
+		element := s.stack[i]
+		if !element.counted || (element.tag == "div" && !element.hasBR) {
+			continue
+		}
+
+		if element.length >= s.minContentLength {
+			s.score += math.Sqrt(float64(element.length - s.minContentLength))
+		}
+	}
+	s.stack = s.stack[:start]
+}
+
+// text adds the length of text into the open candidates. The text is
+// split into the leading whitespaces, the content and the trailing
+// whitespaces, so it can be trimmed in each candidate without copying.
+func (s *checkScanner) text(data []byte) {
+	content := bytes.TrimLeftFunc(data, unicode.IsSpace)
+	leading := len(data) - len(content)
+	content = bytes.TrimRightFunc(content, unicode.IsSpace)
+	trailing := len(data) - leading - len(content)
+
+	for i := range s.stack {
+		element := &s.stack[i]
+		if !element.counted {
+			continue
+		}
+
+		switch {
+		case len(content) == 0:
+			if element.started {
+				element.pending += len(data)
+			}
+		case element.started:
+			element.length += element.pending + leading + len(content)
+			element.pending = trailing
+		default:
+			element.length = len(content)
+			element.pending = trailing
+			element.started = true
+		}
+	}
+}
+
+// isCandidate checks if the current element would be scored by checkScore.
+func (s *checkScanner) isCandidate(tag string, hasAttr bool) bool {
+	node := &html.Node{Type: html.ElementNode, DataAtom: atom.Lookup([]byte(tag)), Data: tag}
+	for hasAttr {
+		var key, val []byte
+		key, val, hasAttr = s.tokenizer.TagAttr()
+		node.Attr = append(node.Attr, html.Attribute{Key: string(key), Val: string(val)})
+	}
+
+	if !s.isVisible(node) {
+		return false
+	}
+
+	if s.parser.isUnlikelyCandidate(dom.ClassName(node) + " " + dom.ID(node)) {
+		return false
+	}
+
+	if tag == "p" {
+		for _, element := range s.stack {
+			if element.tag == "li" {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// tagString returns the tag name as string, without allocation for the
+// known tags.
+func tagString(name []byte) string {
+	if a := atom.Lookup(name); a != 0 {
+		return a.String()
+	}
+	return string(name)
+}
+
+// inForeignContent checks if the scanner is inside SVG or MathML.
+func (s *checkScanner) inForeignContent() bool {
+	for _, element := range s.stack {
+		if element.tag == "svg" || element.tag == "math" {
+			return true
+		}
+	}
+	return false
+}
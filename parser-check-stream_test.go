@@ -0,0 +1,136 @@
+package readability
+
+import (
+	"io"
+	"os"
+	fp "path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-shiori/dom"
+	"golang.org/x/net/html"
+)
+
+func Test_Parser_CheckStreamWithOptions(t *testing.T) {
+	paragraph := articleParagraph(10)
+	input := "<html><body>" + paragraph + "</body></html>"
+
+	scenarios := map[string]struct {
+		opts     CheckOptions
+		expected bool
+	}{
+		"default":            {CheckOptions{}, true},
+		"min content length": {CheckOptions{MinContentLength: 700}, false},
+		"min score":          {CheckOptions{MinScore: 30}, false},
+		"visibility checker": {CheckOptions{VisibilityChecker: func(node *html.Node) bool {
+			return dom.TagName(node) != "p"
+		}}, false},
+	}
+
+	for name, scenario := range scenarios {
+		if readable := CheckStreamWithOptions(strings.NewReader(input), scenario.opts); readable != scenario.expected {
+			t.Errorf("%s, want %v got %v", name, scenario.expected, readable)
+		}
+	}
+}
+
+func Test_Parser_CheckStream(t *testing.T) {
+	sentences := strings.Repeat(articleSentence, 10)
+
+	// Each input must give the same result as Check
+	scenarios := map[string]bool{
+		"<p>" + sentences + "</p>":                                              true,
+		"<p>" + sentences:                                                       true,
+		"<p>  " + sentences + "  <b>bold</b>  </p>":                             true,
+		"<p hidden>" + sentences + "</p>":                                       false,
+		`<p style="display: none">` + sentences + "</p>":                        false,
+		`<p class="comment">` + sentences + "</p>":                              false,
+		"<ul><li><p>" + sentences + "</p></li></ul>":                            false,
+		"<ul><li>short<li><p>" + sentences + "</p></ul>":                        false,
+		"<ul><li>short</ul><p>" + sentences + "</p>":                            true,
+		"<div>" + sentences + "</div>":                                          false,
+		"<div>" + sentences + "<br>" + sentences + "</div>":                     true,
+		"<div><span>" + sentences + "<br></span>" + sentences + "</div>":        false,
+		"<p>" + sentences[:100] + "<div>" + sentences[100:] + "</div></p>":      false,
+		"<pre>" + sentences + "</pre>":                                          true,
+		"<article>" + sentences + "</article>":                                  true,
+		"<script><p>" + sentences + "</p></script>":                             false,
+		"<p>" + sentences[:100] + "<!-- comment -->" + sentences[100:] + "</p>": true,
+		"<svg><p/></svg><p>" + sentences + "</p>":                               true,
+	}
+
+	for input, expected := range scenarios {
+		if readable := Check(strings.NewReader(input)); readable != expected {
+			t.Fatalf("invalid scenario %q, Check returns %v", input, readable)
+		}
+
+		if readable := CheckStream(strings.NewReader(input)); readable != expected {
+			t.Errorf("\n"+
+				"html : %q\n"+
+				"want : %v\n"+
+				"got  : %v", input, expected, readable)
+		}
+	}
+}
+
+// failingReader fails the test if it's read after the limit.
+type failingReader struct {
+	t      *testing.T
+	reader io.Reader
+	limit  int
+	read   int
+}
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	if r.read >= r.limit {
+		r.t.Fatalf("input is still read after %d bytes", r.read)
+	}
+
+	n, err := r.reader.Read(p)
+	r.read += n
+	return n, err
+}
+
+func Test_Parser_CheckStream_earlyExit(t *testing.T) {
+	paragraph := articleParagraph(10)
+	input := strings.Repeat(paragraph, 5) + strings.Repeat("<div>filler</div>", 100000)
+
+	reader := &failingReader{t: t, reader: strings.NewReader(input), limit: 64 * 1024}
+	if !CheckStream(reader) {
+		t.Error("want readable, got not readable")
+	}
+}
+
+func Test_Parser_CheckStream_testPages(t *testing.T) {
+	parser := NewParser()
+	dirs, err := fp.Glob(fp.Join("test-pages", "*", "source.html"))
+	if err != nil {
+		t.Fatalf("failed to list test pages: %v", err)
+	}
+
+	for _, path := range dirs {
+		source, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", path, err)
+		}
+
+		expected := parser.Check(strings.NewReader(string(source)))
+		if readable := parser.CheckStream(strings.NewReader(string(source))); readable != expected {
+			t.Errorf("%s, want %v got %v", path, expected, readable)
+		}
+	}
+}
+
+func Benchmark_Parser_CheckStream(b *testing.B) {
+	source, err := os.ReadFile(fp.Join("test-pages", "wikipedia", "source.html"))
+	if err != nil {
+		b.Fatalf("failed to read source: %v", err)
+	}
+
+	parser := NewParser()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		parser.CheckStream(strings.NewReader(string(source)))
+	}
+}
@@ -1,6 +1,7 @@
 package readability
 
 import (
+	"fmt"
 	"io"
 	"math"
 	"strings"
@@ -9,19 +10,95 @@ import (
 	"golang.org/x/net/html"
 )
 
+// Default values of CheckOptions, which are the same as Readability.js.
+const (
+	DefaultCheckMinContentLength = 140
+	DefaultCheckMinScore         = 20
+)
+
+// CheckOptions is the options for checking whether the document is
+// readable. The zero value of each field means the default value.
+type CheckOptions struct {
+	// MinContentLength is the min length of text in a node for it to be
+	// counted. Default: DefaultCheckMinContentLength.
+	MinContentLength int
+	// MinScore is the min accumulated score of the nodes for the document
+	// to be readable. Default: DefaultCheckMinScore.
+	MinScore float64
+	// VisibilityChecker is used to check whether a node is visible. Default:
+	// nil (use the same check as the parser).
+	VisibilityChecker func(*html.Node) bool
+}
+
 // Check checks whether the input is readable without parsing the whole thing.
 func (ps *Parser) Check(input io.Reader) bool {
+	return ps.CheckWithOptions(input, CheckOptions{})
+}
+
+// CheckWithOptions is like Check, but uses the specified options.
+func (ps *Parser) CheckWithOptions(input io.Reader, opts CheckOptions) (readable bool) {
+	defer ps.recoverPanic(nil)
+
 	// Parse input
 	doc, err := dom.Parse(input)
 	if err != nil {
 		return false
 	}
 
-	return ps.CheckDocument(doc)
+	return ps.CheckDocumentWithOptions(doc, opts)
 }
 
 // CheckDocument checks whether the document is readable without parsing the whole thing.
 func (ps *Parser) CheckDocument(doc *html.Node) bool {
+	return ps.CheckDocumentWithOptions(doc, CheckOptions{})
+}
+
+// CheckDocumentWithOptions is like CheckDocument, but uses the specified options.
+func (ps *Parser) CheckDocumentWithOptions(doc *html.Node, opts CheckOptions) (readable bool) {
+	defer ps.recoverPanic(nil)
+
+	minScore := opts.MinScore
+	if minScore <= 0 {
+		minScore = DefaultCheckMinScore
+	}
+
+	return ps.checkScore(doc, opts, minScore) > minScore
+}
+
+// CheckScore returns the readability score of the input, which is used by
+// Check to decide whether the input is readable. Unlike Check, the score is
+// calculated from the whole document, so it can be used to rank the inputs.
+func (ps *Parser) CheckScore(input io.Reader) (_ float64, err error) {
+	defer ps.recoverPanic(&err)
+
+	// Parse input
+	doc, err := dom.Parse(input)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse input: %v", err)
+	}
+
+	return ps.CheckDocumentScore(doc), nil
+}
+
+// CheckDocumentScore returns the readability score of the document.
+func (ps *Parser) CheckDocumentScore(doc *html.Node) (score float64) {
+	defer ps.recoverPanic(nil)
+	return ps.checkScore(doc, CheckOptions{}, -1)
+}
+
+// checkScore calculates the readability score of the document. It stops
+// as soon as the score exceeds stopAt, unless stopAt is negative.
+func (ps *Parser) checkScore(doc *html.Node, opts CheckOptions, stopAt float64) float64 {
+	minContentLength := opts.MinContentLength
+	if minContentLength <= 0 {
+		minContentLength = DefaultCheckMinContentLength
+	}
+
+	isVisible := opts.VisibilityChecker
+	if isVisible == nil {
+		isVisible = ps.isProbablyVisible
+	}
+
 	// Get <p> and <pre> nodes.
 	nodes := dom.QuerySelectorAll(doc, "p, pre, article")
 
@@ -52,14 +129,13 @@ func (ps *Parser) CheckDocument(doc *html.Node) bool {
 	// This is a little cheeky, we use the accumulator 'score' to decide what
 	// to return from this callback.
 	score := float64(0)
-	return ps.someNode(nodes, func(node *html.Node) bool {
-		if !ps.isProbablyVisible(node) {
+	ps.someNode(nodes, func(node *html.Node) bool {
+		if !isVisible(node) {
 			return false
 		}
 
 		matchString := dom.ClassName(node) + " " + dom.ID(node)
-		if rxUnlikelyCandidates.MatchString(matchString) &&
-			!rxOkMaybeItsACandidate.MatchString(matchString) {
+		if ps.isUnlikelyCandidate(matchString) {
 			return false
 		}
 
@@ -69,11 +145,13 @@ func (ps *Parser) CheckDocument(doc *html.Node) bool {
 
 		nodeText := strings.TrimSpace(dom.TextContent(node))
 		nodeTextLength := len(nodeText)
-		if nodeTextLength < 140 {
+		if nodeTextLength < minContentLength {
 			return false
 		}
 
-		score += math.Sqrt(float64(nodeTextLength - 140))
-		return score > 20
+		score += math.Sqrt(float64(nodeTextLength - minContentLength))
+		return stopAt >= 0 && score > stopAt
 	})
+
+	return score
 }
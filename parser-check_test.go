@@ -0,0 +1,60 @@
+package readability
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/go-shiori/dom"
+	"golang.org/x/net/html"
+)
+
+func Test_Parser_CheckWithOptions(t *testing.T) {
+	paragraph := articleParagraph(10)
+	input := "<html><body>" + paragraph + "</body></html>"
+
+	scenarios := map[string]struct {
+		opts     CheckOptions
+		expected bool
+	}{
+		"default":            {CheckOptions{}, true},
+		"min content length": {CheckOptions{MinContentLength: 700}, false},
+		"min score":          {CheckOptions{MinScore: 30}, false},
+		"visibility checker": {CheckOptions{VisibilityChecker: func(node *html.Node) bool {
+			return dom.TagName(node) != "p"
+		}}, false},
+	}
+
+	for name, scenario := range scenarios {
+		if readable := CheckWithOptions(strings.NewReader(input), scenario.opts); readable != scenario.expected {
+			t.Errorf("%s, want %v got %v", name, scenario.expected, readable)
+		}
+	}
+}
+
+func Test_Parser_CheckScore(t *testing.T) {
+	paragraph := articleParagraph(10)
+	short := "<p>This paragraph is too short to be scored.</p>"
+	paragraphScore := math.Sqrt(float64(len(strings.TrimSpace(paragraph[3:len(paragraph)-4])) - 140))
+
+	// Unlike Check, the score is accumulated from the whole document
+	scenarios := map[string]float64{
+		"<html><body>" + short + "</body></html>":                                     0,
+		"<html><body>" + paragraph + "</body></html>":                                 paragraphScore,
+		"<html><body>" + paragraph + short + paragraph + paragraph + "</body></html>": 3 * paragraphScore,
+	}
+
+	for input, expected := range scenarios {
+		score, err := CheckScore(strings.NewReader(input))
+		if err != nil {
+			t.Fatalf("failed to check score: %v", err)
+		}
+
+		if math.Abs(score-expected) > 1e-9 {
+			t.Errorf("\n"+
+				"html : %q\n"+
+				"want : %v\n"+
+				"got  : %v", input, expected, score)
+		}
+	}
+}
@@ -1,10 +1,12 @@
 package readability
 
 import (
+	"context"
 	"fmt"
 	"io"
 	nurl "net/url"
 	"strings"
+	"time"
 
 	"github.com/go-shiori/dom"
 	"golang.org/x/net/html"
@@ -12,68 +14,211 @@ import (
 
 // Parse parses a reader and find the main readable content.
 func (ps *Parser) Parse(input io.Reader, pageURL *nurl.URL) (Article, error) {
+	return ps.ParseWithContext(context.Background(), input, pageURL)
+}
+
+// ParseWithContext is like Parse, but it will stop parsing and return
+// the context's error as soon as ctx is done.
+func (ps *Parser) ParseWithContext(ctx context.Context, input io.Reader, pageURL *nurl.URL) (Article, error) {
+	content, err := io.ReadAll(input)
+	if err != nil {
+		return Article{}, fmt.Errorf("failed to parse input: %w", err)
+	}
+	return ps.parseContent(ctx, content, pageURL)
+}
+
+// parseContent is like ParseWithContext, but the input is already read
+// into content, so it doesn't need to be copied.
+func (ps *Parser) parseContent(ctx context.Context, content []byte, pageURL *nurl.URL) (_ Article, err error) {
+	defer ps.recoverPanic(&err)
+	ctx, finish := ps.limitParseDuration(ctx)
+
 	// Parse input
-	doc, err := dom.Parse(input)
+	doc, err := parseContent(ctx, content)
 	if err != nil {
-		return Article{}, fmt.Errorf("failed to parse input: %v", err)
+		return Article{}, finish(fmt.Errorf("failed to parse input: %w", err))
 	}
 
-	return ps.ParseDocument(doc, pageURL)
+	article, err := ps.ParseDocumentWithContext(ctx, doc, pageURL)
+	return article, finish(err)
 }
 
 // ParseDocument parses the specified document and find the main readable content.
 func (ps *Parser) ParseDocument(doc *html.Node, pageURL *nurl.URL) (Article, error) {
+	return ps.ParseDocumentWithContext(context.Background(), doc, pageURL)
+}
+
+// ParseDocumentWithContext is like ParseDocument, but it will stop parsing
+// and return the context's error as soon as ctx is done.
+func (ps *Parser) ParseDocumentWithContext(ctx context.Context, doc *html.Node, pageURL *nurl.URL) (_ Article, err error) {
+	defer ps.recoverPanic(&err)
+	ctx, finish := ps.limitParseDuration(ctx)
+
+	// Parse on a copy of parser, so it can be used concurrently
+	parser := ps.clone()
+	article, err := parser.parseDocument(ctx, doc, pageURL)
+	return article, finish(err)
+}
+
+// parseDocument parses the document using the state of ps. Since the state
+// is modified while parsing, it must only be called on a copy of parser.
+func (ps *Parser) parseDocument(ctx context.Context, doc *html.Node, pageURL *nurl.URL) (Article, error) {
+	// Make sure the context is not done before we do the heavy lifting
+	if err := ctx.Err(); err != nil {
+		return Article{}, err
+	}
+
+	// Avoid parsing too large or too deep documents, as per configuration
+	// option. This is checked before cloning, so we can bail out early.
+	if err := checkDocumentLimits(doc, ps.MaxElemsToParse, ps.MaxElementDepth); err != nil {
+		return Article{}, err
+	}
+
+	// Diagnostics is only collected when requested
+	ps.diagnostics = nil
+	if ps.CollectDiagnostics {
+		ps.diagnostics = &Diagnostics{}
+	}
+	phaseStart := time.Now()
+
 	// Clone document to make sure the original kept untouched
+	ps.ctx = ctx
 	ps.doc = dom.Clone(doc, true)
 
 	// Reset parser data
-	ps.articleTitle = ""
-	ps.articleByline = ""
-	ps.articleDir = ""
-	ps.articleSiteName = ""
 	ps.documentURI = pageURL
-	ps.attempts = []parseAttempt{}
+	ps.attempts = ps.attempts[:0]
 	ps.flags = flags{
 		stripUnlikelys:     true,
 		useWeightClasses:   true,
 		cleanConditionally: true,
 	}
+	rule, hasRule := ps.siteRule()
 
-	// Avoid parsing too large documents, as per configuration option
-	if ps.MaxElemsToParse > 0 {
-		numTags := len(dom.GetElementsByTagName(ps.doc, "*"))
-		if numTags > ps.MaxElemsToParse {
-			return Article{}, fmt.Errorf("documents too large: %d elements", numTags)
-		}
+	for _, fn := range ps.Preprocessors {
+		fn(ps.doc)
 	}
 
 	// Unwrap image from noscript
-	ps.unwrapNoscriptImages(ps.doc)
+	if !ps.DisableNoscriptUnwrap {
+		ps.unwrapNoscriptImages(ps.doc)
+	}
+
+	// Convert social media embeds before their scripts are removed
+	if ps.ConvertSocialEmbeds {
+		ps.convertSocialEmbeds(ps.doc)
+	}
+
+	// Convert math before its TeX source in script is removed
+	if ps.PreserveMath {
+		ps.convertMath(ps.doc)
+	}
 
 	// Extract JSON-LD metadata before removing scripts
 	var jsonLd map[string]string
+	var jsonLdVideos, jsonLdAudios []EmbeddedMedia
 	if !ps.DisableJSONLD {
 		jsonLd, _ = ps.getJSONLD()
+		jsonLdVideos, jsonLdAudios = ps.getJSONLDMedia()
+	}
+
+	phaseStart = ps.diagnosePhase("preprocess", phaseStart)
+
+	// Remove script tags from the document. The paywall is checked in the
+	// same pass, before it's removed along with other clutters
+	paywall := newPaywallDetector(jsonLd)
+	walkNodes(ps.doc, ps.visitScript, paywall.visit)
+
+	// Find the next page before the navigation links are removed
+	var nextPageURL, singlePageURL string
+	if hasRule {
+		nextPageURL = ps.ruleLink(rule.NextPage)
+		singlePageURL = ps.ruleLink(rule.SinglePageLink)
+	}
+	if nextPageURL == "" {
+		nextPageURL = ps.getNextPageURL()
+	}
+	ampURL := ps.getAMPURL()
+	canonicalURL := ps.getCanonicalURL()
+	feeds := ps.getFeeds()
+
+	// Extract comments, which will be removed as clutter later
+	var comments []Comment
+	if ps.ExtractComments {
+		comments = ps.getComments()
 	}
+	phaseStart = ps.diagnosePhase("inspect", phaseStart)
 
-	// Remove script tags from the document.
-	ps.removeScripts(ps.doc)
+	// Strip the elements that specified by site rule
+	if hasRule {
+		ps.stripRuleElements(ps.doc, rule)
+	}
+
+	// Prepares the HTML document, and remove the consent banners in the
+	// same pass
+	var visitors []nodeVisitor
+	if !ps.KeepConsentBanners {
+		visitors = append(visitors, ps.consentBannerVisitor())
+	}
+	ps.prepDocument(visitors...)
+	phaseStart = ps.diagnosePhase("prepare", phaseStart)
 
-	// Prepares the HTML document
-	ps.prepDocument()
+	// Mark the regions before they are removed along with other clutters
+	var regions, removedRegions []RemovedRegion
+	if ps.ExtractRemovedRegions {
+		regions = ps.markRegions(ps.doc)
+	}
 
 	// Fetch metadata
-	metadata := ps.getArticleMetadata(jsonLd)
+	metaTags := ps.getMetaTags()
+	dublinCore := getDublinCore(metaTags)
+	var hEntry *HEntry
+	if ps.UseMicroformats {
+		hEntry = ps.getHEntry()
+	}
+	var microdata *Microdata
+	if ps.UseMicrodata {
+		microdata = ps.getMicrodata()
+	}
+	metadata := ps.getArticleMetadata(jsonLd, dublinCore, hEntry, microdata)
+	if hasRule {
+		ps.applyRuleMetadata(metadata, rule)
+	}
 	ps.articleTitle = metadata["title"]
+	phaseStart = ps.diagnosePhase("metadata", phaseStart)
 
 	// Try to grab article content
 	finalHTMLContent := ""
 	finalTextContent := ""
-	articleContent := ps.grabArticle()
+	var err error
+	var articleContent *html.Node
+	if hasRule {
+		articleContent = ps.grabRuleContent(rule)
+	}
+	if articleContent == nil {
+		articleContent, err = ps.grabContent()
+		if err != nil {
+			return Article{}, err
+		}
+	}
+	phaseStart = ps.diagnosePhase("grab", phaseStart)
+
+	if ps.ExtractRemovedRegions {
+		removedRegions = ps.getRemovedRegions(regions, articleContent)
+	}
+
 	var readableNode *html.Node
+	var media []Media
+	var images []ImageInfo
+	var sections []Section
 
 	if articleContent != nil {
 		ps.postProcessContent(articleContent)
+		media = ps.getArticleMedia(articleContent)
+		images = ps.getArticleImages(articleContent)
+		if ps.ExtractSections {
+			sections = ps.getSections(articleContent)
+		}
 
 		// If we haven't found an excerpt in the article's metadata,
 		// use the article's first paragraph as the excerpt. This is used
@@ -86,9 +231,13 @@ func (ps *Parser) ParseDocument(doc *html.Node, pageURL *nurl.URL) (Article, err
 		}
 
 		readableNode = dom.FirstElementChild(articleContent)
-		finalHTMLContent = dom.InnerHTML(articleContent)
+		finalHTMLContent, err = ps.serialize(articleContent)
+		if err != nil {
+			return Article{}, err
+		}
 		finalTextContent = dom.TextContent(articleContent)
 		finalTextContent = strings.TrimSpace(finalTextContent)
+		ps.diagnosePhase("post-process", phaseStart)
 	}
 
 	finalByline := metadata["byline"]
@@ -104,9 +253,33 @@ func (ps *Parser) ParseDocument(doc *html.Node, pageURL *nurl.URL) (Article, err
 	// go-readability special:
 	// Internet is dangerous and weird, and sometimes we will find
 	// metadata isn't encoded using a valid Utf-8, so here we check it.
-	var replacementTitle string
+	var documentURL string
 	if pageURL != nil {
-		replacementTitle = pageURL.String()
+		documentURL = pageURL.String()
+	}
+	replacementTitle := documentURL
+
+	language := ps.articleLang
+	if language == "" && ps.DetectLanguage {
+		language = ps.getMetaLanguage(jsonLd)
+		if language == "" {
+			language = detectLanguage(finalTextContent)
+		}
+	}
+
+	openGraph := ps.getOpenGraph()
+	tags := ps.getArticleTags(jsonLd, openGraph, hEntry)
+
+	var links []LinkInfo
+	if ps.ExtractLinks {
+		links = ps.getLinks(articleContent)
+	}
+	videos, audios := ps.getEmbeddedMedia(articleContent, jsonLdVideos, jsonLdAudios)
+	twitterCard := ps.getTwitterCard()
+	imageCandidates := ps.getImageCandidates(jsonLd, metaTags, openGraph, twitterCard, microdata, articleContent)
+	image := metadata["image"]
+	if len(imageCandidates) > 0 {
+		image = imageCandidates[0].URL
 	}
 
 	validTitle := strings.ToValidUTF8(ps.articleTitle, replacementTitle)
@@ -114,16 +287,56 @@ func (ps *Parser) ParseDocument(doc *html.Node, pageURL *nurl.URL) (Article, err
 	validExcerpt := strings.ToValidUTF8(excerpt, "")
 
 	return Article{
-		Title:       validTitle,
-		Byline:      validByline,
-		Node:        readableNode,
-		Content:     finalHTMLContent,
-		TextContent: finalTextContent,
-		Length:      charCount(finalTextContent),
-		Excerpt:     validExcerpt,
-		SiteName:    metadata["siteName"],
-		Image:       metadata["image"],
-		Favicon:     metadata["favicon"],
-		Language:    ps.articleLang,
+		Title:           validTitle,
+		Byline:          validByline,
+		Authors:         ps.getArticleAuthors(validByline, hEntry, microdata),
+		Node:            readableNode,
+		Content:         finalHTMLContent,
+		TextContent:     finalTextContent,
+		Length:          charCount(finalTextContent),
+		Excerpt:         validExcerpt,
+		SiteName:        metadata["siteName"],
+		Image:           image,
+		ImageCandidates: imageCandidates,
+		Favicon:         metadata["favicon"],
+		Language:        language,
+		Dir:             ps.articleDir,
+		PublishedTime:   parseDate(metadata["publishedTime"]),
+		ModifiedTime:    parseDate(metadata["modifiedTime"]),
+		NextPageURL:     nextPageURL,
+		AMPURL:          ampURL,
+		SinglePageURL:   singlePageURL,
+		URL:             documentURL,
+		CanonicalURL:    canonicalURL,
+		Feeds:           feeds,
+		Tags:            tags,
+		Comments:        comments,
+		Links:           links,
+		Truncated:       paywall.paywalled || isAbruptlyCut(finalTextContent),
+		Media:           media,
+		Images:          images,
+		Videos:          videos,
+		Audios:          audios,
+		OpenGraph:       openGraph,
+		TwitterCard:     twitterCard,
+		DublinCore:      dublinCore,
+		HEntry:          hEntry,
+		Microdata:       microdata,
+		Sections:        sections,
+		Strategy:        ps.strategy,
+		RemovedRegions:  removedRegions,
+		Confidence:      ps.getConfidence(articleContent, charCount(finalTextContent)),
+		ReadingTime:     ps.estimateReadingTime(finalTextContent),
+
+		WordCount:      articleWordCount(finalTextContent),
+		CharCount:      nonSpaceCharCount(finalTextContent),
+		ParagraphCount: paragraphCount(articleContent),
+		Readability:    ps.getReadabilityMetrics(finalTextContent, language),
+		DebugHTML:      ps.debugHTML,
+		Meta:           flattenMetaTags(metaTags),
+		Description:    strOr(metaTags["description"]...),
+		MetaKeywords:   getMetaKeywords(metaTags),
+		Keywords:       ps.getKeywords(finalTextContent, language),
+		Diagnostics:    ps.diagnostics,
 	}, nil
 }
@@ -1,45 +1,52 @@
 package readability
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	shtml "html"
-	"log"
 	"math"
 	nurl "net/url"
 	"regexp"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/go-shiori/dom"
 	"golang.org/x/net/html"
 )
 
+const (
+	// DefaultNTopCandidates is the default number of top candidates to
+	// consider when analysing the candidates.
+	DefaultNTopCandidates = 5
+	// DefaultCharThresholds is the default number of chars an article
+	// must have in order to return a result.
+	DefaultCharThresholds = 500
+)
+
 // All of the regular expressions in use within readability.
 // Defined up here so we don't instantiate them repeatedly in loops *.
 var (
-	rxUnlikelyCandidates   = regexp.MustCompile(`(?i)-ad-|ai2html|banner|breadcrumbs|combx|comment|community|cover-wrap|disqus|extra|footer|gdpr|header|legends|menu|related|remark|replies|rss|shoutbox|sidebar|skyscraper|social|sponsor|supplemental|ad-break|agegate|pagination|pager|popup|yom-remote`)
-	rxOkMaybeItsACandidate = regexp.MustCompile(`(?i)and|article|body|column|content|main|shadow`)
-	rxPositive             = regexp.MustCompile(`(?i)article|body|content|entry|hentry|h-entry|main|page|pagination|post|text|blog|story`)
-	rxNegative             = regexp.MustCompile(`(?i)-ad-|hidden|^hid$| hid$| hid |^hid |banner|combx|comment|com-|contact|foot|footer|footnote|gdpr|masthead|media|meta|outbrain|promo|related|scroll|share|shoutbox|sidebar|skyscraper|sponsor|shopping|tags|tool|widget`)
+	rxUnlikelyCandidates   = newKeywordMatcher(DefaultUnlikelyPattern)
+	rxOkMaybeItsACandidate = newKeywordMatcher(DefaultMaybeCandidatePattern)
+	rxPositive             = newKeywordMatcher(DefaultPositivePattern)
+	rxNegative             = newKeywordMatcher(DefaultNegativePattern)
 	rxByline               = regexp.MustCompile(`(?i)byline|author|dateline|writtenby|p-author`)
-	rxNormalize            = regexp.MustCompile(`(?i)\s{2,}`)
 	rxVideosx              = regexp.MustCompile(`(?i)//(www\.)?((dailymotion|youtube|youtube-nocookie|player\.vimeo|v\.qq)\.com|(archive|upload\.wikimedia)\.org|player\.twitch\.tv)`)
 	rxTokenize             = regexp.MustCompile(`(?i)\W+`)
 	rxWhitespace           = regexp.MustCompile(`(?i)^\s*$`)
 	rxHasContent           = regexp.MustCompile(`(?i)\S$`)
 	rxHashURL              = regexp.MustCompile(`(?i)^#.+`)
-	rxPropertyPattern      = regexp.MustCompile(`(?i)\s*(dc|dcterm|og|twitter)\s*:\s*(author|creator|description|title|site_name|image\S*)\s*`)
-	rxNamePattern          = regexp.MustCompile(`(?i)^\s*(?:(dc|dcterm|og|twitter|weibo:(article|webpage))\s*[\.:]\s*)?(author|creator|description|title|site_name|image)\s*$`)
+	rxPropertyPattern      = regexp.MustCompile(`(?i)\s*(article|dc|dcterm|og|twitter)\s*:\s*(author|creator|description|published_time|modified_time|updated_time|title|site_name|image\S*)\s*`)
+	rxNamePattern          = regexp.MustCompile(`(?i)^\s*(?:(dc|dcterm|og|twitter|parsely|weibo:(article|webpage))\s*[-\.:]\s*)?(author|creator|pub-date|description|title|site_name|image)\s*$`)
 	rxTitleSeparator       = regexp.MustCompile(`(?i) [\|\-\\/>»] `)
 	rxTitleHierarchySep    = regexp.MustCompile(`(?i) [\\/>»] `)
 	rxTitleRemoveFinalPart = regexp.MustCompile(`(?i)(.*)[\|\-\\/>»] .*`)
 	rxTitleRemove1stPart   = regexp.MustCompile(`(?i)[^\|\-\\/>»]*[\|\-\\/>»](.*)`)
 	rxTitleAnySeparator    = regexp.MustCompile(`(?i)[\|\-\\/>»]+`)
 	rxDisplayNone          = regexp.MustCompile(`(?i)display\s*:\s*none`)
-	rxSentencePeriod       = regexp.MustCompile(`(?i)\.( |$)`)
-	rxShareElements        = regexp.MustCompile(`(?i)(\b|_)(share|sharedaddy)(\b|_)`)
 	rxFaviconSize          = regexp.MustCompile(`(?i)(\d+)x(\d+)`)
 	rxLazyImageSrcset      = regexp.MustCompile(`(?i)\.(jpg|jpeg|png|webp)\s+\d`)
 	rxLazyImageSrc         = regexp.MustCompile(`(?i)^\s*\S+\.(jpg|jpeg|png|webp)\S*\s*$`)
@@ -58,6 +65,7 @@ var (
 	alterToDivExceptions         = []string{"div", "article", "section", "p"}
 	presentationalAttributes     = []string{"align", "background", "bgcolor", "border", "cellpadding", "cellspacing", "frame", "hspace", "rules", "style", "valign", "vspace"}
 	deprecatedSizeAttributeElems = []string{"table", "th", "td", "hr", "pre"}
+	readabilityClasses           = []string{"page"}
 	phrasingElems                = []string{
 		"abbr", "audio", "b", "bdo", "br", "button", "cite", "code", "data",
 		"datalist", "dfn", "em", "embed", "i", "img", "input", "kbd", "label",
@@ -75,73 +83,454 @@ type flags struct {
 
 // parseAttempt is container for the result of previous parse attempts.
 type parseAttempt struct {
-	articleContent *html.Node
-	textLength     int
+	articleContent    *html.Node
+	textLength        int
+	debugHTML         string
+	number            int
+	topCandidateScore float64
+	dir               string
 }
 
-// Article is the final readable content.
+// Article is the final readable content. When encoded to JSON, the
+// field names are fixed as written in the struct tags and won't be
+// changed between releases. The node of the article is never encoded.
 type Article struct {
-	Title       string
-	Byline      string
-	Node        *html.Node
-	Content     string
-	TextContent string
-	Length      int
-	Excerpt     string
-	SiteName    string
-	Image       string
-	Favicon     string
-	Language    string
+	Title         string          `json:"title"`
+	Byline        string          `json:"byline"`
+	Authors       []Author        `json:"authors"`
+	Node          *html.Node      `json:"-"`
+	Content       string          `json:"content"`
+	TextContent   string          `json:"text_content"`
+	Length        int             `json:"length"`
+	Excerpt       string          `json:"excerpt"`
+	SiteName      string          `json:"site_name"`
+	Image         string          `json:"image"`
+	Favicon       string          `json:"favicon"`
+	Language      string          `json:"language"`
+	Dir           string          `json:"dir"`
+	PublishedTime *time.Time      `json:"published_time"`
+	ModifiedTime  *time.Time      `json:"modified_time"`
+	NextPageURL   string          `json:"next_page_url"`
+	AMPURL        string          `json:"amp_url"`
+	SinglePageURL string          `json:"single_page_url"`
+	URL           string          `json:"url"`
+	CanonicalURL  string          `json:"canonical_url"`
+	Feeds         []Feed          `json:"feeds"`
+	Tags          []string        `json:"tags"`
+	Comments      []Comment       `json:"comments"`
+	Links         []LinkInfo      `json:"links"`
+	Truncated     bool            `json:"truncated"`
+	Media         []Media         `json:"media"`
+	Images        []ImageInfo     `json:"images"`
+	Videos        []EmbeddedMedia `json:"videos"`
+	Audios        []EmbeddedMedia `json:"audios"`
+	OpenGraph     *OpenGraph      `json:"open_graph"`
+	TwitterCard   *TwitterCard    `json:"twitter_card"`
+	DublinCore    *DublinCore     `json:"dublin_core"`
+	HEntry        *HEntry         `json:"h_entry"`
+	Microdata     *Microdata      `json:"microdata"`
+	Sections      []Section       `json:"sections"`
+	ReadingTime   time.Duration   `json:"-"`
+
+	// WordCount is the number of words in the text content. Since
+	// Chinese and Japanese words are not delimited by space, each of
+	// their characters is counted as a word.
+	WordCount int `json:"word_count"`
+	// CharCount is the number of characters in the text content,
+	// excluding the whitespaces.
+	CharCount int `json:"char_count"`
+	// ParagraphCount is the number of paragraphs in the content.
+	ParagraphCount int `json:"paragraph_count"`
+	// Readability is the readability scores of the text content, e.g.
+	// Flesch-Kincaid grade and SMOG index. Only filled when the parser has
+	// ExtractReadabilityMetrics enabled, and it's nil if the text is empty
+	// or mostly written in CJK.
+	Readability *ReadabilityMetrics `json:"readability"`
+
+	// ImageCandidates is the candidates of lead image from the metadata
+	// sources in Parser.MetadataPrecedence and the top of content, ranked
+	// by their score. The first one is used as Image.
+	ImageCandidates []ImageCandidate `json:"image_candidates"`
+
+	// Meta is the content of meta tags keyed by their name or property
+	// in lowercase. Only the first value is kept for repeated tags.
+	Meta map[string]string `json:"meta"`
+
+	// Description is the classic meta description as written, unlike
+	// Excerpt which may be taken from the article content.
+	Description string `json:"description"`
+
+	// MetaKeywords is the keywords from meta keywords and news_keywords.
+	MetaKeywords []string `json:"meta_keywords"`
+
+	// Keywords is the key phrases extracted from the text content, ranked
+	// by their score. Only filled when the parser has ExtractKeywords
+	// enabled.
+	Keywords []Keyword `json:"keywords"`
+
+	// Strategy is the strategy that found the article content, e.g. the
+	// site rule or the density fallback.
+	Strategy Strategy `json:"strategy"`
+
+	// Confidence is how likely the content is extracted correctly, from
+	// 0 to 1. It's derived from the score of the winning candidate, the
+	// length and link density of the content, and whether the title in
+	// metadata agrees with the title in the document. Extraction with
+	// low confidence may need to be reviewed or retried with another
+	// scraper.
+	Confidence float64 `json:"confidence"`
+
+	// RemovedRegions is the regions of the page that not included in the
+	// content, labeled by what they look like, e.g. comments, footer and
+	// ads. Only filled when the parser has ExtractRemovedRegions enabled.
+	RemovedRegions []RemovedRegion `json:"removed_regions"`
+
+	// DebugHTML is the document before cleanup, with the score of each
+	// candidate in data-readability-score attribute. Only filled when
+	// the parser is in debug mode.
+	DebugHTML string `json:"-"`
+
+	// Diagnostics is the report of the extraction. Only filled when the
+	// parser has CollectDiagnostics enabled.
+	Diagnostics *Diagnostics `json:"-"`
+}
+
+// DataTableThresholds is the thresholds for detecting data table. A table
+// is considered as data table if it has any of the enabled hints, or if
+// its size reaches any of the min values.
+type DataTableThresholds struct {
+	// MinRows is the min number of rows in a data table. Default: 10.
+	MinRows int
+	// MinColumns is the min number of columns in a data table. Default: 5.
+	MinColumns int
+	// MinCells is the min number of cells (rows * columns) in a data
+	// table. Default: 11.
+	MinCells int
+	// UseSummary determines if table with summary attribute is data table.
+	UseSummary bool
+	// UseCaption determines if table with caption is data table.
+	UseCaption bool
+	// UseDataTags determines if table which has any of col, colgroup, tfoot,
+	// thead or th is data table.
+	UseDataTags bool
 }
 
 // Parser is the parser that parses the page to get the readable content.
+//
+// Parser is safe for concurrent use by multiple goroutines once it's
+// configured: Parse, Check and Candidates never modify the parser, since
+// the state of each call is kept in its own copy. However, the exported
+// fields must not be changed, e.g. by AddPreprocessor, while the parser
+// is in use.
 type Parser struct {
 	// MaxElemsToParse is the max number of nodes supported by this
 	// parser. Default: 0 (no limit)
 	MaxElemsToParse int
+	// MaxElementDepth is the max depth of nested elements supported by
+	// this parser. Deeper document is rejected with ErrTooDeep before
+	// it's processed, which also bounds the recursion while parsing.
+	// Default: DefaultMaxElementDepth.
+	MaxElementDepth int
+	// MaxParseDuration is the max duration of each parse, including
+	// parsing the HTML input. Once it's exceeded, the parse is aborted
+	// with ErrParseTimeout. Default: 0 (no limit).
+	MaxParseDuration time.Duration
 	// NTopCandidates is the number of top candidates to consider when
-	// analysing how tight the competition is among candidates.
+	// analysing how tight the competition is among candidates. Layout
+	// with many columns might need more candidates to find the article.
+	// Default: DefaultNTopCandidates.
 	NTopCandidates int
 	// CharThresholds is the default number of chars an article must
-	// have in order to return a result
+	// have in order to return a result. When the content is shorter,
+	// the parser retries with less strict rules which might include
+	// clutters, so lower it for short-form content like poem or release
+	// notes. Default: DefaultCharThresholds.
 	CharThresholds int
-	// ClassesToPreserve are the classes that readability sets itself.
+	// ClassesToPreserve are the classes that kept when the classes are
+	// stripped, e.g. for syntax highlighting. Just like Readability.js,
+	// the classes that readability sets itself (i.e. "page") are always
+	// kept, even if they're not listed here.
 	ClassesToPreserve []string
 	// KeepClasses specify whether the classes should be stripped or not.
 	KeepClasses bool
+	// KeepAttributes is the attributes that kept when the presentational
+	// attributes are removed, e.g. "style" or "align". Attribute that ends
+	// with "*" matches by prefix, e.g. "data-*" and "aria-*". They are
+	// also kept by the strict sanitizer, except the event handlers and
+	// style. Default: nil.
+	KeepAttributes []string
+	// KeepStyles is the CSS properties that kept in the style attribute,
+	// e.g. "text-align" or "border-*". The strict sanitizer keeps them as
+	// well, as long as their value can't load resource. Default: nil, i.e.
+	// the style attribute is removed.
+	KeepStyles []string
+	// AttributeFilter is called for each attribute of the article content
+	// when it's cleaned up, to decide whether it should be kept. It's not
+	// used by the strict sanitizer. Default: nil.
+	AttributeFilter AttributeFilter
 	// TagsToScore is element tags to score by default.
 	TagsToScore []string
-	// Debug determines if the log should be printed or not. Default: false.
+	// Logger is used to log the decisions that made by the parser. If it's
+	// nil, the log is printed using the standard logger in debug mode.
+	Logger Logger
+	// Debug determines if the log should be printed or not. In debug mode,
+	// the scored document is also returned in Article.DebugHTML, which is
+	// useful to understand why a section was kept or dropped. Default: false.
 	Debug bool
+	// HeadingBaseLevel is the level of the top headings in the article
+	// content. When specified, headings are normalized so they start at
+	// this level and skipped levels are fixed, e.g. h1, h4 and h3 are
+	// changed to h2, h3 and h3 with base level 2. Default: 0 (only h1 is
+	// replaced with h2, like Readability.js).
+	HeadingBaseLevel int
+	// WordsPerMinute is the reading speed that used to estimate the reading
+	// time of the article. Default: DefaultWordsPerMinute.
+	WordsPerMinute int
+	// CJKCharsPerMinute is the reading speed for Chinese and Japanese text,
+	// which words are not delimited by space.
+	// Default: DefaultCJKCharsPerMinute.
+	CJKCharsPerMinute int
+	// PropagateDir determines if the text direction of the article is set
+	// as dir attribute of the page in article content, so the content is
+	// rendered correctly on its own. Default: true.
+	PropagateDir bool
+	// DetectLanguage determines if the language of the article should be
+	// taken from meta tags, or detected from its text, when it's not
+	// specified in <html lang>. Default: false.
+	DetectLanguage bool
+	// ExtractSections determines if the article content is split into
+	// Article.Sections by its headings. Headings that don't have id are
+	// given one, so the sections can be linked to. Default: false.
+	ExtractSections bool
+	// UseMicroformats determines if the microformats2 h-entry is used as
+	// source of metadata, and its e-content as hint of article content.
+	// The h-entry itself is returned in Article.HEntry. Default: false.
+	UseMicroformats bool
+	// UseMicrodata determines if the Schema.org article in microdata is
+	// used as source of metadata, and its articleBody as hint of article
+	// content. The article itself is returned in Article.Microdata.
+	// Default: false.
+	UseMicrodata bool
+	// ExtractComments determines if the comments of the article are
+	// extracted into Article.Comments. Only the known structures are
+	// recognized, e.g. WordPress comment list, Disqus static fallback
+	// and Hacker News style thread. Default: false.
+	ExtractComments bool
+	// ExtractLinks determines if the links in the page are listed in
+	// Article.Links, along with whether they are kept in the article
+	// content and where they are found in the page. Default: false.
+	ExtractLinks bool
+	// ExtractRemovedRegions determines if the regions that not included in
+	// the article content, e.g. comments, navigation, footer, related
+	// articles and ads, are listed in Article.RemovedRegions along with
+	// their HTML. Default: false.
+	ExtractRemovedRegions bool
+	// StripTrackingParams determines if the tracking parameters, e.g.
+	// utm_source and fbclid, are removed from the URLs in the article
+	// content. Default: false.
+	StripTrackingParams bool
+	// TrackingParams is the query parameters that removed when
+	// StripTrackingParams is enabled. Parameter that ends with "*"
+	// matches by prefix. Default: DefaultTrackingParams.
+	TrackingParams []string
+	// ExtractKeywords determines if the key phrases of the article are
+	// extracted from its text content into Article.Keywords. Default: false.
+	ExtractKeywords bool
+	// MaxKeywords is the max number of keywords that extracted when
+	// ExtractKeywords is enabled. Default: DefaultMaxKeywords.
+	MaxKeywords int
+	// ExtractReadabilityMetrics determines if the readability scores of
+	// the article are computed from its text content into
+	// Article.Readability. Default: false.
+	ExtractReadabilityMetrics bool
+	// DisableAbsoluteURLs determines if the relative URLs in the article
+	// content should be kept as they are, instead of converted into
+	// absolute URLs. Default: false.
+	DisableAbsoluteURLs bool
+	// BaseURL is the URL that used to resolve the relative URLs in the
+	// article content, e.g. when the page is served from a mirror.
+	// Default: nil (use the page URL).
+	BaseURL *nurl.URL
+	// URLRewriter is called for each URL in the article content after
+	// it's resolved, e.g. to route the images through a proxy. Default:
+	// nil (keep the URL as it is).
+	URLRewriter URLRewriter
+	// Rules is the registry of site rules, which are used to select the
+	// article content, strip elements and override metadata of matching
+	// sites. Default: nil.
+	Rules *Rules
+	// CollectDiagnostics determines if the report of the extraction, e.g.
+	// the removed nodes and the time spent in each phase, is returned in
+	// Article.Diagnostics. Default: false.
+	CollectDiagnostics bool
+	// LinkDensityModifier is added to the link density thresholds that used
+	// to remove the link-heavy sections, so legitimate content like reference
+	// list and documentation index is not classified as navigation. Positive
+	// value permits more links. Default: 0.
+	LinkDensityModifier float64
 	// DisableJSONLD determines if metadata in JSON+LD will be extracted
 	// or not. Default: false.
 	DisableJSONLD bool
+	// MetadataPrecedence is the order of sources that used when they have
+	// different value of the same metadata. The source that not listed
+	// here will be ignored. Default: DefaultMetadataPrecedence.
+	MetadataPrecedence []MetadataSource
+	// DisableNoscriptUnwrap determines if the fallback images inside
+	// <noscript> should not be used to replace the lazy placeholder
+	// images. Default: false.
+	DisableNoscriptUnwrap bool
+	// LazyImageAttributes are the attributes that used by lazy-loading sites
+	// to store the real URL of image, which will be promoted to src (or
+	// srcset, for the attribute whose name ends with "srcset"). Without
+	// it, only the attributes whose value looks like image URL are used.
+	// Default: nil.
+	LazyImageAttributes []string
+	// CollapsePictures determines whether each <picture> element should be
+	// replaced by a single <img> that uses its largest image. Default: false.
+	CollapsePictures bool
+	// ConvertSocialEmbeds determines whether the embedded social media post
+	// (e.g. tweet and Instagram post), which lose their content once the
+	// scripts are removed, should be converted into static blockquotes.
+	// Default: false.
+	ConvertSocialEmbeds bool
+	// SocialEmbedResolver is used to resolve the social media post whose
+	// content can't be extracted from its markup. Default: nil.
+	SocialEmbedResolver SocialEmbedResolver
+	// PreserveMath determines whether the math that rendered by MathJax
+	// or KaTeX should be converted into its TeX source, which wrapped in
+	// element with class "math-tex". Default: false.
+	PreserveMath bool
+	// PreserveCode enables the code fidelity mode, where <pre> blocks are
+	// kept with their exact whitespaces and structure, "language-*" class
+	// of code is kept, and the code-heavy sections are not removed even
+	// though their text is not dense. Default: false.
+	PreserveCode bool
+	// PreserveDetails determines whether the <details> disclosure elements
+	// and their open state should be kept, even when they are in section
+	// that looks like clutter. Default: false.
+	PreserveDetails bool
+	// PreserveMedia determines whether the section that contains audio or
+	// video with controls should be kept, even when it looks like clutter.
+	// Default: false.
+	PreserveMedia bool
+	// SVGPolicy is the policy for handling inline SVG, which can be chart or
+	// diagram, but can also be decorative icon. Default: SVGKeepAll.
+	SVGPolicy SVGPolicy
+	// SVGMinSize is the min width or height of SVG that kept when SVGPolicy
+	// is SVGKeepLarge. Default: DefaultSVGMinSize.
+	SVGMinSize float64
+	// Algorithm is the algorithm that used to find the article content.
+	// Default: AlgorithmReadability.
+	Algorithm Algorithm
+	// ScoringWeights is the class patterns and tag scores that used to
+	// score the candidates, e.g. to penalize "paywall" class or to keep
+	// "comment" class on sites where the article lives in div.comment.
+	ScoringWeights ScoringWeights
+	// DataTableThresholds is the thresholds that used to determine whether
+	// a table is data table, which is kept, or layout table.
+	DataTableThresholds DataTableThresholds
+	// Serializer is used to convert the article content into the HTML string
+	// that stored in Article.Content, e.g. to produce XHTML or minified
+	// HTML. It receives the container of the article content, and the
+	// container itself should not be serialized. Default: nil (inner HTML).
+	Serializer func(*html.Node) (string, error)
 	// AllowedVideoRegex is a regular expression that matches video URLs that should be
-	// allowed to be included in the article content. If undefined, it will use default filter.
+	// allowed to be included in the article content, e.g. to allow embed from Vimeo,
+	// PeerTube or self-hosted players. If undefined, it will use default filter.
 	AllowedVideoRegex *regexp.Regexp
-
-	doc             *html.Node
-	documentURI     *nurl.URL
-	articleTitle    string
-	articleByline   string
-	articleDir      string
-	articleSiteName string
-	articleLang     string
-	attempts        []parseAttempt
-	flags           flags
-}
-
-// NewParser returns new Parser which set up with default value.
-func NewParser() Parser {
-	return Parser{
+	// Preprocessors is called in order with the whole document before it's
+	// prepared and scored, e.g. to fix the broken markup of a site. It
+	// receives a copy, so the original document is kept untouched.
+	Preprocessors []func(*html.Node)
+	// Postprocessors is called in order with the container of the article
+	// content after it's cleaned up and before it's serialized, e.g. to
+	// rewrite or annotate the elements.
+	Postprocessors []func(*html.Node)
+	// Sanitize determines if the article content is run through the strict
+	// allowlist sanitizer after all postprocessors, so Article.Content,
+	// Article.Node and Article.Sections are safe to be embedded directly
+	// in web page. Default: false.
+	Sanitize bool
+	// SanitizePolicy is the allowed elements, attributes and URL schemes
+	// when Sanitize is enabled. Default: DefaultSanitizePolicy.
+	SanitizePolicy *SanitizePolicy
+	// KeepConsentBanners determines whether the cookie banners and consent
+	// overlays should be kept instead of removed before scoring. Default:
+	// false.
+	KeepConsentBanners bool
+	// ConsentPatterns is the class names and ids of consent banners that
+	// always removed, e.g. to add the consent platform used by a site.
+	// Pattern that ends with "*" matches by prefix. Default:
+	// DefaultConsentPatterns.
+	ConsentPatterns []string
+	// DisablePromoRemoval determines whether the newsletter signup boxes and
+	// call to action cards inside the article should be kept. Default:
+	// false.
+	DisablePromoRemoval bool
+	// SharePatterns is the class names and ids of share buttons and social
+	// widgets that removed from the article. Each pattern is matched as
+	// a word, e.g. "share" matches "post-share" but not "shared". Empty
+	// list disables it. Default: DefaultSharePatterns.
+	SharePatterns []string
+	// DisableShareBarRemoval determines whether the share bars that don't
+	// match SharePatterns should be kept, even though all of their links
+	// point to share endpoint or only show an icon. Default: false.
+	DisableShareBarRemoval bool
+
+	parseState
+}
+
+// parseState is the state of parser that changed while parsing a single
+// document. Each call of Parse works on its own copy of parser, so this
+// state is never shared between the concurrent calls.
+type parseState struct {
+	ctx               context.Context
+	doc               *html.Node
+	documentURI       *nurl.URL
+	articleTitle      string
+	articleByline     string
+	articleDir        string
+	articleSiteName   string
+	articleLang       string
+	attempts          []parseAttempt
+	debugHTML         string
+	jsonLdAuthors     []Author
+	diagnostics       *Diagnostics
+	strategy          Strategy
+	topCandidateScore float64
+	flags             flags
+	textLengths       map[*html.Node]int
+	textLengthCache   map[*html.Node]int
+}
+
+// NewParser returns new Parser which set up with default value. The
+// default value can be changed by passing one or more Option.
+func NewParser(opts ...Option) Parser {
+	ps := Parser{
 		MaxElemsToParse:   0,
-		NTopCandidates:    5,
-		CharThresholds:    500,
+		MaxElementDepth:   DefaultMaxElementDepth,
+		NTopCandidates:    DefaultNTopCandidates,
+		CharThresholds:    DefaultCharThresholds,
 		ClassesToPreserve: []string{"page"},
 		KeepClasses:       false,
 		TagsToScore:       []string{"section", "h2", "h3", "h4", "h5", "h6", "p", "td", "pre"},
+		PropagateDir:      true,
 		Debug:             false,
+		DataTableThresholds: DataTableThresholds{
+			MinRows:     10,
+			MinColumns:  5,
+			MinCells:    11,
+			UseSummary:  true,
+			UseCaption:  true,
+			UseDataTags: true,
+		},
 	}
+
+	for _, opt := range opts {
+		opt(&ps)
+	}
+
+	return ps
 }
 
 // postProcessContent runs any post-process modifications to article
@@ -150,15 +539,33 @@ func (ps *Parser) postProcessContent(articleContent *html.Node) {
 	// Readability cannot open relative uris so we convert them to absolute uris.
 	ps.fixRelativeURIs(articleContent)
 
+	if ps.StripTrackingParams {
+		ps.stripTrackingParams(articleContent)
+	}
+
+	if ps.CollapsePictures {
+		ps.collapsePictures(articleContent)
+	}
+
+	ps.applySVGPolicy(articleContent)
+
 	ps.simplifyNestedElements(articleContent)
 
-	// Remove classes.
+	// Remove classes and readability attributes in a single pass.
+	var visitors []nodeVisitor
 	if !ps.KeepClasses {
-		ps.cleanClasses(articleContent)
+		visitors = append(visitors, ps.cleanClasses)
+	}
+	walkNodes(articleContent, append(visitors, ps.clearReadabilityAttr)...)
+
+	for _, fn := range ps.Postprocessors {
+		fn(articleContent)
 	}
 
-	// Remove readability attributes.
-	ps.clearReadabilityAttr(articleContent)
+	// Sanitize last, so nothing unsafe can be added after it
+	if ps.Sanitize {
+		ps.sanitizeContent(articleContent)
+	}
 }
 
 // removeNodes iterates over a NodeList, calls `filterFn` for each node
@@ -230,14 +637,22 @@ func (ps *Parser) getAllNodesWithTag(node *html.Node, tagNames ...string) []*htm
 	return result
 }
 
-// cleanClasses removes the class="" attribute from every element in the
-// given subtree, except those that match CLASSES_TO_PRESERVE and the
-// classesToPreserve array from the options object.
-func (ps *Parser) cleanClasses(node *html.Node) {
+// cleanClasses removes the class="" attribute from the element, except
+// those that match CLASSES_TO_PRESERVE and the classesToPreserve array
+// from the options object. It's a visitor, so the whole subtree is
+// cleaned when it's walked.
+func (ps *Parser) cleanClasses(node *html.Node) visitResult {
+	if node.Type != html.ElementNode {
+		return visitChildren
+	}
+
 	nodeClassName := dom.ClassName(node)
 	preservedClassName := []string{}
 	for _, class := range strings.Fields(nodeClassName) {
-		if indexOf(ps.ClassesToPreserve, class) != -1 {
+		if indexOf(ps.ClassesToPreserve, class) != -1 ||
+			indexOf(readabilityClasses, class) != -1 ||
+			(ps.PreserveMath && class == mathTeXClass) ||
+			(ps.PreserveCode && isCodeLanguageClass(node, class)) {
 			preservedClassName = append(preservedClassName, class)
 		}
 	}
@@ -247,14 +662,12 @@ func (ps *Parser) cleanClasses(node *html.Node) {
 	} else {
 		dom.RemoveAttribute(node, "class")
 	}
-
-	for child := dom.FirstElementChild(node); child != nil; child = dom.NextElementSibling(child) {
-		ps.cleanClasses(child)
-	}
+	return visitChildren
 }
 
 // fixRelativeURIs converts each <a> and <img> uri in the given element
-// to an absolute URI, ignoring #ref URIs.
+// to an absolute URI, ignoring #ref URIs. In go-readability, the URIs are
+// also passed to the parser's URLRewriter, if any.
 func (ps *Parser) fixRelativeURIs(articleContent *html.Node) {
 	links := ps.getAllNodesWithTag(articleContent, "a")
 	ps.forEachNode(links, func(link *html.Node, _ int) {
@@ -283,7 +696,7 @@ func (ps *Parser) fixRelativeURIs(articleContent *html.Node) {
 				dom.ReplaceChild(link.Parent, container, link)
 			}
 		} else {
-			newHref := toAbsoluteURI(href, ps.documentURI)
+			newHref := ps.rewriteContentURL(link, "href", href)
 			if newHref == "" {
 				dom.RemoveAttribute(link, "href")
 			} else {
@@ -292,29 +705,36 @@ func (ps *Parser) fixRelativeURIs(articleContent *html.Node) {
 		}
 	})
 
-	medias := ps.getAllNodesWithTag(articleContent, "img", "picture", "figure", "video", "audio", "source")
+	medias := ps.getAllNodesWithTag(articleContent, "img", "picture", "figure", "video", "audio", "source", "track")
 	ps.forEachNode(medias, func(media *html.Node, _ int) {
 		src := dom.GetAttribute(media, "src")
 		poster := dom.GetAttribute(media, "poster")
 		srcset := dom.GetAttribute(media, "srcset")
 
 		if src != "" {
-			newSrc := toAbsoluteURI(src, ps.documentURI)
-			dom.SetAttribute(media, "src", newSrc)
+			setOrRemoveAttribute(media, "src", ps.rewriteContentURL(media, "src", src))
 		}
 
 		if poster != "" {
-			newPoster := toAbsoluteURI(poster, ps.documentURI)
-			dom.SetAttribute(media, "poster", newPoster)
+			setOrRemoveAttribute(media, "poster", ps.rewriteContentURL(media, "poster", poster))
 		}
 
 		if srcset != "" {
+			removed := false
 			newSrcset := rxSrcsetURL.ReplaceAllStringFunc(srcset, func(s string) string {
 				p := rxSrcsetURL.FindStringSubmatch(s)
-				return toAbsoluteURI(p[1], ps.documentURI) + p[2] + p[3]
+				newURL := ps.rewriteContentURL(media, "srcset", p[1])
+				if newURL == "" {
+					removed = true
+					return ""
+				}
+				return newURL + p[2] + p[3]
 			})
 
-			dom.SetAttribute(media, "srcset", newSrcset)
+			if removed {
+				newSrcset = strings.TrimSuffix(strings.TrimSpace(newSrcset), ",")
+			}
+			setOrRemoveAttribute(media, "srcset", newSrcset)
 		}
 	})
 }
@@ -326,6 +746,11 @@ func (ps *Parser) simplifyNestedElements(articleContent *html.Node) {
 		nodeID := dom.ID(node)
 		nodeTagName := dom.TagName(node)
 
+		if ps.PreserveCode && nodeTagName == "pre" {
+			node = ps.getNextNode(node, true)
+			continue
+		}
+
 		if node.Parent != nil && (nodeTagName == "div" || nodeTagName == "section") &&
 			!strings.HasPrefix(nodeID, "readability") {
 			if ps.isElementWithoutContent(node) {
@@ -407,7 +832,7 @@ func (ps *Parser) getArticleTitle() string {
 	}
 
 	curTitle = strings.TrimSpace(curTitle)
-	curTitle = rxNormalize.ReplaceAllString(curTitle, " ")
+	curTitle = collapseSpaces(curTitle, 2)
 	// If we now have 4 words or fewer as our title, and either no
 	// 'hierarchical' separators (\, /, > or ») were found in the original
 	// title or we decreased the number of words by more than 1 word, use
@@ -427,15 +852,13 @@ func (ps *Parser) getArticleTitle() string {
 // prepDocument prepares the HTML document for readability to scrape it.
 // This includes things like stripping javascript, CSS, and handling
 // terrible markup.
-func (ps *Parser) prepDocument() {
+func (ps *Parser) prepDocument(visitors ...nodeVisitor) {
 	doc := ps.doc
 
 	// ADDITIONAL, not exist in readability.js:
-	// Remove all comments,
-	ps.removeComments(doc)
-
-	// Remove all style tags in head
-	ps.removeNodes(dom.GetElementsByTagName(doc, "style"), nil)
+	// Remove all comments, and all style tags in head. They are removed in
+	// a single pass, along with the passes that requested by caller.
+	walkNodes(doc, append(visitors, ps.visitComment, ps.visitStyle)...)
 
 	if nodes := dom.GetElementsByTagName(doc, "body"); len(nodes) > 0 && nodes[0] != nil {
 		ps.replaceBrs(nodes[0])
@@ -465,6 +888,10 @@ func (ps *Parser) nextNode(node *html.Node) *html.Node {
 //	<div>foo<br>bar<p>abc</p></div>
 func (ps *Parser) replaceBrs(elem *html.Node) {
 	ps.forEachNode(ps.getAllNodesWithTag(elem, "br"), func(br *html.Node, _ int) {
+		if ps.PreserveCode && ps.hasAncestorTag(br, "pre", -1, nil) {
+			return
+		}
+
 		next := br.NextSibling
 
 		// Whether 2 or more <br> elements have been found and replaced
@@ -544,6 +971,13 @@ func (ps *Parser) prepArticle(articleContent *html.Node) {
 
 	ps.fixLazyImages(articleContent)
 
+	// ADDITIONAL, not exist in readability.js:
+	// Remove newsletter signup and call to action, before their forms
+	// and inputs are cleaned
+	if !ps.DisablePromoRemoval {
+		ps.removePromoBoxes(articleContent)
+	}
+
 	// Clean out junk from the article content
 	ps.cleanConditionally(articleContent, "form")
 	ps.cleanConditionally(articleContent, "fieldset")
@@ -558,11 +992,23 @@ func (ps *Parser) prepArticle(articleContent *html.Node) {
 	// candidates even they have "share".
 	shareElementThreshold := ps.CharThresholds
 
-	ps.forEachNode(dom.Children(articleContent), func(topCandidate *html.Node, _ int) {
-		ps.cleanMatchedNodes(topCandidate, func(node *html.Node, nodeClassID string) bool {
-			return rxShareElements.MatchString(nodeClassID) && charCount(dom.TextContent(node)) < shareElementThreshold
+	if shareElements := ps.shareElements(); shareElements != nil {
+		ps.forEachNode(dom.Children(articleContent), func(topCandidate *html.Node, _ int) {
+			ps.cleanMatchedNodes(topCandidate, func(node *html.Node, nodeClassID string) bool {
+				if shareElements.MatchString(nodeClassID) && charCount(dom.TextContent(node)) < shareElementThreshold {
+					ps.diagnoseRemoval(node, RemovedShareElement)
+					return true
+				}
+				return false
+			})
 		})
-	})
+	}
+
+	// ADDITIONAL, not exist in readability.js:
+	// Remove share bars that only identified by their links
+	if !ps.DisableShareBarRemoval {
+		ps.removeShareBars(articleContent)
+	}
 
 	ps.clean(articleContent, "iframe")
 	ps.clean(articleContent, "input")
@@ -578,7 +1024,11 @@ func (ps *Parser) prepArticle(articleContent *html.Node) {
 	ps.cleanConditionally(articleContent, "div")
 
 	// Replace H1 with H2 as H1 should be only title that is displayed separately
-	ps.replaceNodeTags(ps.getAllNodesWithTag(articleContent, "h1"), "h2")
+	if ps.HeadingBaseLevel > 0 {
+		ps.normalizeHeadings(articleContent, ps.HeadingBaseLevel)
+	} else {
+		ps.replaceNodeTags(ps.getAllNodesWithTag(articleContent, "h1"), "h2")
+	}
 
 	// Remove extra paragraphs
 	ps.removeNodes(dom.GetElementsByTagName(articleContent, "p"), func(p *html.Node) bool {
@@ -590,7 +1040,15 @@ func (ps *Parser) prepArticle(articleContent *html.Node) {
 		iframeCount := len(dom.GetElementsByTagName(p, "iframe"))
 		totalCount := imgCount + embedCount + objectCount + iframeCount
 
-		return totalCount == 0 && ps.getInnerText(p, false) == ""
+		if ps.PreserveMedia && ps.hasPlayableMedia(p) {
+			return false
+		}
+
+		if totalCount == 0 && ps.getInnerText(p, false) == "" {
+			ps.diagnoseRemoval(p, RemovedEmpty)
+			return true
+		}
+		return false
 	})
 
 	ps.forEachNode(dom.GetElementsByTagName(articleContent, "br"), func(br *html.Node, _ int) {
@@ -627,16 +1085,10 @@ func (ps *Parser) prepArticle(articleContent *html.Node) {
 // initializeNode initializes a node with the readability score.
 // Also checks the className/id for special names to add to its score.
 func (ps *Parser) initializeNode(node *html.Node) {
-	contentScore := float64(ps.getClassWeight(node))
-	switch dom.TagName(node) {
-	case "div":
-		contentScore += 5
-	case "pre", "td", "blockquote":
-		contentScore += 3
-	case "address", "ol", "ul", "dl", "dd", "dt", "li", "form":
-		contentScore -= 3
-	case "h1", "h2", "h3", "h4", "h5", "h6", "th":
-		contentScore -= 5
+	contentScore := float64(ps.getClassWeight(node)) + ps.tagScore(node)
+
+	if ps.isContentHint(node) {
+		contentScore += contentHintWeight
 	}
 
 	ps.setContentScore(node, contentScore)
@@ -715,9 +1167,14 @@ func (ps *Parser) checkByline(node *html.Node, matchString string) bool {
 
 	rel := dom.GetAttribute(node, "rel")
 	itemprop := dom.GetAttribute(node, "itemprop")
-	nodeText := dom.TextContent(node)
-	if (rel == "author" || strings.Contains(itemprop, "author") || rxByline.MatchString(matchString)) &&
-		ps.isValidByline(nodeText) {
+	if rel != "author" && !strings.Contains(itemprop, "author") && !rxByline.MatchString(matchString) {
+		return false
+	}
+
+	// The text is only taken for byline candidate, since it's expensive
+	// to do it for every node in document
+	nodeText := textContent(node)
+	if ps.isValidByline(nodeText) {
 		nodeText = strings.TrimSpace(nodeText)
 		nodeText = strings.Join(strings.Fields(nodeText), " ")
 		ps.articleByline = nodeText
@@ -728,7 +1185,7 @@ func (ps *Parser) checkByline(node *html.Node, matchString string) bool {
 }
 
 func (ps *Parser) getTextDensity(node *html.Node, tags ...string) float64 {
-	textLength := charCount(ps.getInnerText(node, true))
+	textLength := ps.getTextLength(node)
 	if textLength == 0 {
 		return 0
 	}
@@ -736,7 +1193,7 @@ func (ps *Parser) getTextDensity(node *html.Node, tags ...string) float64 {
 	var childrenLength int
 	children := ps.getAllNodesWithTag(node, tags...)
 	ps.forEachNode(children, func(child *html.Node, _ int) {
-		childrenLength += charCount(ps.getInnerText(child, true))
+		childrenLength += ps.getTextLength(child)
 	})
 
 	return float64(childrenLength) / float64(textLength)
@@ -762,11 +1219,20 @@ func (ps *Parser) getNodeAncestors(node *html.Node, maxDepth int) []*html.Node {
 // grabArticle uses a variety of metrics (content score, classname,
 // element types), find the content that is most likely to be the
 // stuff a user wants to read. Then return it wrapped up in a div.
-func (ps *Parser) grabArticle() *html.Node {
-	ps.log("**** GRAB ARTICLE ****")
+// The only error returned is the one from parser's context, when
+// it's done before the article is grabbed.
+func (ps *Parser) grabArticle() (*html.Node, error) {
+	ps.logDebug("grabbing article")
 
 	for {
+		if err := ps.ctxErr(); err != nil {
+			return nil, err
+		}
+
 		doc := dom.Clone(ps.doc, true)
+		if ps.diagnostics != nil {
+			ps.diagnostics.Attempts++
+		}
 
 		var page *html.Node
 		if nodes := dom.GetElementsByTagName(doc, "body"); len(nodes) > 0 {
@@ -775,221 +1241,24 @@ func (ps *Parser) grabArticle() *html.Node {
 
 		// We can't grab an article if we don't have a page!
 		if page == nil {
-			ps.log("no body found in document, abort")
-			return nil
+			ps.logWarn("no body found in document, abort")
+			return nil, nil
 		}
 
-		// First, node prepping. Trash nodes that look cruddy (like ones
-		// with the class name "comment", etc), and turn divs into P
-		// tags where they have been used inappropriately (as in, where
-		// they contain no other block level elements.)
-		var elementsToScore []*html.Node
-		var node = dom.DocumentElement(doc)
-		shouldRemoveTitleHeader := true
-
-		for node != nil {
-			matchString := dom.ClassName(node) + " " + dom.ID(node)
-
-			if dom.TagName(node) == "html" {
-				ps.articleLang = dom.GetAttribute(node, "lang")
-			}
-
-			if !ps.isProbablyVisible(node) {
-				ps.logf("removing hidden node: %q\n", matchString)
-				node = ps.removeAndGetNext(node)
-				continue
-			}
-
-			// User is not able to see elements applied with both "aria-modal = true"
-			// and "role = dialog"
-			if dom.GetAttribute(node, "aria-modal") == "true" &&
-				dom.GetAttribute(node, "role") == "dialog" {
-				node = ps.removeAndGetNext(node)
-				continue
-			}
-
-			// Check to see if this node is a byline, and remove it if
-			// it is true.
-			if ps.checkByline(node, matchString) {
-				node = ps.removeAndGetNext(node)
-				continue
-			}
-
-			if shouldRemoveTitleHeader && ps.headerDuplicatesTitle(node) {
-				ps.logf("removing header: %q duplicate of %q\n",
-					trim(dom.TextContent(node)), trim(ps.articleTitle))
-				shouldRemoveTitleHeader = false
-				node = ps.removeAndGetNext(node)
-				continue
-			}
-
-			// Remove unlikely candidates
-			nodeTagName := dom.TagName(node)
-			if ps.flags.stripUnlikelys {
-				if rxUnlikelyCandidates.MatchString(matchString) &&
-					!rxOkMaybeItsACandidate.MatchString(matchString) &&
-					!ps.hasAncestorTag(node, "table", 3, nil) &&
-					!ps.hasAncestorTag(node, "code", 3, nil) &&
-					nodeTagName != "body" && nodeTagName != "a" {
-					ps.logf("removing unlikely candidate: %q\n", matchString)
-					node = ps.removeAndGetNext(node)
-					continue
-				}
-
-				role := dom.GetAttribute(node, "role")
-				if _, include := unlikelyRoles[role]; include {
-					ps.logf("removing content with role %q: %q\n", role, matchString)
-					node = ps.removeAndGetNext(node)
-					continue
-				}
-			}
-
-			// Remove DIV, SECTION, and HEADER nodes without any
-			// content(e.g. text, image, video, or iframe).
-			switch nodeTagName {
-			case "div", "section", "header",
-				"h1", "h2", "h3", "h4", "h5", "h6":
-				if ps.isElementWithoutContent(node) {
-					node = ps.removeAndGetNext(node)
-					continue
-				}
-			}
-
-			if indexOf(ps.TagsToScore, nodeTagName) != -1 {
-				elementsToScore = append(elementsToScore, node)
-			}
-
-			// Turn all divs that don't have children block level
-			// elements into p's
-			if nodeTagName == "div" {
-				// Put phrasing content into paragraphs.
-				var p *html.Node
-				childNode := node.FirstChild
-				for childNode != nil {
-					nextSibling := childNode.NextSibling
-					if ps.isPhrasingContent(childNode) {
-						if p != nil {
-							dom.AppendChild(p, childNode)
-						} else if !ps.isWhitespace(childNode) {
-							p = dom.CreateElement("p")
-							dom.AppendChild(p, dom.Clone(childNode, true))
-							dom.ReplaceChild(node, p, childNode)
-						}
-					} else if p != nil {
-						for p.LastChild != nil && ps.isWhitespace(p.LastChild) {
-							p.RemoveChild(p.LastChild)
-						}
-						p = nil
-					}
-					childNode = nextSibling
-				}
-
-				// Sites like http://mobile.slate.com encloses each
-				// paragraph with a DIV element. DIVs with only a P
-				// element inside and no text content can be safely
-				// converted into plain P elements to avoid confusing
-				// the scoring algorithm with DIVs with are, in
-				// practice, paragraphs.
-				if ps.hasSingleTagInsideElement(node, "p") && ps.getLinkDensity(node) < 0.25 {
-					newNode := dom.Children(node)[0]
-					node, _ = dom.ReplaceChild(node.Parent, newNode, node)
-					elementsToScore = append(elementsToScore, node)
-				} else if !ps.hasChildBlockElement(node) {
-					ps.setNodeTag(node, "p")
-					elementsToScore = append(elementsToScore, node)
-				}
-			}
-			node = ps.getNextNode(node, false)
+		candidates, err := ps.scoreCandidates(doc)
+		if err != nil {
+			return nil, err
 		}
 
-		// Loop through all paragraphs, and assign a score to them based
-		// on how content-y they look. Then add their score to their
-		// parent node. A score is determined by things like number of
-		// commas, class names, etc. Maybe eventually link density.
-		var candidates []*html.Node
-		ps.forEachNode(elementsToScore, func(elementToScore *html.Node, _ int) {
-			if elementToScore.Parent == nil || dom.TagName(elementToScore.Parent) == "" {
-				return
-			}
-
-			// If this paragraph is less than 25 characters, don't even count it.
-			innerText := ps.getInnerText(elementToScore, true)
-			if charCount(innerText) < 25 {
-				return
-			}
-
-			// Exclude nodes with no ancestor.
-			ancestors := ps.getNodeAncestors(elementToScore, 5)
-			if len(ancestors) == 0 {
-				return
-			}
-
-			// Add a point for the paragraph itself as a base.
-			contentScore := 1
-
-			// Add points for any commas within this paragraph.
-			contentScore += strings.Count(innerText, ",")
-
-			// For every 100 characters in this paragraph, add another point. Up to 3 points.
-			contentScore += int(math.Min(math.Floor(float64(charCount(innerText))/100.0), 3.0))
-
-			// Initialize and score ancestors.
-			ps.forEachNode(ancestors, func(ancestor *html.Node, level int) {
-				if dom.TagName(ancestor) == "" || ancestor.Parent == nil || ancestor.Parent.Type != html.ElementNode {
-					return
-				}
-
-				if !ps.hasContentScore(ancestor) {
-					ps.initializeNode(ancestor)
-					candidates = append(candidates, ancestor)
-				}
-
-				// Node score divider:
-				// - parent:             1 (no division)
-				// - grandparent:        2
-				// - great grandparent+: ancestor level * 3
-				scoreDivider := 1
-				switch level {
-				case 0:
-					scoreDivider = 1
-				case 1:
-					scoreDivider = 2
-				default:
-					scoreDivider = level * 3
-				}
-
-				ancestorScore := ps.getContentScore(ancestor)
-				ancestorScore += float64(contentScore) / float64(scoreDivider)
-				ps.setContentScore(ancestor, ancestorScore)
-			})
-		})
-
-		// These lines are a bit different compared to Readability.js.
-		// In Readability.js, they fetch NTopCandidates utilising array
-		// method like `splice` and `pop`. In Go, array method like that
-		// is not as simple, especially since we are working with pointer.
-		// So, here we simply sort top candidates, and limit it to
-		// max NTopCandidates.
-
-		// Scale the final candidates score based on link density. Good
-		// content should have a relatively small link density (5% or
-		// less) and be mostly unaffected by this operation.
-		for i := 0; i < len(candidates); i++ {
-			candidate := candidates[i]
-			candidateScore := ps.getContentScore(candidate) * (1 - ps.getLinkDensity(candidate))
-			ps.logf("candidate %q with score: %f\n", dom.OuterHTML(candidate), candidateScore)
-			ps.setContentScore(candidate, candidateScore)
-		}
-
-		// After we've calculated scores, sort through all of the possible
-		// candidate nodes we found and find the one with the highest score.
-		sort.Slice(candidates, func(i int, j int) bool {
-			return ps.getContentScore(candidates[i]) > ps.getContentScore(candidates[j])
-		})
+		// In debug mode, keep the scored document before it's cleaned up
+		var debugHTML string
+		if ps.Debug {
+			debugHTML = dom.OuterHTML(dom.DocumentElement(doc))
+		}
 
 		var topCandidates []*html.Node
-		if len(candidates) > ps.NTopCandidates {
-			topCandidates = candidates[:ps.NTopCandidates]
+		if nTopCandidates := ps.nTopCandidates(); len(candidates) > nTopCandidates {
+			topCandidates = candidates[:nTopCandidates]
 		} else {
 			topCandidates = candidates
 		}
@@ -1010,7 +1279,7 @@ func (ps *Parser) grabArticle() *html.Node {
 			// Move everything (not just elements, also text nodes etc.)
 			// into the container so we even include text directly in the body:
 			for page.FirstChild != nil {
-				ps.logf("moving child out: %q\n", dom.OuterHTML(page.FirstChild))
+				ps.logDebug("moving child out of body", "tag", dom.TagName(page.FirstChild))
 				dom.AppendChild(topCandidate, page.FirstChild)
 			}
 
@@ -1137,7 +1406,7 @@ func (ps *Parser) grabArticle() *html.Node {
 					if nodeLength > 80 && linkDensity < 0.25 {
 						appendNode = true
 					} else if nodeLength < 80 && nodeLength > 0 && linkDensity == 0 &&
-						rxSentencePeriod.MatchString(nodeContent) {
+						hasSentencePeriod(nodeContent) {
 						appendNode = true
 					}
 				}
@@ -1192,6 +1461,18 @@ func (ps *Parser) grabArticle() *html.Node {
 			dom.AppendChild(articleContent, div)
 		}
 
+		// Find out text direction from ancestors of final top candidate.
+		var articleDir string
+		ancestors := append([]*html.Node{parentOfTopCandidate, topCandidate}, ps.getNodeAncestors(parentOfTopCandidate, 0)...)
+		ps.someNode(ancestors, func(ancestor *html.Node) bool {
+			if ancestor == nil || ancestor.Type != html.ElementNode {
+				return false
+			}
+
+			articleDir = dom.GetAttribute(ancestor, "dir")
+			return articleDir != ""
+		})
+
 		parseSuccessful := true
 
 		// Now that we've gone through the full algorithm, check to
@@ -1200,33 +1481,32 @@ func (ps *Parser) grabArticle() *html.Node {
 		// gives us a higher likelihood of finding the content, and
 		// the sieve approach gives us a higher likelihood of
 		// finding the -right- content.
-		textLength := charCount(ps.getInnerText(articleContent, true))
+		textLength := ps.getTextLength(articleContent)
+		attempt := parseAttempt{
+			articleContent:    articleContent,
+			textLength:        textLength,
+			debugHTML:         debugHTML,
+			number:            len(ps.attempts) + 1,
+			topCandidateScore: topCandidateScore,
+			dir:               articleDir,
+		}
+
 		if textLength < ps.CharThresholds {
 			parseSuccessful = false
+			ps.logInfo("grabbed article is too short, retrying with less strict flags",
+				"attempt", attempt.number, "length", textLength, "threshold", ps.CharThresholds)
 
 			if ps.flags.stripUnlikelys {
 				ps.flags.stripUnlikelys = false
-				ps.attempts = append(ps.attempts, parseAttempt{
-					articleContent: articleContent,
-					textLength:     textLength,
-				})
+				ps.attempts = append(ps.attempts, attempt)
 			} else if ps.flags.useWeightClasses {
 				ps.flags.useWeightClasses = false
-				ps.attempts = append(ps.attempts, parseAttempt{
-					articleContent: articleContent,
-					textLength:     textLength,
-				})
+				ps.attempts = append(ps.attempts, attempt)
 			} else if ps.flags.cleanConditionally {
 				ps.flags.cleanConditionally = false
-				ps.attempts = append(ps.attempts, parseAttempt{
-					articleContent: articleContent,
-					textLength:     textLength,
-				})
+				ps.attempts = append(ps.attempts, attempt)
 			} else {
-				ps.attempts = append(ps.attempts, parseAttempt{
-					articleContent: articleContent,
-					textLength:     textLength,
-				})
+				ps.attempts = append(ps.attempts, attempt)
 
 				// No luck after removing flags, just return the
 				// longest text we found during the different loops *
@@ -1236,18 +1516,296 @@ func (ps *Parser) grabArticle() *html.Node {
 
 				// But first check if we actually have something
 				if ps.attempts[0].textLength == 0 {
-					return nil
+					return nil, nil
 				}
 
-				articleContent = ps.attempts[0].articleContent
+				attempt = ps.attempts[0]
+				articleContent = attempt.articleContent
 				parseSuccessful = true
 			}
 		}
 
 		if parseSuccessful {
-			return articleContent
+			ps.logInfo("article grabbed", "attempt", attempt.number,
+				"length", attempt.textLength, "score", attempt.topCandidateScore)
+			ps.debugHTML = attempt.debugHTML
+			ps.setArticleDir(articleContent, attempt.dir)
+			ps.topCandidateScore = attempt.topCandidateScore
+			ps.strategy = StrategyReadability
+			if attempt.number > 1 {
+				ps.strategy = StrategyRelaxed
+			}
+			if ps.diagnostics != nil {
+				ps.diagnostics.Attempt = attempt.number
+				ps.diagnostics.TopCandidateScore = attempt.topCandidateScore
+			}
+			return articleContent, nil
+		}
+	}
+}
+
+// setArticleDir saves dir as the text direction of the article. When
+// PropagateDir is enabled, it's also set as dir attribute of the page in
+// article content.
+func (ps *Parser) setArticleDir(articleContent *html.Node, dir string) {
+	ps.articleDir = dir
+	if ps.PropagateDir && dir != "" {
+		if page := dom.FirstElementChild(articleContent); page != nil {
+			dom.SetAttribute(page, "dir", dir)
+		}
+	}
+}
+
+// getNodeDir returns the text direction of node, which is taken from dir
+// attribute of the node or its nearest ancestor that has it.
+func getNodeDir(node *html.Node) string {
+	for ; node != nil; node = node.Parent {
+		if node.Type == html.ElementNode {
+			if dir := dom.GetAttribute(node, "dir"); dir != "" {
+				return dir
+			}
 		}
 	}
+	return ""
+}
+
+// scoreCandidates preps the nodes in document, then scores the paragraphs
+// and their ancestors. It returns all of the scored candidates, sorted
+// from the highest score.
+func (ps *Parser) scoreCandidates(doc *html.Node) ([]*html.Node, error) {
+	// First, node prepping. Trash nodes that look cruddy (like ones
+	// with the class name "comment", etc), and turn divs into P
+	// tags where they have been used inappropriately (as in, where
+	// they contain no other block level elements.)
+	var elementsToScore []*html.Node
+	var node = dom.DocumentElement(doc)
+	shouldRemoveTitleHeader := true
+
+	for node != nil {
+		if err := ps.ctxErr(); err != nil {
+			return nil, err
+		}
+
+		matchString := dom.ClassName(node) + " " + dom.ID(node)
+
+		if dom.TagName(node) == "html" {
+			ps.articleLang = dom.GetAttribute(node, "lang")
+		}
+
+		if !ps.isProbablyVisible(node) {
+			ps.logDebug("removing hidden node", "match", matchString)
+			ps.diagnoseRemoval(node, RemovedHidden)
+			node = ps.removeAndGetNext(node)
+			continue
+		}
+
+		// User is not able to see elements applied with both "aria-modal = true"
+		// and "role = dialog"
+		if dom.GetAttribute(node, "aria-modal") == "true" &&
+			dom.GetAttribute(node, "role") == "dialog" {
+			ps.diagnoseRemoval(node, RemovedModalDialog)
+			node = ps.removeAndGetNext(node)
+			continue
+		}
+
+		// Check to see if this node is a byline, and remove it if
+		// it is true.
+		if ps.checkByline(node, matchString) {
+			ps.diagnoseRemoval(node, RemovedByline)
+			node = ps.removeAndGetNext(node)
+			continue
+		}
+
+		if shouldRemoveTitleHeader && ps.headerDuplicatesTitle(node) {
+			ps.logDebug("removing header that duplicates title",
+				"header", trim(dom.TextContent(node)), "title", trim(ps.articleTitle))
+			shouldRemoveTitleHeader = false
+			ps.diagnoseRemoval(node, RemovedDuplicateTitle)
+			node = ps.removeAndGetNext(node)
+			continue
+		}
+
+		// In code fidelity mode, code block is kept exactly as it is
+		if ps.PreserveCode && dom.TagName(node) == "pre" {
+			if indexOf(ps.TagsToScore, "pre") != -1 {
+				elementsToScore = append(elementsToScore, node)
+			}
+			node = ps.getNextNode(node, true)
+			continue
+		}
+
+		// Remove unlikely candidates
+		nodeTagName := dom.TagName(node)
+		if ps.flags.stripUnlikelys {
+			if ps.isUnlikelyCandidate(matchString) &&
+				!ps.hasAncestorTag(node, "table", 3, nil) &&
+				!ps.hasAncestorTag(node, "code", 3, nil) &&
+				nodeTagName != "body" && nodeTagName != "a" {
+				ps.logDebug("removing unlikely candidate", "match", matchString)
+				ps.diagnoseRemoval(node, RemovedUnlikely)
+				node = ps.removeAndGetNext(node)
+				continue
+			}
+
+			role := dom.GetAttribute(node, "role")
+			if _, include := unlikelyRoles[role]; include {
+				ps.logDebug("removing content with unlikely role", "role", role, "match", matchString)
+				ps.diagnoseRemoval(node, RemovedUnlikelyRole)
+				node = ps.removeAndGetNext(node)
+				continue
+			}
+		}
+
+		// Remove DIV, SECTION, and HEADER nodes without any
+		// content(e.g. text, image, video, or iframe).
+		switch nodeTagName {
+		case "div", "section", "header",
+			"h1", "h2", "h3", "h4", "h5", "h6":
+			if ps.isElementWithoutContent(node) {
+				ps.diagnoseRemoval(node, RemovedEmpty)
+				node = ps.removeAndGetNext(node)
+				continue
+			}
+		}
+
+		if indexOf(ps.TagsToScore, nodeTagName) != -1 {
+			elementsToScore = append(elementsToScore, node)
+		}
+
+		// Turn all divs that don't have children block level
+		// elements into p's
+		if nodeTagName == "div" {
+			// Put phrasing content into paragraphs.
+			var p *html.Node
+			childNode := node.FirstChild
+			for childNode != nil {
+				nextSibling := childNode.NextSibling
+				if ps.isPhrasingContent(childNode) {
+					if p != nil {
+						dom.AppendChild(p, childNode)
+					} else if !ps.isWhitespace(childNode) {
+						p = dom.CreateElement("p")
+						dom.AppendChild(p, dom.Clone(childNode, true))
+						dom.ReplaceChild(node, p, childNode)
+					}
+				} else if p != nil {
+					for p.LastChild != nil && ps.isWhitespace(p.LastChild) {
+						p.RemoveChild(p.LastChild)
+					}
+					p = nil
+				}
+				childNode = nextSibling
+			}
+
+			// Sites like http://mobile.slate.com encloses each
+			// paragraph with a DIV element. DIVs with only a P
+			// element inside and no text content can be safely
+			// converted into plain P elements to avoid confusing
+			// the scoring algorithm with DIVs with are, in
+			// practice, paragraphs.
+			if ps.hasSingleTagInsideElement(node, "p") && ps.getLinkDensity(node) < 0.25 {
+				newNode := dom.Children(node)[0]
+				node, _ = dom.ReplaceChild(node.Parent, newNode, node)
+				elementsToScore = append(elementsToScore, node)
+			} else if !ps.hasChildBlockElement(node) {
+				ps.setNodeTag(node, "p")
+				elementsToScore = append(elementsToScore, node)
+			}
+		}
+		node = ps.getNextNode(node, false)
+	}
+
+	// Loop through all paragraphs, and assign a score to them based
+	// on how content-y they look. Then add their score to their
+	// parent node. A score is determined by things like number of
+	// commas, class names, etc. Maybe eventually link density.
+	if err := ps.ctxErr(); err != nil {
+		return nil, err
+	}
+
+	var candidates []*html.Node
+	ps.forEachNode(elementsToScore, func(elementToScore *html.Node, _ int) {
+		if elementToScore.Parent == nil || dom.TagName(elementToScore.Parent) == "" {
+			return
+		}
+
+		// If this paragraph is less than 25 characters, don't even count it.
+		innerText := ps.getInnerText(elementToScore, true)
+		if charCount(innerText) < 25 {
+			return
+		}
+
+		// Exclude nodes with no ancestor.
+		ancestors := ps.getNodeAncestors(elementToScore, 5)
+		if len(ancestors) == 0 {
+			return
+		}
+
+		// Add a point for the paragraph itself as a base.
+		contentScore := 1
+
+		// Add points for any commas within this paragraph.
+		contentScore += strings.Count(innerText, ",")
+
+		// For every 100 characters in this paragraph, add another point. Up to 3 points.
+		contentScore += int(math.Min(math.Floor(float64(charCount(innerText))/100.0), 3.0))
+
+		// Initialize and score ancestors.
+		ps.forEachNode(ancestors, func(ancestor *html.Node, level int) {
+			if dom.TagName(ancestor) == "" || ancestor.Parent == nil || ancestor.Parent.Type != html.ElementNode {
+				return
+			}
+
+			if !ps.hasContentScore(ancestor) {
+				ps.initializeNode(ancestor)
+				candidates = append(candidates, ancestor)
+			}
+
+			// Node score divider:
+			// - parent:             1 (no division)
+			// - grandparent:        2
+			// - great grandparent+: ancestor level * 3
+			scoreDivider := 1
+			switch level {
+			case 0:
+				scoreDivider = 1
+			case 1:
+				scoreDivider = 2
+			default:
+				scoreDivider = level * 3
+			}
+
+			ancestorScore := ps.getContentScore(ancestor)
+			ancestorScore += float64(contentScore) / float64(scoreDivider)
+			ps.setContentScore(ancestor, ancestorScore)
+		})
+	})
+
+	// These lines are a bit different compared to Readability.js.
+	// In Readability.js, they fetch NTopCandidates utilising array
+	// method like `splice` and `pop`. In Go, array method like that
+	// is not as simple, especially since we are working with pointer.
+	// So, here we simply sort top candidates, and limit it to
+	// max NTopCandidates.
+
+	// Scale the final candidates score based on link density. Good
+	// content should have a relatively small link density (5% or
+	// less) and be mostly unaffected by this operation.
+	defer ps.cacheTextLengths()()
+	for i := 0; i < len(candidates); i++ {
+		candidate := candidates[i]
+		candidateScore := ps.getContentScore(candidate) * (1 - ps.getLinkDensity(candidate))
+		ps.logDebug("scored candidate", "tag", dom.TagName(candidate), "match", dom.ClassName(candidate)+" "+dom.ID(candidate), "score", candidateScore)
+		ps.setContentScore(candidate, candidateScore)
+	}
+
+	// After we've calculated scores, sort through all of the possible
+	// candidate nodes we found and find the one with the highest score.
+	sort.Slice(candidates, func(i int, j int) bool {
+		return ps.getContentScore(candidates[i]) > ps.getContentScore(candidates[j])
+	})
+
+	return candidates, nil
 }
 
 // isValidByline checks whether the input string could be a byline.
@@ -1277,7 +1835,7 @@ func (ps *Parser) getJSONLD() (map[string]string, error) {
 		var parsed map[string]interface{}
 		err := json.Unmarshal([]byte(content), &parsed)
 		if err != nil {
-			ps.logf("error while decoding json: %v", err)
+			ps.logWarn("failed to decode JSON-LD", "error", err)
 			return
 		}
 
@@ -1343,6 +1901,7 @@ func (ps *Parser) getJSONLD() (map[string]string, error) {
 		}
 
 		// Author
+		ps.jsonLdAuthors = jsonLDAuthors(parsed["author"])
 		switch val := parsed["author"].(type) {
 		case map[string]interface{}:
 			if name, isString := val["name"].(string); isString {
@@ -1375,6 +1934,65 @@ func (ps *Parser) getJSONLD() (map[string]string, error) {
 				metadata["siteName"] = strings.TrimSpace(name)
 			}
 		}
+
+		// Image, which may be written as URL, ImageObject or list of them
+		image := parsed["image"]
+		if images, isArray := image.([]interface{}); isArray && len(images) > 0 {
+			image = images[0]
+		}
+
+		switch val := image.(type) {
+		case string:
+			metadata["image"] = strings.TrimSpace(val)
+		case map[string]interface{}:
+			if url, isString := val["url"].(string); isString {
+				metadata["image"] = strings.TrimSpace(url)
+				metadata["imageWidth"] = fmt.Sprint(val["width"])
+				metadata["imageHeight"] = fmt.Sprint(val["height"])
+			}
+		}
+
+		// Keywords, which may be written as comma separated text or list
+		switch val := parsed["keywords"].(type) {
+		case string:
+			metadata["keywords"] = val
+		case []interface{}:
+			var keywords []string
+			for _, keyword := range val {
+				if strKeyword, isString := keyword.(string); isString {
+					keywords = append(keywords, strKeyword)
+				}
+			}
+			metadata["keywords"] = strings.Join(keywords, ",")
+		}
+
+		// DatePublished
+		if datePublished, isString := parsed["datePublished"].(string); isString {
+			metadata["datePublished"] = strings.TrimSpace(datePublished)
+		}
+
+		// DateModified
+		if dateModified, isString := parsed["dateModified"].(string); isString {
+			metadata["dateModified"] = strings.TrimSpace(dateModified)
+		}
+
+		// InLanguage, which may be written as text or Language object
+		switch val := parsed["inLanguage"].(type) {
+		case string:
+			metadata["inLanguage"] = strings.TrimSpace(val)
+		case map[string]interface{}:
+			if name, isString := val["alternateName"].(string); isString {
+				metadata["inLanguage"] = strings.TrimSpace(name)
+			}
+		}
+
+		// IsAccessibleForFree, which may be written as boolean or string
+		switch val := parsed["isAccessibleForFree"].(type) {
+		case bool:
+			metadata["isAccessibleForFree"] = strconv.FormatBool(val)
+		case string:
+			metadata["isAccessibleForFree"] = strings.ToLower(strings.TrimSpace(val))
+		}
 	})
 
 	return metadata, nil
@@ -1382,7 +2000,7 @@ func (ps *Parser) getJSONLD() (map[string]string, error) {
 
 // getArticleMetadata attempts to get excerpt and byline
 // metadata for the article.
-func (ps *Parser) getArticleMetadata(jsonLd map[string]string) map[string]string {
+func (ps *Parser) getArticleMetadata(jsonLd map[string]string, dublinCore *DublinCore, hEntry *HEntry, microdata *Microdata) map[string]string {
 	values := make(map[string]string)
 	metaElements := dom.GetElementsByTagName(ps.doc, "meta")
 
@@ -1419,51 +2037,103 @@ func (ps *Parser) getArticleMetadata(jsonLd map[string]string) map[string]string
 		}
 	})
 
+	var entry HEntry
+	var entryAuthor HCard
+	if hEntry != nil {
+		entry = *hEntry
+		if hEntry.Author != nil {
+			entryAuthor = *hEntry.Author
+		}
+	}
+
+	var md Microdata
+	if microdata != nil {
+		md = *microdata
+	}
+
 	// get title
-	metadataTitle := strOr(
-		jsonLd["title"],
-		values["dc:title"],
-		values["dcterm:title"],
-		values["og:title"],
-		values["weibo:article:title"],
-		values["weibo:webpage:title"],
-		values["title"],
-		values["twitter:title"])
+	metadataTitle := ps.pickMetadata(map[MetadataSource][]string{
+		MetadataJSONLD:       {jsonLd["title"]},
+		MetadataDublinCore:   {values["dc:title"], values["dcterm:title"]},
+		MetadataOpenGraph:    {values["og:title"]},
+		MetadataMetaTag:      {values["weibo:article:title"], values["weibo:webpage:title"], values["title"]},
+		MetadataTwitterCard:  {values["twitter:title"]},
+		MetadataMicroformats: {entry.Name},
+		MetadataMicrodata:    {md.Headline},
+	})
 
 	if metadataTitle == "" {
 		metadataTitle = ps.getArticleTitle()
 	}
 
 	// get author
-	metadataByline := strOr(
-		jsonLd["byline"],
-		values["dc:creator"],
-		values["dcterm:creator"],
-		values["author"])
+	metadataByline := ps.pickMetadata(map[MetadataSource][]string{
+		MetadataJSONLD:       {jsonLd["byline"]},
+		MetadataDublinCore:   {values["dc:creator"], values["dcterm:creator"]},
+		MetadataMetaTag:      {values["author"]},
+		MetadataMicroformats: {entryAuthor.Name},
+		MetadataMicrodata:    {microdata.byline()},
+	})
 
 	// get description
-	metadataExcerpt := strOr(
-		jsonLd["excerpt"],
-		values["dc:description"],
-		values["dcterm:description"],
-		values["og:description"],
-		values["weibo:article:description"],
-		values["weibo:webpage:description"],
-		values["description"],
-		values["twitter:description"])
+	metadataExcerpt := ps.pickMetadata(map[MetadataSource][]string{
+		MetadataJSONLD:       {jsonLd["excerpt"]},
+		MetadataDublinCore:   {values["dc:description"], values["dcterm:description"]},
+		MetadataOpenGraph:    {values["og:description"]},
+		MetadataMetaTag:      {values["weibo:article:description"], values["weibo:webpage:description"], values["description"]},
+		MetadataTwitterCard:  {values["twitter:description"]},
+		MetadataMicroformats: {entry.Summary},
+		MetadataMicrodata:    {md.Description},
+	})
 
 	// get site name
-	metadataSiteName := strOr(jsonLd["siteName"], values["og:site_name"])
+	metadataSiteName := ps.pickMetadata(map[MetadataSource][]string{
+		MetadataJSONLD:    {jsonLd["siteName"]},
+		MetadataOpenGraph: {values["og:site_name"]},
+	})
 
 	// get image thumbnail
-	metadataImage := strOr(
-		values["og:image"],
-		values["image"],
-		values["twitter:image"])
+	metadataImage := ps.pickMetadata(map[MetadataSource][]string{
+		MetadataOpenGraph:   {values["og:image"]},
+		MetadataMetaTag:     {values["image"]},
+		MetadataTwitterCard: {values["twitter:image"]},
+		MetadataMicrodata:   {md.Image},
+	})
 
 	// get favicon
 	metadataFavicon := ps.getArticleFavicon()
 
+	// get published time
+	var dublinCoreDate string
+	if dublinCore != nil {
+		dublinCoreDate = dublinCore.Date
+	}
+
+	metadataPublishedTime := ps.pickMetadata(map[MetadataSource][]string{
+		MetadataJSONLD:       {jsonLd["datePublished"]},
+		MetadataDublinCore:   {dublinCoreDate},
+		MetadataOpenGraph:    {values["article:published_time"]},
+		MetadataMetaTag:      {values["parsely-pub-date"]},
+		MetadataMicroformats: {entry.Published},
+		MetadataMicrodata:    {md.DatePublished},
+	})
+
+	if metadataPublishedTime == "" {
+		metadataPublishedTime = ps.getTimeElementDate("datePublished")
+	}
+
+	// get modified time
+	metadataModifiedTime := ps.pickMetadata(map[MetadataSource][]string{
+		MetadataJSONLD:       {jsonLd["dateModified"]},
+		MetadataOpenGraph:    {values["article:modified_time"], values["og:updated_time"]},
+		MetadataMicroformats: {entry.Updated},
+		MetadataMicrodata:    {md.DateModified},
+	})
+
+	if metadataModifiedTime == "" {
+		metadataModifiedTime = ps.getTimeElementDate("dateModified")
+	}
+
 	// in many sites the meta value is escaped with HTML entities,
 	// so here we need to unescape it
 	metadataTitle = shtml.UnescapeString(metadataTitle)
@@ -1472,13 +2142,44 @@ func (ps *Parser) getArticleMetadata(jsonLd map[string]string) map[string]string
 	metadataSiteName = shtml.UnescapeString(metadataSiteName)
 
 	return map[string]string{
-		"title":    metadataTitle,
-		"byline":   metadataByline,
-		"excerpt":  metadataExcerpt,
-		"siteName": metadataSiteName,
-		"image":    metadataImage,
-		"favicon":  metadataFavicon,
+		"title":         metadataTitle,
+		"byline":        metadataByline,
+		"excerpt":       metadataExcerpt,
+		"siteName":      metadataSiteName,
+		"image":         metadataImage,
+		"favicon":       metadataFavicon,
+		"publishedTime": metadataPublishedTime,
+		"modifiedTime":  metadataModifiedTime,
+	}
+}
+
+// getTimeElementDate returns the date in <time> element that marked with
+// the specified microdata property. For published date, the obsolete
+// "pubdate" attribute is recognized as well. The compact date like
+// "20210627" is only accepted in datetime attribute, since in text it's
+// more likely to be just some number.
+func (ps *Parser) getTimeElementDate(itemProp string) string {
+	selector := `time[itemprop="` + itemProp + `"]`
+	if itemProp == "datePublished" {
+		selector += ", time[pubdate]"
+	}
+
+	for _, timeElement := range dom.QuerySelectorAll(ps.doc, selector) {
+		date := strings.TrimSpace(dom.GetAttribute(timeElement, "datetime"))
+		if t, err := time.Parse("20060102", date); err == nil {
+			date = t.Format("2006-01-02")
+		}
+
+		if date == "" {
+			date = strings.TrimSpace(dom.TextContent(timeElement))
+		}
+
+		if date != "" {
+			return date
+		}
 	}
+
+	return ""
 }
 
 // isSingleImage checks if node is image, or if node contains exactly
@@ -1578,7 +2279,20 @@ func (ps *Parser) unwrapNoscriptImages(doc *html.Node) {
 
 // removeScripts removes script tags from the document.
 func (ps *Parser) removeScripts(doc *html.Node) {
-	ps.removeNodes(ps.getAllNodesWithTag(doc, "script", "noscript"), nil)
+	walkNodes(doc, ps.visitScript)
+}
+
+// visitScript is the visitor of removeScripts, which removes node if it's
+// script or noscript.
+func (ps *Parser) visitScript(node *html.Node) visitResult {
+	switch dom.TagName(node) {
+	case "script", "noscript":
+		if node.Parent != nil {
+			node.Parent.RemoveChild(node)
+			return visitRemoved
+		}
+	}
+	return visitChildren
 }
 
 // hasSingleTagInsideElement check if this node has only whitespace
@@ -1600,13 +2314,14 @@ func (ps *Parser) hasSingleTagInsideElement(element *html.Node, tag string) bool
 // isElementWithoutContent determines if node is empty
 // or only fille with <br> and <hr>.
 func (ps *Parser) isElementWithoutContent(node *html.Node) bool {
+	if node.Type != html.ElementNode || hasTextContent(node) {
+		return false
+	}
+
 	brs := dom.GetElementsByTagName(node, "br")
 	hrs := dom.GetElementsByTagName(node, "hr")
 	childs := dom.Children(node)
-
-	return node.Type == html.ElementNode &&
-		strings.TrimSpace(dom.TextContent(node)) == "" &&
-		(len(childs) == 0 || len(childs) == len(brs)+len(hrs))
+	return len(childs) == 0 || len(childs) == len(brs)+len(hrs)
 }
 
 // hasChildBlockElement determines whether element has any children
@@ -1628,7 +2343,7 @@ func (ps *Parser) isPhrasingContent(node *html.Node) bool {
 
 // isWhitespace determines if a node only used as whitespace.
 func (ps *Parser) isWhitespace(node *html.Node) bool {
-	return (node.Type == html.TextNode && strings.TrimSpace(dom.TextContent(node)) == "") ||
+	return (node.Type == html.TextNode && !hasTextContent(node)) ||
 		(node.Type == html.ElementNode && dom.TagName(node) == "br")
 }
 
@@ -1636,11 +2351,11 @@ func (ps *Parser) isWhitespace(node *html.Node) bool {
 // This also strips * out any excess whitespace to be found.
 // In Readability.js, normalizeSpaces default to true.
 func (ps *Parser) getInnerText(node *html.Node, normalizeSpaces bool) string {
-	textContent := strings.TrimSpace(dom.TextContent(node))
+	text := strings.TrimSpace(textContent(node))
 	if normalizeSpaces {
-		textContent = rxNormalize.ReplaceAllString(textContent, " ")
+		text = collapseSpaces(text, 2)
 	}
-	return textContent
+	return text
 }
 
 // getCharCount returns the number of times a string s
@@ -1657,15 +2372,32 @@ func (ps *Parser) cleanStyles(node *html.Node) {
 		return
 	}
 
-	// Remove `style` and deprecated presentational attributes
-	for i := 0; i < len(presentationalAttributes); i++ {
-		dom.RemoveAttribute(node, presentationalAttributes[i])
-	}
+	// Remove `style` and deprecated presentational attributes, except
+	// the ones that configured to be kept
+	removeSize := indexOf(deprecatedSizeAttributeElems, nodeTagName) != -1
+	attrs := node.Attr[:0]
+	for _, attr := range node.Attr {
+		keep := true
+		switch {
+		case matchNamePattern(attr.Key, ps.KeepAttributes):
+		case attr.Key == "style" && len(ps.KeepStyles) > 0:
+			attr.Val = filterStyle(attr.Val, ps.KeepStyles, false)
+			keep = attr.Val != ""
+		case indexOf(presentationalAttributes, attr.Key) != -1:
+			keep = false
+		case removeSize && (attr.Key == "width" || attr.Key == "height"):
+			keep = false
+		}
 
-	if indexOf(deprecatedSizeAttributeElems, nodeTagName) != -1 {
-		dom.RemoveAttribute(node, "width")
-		dom.RemoveAttribute(node, "height")
+		if ps.AttributeFilter != nil {
+			keep = ps.AttributeFilter(node, attr, keep)
+		}
+
+		if keep {
+			attrs = append(attrs, attr)
+		}
 	}
+	node.Attr = attrs
 
 	for child := dom.FirstElementChild(node); child != nil; child = dom.NextElementSibling(child) {
 		ps.cleanStyles(child)
@@ -1676,7 +2408,7 @@ func (ps *Parser) cleanStyles(node *html.Node) {
 // content. This is the amount of text that is inside a link divided
 // by the total text in the node.
 func (ps *Parser) getLinkDensity(element *html.Node) float64 {
-	textLength := charCount(ps.getInnerText(element, true))
+	textLength := ps.getTextLength(element)
 	if textLength == 0 {
 		return 0
 	}
@@ -1691,7 +2423,7 @@ func (ps *Parser) getLinkDensity(element *html.Node) float64 {
 			coefficient = 0.3
 		}
 
-		nodeLength := charCount(ps.getInnerText(linkNode, true))
+		nodeLength := ps.getTextLength(linkNode)
 		linkLength += float64(nodeLength) * coefficient
 	})
 
@@ -1706,40 +2438,48 @@ func (ps *Parser) getClassWeight(node *html.Node) int {
 	}
 
 	weight := 0
+	positive, negative := ps.classPatterns()
+	classWeight := ps.classWeight()
 
 	// Look for a special classname
 	if nodeClassName := dom.ClassName(node); nodeClassName != "" {
-		if rxNegative.MatchString(nodeClassName) {
-			weight -= 25
+		if negative.MatchString(nodeClassName) {
+			weight -= classWeight
 		}
 
-		if rxPositive.MatchString(nodeClassName) {
-			weight += 25
+		if positive.MatchString(nodeClassName) {
+			weight += classWeight
 		}
 	}
 
 	// Look for a special ID
 	if nodeID := dom.ID(node); nodeID != "" {
-		if rxNegative.MatchString(nodeID) {
-			weight -= 25
+		if negative.MatchString(nodeID) {
+			weight -= classWeight
 		}
 
-		if rxPositive.MatchString(nodeID) {
-			weight += 25
+		if positive.MatchString(nodeID) {
+			weight += classWeight
 		}
 	}
 
 	return weight
 }
 
+// videoRegex returns the regular expression that used to find the
+// embedded videos that allowed in the article content.
+func (ps *Parser) videoRegex() *regexp.Regexp {
+	if ps.AllowedVideoRegex != nil {
+		return ps.AllowedVideoRegex
+	}
+	return rxVideosx
+}
+
 // clean cleans a node of all elements of type "tag".
 // (Unless it's a youtube/vimeo video. People love movies.)
 func (ps *Parser) clean(node *html.Node, tag string) {
 	isEmbed := indexOf([]string{"object", "embed", "iframe"}, tag) != -1
-	rxVideoVilter := ps.AllowedVideoRegex
-	if rxVideoVilter == nil {
-		rxVideoVilter = rxVideosx
-	}
+	rxVideoFilter := ps.videoRegex()
 
 	ps.removeNodes(dom.GetElementsByTagName(node, tag), func(element *html.Node) bool {
 		// Allow youtube and vimeo videos through as people usually want to see those.
@@ -1747,16 +2487,18 @@ func (ps *Parser) clean(node *html.Node, tag string) {
 			// First, check the elements attributes to see if any of them contain
 			// youtube or vimeo
 			for _, attr := range element.Attr {
-				if rxVideoVilter.MatchString(attr.Val) {
+				if rxVideoFilter.MatchString(attr.Val) {
 					return false
 				}
 			}
 
 			// For embed with <object> tag, check inner HTML as well.
-			if dom.TagName(element) == "object" && rxVideoVilter.MatchString(dom.InnerHTML(element)) {
+			if dom.TagName(element) == "object" && rxVideoFilter.MatchString(dom.InnerHTML(element)) {
 				return false
 			}
 		}
+
+		ps.diagnoseRemoval(element, RemovedTag)
 		return true
 	})
 }
@@ -1818,6 +2560,7 @@ func (ps *Parser) getRowAndColumnCount(table *html.Node) (int, int) {
 // and mark it, which similar as used in Firefox:
 // https://searchfox.org/mozilla-central/rev/f82d5c549f046cb64ce5602bfd894b7ae807c8f8/accessible/generic/TableAccessible.cpp#19
 func (ps *Parser) markDataTables(root *html.Node) {
+	thresholds := ps.DataTableThresholds
 	tables := dom.GetElementsByTagName(root, "table")
 	for i := 0; i < len(tables); i++ {
 		table := tables[i]
@@ -1834,12 +2577,12 @@ func (ps *Parser) markDataTables(root *html.Node) {
 			continue
 		}
 
-		if dom.HasAttribute(table, "summary") {
+		if thresholds.UseSummary && dom.HasAttribute(table, "summary") {
 			ps.setReadabilityDataTable(table, true)
 			continue
 		}
 
-		if captions := dom.GetElementsByTagName(table, "caption"); len(captions) > 0 {
+		if captions := dom.GetElementsByTagName(table, "caption"); thresholds.UseCaption && len(captions) > 0 {
 			if caption := captions[0]; caption != nil && len(dom.ChildNodes(caption)) > 0 {
 				ps.setReadabilityDataTable(table, true)
 				continue
@@ -1856,7 +2599,7 @@ func (ps *Parser) markDataTables(root *html.Node) {
 			}
 		}
 
-		if hasDataTableDescendantTags {
+		if thresholds.UseDataTags && hasDataTableDescendantTags {
 			ps.setReadabilityDataTable(table, true)
 			continue
 		}
@@ -1868,13 +2611,13 @@ func (ps *Parser) markDataTables(root *html.Node) {
 		}
 
 		rows, columns := ps.getRowAndColumnCount(table)
-		if rows >= 10 || columns > 4 {
+		if rows >= thresholds.MinRows || columns >= thresholds.MinColumns {
 			ps.setReadabilityDataTable(table, true)
 			continue
 		}
 
 		// Now just go by size entirely:
-		if rows*columns > 10 {
+		if rows*columns >= thresholds.MinCells {
 			ps.setReadabilityDataTable(table, true)
 		}
 	}
@@ -1883,6 +2626,12 @@ func (ps *Parser) markDataTables(root *html.Node) {
 // fixLazyImages convert images and figures that have properties like data-src into
 // images that can be loaded without JS.
 func (ps *Parser) fixLazyImages(root *html.Node) {
+	// Promote the configured lazy attributes first, since they're known
+	// to contain the real image even when its URL has no extension.
+	ps.forEachNode(ps.getAllNodesWithTag(root, "img", "source"), func(elem *html.Node, _ int) {
+		ps.promoteLazyImageAttributes(elem)
+	})
+
 	imageNodes := ps.getAllNodesWithTag(root, "img", "picture", "figure")
 	ps.forEachNode(imageNodes, func(elem *html.Node, _ int) {
 		src := dom.GetAttribute(elem, "src")
@@ -1964,6 +2713,37 @@ func (ps *Parser) fixLazyImages(root *html.Node) {
 	})
 }
 
+// promoteLazyImageAttributes copies the value of LazyImageAttributes into
+// src or srcset of the element. The attribute that listed first wins.
+func (ps *Parser) promoteLazyImageAttributes(elem *html.Node) {
+	promoted := map[string]struct{}{}
+	for _, name := range ps.LazyImageAttributes {
+		value := strings.TrimSpace(dom.GetAttribute(elem, name))
+		if value == "" || rxB64DataURL.MatchString(value) {
+			continue
+		}
+
+		copyTo := "src"
+		if strings.HasSuffix(strings.ToLower(name), "srcset") {
+			copyTo = "srcset"
+		} else if !isValidURL(toAbsoluteURI(value, ps.documentURI)) {
+			continue
+		}
+
+		// The <source> element only uses srcset
+		if dom.TagName(elem) == "source" {
+			copyTo = "srcset"
+		}
+
+		if _, exist := promoted[copyTo]; exist {
+			continue
+		}
+
+		promoted[copyTo] = struct{}{}
+		dom.SetAttribute(elem, copyTo, value)
+	}
+}
+
 // cleanConditionally cleans an element of all tags of type "tag" if
 // they look fishy. "Fishy" is an algorithm based on content length,
 // classnames, link density, number of images & embeds, etc.
@@ -1973,16 +2753,16 @@ func (ps *Parser) cleanConditionally(element *html.Node, tag string) {
 	}
 
 	// Prepare regex video filter
-	rxVideoVilter := ps.AllowedVideoRegex
-	if rxVideoVilter == nil {
-		rxVideoVilter = rxVideosx
-	}
+	rxVideoFilter := ps.videoRegex()
 
 	// Gather counts for other typical elements embedded within.
 	// Traverse backwards so we can remove nodes at the same time
 	// without effecting the traversal.
 	// TODO: Consider taking into account original contentScore here.
 	ps.removeNodes(dom.GetElementsByTagName(element, tag), func(node *html.Node) bool {
+		// The node is measured many times below, but it's not modified
+		defer ps.cacheTextLengths()()
+
 		// First check if this node IS data table, in which case don't remove it.
 		if tag == "table" && ps.isReadabilityDataTable(node) {
 			return false
@@ -1993,10 +2773,10 @@ func (ps *Parser) cleanConditionally(element *html.Node, tag string) {
 			var listLength int
 			listNodes := ps.getAllNodesWithTag(node, "ul", "ol")
 			ps.forEachNode(listNodes, func(list *html.Node, _ int) {
-				listLength += charCount(ps.getInnerText(list, true))
+				listLength += ps.getTextLength(list)
 			})
 
-			nodeLength := charCount(ps.getInnerText(node, true))
+			nodeLength := ps.getTextLength(node)
 			isList = float64(listLength)/float64(nodeLength) > 0.9
 		}
 
@@ -2009,9 +2789,20 @@ func (ps *Parser) cleanConditionally(element *html.Node, tag string) {
 			return false
 		}
 
+		if ps.PreserveCode && ps.isCodeHeavy(node) {
+			return false
+		}
+
+		// FAQ is usually a list of <details>, which doesn't have a lot
+		// of text nor commas, but it's a part of content.
+		if ps.PreserveDetails && ps.getTextDensity(node, "details") > 0.5 {
+			return false
+		}
+
 		var contentScore int
 		weight := ps.getClassWeight(node)
 		if weight+contentScore < 0 {
+			ps.diagnoseRemoval(node, RemovedNegativeWeight)
 			return true
 		}
 
@@ -2032,34 +2823,52 @@ func (ps *Parser) cleanConditionally(element *html.Node, tag string) {
 				// If this embed has attribute that matches video regex,
 				// don't delete it.
 				for _, attr := range embed.Attr {
-					if rxVideoVilter.MatchString(attr.Val) {
+					if rxVideoFilter.MatchString(attr.Val) {
 						return false
 					}
 				}
 
 				// For embed with <object> tag, check inner HTML as well.
-				if dom.TagName(embed) == "object" && rxVideoVilter.MatchString(dom.InnerHTML(embed)) {
+				if dom.TagName(embed) == "object" && rxVideoFilter.MatchString(dom.InnerHTML(embed)) {
 					return false
 				}
 
 				embedCount++
 			}
 
+			// Audio and video with controls are usually the main content
+			// of podcast and video article, so keep them.
+			if ps.PreserveMedia && ps.hasPlayableMedia(node) {
+				return false
+			}
+
 			linkDensity := ps.getLinkDensity(node)
-			contentLength := charCount(ps.getInnerText(node, true))
-			haveToRemove := (img > 1 && p/img < 0.5 && !ps.hasAncestorTag(node, "figure", 3, nil)) ||
-				(!isList && li > p) ||
-				(input > math.Floor(p/3)) ||
-				(!isList && headingDensity < 0.9 && contentLength < 25 && (img == 0 || img > 2) && !ps.hasAncestorTag(node, "figure", 3, nil)) ||
-				(!isList && weight < 25 && linkDensity > 0.2) ||
-				(weight >= 25 && linkDensity > 0.5) ||
-				((embedCount == 1 && contentLength < 75) || embedCount > 1)
+			contentLength := ps.getTextLength(node)
+
+			var reason RemovalReason
+			switch {
+			case img > 1 && p/img < 0.5 && !ps.hasAncestorTag(node, "figure", 3, nil):
+				reason = RemovedTooManyImages
+			case !isList && li > p:
+				reason = RemovedTooManyListItem
+			case input > math.Floor(p/3):
+				reason = RemovedTooManyInputs
+			case !isList && headingDensity < 0.9 && contentLength < 25 && (img == 0 || img > 2) && !ps.hasAncestorTag(node, "figure", 3, nil):
+				reason = RemovedTooShort
+			case !isList && weight < 25 && linkDensity > 0.2+ps.LinkDensityModifier,
+				weight >= 25 && linkDensity > 0.5+ps.LinkDensityModifier:
+				reason = RemovedLinkDensity
+			case (embedCount == 1 && contentLength < 75) || embedCount > 1:
+				reason = RemovedTooManyEmbeds
+			}
+			haveToRemove := reason != ""
 
 			// Allow simple lists of images to remain in pages
 			if isList && haveToRemove {
 				for _, child := range dom.Children(node) {
 					// Don't filter in lists with li's that contain more than one child
 					if len(dom.Children(child)) > 1 {
+						ps.diagnoseRemoval(node, reason)
 						return haveToRemove
 					}
 				}
@@ -2071,6 +2880,9 @@ func (ps *Parser) cleanConditionally(element *html.Node, tag string) {
 				}
 			}
 
+			if haveToRemove {
+				ps.diagnoseRemoval(node, reason)
+			}
 			return haveToRemove
 		}
 
@@ -2098,7 +2910,8 @@ func (ps *Parser) cleanHeaders(e *html.Node) {
 	ps.removeNodes(headingNodes, func(node *html.Node) bool {
 		// Removing header with low class weight
 		if ps.getClassWeight(node) < 0 {
-			ps.logf("removing header with low class weight: %q\n", dom.OuterHTML(node))
+			ps.logDebug("removing header with low class weight", "header", trim(dom.TextContent(node)))
+			ps.diagnoseRemoval(node, RemovedNegativeWeight)
 			return true
 		}
 		return false
@@ -2113,7 +2926,7 @@ func (ps *Parser) headerDuplicatesTitle(node *html.Node) bool {
 	}
 
 	heading := ps.getInnerText(node, false)
-	ps.logf("evaluating similarity of header: %q and %q\n", heading, ps.articleTitle)
+	ps.logDebug("evaluating similarity of header", "header", heading, "title", ps.articleTitle)
 	return ps.textSimilarity(ps.articleTitle, heading) > 0.75
 }
 
@@ -2138,38 +2951,55 @@ func (ps *Parser) isProbablyVisible(node *html.Node) bool {
 // package is written in Go, which is static.
 // =========================================================
 
-// getArticleFavicon attempts to get high quality favicon
-// that used in article. It will only pick favicon in PNG
-// format, so small favicon that uses ico file won't be picked.
-// Using algorithm by philippe_b.
+// getArticleFavicon attempts to get high quality favicon that used in
+// article. The icons are taken from rel="icon" and rel="apple-touch-icon"
+// links, then the largest one is picked. The size is taken from sizes
+// attribute or the URL of the icon, while apple-touch-icon without size
+// is assumed to be 180x180 and SVG icon is assumed to be the largest.
 func (ps *Parser) getArticleFavicon() string {
 	favicon := ""
 	faviconSize := -1
 	linkElements := dom.GetElementsByTagName(ps.doc, "link")
 
 	ps.forEachNode(linkElements, func(link *html.Node, _ int) {
-		linkRel := strings.TrimSpace(dom.GetAttribute(link, "rel"))
+		linkRels := strings.Fields(strings.ToLower(dom.GetAttribute(link, "rel")))
 		linkType := strings.TrimSpace(dom.GetAttribute(link, "type"))
 		linkHref := strings.TrimSpace(dom.GetAttribute(link, "href"))
-		linkSizes := strings.TrimSpace(dom.GetAttribute(link, "sizes"))
+		linkSizes := strings.ToLower(strings.TrimSpace(dom.GetAttribute(link, "sizes")))
 
-		if linkHref == "" || !strings.Contains(linkRel, "icon") {
-			return
-		}
-
-		if linkType != "image/png" && !strings.Contains(linkHref, ".png") {
+		isIcon := indexOf(linkRels, "icon") >= 0
+		isTouchIcon := indexOf(linkRels, "apple-touch-icon") >= 0 ||
+			indexOf(linkRels, "apple-touch-icon-precomposed") >= 0
+		if linkHref == "" || strings.HasPrefix(linkHref, "data:") || (!isIcon && !isTouchIcon) {
 			return
 		}
 
 		size := 0
 		for _, sizesLocation := range []string{linkSizes, linkHref} {
-			sizeParts := rxFaviconSize.FindStringSubmatch(sizesLocation)
-			if len(sizeParts) != 3 || sizeParts[1] != sizeParts[2] {
-				continue
+			for _, sizeParts := range rxFaviconSize.FindAllStringSubmatch(sizesLocation, -1) {
+				width, _ := strconv.Atoi(sizeParts[1])
+				height, _ := strconv.Atoi(sizeParts[2])
+				iconSize := width
+				if height < iconSize {
+					iconSize = height
+				}
+
+				if iconSize > size {
+					size = iconSize
+				}
 			}
 
-			size, _ = strconv.Atoi(sizeParts[1])
-			break
+			if size > 0 {
+				break
+			}
+		}
+
+		switch {
+		case size > 0:
+		case linkSizes == "any" || linkType == "image/svg+xml" || strings.HasSuffix(strings.ToLower(linkHref), ".svg"):
+			size = math.MaxInt32
+		case isTouchIcon:
+			size = 180
 		}
 
 		if size > faviconSize {
@@ -2181,28 +3011,22 @@ func (ps *Parser) getArticleFavicon() string {
 	return toAbsoluteURI(favicon, ps.documentURI)
 }
 
-// removeComments find all comments in document then remove it.
-func (ps *Parser) removeComments(doc *html.Node) {
-	// Find all comments
-	var comments []*html.Node
-	var finder func(*html.Node)
-
-	finder = func(node *html.Node) {
-		if node.Type == html.CommentNode {
-			comments = append(comments, node)
-		}
-
-		for child := node.FirstChild; child != nil; child = child.NextSibling {
-			finder(child)
-		}
+// visitComment is the visitor that removes the comment nodes.
+func (ps *Parser) visitComment(node *html.Node) visitResult {
+	if node.Type == html.CommentNode && node.Parent != nil {
+		node.Parent.RemoveChild(node)
+		return visitRemoved
 	}
+	return visitChildren
+}
 
-	for child := doc.FirstChild; child != nil; child = child.NextSibling {
-		finder(child)
+// visitStyle is the visitor that removes the style elements.
+func (ps *Parser) visitStyle(node *html.Node) visitResult {
+	if dom.TagName(node) == "style" && node.Parent != nil {
+		node.Parent.RemoveChild(node)
+		return visitRemoved
 	}
-
-	// Remove it
-	ps.removeNodes(comments, nil)
+	return visitChildren
 }
 
 // In dynamic language like JavaScript, we can easily add new
@@ -2255,26 +3079,56 @@ func (ps *Parser) getContentScore(node *html.Node) float64 {
 }
 
 // clearReadabilityAttr removes Readability attribute that
-// created by this package. Used in `postProcessContent`.
-func (ps *Parser) clearReadabilityAttr(node *html.Node) {
-	dom.RemoveAttribute(node, "data-readability-score")
-	dom.RemoveAttribute(node, "data-readability-table")
+// created by this package. It's a visitor that used in
+// `postProcessContent`.
+func (ps *Parser) clearReadabilityAttr(node *html.Node) visitResult {
+	if node.Type == html.ElementNode {
+		dom.RemoveAttribute(node, "data-readability-score")
+		dom.RemoveAttribute(node, "data-readability-table")
+		dom.RemoveAttribute(node, regionAttr)
+	}
+	return visitChildren
+}
 
-	for child := dom.FirstElementChild(node); child != nil; child = dom.NextElementSibling(child) {
-		ps.clearReadabilityAttr(child)
+// nTopCandidates returns the number of top candidates to consider. If
+// it's not set, the default value is used.
+func (ps *Parser) nTopCandidates() int {
+	if ps.NTopCandidates <= 0 {
+		return DefaultNTopCandidates
 	}
+	return ps.NTopCandidates
 }
 
-func (ps *Parser) log(args ...interface{}) {
-	if ps.Debug {
-		log.Println(args...)
+// serialize converts the article content into HTML string, using the
+// serializer of parser if it's specified.
+func (ps *Parser) serialize(articleContent *html.Node) (string, error) {
+	if ps.Serializer == nil {
+		return dom.InnerHTML(articleContent), nil
+	}
+
+	content, err := ps.Serializer(articleContent)
+	if err != nil {
+		return "", fmt.Errorf("failed to serialize content: %w", err)
 	}
+	return content, nil
 }
 
-func (ps *Parser) logf(format string, args ...interface{}) {
-	if ps.Debug {
-		log.Printf(format, args...)
+// ctxErr returns the error of parser's context. If parser doesn't
+// have context, e.g. when the method is called directly, it will
+// always return nil.
+func (ps *Parser) ctxErr() error {
+	if ps.ctx == nil {
+		return nil
 	}
+	return ps.ctx.Err()
+}
+
+// clone returns a copy of parser with the same configuration but fresh
+// parse state, which is used by each call of Parse and Candidates.
+func (ps *Parser) clone() *Parser {
+	parser := *ps
+	parser.parseState = parseState{}
+	return &parser
 }
 
 // UNUSED CODES
@@ -1,12 +1,15 @@
 package readability
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/url"
 	"os"
 	fp "path/filepath"
+	"regexp"
 	"strings"
 	"testing"
 
@@ -24,6 +27,7 @@ type ExpectedMetadata struct {
 	Byline     string `json:"byline,omitempty"`
 	Excerpt    string `json:"excerpt,omitempty"`
 	Language   string `json:"language,omitempty"`
+	Dir        string `json:"dir,omitempty"`
 	SiteName   string `json:"siteName,omitempty"`
 	Readerable bool   `json:"readerable"`
 }
@@ -95,6 +99,10 @@ func Test_parser(t *testing.T) {
 			if metadata.Language != article.Language {
 				t1.Errorf("language, want %q got %q\n", metadata.Language, article.Language)
 			}
+
+			if metadata.Dir != article.Dir {
+				t1.Errorf("dir, want %q got %q\n", metadata.Dir, article.Dir)
+			}
 		})
 	}
 }
@@ -113,8 +121,11 @@ func extractSourceFile(path string) (Article, *html.Node, *html.Node, error) {
 		return Article{}, nil, nil, fmt.Errorf("failed to decode source: %v", err)
 	}
 
-	// Extract readable article
-	article, err := FromDocument(originalDoc, fakeHostURL)
+	// Extract readable article. The expected results are made by
+	// Readability.js, so the cleanups that not exist there are disabled.
+	parser := NewParser(WithDisablePromoRemoval(true), WithDisableShareBarRemoval(true),
+		WithPropagateDir(false))
+	article, err := parser.ParseDocument(originalDoc, fakeHostURL)
 	if err != nil {
 		return Article{}, nil, nil, fmt.Errorf("failed to extract source: %v", err)
 	}
@@ -252,3 +263,415 @@ func getNodeExcerpt(node *html.Node) string {
 	}
 	return outer[:120]
 }
+
+func Test_ParseWithContext(t *testing.T) {
+	f, err := os.Open(fp.Join("test-pages", "001", "source.html"))
+	if err != nil {
+		t.Fatalf("failed to open source: %v", err)
+	}
+	defer f.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	parser := NewParser()
+	_, err = parser.ParseWithContext(ctx, f, fakeHostURL)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("error, want %v got %v", context.Canceled, err)
+	}
+}
+
+func Test_Parser_LazyImageAttributes(t *testing.T) {
+	scenarios := map[string]string{
+		`<img data-src="/image?id=1">`:                                `<img data-src="/image?id=1" src="/image?id=1"/>`,
+		`<img src="/placeholder.gif" data-lazy-src="/image?id=1">`:    `<img src="/image?id=1" data-lazy-src="/image?id=1"/>`,
+		`<img data-srcset="/small 1x, /large 2x">`:                    `<img data-srcset="/small 1x, /large 2x" srcset="/small 1x, /large 2x"/>`,
+		`<img data-src="/first" data-original="/second">`:             `<img data-src="/first" data-original="/second" src="/first"/>`,
+		`<picture><source data-src="/image?id=1"></picture>`:          `<picture><source data-src="/image?id=1" srcset="/image?id=1"/></picture>`,
+		`<img data-src="data:image/gif;base64,R0lGODlhAQABAAAAACw=">`: `<img data-src="data:image/gif;base64,R0lGODlhAQABAAAAACw="/>`,
+	}
+
+	ps := NewParser(WithLazyImageAttributes("data-src", "data-lazy-src", "data-original", "data-srcset"))
+	for input, expected := range scenarios {
+		doc, err := dom.Parse(strings.NewReader(input))
+		if err != nil {
+			t.Fatalf("failed to parse %q: %v", input, err)
+		}
+
+		body := dom.QuerySelector(doc, "body")
+		ps.fixLazyImages(body)
+		if result := dom.InnerHTML(body); result != expected {
+			t.Errorf("\n"+
+				"html : %q\n"+
+				"want : %q\n"+
+				"got  : %q", input, expected, result)
+		}
+	}
+}
+
+func Test_Parser_DisableNoscriptUnwrap(t *testing.T) {
+	paragraph := articleParagraph(10)
+	image := `<figure><img class="lazy" src="data:image/gif;base64,R0lGODlhAQABAAAAACw=">` +
+		`<noscript><img src="/real-image.jpg" alt="Real image"></noscript></figure>`
+	input := "<html><body><article>" + paragraph + image + paragraph + "</article></body></html>"
+
+	for _, disable := range []bool{false, true} {
+		ps := NewParser(WithDisableNoscriptUnwrap(disable))
+		article, err := ps.Parse(strings.NewReader(input), fakeHostURL)
+		if err != nil {
+			t.Fatalf("failed to parse: %v", err)
+		}
+
+		hasImage := strings.Contains(article.Content, `src="http://fakehost/real-image.jpg"`)
+		if hasImage == disable {
+			t.Errorf("disable unwrap %v, but image exist is %v\ngot: %s", disable, hasImage, article.Content)
+		}
+	}
+}
+
+func Test_Parser_AllowedVideoRegex(t *testing.T) {
+	paragraph := articleParagraph(10)
+	embeds := `<iframe src="https://www.youtube.com/embed/abc"></iframe>` +
+		`<iframe src="https://peertube.example.org/videos/embed/abc"></iframe>`
+	input := "<html><body><article>" + paragraph + embeds + paragraph + "</article></body></html>"
+
+	scenarios := map[*regexp.Regexp][]string{
+		nil:                                    {"youtube.com"},
+		regexp.MustCompile(`(?i)//peertube\.`): {"peertube.example.org"},
+	}
+
+	for rx, expectedEmbeds := range scenarios {
+		ps := NewParser(WithAllowedVideoRegex(rx))
+		article, err := ps.Parse(strings.NewReader(input), fakeHostURL)
+		if err != nil {
+			t.Fatalf("failed to parse: %v", err)
+		}
+
+		if count := strings.Count(article.Content, "<iframe"); count != len(expectedEmbeds) {
+			t.Errorf("regex %v, want %d iframe got %d", rx, len(expectedEmbeds), count)
+		}
+
+		for _, embed := range expectedEmbeds {
+			if !strings.Contains(article.Content, embed) {
+				t.Errorf("regex %v, content should contain %q", rx, embed)
+			}
+		}
+	}
+}
+
+func Test_Parser_DataTableThresholds(t *testing.T) {
+	// Small table without any hint is a layout table by default
+	smallTable := `<table><tr><td>Team</td><td>Score</td></tr><tr><td>A</td><td>3</td></tr></table>`
+	captionTable := `<table><caption>Result</caption><tr><td>Team</td><td>Score</td></tr></table>`
+
+	lenient := NewParser().DataTableThresholds
+	lenient.MinCells = 4
+
+	strict := NewParser().DataTableThresholds
+	strict.UseCaption = false
+
+	scenarios := []struct {
+		html       string
+		thresholds DataTableThresholds
+		expected   bool
+	}{
+		{smallTable, NewParser().DataTableThresholds, false},
+		{smallTable, lenient, true},
+		{captionTable, NewParser().DataTableThresholds, true},
+		{captionTable, strict, false},
+	}
+
+	for _, scenario := range scenarios {
+		doc, err := dom.Parse(strings.NewReader(scenario.html))
+		if err != nil {
+			t.Fatalf("failed to parse %q: %v", scenario.html, err)
+		}
+
+		ps := NewParser(WithDataTableThresholds(scenario.thresholds))
+		ps.markDataTables(doc)
+
+		table := dom.QuerySelector(doc, "table")
+		if result := ps.isReadabilityDataTable(table); result != scenario.expected {
+			t.Errorf("\n"+
+				"html       : %q\n"+
+				"thresholds : %+v\n"+
+				"want       : %v\n"+
+				"got        : %v", scenario.html, scenario.thresholds, scenario.expected, result)
+		}
+	}
+}
+
+func Test_Parser_PreserveDetails(t *testing.T) {
+	paragraph := articleParagraph(10)
+	faq := `<div class="faq"><details open><summary>Free?</summary><div>Yes.</div></details>` +
+		`<details><summary>Open?</summary><div>Yes.</div></details></div>`
+	input := "<html><body><article>" + paragraph + faq + paragraph + "</article></body></html>"
+
+	for _, preserve := range []bool{false, true} {
+		ps := NewParser(WithPreserveDetails(preserve))
+		article, err := ps.Parse(strings.NewReader(input), fakeHostURL)
+		if err != nil {
+			t.Fatalf("failed to parse: %v", err)
+		}
+
+		hasDetails := strings.Contains(article.Content, `<details open=""><summary>Free?</summary>`) &&
+			strings.Contains(article.Content, `<summary>Open?</summary>`)
+		if hasDetails != preserve {
+			t.Errorf("preserve %v, but details exist is %v\ngot: %s", preserve, hasDetails, article.Content)
+		}
+	}
+}
+
+func Test_Parser_ClassesToPreserve(t *testing.T) {
+	paragraph := `<p class="lead highlight">` + strings.Repeat(articleSentence, 10) + "</p>"
+	input := "<html><body><article>" + paragraph + paragraph + "</article></body></html>"
+
+	scenarios := []struct {
+		opts     []Option
+		expected []string
+	}{
+		{nil, []string{`class="page"`, `<p>`}},
+		{[]Option{WithClassesToPreserve("highlight")}, []string{`class="page"`, `<p class="highlight">`}},
+		{[]Option{WithKeepClasses(true)}, []string{`class="page"`, `<p class="lead highlight">`}},
+	}
+
+	for _, scenario := range scenarios {
+		ps := NewParser(scenario.opts...)
+		article, err := ps.Parse(strings.NewReader(input), fakeHostURL)
+		if err != nil {
+			t.Fatalf("failed to parse: %v", err)
+		}
+
+		for _, expected := range scenario.expected {
+			if !strings.Contains(article.Content, expected) {
+				t.Errorf("classes %v keep %v, content should contain %q", ps.ClassesToPreserve, ps.KeepClasses, expected)
+			}
+		}
+	}
+}
+
+func Test_Parser_MaxElemsToParse(t *testing.T) {
+	input := "<html><body><article>" + strings.Repeat("<p>Paragraph</p>", 10) + "</article></body></html>"
+
+	// The document has html, head, body, article and 10 paragraphs
+	scenarios := map[int]bool{
+		0:  false,
+		13: true,
+		14: false,
+	}
+
+	for max, expectError := range scenarios {
+		ps := NewParser(WithMaxElemsToParse(max))
+		_, err := ps.Parse(strings.NewReader(input), fakeHostURL)
+		if tooMany := errors.Is(err, ErrTooManyElements); tooMany != expectError {
+			t.Errorf("max %d, want too many elements %v got error %v", max, expectError, err)
+		}
+	}
+}
+
+func Test_Parser_NTopCandidates(t *testing.T) {
+	f, err := os.Open(fp.Join("test-pages", "nytimes-1", "source.html"))
+	if err != nil {
+		t.Fatalf("failed to open source: %v", err)
+	}
+	defer f.Close()
+
+	doc, err := dom.Parse(f)
+	if err != nil {
+		t.Fatalf("failed to parse source: %v", err)
+	}
+
+	// Invalid number of candidates should fall back to the default
+	defaultParser := NewParser()
+	expected, err := defaultParser.ParseDocument(doc, fakeHostURL)
+	if err != nil {
+		t.Fatalf("failed to parse document: %v", err)
+	}
+
+	for _, n := range []int{0, -1} {
+		ps := NewParser(WithNTopCandidates(n))
+		article, err := ps.ParseDocument(doc, fakeHostURL)
+		if err != nil {
+			t.Fatalf("failed to parse document: %v", err)
+		}
+
+		if article.Content != expected.Content {
+			t.Errorf("%d top candidates should use the default number of candidates", n)
+		}
+	}
+}
+
+func Test_Parser_CharThresholds(t *testing.T) {
+	poem := `<p>Whose woods these are I think I know.<br>His house is in the village though;<br>` +
+		`He will not see me stopping here<br>To watch his woods fill up with snow.</p>`
+	input := `<html><body><article><div class="sidebar">Share this poem</div>` +
+		poem + `</article></body></html>`
+
+	scenarios := map[int]bool{
+		DefaultCharThresholds: true,
+		100:                   false,
+	}
+
+	for threshold, expectClutter := range scenarios {
+		ps := NewParser(WithCharThresholds(threshold))
+		article, err := ps.Parse(strings.NewReader(input), fakeHostURL)
+		if err != nil {
+			t.Fatalf("failed to parse: %v", err)
+		}
+
+		if !strings.Contains(article.TextContent, "To watch his woods fill up with snow.") {
+			t.Errorf("threshold %d, content should contain the poem", threshold)
+		}
+
+		if hasClutter := strings.Contains(article.TextContent, "Share this poem"); hasClutter != expectClutter {
+			t.Errorf("threshold %d, want clutter %v got %v", threshold, expectClutter, hasClutter)
+		}
+	}
+}
+
+func Test_Parser_Serializer(t *testing.T) {
+	paragraph := articleParagraph(10)
+	input := "<html><body><article>" + paragraph + "<br></article></body></html>"
+
+	// Serializer that produces XHTML-like output
+	xhtml := func(node *html.Node) (string, error) {
+		var sb strings.Builder
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			if err := html.Render(&sb, child); err != nil {
+				return "", err
+			}
+		}
+		return "<!-- custom -->" + sb.String(), nil
+	}
+
+	ps := NewParser(WithSerializer(xhtml))
+	article, err := ps.Parse(strings.NewReader(input), fakeHostURL)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	if !strings.HasPrefix(article.Content, `<!-- custom --><div id="readability-page-1" class="page">`) {
+		t.Errorf("content should be produced by serializer, got %q", article.Content)
+	}
+
+	// Error from serializer should be returned
+	errSerializer := errors.New("serializer error")
+	ps = NewParser(WithSerializer(func(*html.Node) (string, error) {
+		return "", errSerializer
+	}))
+
+	if _, err = ps.Parse(strings.NewReader(input), fakeHostURL); !errors.Is(err, errSerializer) {
+		t.Errorf("want serializer error, got %v", err)
+	}
+}
+
+func Test_Parser_LinkDensityModifier(t *testing.T) {
+	paragraph := articleParagraph(10)
+	references := `<div><b>References:</b> see <a href="/a">the first paper</a> and <a href="/b">the second paper</a> ` +
+		`for the details.</div>`
+	input := "<html><body><article>" + paragraph + references + paragraph + "</article></body></html>"
+
+	scenarios := map[float64]bool{
+		0:   false,
+		0.5: true,
+	}
+
+	for modifier, expected := range scenarios {
+		ps := NewParser(WithLinkDensityModifier(modifier))
+		article, err := ps.Parse(strings.NewReader(input), fakeHostURL)
+		if err != nil {
+			t.Fatalf("failed to parse: %v", err)
+		}
+
+		if hasReferences := strings.Contains(article.TextContent, "the second paper"); hasReferences != expected {
+			t.Errorf("modifier %v, want references %v got %v", modifier, expected, hasReferences)
+		}
+	}
+}
+
+func Test_Parser_DebugHTML(t *testing.T) {
+	paragraph := articleParagraph(10)
+	input := `<html><body><div id="main">` + paragraph + paragraph + `</div>` +
+		`<div class="sidebar">Related links</div></body></html>`
+
+	ps := NewParser()
+	article, err := ps.Parse(strings.NewReader(input), fakeHostURL)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	if article.DebugHTML != "" {
+		t.Errorf("debug HTML should be empty outside debug mode, got %q", article.DebugHTML)
+	}
+
+	ps = NewParser(WithDebug(true))
+	article, err = ps.Parse(strings.NewReader(input), fakeHostURL)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	if !strings.Contains(article.DebugHTML, `<div id="main" data-readability-score="`) {
+		t.Errorf("candidate should be annotated with its score, got %q", article.DebugHTML)
+	}
+
+	if strings.Contains(article.Content, "data-readability-score") {
+		t.Errorf("content shouldn't contain the score, got %q", article.Content)
+	}
+}
+
+func Test_Parser_PropagateDir(t *testing.T) {
+	paragraph := articleParagraph(10)
+	input := `<html dir="rtl"><body><article>` + paragraph + paragraph + `</article></body></html>`
+
+	rules := NewRules()
+	rules.Register("fakehost", Rule{Content: []string{"article"}})
+
+	propagated := `<div id="readability-page-1" class="page" dir="rtl">`
+	scenarios := map[string]struct {
+		opts   []Option
+		prefix string
+	}{
+		"default":   {nil, propagated},
+		"disabled":  {[]Option{WithPropagateDir(false)}, `<div id="readability-page-1" class="page">`},
+		"density":   {[]Option{WithAlgorithm(AlgorithmDensity)}, propagated},
+		"site rule": {[]Option{WithRules(rules)}, propagated},
+	}
+
+	for name, scenario := range scenarios {
+		ps := NewParser(scenario.opts...)
+		article, err := ps.Parse(strings.NewReader(input), fakeHostURL)
+		if err != nil {
+			t.Fatalf("%s: failed to parse: %v", name, err)
+		}
+
+		if article.Dir != "rtl" {
+			t.Errorf("%s: dir, want %q got %q", name, "rtl", article.Dir)
+		}
+
+		if !strings.HasPrefix(article.Content, scenario.prefix) {
+			t.Errorf("%s: want prefix %q got %q", name, scenario.prefix, article.Content[:80])
+		}
+	}
+}
+
+func Benchmark_Parser_Parse(b *testing.B) {
+	var sources [][]byte
+	for _, name := range []string{"wikipedia", "nytimes-3", "yahoo-2"} {
+		source, err := os.ReadFile(fp.Join("test-pages", name, "source.html"))
+		if err != nil {
+			b.Fatalf("failed to read source of %s: %v", name, err)
+		}
+		sources = append(sources, source)
+	}
+
+	ps := NewParser()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, source := range sources {
+			if _, err := ps.Parse(strings.NewReader(string(source)), fakeHostURL); err != nil {
+				b.Fatalf("failed to parse: %v", err)
+			}
+		}
+	}
+}
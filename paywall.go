@@ -0,0 +1,79 @@
+package readability
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/go-shiori/dom"
+	"golang.org/x/net/html"
+)
+
+var (
+	rxPaywallClass = newKeywordMatcher(`(?i)paywall|regwall|subscriber-only|subscribers-only|premium-content|metered-content|locked-content|article-locked`)
+	rxPaywallText  = regexp.MustCompile(`(?i)\b(subscribe|sign up|log ?in|register)( now)? to (continue|keep) reading|` +
+		`\balready an? (subscriber|member)\b|` +
+		`\b(article|content|story) is (only )?(available )?(for|to) (paid |premium )?(subscribers|members)\b|` +
+		`\bbecome an? (subscriber|member) to (read|continue)\b|` +
+		`\bsubscribers only\b`)
+	// rxPaywallHint matches the keywords that every marker of rxPaywallText
+	// has, so the expensive regex only runs on the text that may match it
+	rxPaywallHint  = newKeywordMatcher(`(?i)reading|already|subscribers|members|become`)
+	rxTruncatedEnd = regexp.MustCompile(`(\.\.\.|…|\[…\]|\[\.\.\.\])$`)
+)
+
+// paywallDetector checks if the document is probably behind a paywall,
+// using schema.org isAccessibleForFree property, paywall class names and
+// the "subscribe to continue" markers inside body. This must be done before
+// the document is cleaned, since the paywall itself is usually removed by
+// then.
+type paywallDetector struct {
+	paywalled bool
+}
+
+// newPaywallDetector creates the detector, which already knows the result
+// if JSON-LD says the article is not free.
+func newPaywallDetector(jsonLd map[string]string) *paywallDetector {
+	return &paywallDetector{paywalled: strings.EqualFold(jsonLd["isAccessibleForFree"], "false")}
+}
+
+// visit checks the node, and stops as soon as the paywall is found.
+func (pd *paywallDetector) visit(node *html.Node) visitResult {
+	if pd.paywalled {
+		return visitDone
+	}
+
+	switch node.Type {
+	case html.TextNode:
+		pd.paywalled = node.Parent != nil && dom.TagName(node.Parent) != "html" &&
+			rxPaywallHint.MatchString(node.Data) &&
+			rxPaywallText.MatchString(collapseSpaces(node.Data, 1))
+
+	case html.ElementNode:
+		switch dom.TagName(node) {
+		case "head":
+			return visitSkipChildren
+		case "html", "body":
+			return visitChildren
+		}
+
+		matchString := dom.ClassName(node) + " " + dom.ID(node)
+		pd.paywalled = rxPaywallClass.MatchString(matchString)
+
+	case html.DocumentNode:
+		return visitChildren
+
+	default:
+		return visitSkipChildren
+	}
+
+	if pd.paywalled {
+		return visitDone
+	}
+	return visitChildren
+}
+
+// isAbruptlyCut checks if the text content of the article ends abruptly,
+// e.g. with ellipsis, which happens when only the teaser is served.
+func isAbruptlyCut(textContent string) bool {
+	return rxTruncatedEnd.MatchString(strings.TrimSpace(textContent))
+}
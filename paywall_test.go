@@ -0,0 +1,37 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_Article_Truncated(t *testing.T) {
+	paragraph := strings.Repeat(articleSentence, 10)
+	content := "<p>" + paragraph + "</p><p>" + paragraph + "</p>"
+
+	scenarios := map[string]bool{
+		content: false,
+		`<script type="application/ld+json">{"@context":"https://schema.org","@type":"NewsArticle",` +
+			`"isAccessibleForFree":false}</script>` + content: true,
+		`<script type="application/ld+json">{"@context":"https://schema.org","@type":"NewsArticle",` +
+			`"isAccessibleForFree":"False"}</script>` + content: true,
+		content + `<div class="article-paywall">Read more</div>`:                            true,
+		content + `<p>Subscribe now to continue reading.</p>`:                               true,
+		content + `<p>Already a subscriber? <a href="/login">Log in</a></p>`:                true,
+		"<p>" + paragraph + "</p><p>" + paragraph + "But then the article suddenly ...</p>": true,
+	}
+
+	for input, expected := range scenarios {
+		article, err := FromReader(strings.NewReader("<html><body><article>"+input+"</article></body></html>"), fakeHostURL)
+		if err != nil {
+			t.Fatalf("failed to parse %q: %v", input, err)
+		}
+
+		if article.Truncated != expected {
+			t.Errorf("\n"+
+				"html : %q\n"+
+				"want : %v\n"+
+				"got  : %v", input, expected, article.Truncated)
+		}
+	}
+}
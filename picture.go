@@ -0,0 +1,121 @@
+package readability
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/go-shiori/dom"
+	"golang.org/x/net/html"
+)
+
+// srcsetCandidate is a single image candidate in srcset attribute.
+type srcsetCandidate struct {
+	url     string
+	width   float64
+	density float64
+}
+
+// parseSrcset parses the value of srcset attribute into its candidates.
+// The URL may contain comma, so just like browser, URL is parsed as the
+// run of non-whitespace characters and the trailing commas are removed,
+// while the descriptors are ended by comma.
+func parseSrcset(srcset string) []srcsetCandidate {
+	var candidates []srcsetCandidate
+	for rest := srcset; ; {
+		rest = strings.TrimLeft(rest, ", \t\n\r\f")
+		if rest == "" {
+			break
+		}
+
+		// Collect URL
+		urlEnd := strings.IndexAny(rest, " \t\n\r\f")
+		if urlEnd < 0 {
+			urlEnd = len(rest)
+		}
+
+		url := rest[:urlEnd]
+		rest = rest[urlEnd:]
+		candidate := srcsetCandidate{url: strings.TrimRight(url, ","), density: 1}
+
+		// If URL ends with comma, it doesn't have any descriptor
+		if !strings.HasSuffix(url, ",") {
+			descriptorsEnd := strings.Index(rest, ",")
+			if descriptorsEnd < 0 {
+				descriptorsEnd = len(rest)
+			}
+
+			for _, descriptor := range strings.Fields(rest[:descriptorsEnd]) {
+				value, err := strconv.ParseFloat(descriptor[:len(descriptor)-1], 64)
+				if err != nil {
+					continue
+				}
+
+				switch descriptor[len(descriptor)-1] {
+				case 'w':
+					candidate.width = value
+				case 'x':
+					candidate.density = value
+				}
+			}
+			rest = rest[descriptorsEnd:]
+		}
+
+		candidates = append(candidates, candidate)
+	}
+	return candidates
+}
+
+// bestSrcsetCandidate returns the URL of largest image in srcset. Width
+// descriptor is preferred over pixel density.
+func bestSrcsetCandidate(srcset string) (string, float64, float64) {
+	var best srcsetCandidate
+	for _, candidate := range parseSrcset(srcset) {
+		if best.url == "" || candidate.width > best.width ||
+			(candidate.width == best.width && candidate.density > best.density) {
+			best = candidate
+		}
+	}
+	return best.url, best.width, best.density
+}
+
+// collapsePictures replaces each <picture> element with its best single
+// <img>, i.e. the largest image among its <source> and <img> children.
+func (ps *Parser) collapsePictures(articleContent *html.Node) {
+	pictures := ps.getAllNodesWithTag(articleContent, "picture")
+	ps.forEachNode(pictures, func(picture *html.Node, _ int) {
+		var img *html.Node
+		if imgs := dom.GetElementsByTagName(picture, "img"); len(imgs) > 0 {
+			img = dom.Clone(imgs[0], false)
+		} else {
+			img = dom.CreateElement("img")
+		}
+
+		bestURL := dom.GetAttribute(img, "src")
+		var bestWidth, bestDensity float64
+		candidates := []string{dom.GetAttribute(img, "srcset")}
+		for _, source := range dom.GetElementsByTagName(picture, "source") {
+			candidates = append(candidates, dom.GetAttribute(source, "srcset"), dom.GetAttribute(source, "src"))
+		}
+
+		for _, srcset := range candidates {
+			url, width, density := bestSrcsetCandidate(srcset)
+			if url == "" {
+				continue
+			}
+
+			if bestURL == "" || width > bestWidth || (width == bestWidth && density > bestDensity) {
+				bestURL, bestWidth, bestDensity = url, width, density
+			}
+		}
+
+		// If there is no image at all, leave the picture as it is
+		if bestURL == "" {
+			return
+		}
+
+		dom.SetAttribute(img, "src", bestURL)
+		dom.RemoveAttribute(img, "srcset")
+		dom.RemoveAttribute(img, "sizes")
+		dom.ReplaceChild(picture.Parent, img, picture)
+	})
+}
@@ -0,0 +1,59 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_bestSrcsetCandidate(t *testing.T) {
+	scenarios := map[string]string{
+		"":                                 "",
+		"/image.jpg":                       "/image.jpg",
+		"/small.jpg 1x, /large.jpg 2x":     "/large.jpg",
+		"/small.jpg 400w,/large.jpg 1200w": "/large.jpg",
+		"/a.jpg 800w, /b.jpg 2x":           "/a.jpg",
+		"/img/w_100,h_100/a.jpg 100w, /img/w_900,h_900/a.jpg 900w": "/img/w_900,h_900/a.jpg",
+	}
+
+	for input, expected := range scenarios {
+		if result, _, _ := bestSrcsetCandidate(input); result != expected {
+			t.Errorf("\n"+
+				"srcset : %q\n"+
+				"want   : %q\n"+
+				"got    : %q", input, expected, result)
+		}
+	}
+}
+
+func Test_Parser_CollapsePictures(t *testing.T) {
+	paragraph := articleParagraph(10)
+	picture := `<picture>` +
+		`<source srcset="/img/large.webp 1200w, /img/medium.webp 800w" sizes="100vw" type="image/webp">` +
+		`<img src="/img/small.jpg" srcset="/img/small.jpg 400w" sizes="100vw" alt="Picture">` +
+		`</picture>`
+	input := "<html><body><article>" + paragraph + picture + paragraph + "</article></body></html>"
+
+	scenarios := map[bool][]string{
+		false: {`<source srcset="http://fakehost/img/large.webp 1200w, http://fakehost/img/medium.webp 800w" sizes="100vw"`,
+			`<img src="http://fakehost/img/small.jpg" srcset="http://fakehost/img/small.jpg 400w"`},
+		true: {`<img src="http://fakehost/img/large.webp" alt="Picture"/>`},
+	}
+
+	for collapse, expectations := range scenarios {
+		ps := NewParser(WithCollapsePictures(collapse))
+		article, err := ps.Parse(strings.NewReader(input), fakeHostURL)
+		if err != nil {
+			t.Fatalf("failed to parse: %v", err)
+		}
+
+		for _, expected := range expectations {
+			if !strings.Contains(article.Content, expected) {
+				t.Errorf("collapse %v, content should contain %q\ngot: %s", collapse, expected, article.Content)
+			}
+		}
+
+		if hasPicture := strings.Contains(article.Content, "<picture>"); hasPicture == collapse {
+			t.Errorf("collapse %v, but picture exist is %v", collapse, hasPicture)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package readability
+
+import (
+	"context"
+	"fmt"
+	"io"
+	nurl "net/url"
+	"sync"
+
+	"golang.org/x/net/html"
+)
+
+// ParserPool is a pool of parsers with the same configuration. Parser is
+// already safe for concurrent use, but each call of Parse has to allocate
+// its own copy of parser. The pool reuses those copies instead, which is
+// useful for the service that parses a lot of pages concurrently.
+type ParserPool struct {
+	pool sync.Pool
+}
+
+// NewParserPool returns a pool of parsers that created by NewParser with
+// the specified options.
+func NewParserPool(opts ...Option) *ParserPool {
+	return &ParserPool{
+		pool: sync.Pool{
+			New: func() interface{} {
+				parser := NewParser(opts...)
+				return &parser
+			},
+		},
+	}
+}
+
+// Get returns a parser from the pool. The parser must not be used anymore
+// after it's returned to the pool by Put.
+func (pp *ParserPool) Get() *Parser {
+	return pp.pool.Get().(*Parser)
+}
+
+// Put returns the parser to the pool. Its parse state is reset, so it
+// doesn't keep the last document alive.
+func (pp *ParserPool) Put(ps *Parser) {
+	// Keep the attempts buffer to be reused by the next parse
+	attempts := ps.attempts[:cap(ps.attempts)]
+	for i := range attempts {
+		attempts[i] = parseAttempt{}
+	}
+
+	ps.parseState = parseState{attempts: attempts[:0]}
+	pp.pool.Put(ps)
+}
+
+// Parse parses a reader using a parser from the pool.
+func (pp *ParserPool) Parse(input io.Reader, pageURL *nurl.URL) (Article, error) {
+	return pp.ParseWithContext(context.Background(), input, pageURL)
+}
+
+// ParseWithContext is like Parse, but it will stop parsing and return
+// the context's error as soon as ctx is done.
+func (pp *ParserPool) ParseWithContext(ctx context.Context, input io.Reader, pageURL *nurl.URL) (_ Article, err error) {
+	ps := pp.Get()
+	defer pp.Put(ps)
+	defer ps.recoverPanic(&err)
+	ctx, finish := ps.limitParseDuration(ctx)
+
+	doc, err := parseInput(ctx, input)
+	if err != nil {
+		return Article{}, finish(fmt.Errorf("failed to parse input: %w", err))
+	}
+
+	article, err := ps.parseDocument(ctx, doc, pageURL)
+	return article, finish(err)
+}
+
+// ParseDocumentWithContext parses the document using a parser from the
+// pool. Unlike Parser.ParseDocumentWithContext, the pooled parser is used
+// directly instead of its copy, since it's not shared until it's returned.
+func (pp *ParserPool) ParseDocumentWithContext(ctx context.Context, doc *html.Node, pageURL *nurl.URL) (_ Article, err error) {
+	ps := pp.Get()
+	defer pp.Put(ps)
+	defer ps.recoverPanic(&err)
+	ctx, finish := ps.limitParseDuration(ctx)
+
+	article, err := ps.parseDocument(ctx, doc, pageURL)
+	return article, finish(err)
+}
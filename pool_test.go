@@ -0,0 +1,81 @@
+package readability
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func poolTestPage(i int) string {
+	title := fmt.Sprintf("Article number %d", i)
+	paragraph := "<p>" + strings.Repeat(fmt.Sprintf("This is a sentence of the article %d, long enough to be scored. ", i), 10) + "</p>"
+	return "<html><head><title>" + title + "</title></head><body><article>" +
+		strings.Repeat(paragraph, 3) + "</article></body></html>"
+}
+
+func Test_Parser_ConcurrentParse(t *testing.T) {
+	ps := NewParser(WithDiagnostics(true))
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			article, err := ps.Parse(strings.NewReader(poolTestPage(i)), fakeHostURL)
+			if err != nil {
+				errs <- err
+				return
+			}
+
+			expected := fmt.Sprintf("the article %d,", i)
+			if article.Title != fmt.Sprintf("Article number %d", i) || !strings.Contains(article.TextContent, expected) {
+				errs <- fmt.Errorf("page %d got mixed result: %q", i, article.Title)
+			}
+		}(i)
+	}
+
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+func Test_ParserPool(t *testing.T) {
+	pool := NewParserPool(WithCharThresholds(100))
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			article, err := pool.Parse(strings.NewReader(poolTestPage(i)), fakeHostURL)
+			if err != nil {
+				errs <- err
+				return
+			}
+
+			if article.Title != fmt.Sprintf("Article number %d", i) {
+				errs <- fmt.Errorf("page %d got mixed result: %q", i, article.Title)
+			}
+		}(i)
+	}
+
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	ps := pool.Get()
+	defer pool.Put(ps)
+	if ps.CharThresholds != 100 {
+		t.Errorf("pooled parser has char thresholds %d, want 100", ps.CharThresholds)
+	}
+	if ps.doc != nil || len(ps.attempts) != 0 {
+		t.Error("pooled parser still has the state of last parse")
+	}
+}
@@ -0,0 +1,133 @@
+package readability
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/go-shiori/dom"
+	"golang.org/x/net/html"
+)
+
+// promoMaxLength is the max length of text in newsletter signup box and
+// call to action card. Longer block is never removed, so the article that
+// talks about newsletter won't be removed by mistake.
+const promoMaxLength = 400
+
+// maxSignupFields is the max number of fields in newsletter signup form.
+const maxSignupFields = 2
+
+var (
+	rxPromoClass = regexp.MustCompile(`(?i)newsletter|subscribe|subscription|signup|sign-up|optin|opt-in|` +
+		`\bcta\b|call-to-action|promo`)
+	rxPromoText = regexp.MustCompile(`(?i)\b(subscribe|sign up) (to|for) (our|the|my) .{0,40}` +
+		`(newsletter|mailing list|updates|daily|weekly)\b|` +
+		`\b(get|receive) (our|the) .{0,40}(newsletter|stories|headlines) .{0,40}(inbox|email)\b|` +
+		`\bjoin (\d[\d,.]*\w* )?(other )?(subscribers|readers)\b|` +
+		`\bdownload (our|the) (free )?app\b|` +
+		`\bfollow us on\b|` +
+		`\b(support|donate to) (our|us|independent) (journalism|work|reporting)\b|` +
+		`\bbecome an? (member|patron|supporter)\b`)
+	rxEmailField = regexp.MustCompile(`(?i)e-?mail`)
+)
+
+// removePromoBoxes removes the newsletter signup boxes and call to action
+// cards that embedded in the article, which are usually not long enough to
+// be cleaned conditionally. Signup box is detected from the form that asks
+// for email, and it's removed along with its heading and description. Call
+// to action is a short block with promotional text, that has link or class
+// name like "newsletter" and "cta".
+func (ps *Parser) removePromoBoxes(articleContent *html.Node) {
+	for _, form := range dom.GetElementsByTagName(articleContent, "form") {
+		if !ps.isSignupForm(form) {
+			continue
+		}
+
+		// The box may be already removed along with the previous form
+		if box := ps.promoContainer(form, articleContent); box != nil && box.Parent != nil {
+			ps.logDebug("removing newsletter signup", "match", dom.ClassName(box)+" "+dom.ID(box))
+			ps.diagnoseRemoval(box, RemovedPromo)
+			box.Parent.RemoveChild(box)
+		}
+	}
+
+	promoBlocks := ps.getAllNodesWithTag(articleContent, "div", "section", "aside", "p", "form")
+	ps.removeNodes(promoBlocks, func(node *html.Node) bool {
+		if isPageContainer(node, articleContent) || !ps.isPromoBlock(node) {
+			return false
+		}
+
+		ps.logDebug("removing call to action", "match", dom.ClassName(node)+" "+dom.ID(node))
+		ps.diagnoseRemoval(node, RemovedPromo)
+		return true
+	})
+}
+
+// promoContainer returns the outermost ancestor of the signup form that
+// still short enough to be a signup box, or nil if the form itself is
+// already too long.
+func (ps *Parser) promoContainer(form, articleContent *html.Node) *html.Node {
+	if ps.getTextLength(form) > promoMaxLength {
+		return nil
+	}
+
+	container := form
+	for parent := container.Parent; parent != nil && !isPageContainer(parent, articleContent); parent = parent.Parent {
+		if parent == articleContent || ps.getTextLength(parent) > promoMaxLength {
+			break
+		}
+		container = parent
+	}
+	return container
+}
+
+// isSignupForm checks if the form only asks for email and maybe the name.
+// Form with text area or many inputs is comment or contact form instead,
+// which is handled as usual.
+func (ps *Parser) isSignupForm(form *html.Node) bool {
+	if len(dom.GetElementsByTagName(form, "textarea")) > 0 {
+		return false
+	}
+
+	var fields []*html.Node
+	for _, input := range dom.GetElementsByTagName(form, "input") {
+		switch strings.ToLower(dom.GetAttribute(input, "type")) {
+		case "hidden", "submit", "button", "image", "checkbox":
+		default:
+			fields = append(fields, input)
+		}
+	}
+
+	return len(fields) <= maxSignupFields && ps.someNode(fields, isEmailInput)
+}
+
+// isPromoBlock checks if node is a short block with promotional text.
+func (ps *Parser) isPromoBlock(node *html.Node) bool {
+	text := ps.getInnerText(node, true)
+	if charCount(text) > promoMaxLength || !rxPromoText.MatchString(text) {
+		return false
+	}
+
+	matchString := dom.ClassName(node) + " " + dom.ID(node)
+	return rxPromoClass.MatchString(matchString) ||
+		len(ps.getAllNodesWithTag(node, "a", "button", "input")) > 0
+}
+
+// isEmailInput checks if the input asks for email address.
+func isEmailInput(input *html.Node) bool {
+	if strings.EqualFold(dom.GetAttribute(input, "type"), "email") {
+		return true
+	}
+
+	for _, attrName := range []string{"name", "id", "placeholder", "aria-label"} {
+		if rxEmailField.MatchString(dom.GetAttribute(input, attrName)) {
+			return true
+		}
+	}
+	return false
+}
+
+// isPageContainer checks if node is the article content or one of its
+// pages, which must not be removed.
+func isPageContainer(node, articleContent *html.Node) bool {
+	return node == articleContent || node.Parent == articleContent
+}
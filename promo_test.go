@@ -0,0 +1,56 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_Parser_removePromoBoxes(t *testing.T) {
+	paragraph := articleParagraph(10)
+	newsletter := "<p>" + strings.Repeat("The newsletter of the city council is sent to subscribe to the news. ", 8) + "</p>"
+	input := `<html><body><article>` + paragraph +
+		`<div class="box"><h3>Stay in the loop</h3><p>Get our best stories delivered to your inbox.</p>` +
+		`<form><input type="email" placeholder="Your email"><input type="submit" value="Go"></form></div>` +
+		`<p>Sign up for our daily newsletter <a href="/newsletter">here</a>.</p>` +
+		`<div class="cta-card"><p>Become a member today and support our journalism.</p><a href="/join">Join</a></div>` +
+		newsletter + paragraph +
+		`<div class="respond"><h3>Leave a Reply</h3><form><input name="author"><input name="email">` +
+		`<textarea name="comment"></textarea></form></div></article></body></html>`
+
+	tests := []struct {
+		name       string
+		opts       []Option
+		expected   []string
+		unexpected []string
+	}{{
+		name:       "default",
+		expected:   []string{"newsletter of the city council", "Leave a Reply"},
+		unexpected: []string{"Stay in the loop", "best stories", "daily newsletter", "Become a member"},
+	}, {
+		name:     "disabled",
+		opts:     []Option{WithDisablePromoRemoval(true)},
+		expected: []string{"Stay in the loop", "daily newsletter", "Become a member"},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ps := NewParser(test.opts...)
+			article, err := ps.Parse(strings.NewReader(input), fakeHostURL)
+			if err != nil {
+				t.Fatalf("failed to parse: %v", err)
+			}
+
+			for _, expected := range test.expected {
+				if !strings.Contains(article.TextContent, expected) {
+					t.Errorf("content should contain %q: %s", expected, article.TextContent)
+				}
+			}
+
+			for _, unexpected := range test.unexpected {
+				if strings.Contains(article.TextContent, unexpected) {
+					t.Errorf("content should not contain %q: %s", unexpected, article.TextContent)
+				}
+			}
+		})
+	}
+}
@@ -9,12 +9,10 @@
 package readability
 
 import (
-	"compress/gzip"
-	"fmt"
+	"context"
 	"io"
 	"net/http"
 	nurl "net/url"
-	"strings"
 	"time"
 
 	"golang.org/x/net/html"
@@ -34,55 +32,46 @@ func FromDocument(doc *html.Node, pageURL *nurl.URL) (Article, error) {
 	return parser.ParseDocument(doc, pageURL)
 }
 
+// FromString parses the HTML in string and returns the readable content. It's the
+// wrapper of `Parser.ParseString()`, so pageURL is guessed from the page when it's nil.
+func FromString(content string, pageURL *nurl.URL) (Article, error) {
+	parser := NewParser()
+	return parser.ParseString(content, pageURL)
+}
+
+// FromBytes is like FromString, but the HTML is in bytes which parsed without copying.
+// It's the wrapper of `Parser.ParseBytes()`.
+func FromBytes(content []byte, pageURL *nurl.URL) (Article, error) {
+	parser := NewParser()
+	return parser.ParseBytes(content, pageURL)
+}
+
+// FromFile parses the web page that saved in path and returns the readable content.
+// It's the wrapper of `Parser.ParseFile()`, so pageURL is guessed from the page, or
+// the file URL of path is used when it's nil.
+func FromFile(path string, pageURL *nurl.URL) (Article, error) {
+	parser := NewParser()
+	return parser.ParseFile(path, pageURL)
+}
+
+// FromMHTML parses the MHTML archive and returns the readable content. It's the
+// wrapper of `Parser.ParseMHTML()` and useful if you only want to use the default parser.
+func FromMHTML(input io.Reader, pageURL *nurl.URL) (Article, error) {
+	parser := NewParser()
+	return parser.ParseMHTML(input, pageURL)
+}
+
 // FromURL fetch the web page from specified url then parses the response to find
 // the readable content.
 func FromURL(pageURL string, timeout time.Duration) (Article, error) {
-	// Make sure URL is valid
-	parsedURL, err := nurl.ParseRequestURI(pageURL)
-	if err != nil {
-		return Article{}, fmt.Errorf("failed to parse URL: %v", err)
-	}
-
-	// Fetch page from URL
-	client := &http.Client{Timeout: timeout}
-	req, err := http.NewRequest("GET", pageURL, nil)
-	if err != nil {
-		return Article{}, fmt.Errorf("failed to create request: %v", err)
-	}
-
-	// Set Accept-Encoding header to indicate support for gzip
-	req.Header.Set("Accept-Encoding", "gzip")
-
-	resp, err := client.Do(req)
-	if err != nil {
-		return Article{}, fmt.Errorf("failed to fetch the page: %v", err)
-	}
-	defer resp.Body.Close()
-
-	// Check if the content is encoded with gzip
-	var reader io.Reader
-	switch resp.Header.Get("Content-Encoding") {
-	case "gzip":
-		// If encoded with gzip, use a gzip reader
-		reader, err = gzip.NewReader(resp.Body)
-		if err != nil {
-			return Article{}, fmt.Errorf("failed to create gzip reader: %v", err)
-		}
-		defer reader.(*gzip.Reader).Close()
-	default:
-		// If not encoded, use the response body as is
-		reader = resp.Body
-	}
-
-	// Make sure content type is HTML
-	cp := resp.Header.Get("Content-Type")
-	if !strings.Contains(cp, "text/html") {
-		return Article{}, fmt.Errorf("URL is not a HTML document")
-	}
-
-	// Parse content
-	parser := NewParser()
-	return parser.Parse(reader, parsedURL)
+	return FromURLWithContext(context.Background(), pageURL, timeout)
+}
+
+// FromURLWithContext is like FromURL, but both the fetching and the parsing
+// will be aborted as soon as ctx is done.
+func FromURLWithContext(ctx context.Context, pageURL string, timeout time.Duration) (Article, error) {
+	fetcher := Fetcher{Client: &http.Client{Timeout: timeout}}
+	return fetcher.Fetch(ctx, pageURL)
 }
 
 // Check checks whether the input is readable without parsing the whole thing. It's the
@@ -99,3 +88,31 @@ func CheckDocument(doc *html.Node) bool {
 	parser := NewParser()
 	return parser.CheckDocument(doc)
 }
+
+// CheckWithOptions is like Check, but uses the specified options. It's the
+// wrapper for `Parser.CheckWithOptions()`.
+func CheckWithOptions(input io.Reader, opts CheckOptions) bool {
+	parser := NewParser()
+	return parser.CheckWithOptions(input, opts)
+}
+
+// CheckStream checks whether the input is readable by scanning its tokens,
+// without building the DOM. It's the wrapper for `Parser.CheckStream()`.
+func CheckStream(input io.Reader) bool {
+	parser := NewParser()
+	return parser.CheckStream(input)
+}
+
+// CheckStreamWithOptions is like CheckStream, but uses the specified options.
+// It's the wrapper for `Parser.CheckStreamWithOptions()`.
+func CheckStreamWithOptions(input io.Reader, opts CheckOptions) bool {
+	parser := NewParser()
+	return parser.CheckStreamWithOptions(input, opts)
+}
+
+// CheckScore returns the readability score of the input. It's the wrapper
+// for `Parser.CheckScore()`.
+func CheckScore(input io.Reader) (float64, error) {
+	parser := NewParser()
+	return parser.CheckScore(input)
+}
@@ -0,0 +1,23 @@
+package readability
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// recoverPanic recovers from the panic while parsing, and replaces the
+// error in err with the one that wraps ErrPanic, so the malformed input
+// or the bug in hook doesn't crash the whole process. The err may be nil
+// for the method that reports failure without error, e.g. Check. It must
+// be deferred directly by the exported method.
+func (ps *Parser) recoverPanic(err *error) {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	ps.logWarn("recovered from panic", "panic", r, "stack", string(debug.Stack()))
+	if err != nil {
+		*err = fmt.Errorf("%w: %v", ErrPanic, r)
+	}
+}
@@ -0,0 +1,57 @@
+package readability
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/go-shiori/dom"
+	"golang.org/x/net/html"
+)
+
+func Test_Parser_recoverPanic(t *testing.T) {
+	paragraph := articleParagraph(10)
+	input := "<html><body>" + paragraph + "</body></html>"
+
+	logger := &testLogger{}
+	ps := NewParser(WithLogger(logger), WithPreprocessors(func(*html.Node) {
+		panic("broken preprocessor")
+	}))
+
+	_, err := ps.Parse(strings.NewReader(input), fakeHostURL)
+	if !errors.Is(err, ErrPanic) || !strings.Contains(err.Error(), "broken preprocessor") {
+		t.Errorf("want %v got %v", ErrPanic, err)
+	}
+
+	if len(logger.records) == 0 || !strings.Contains(logger.records[len(logger.records)-1], "stack") {
+		t.Errorf("panic is not logged with its stack: %v", logger.records)
+	}
+
+	// Check reports the panic as not readable
+	panicking := CheckOptions{VisibilityChecker: func(*html.Node) bool {
+		panic("broken visibility checker")
+	}}
+
+	if ps.CheckWithOptions(strings.NewReader(input), panicking) {
+		t.Error("check, want not readable got readable")
+	}
+
+	if ps.CheckStreamWithOptions(strings.NewReader(input), panicking) {
+		t.Error("stream check, want not readable got readable")
+	}
+
+	// The pooled parser is still usable after it panics
+	pool := NewParserPool(WithPreprocessors(func(doc *html.Node) {
+		if dom.QuerySelector(doc, "p.panic") != nil {
+			panic("broken preprocessor")
+		}
+	}))
+
+	if _, err := pool.Parse(strings.NewReader(`<p class="panic">text</p>`), fakeHostURL); !errors.Is(err, ErrPanic) {
+		t.Errorf("pool, want %v got %v", ErrPanic, err)
+	}
+
+	if _, err := pool.Parse(strings.NewReader(input), fakeHostURL); err != nil {
+		t.Errorf("pool after panic, want no error got %v", err)
+	}
+}
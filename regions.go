@@ -0,0 +1,120 @@
+package readability
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-shiori/dom"
+	"golang.org/x/net/html"
+)
+
+// RegionLabel is the label of a region in the page.
+type RegionLabel string
+
+// Labels of the regions that reported in Article.RemovedRegions.
+const (
+	RegionComments   RegionLabel = "comments"
+	RegionNavigation RegionLabel = "nav"
+	RegionFooter     RegionLabel = "footer"
+	RegionRelated    RegionLabel = "related"
+	RegionAds        RegionLabel = "ads"
+	RegionSidebar    RegionLabel = "sidebar"
+)
+
+// RemovedRegion is a region of the page that not included in the article
+// content, e.g. the comments or the related articles.
+type RemovedRegion struct {
+	Label RegionLabel `json:"label"`
+	HTML  string      `json:"html"`
+}
+
+// regionAttr is the attribute that used to mark the region of an element,
+// so it can be found in the article content after it's extracted.
+const regionAttr = "data-readability-region"
+
+var (
+	rxCommentsRegion   = regexp.MustCompile(`(?i)comment|disqus|discussion|respond|replies`)
+	rxNavigationRegion = regexp.MustCompile(`(?i)(^|[\s_-])(nav|navbar|navigation|menu|breadcrumbs?)([\s_-]|$)`)
+	rxFooterRegion     = regexp.MustCompile(`(?i)footer|colophon`)
+	rxRelatedRegion    = regexp.MustCompile(`(?i)related|recommend|more-stories|read-more|you-may-like|outbrain|taboola`)
+	rxAdsRegion        = regexp.MustCompile(`(?i)(^|[\s_-])(ad|ads|advert|advertisement|adsense|dfp|sponsored?)([\s_-]|$)|google_ads`)
+	rxSidebarRegion    = regexp.MustCompile(`(?i)sidebar|widget-area`)
+)
+
+// markRegions finds the regions of the page, i.e. the outermost elements
+// that look like comments, navigation, footer, related articles, ads or
+// sidebar. Each region and its descendants are marked with its index, so
+// it's known later whether any part of it is kept in the article content.
+// The HTML of region is taken before it's scored and cleaned.
+func (ps *Parser) markRegions(doc *html.Node) []RemovedRegion {
+	body := dom.QuerySelector(doc, "body")
+	if body == nil {
+		return nil
+	}
+
+	var regions []RemovedRegion
+	node := dom.FirstElementChild(body)
+	for node != nil {
+		label := regionLabel(node)
+		if label == "" {
+			node = ps.getNextNode(node, false)
+			continue
+		}
+
+		regions = append(regions, RemovedRegion{Label: label, HTML: dom.OuterHTML(node)})
+		index := strconv.Itoa(len(regions) - 1)
+		dom.SetAttribute(node, regionAttr, index)
+		for _, child := range dom.QuerySelectorAll(node, "*") {
+			dom.SetAttribute(child, regionAttr, index)
+		}
+
+		node = ps.getNextNode(node, true)
+	}
+
+	return regions
+}
+
+// getRemovedRegions returns the regions that none of their parts is kept in
+// the article content.
+func (ps *Parser) getRemovedRegions(regions []RemovedRegion, articleContent *html.Node) []RemovedRegion {
+	kept := make(map[string]struct{})
+	if articleContent != nil {
+		for _, node := range dom.QuerySelectorAll(articleContent, "["+regionAttr+"]") {
+			kept[dom.GetAttribute(node, regionAttr)] = struct{}{}
+		}
+	}
+
+	var removed []RemovedRegion
+	for i, region := range regions {
+		if _, exist := kept[strconv.Itoa(i)]; !exist {
+			removed = append(removed, region)
+		}
+	}
+	return removed
+}
+
+// regionLabel returns the label of region that node looks like, or empty
+// string if it's not a region.
+func regionLabel(node *html.Node) RegionLabel {
+	tagName := dom.TagName(node)
+	role := strings.ToLower(dom.GetAttribute(node, "role"))
+	matchString := dom.ClassName(node) + " " + dom.ID(node)
+
+	switch {
+	case rxCommentsRegion.MatchString(matchString):
+		return RegionComments
+	case tagName == "nav" || role == "navigation" || rxNavigationRegion.MatchString(matchString):
+		return RegionNavigation
+	case tagName == "footer" || role == "contentinfo" || rxFooterRegion.MatchString(matchString):
+		return RegionFooter
+	case rxRelatedRegion.MatchString(matchString):
+		return RegionRelated
+	case rxAdsRegion.MatchString(matchString):
+		return RegionAds
+	case tagName == "aside" || role == "complementary" || rxSidebarRegion.MatchString(matchString):
+		return RegionSidebar
+	default:
+		return ""
+	}
+}
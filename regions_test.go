@@ -0,0 +1,65 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_Parser_RemovedRegions(t *testing.T) {
+	paragraph := articleParagraph(10)
+	input := `<html><body>` +
+		`<nav><a href="/">Home</a> <a href="/news">News</a></nav>` +
+		`<div class="ad-slot">Advertisement</div>` +
+		`<main><article>` + paragraph + `<nav class="toc">` + paragraph + `</nav>` + paragraph + `</article>` +
+		`<section class="related-posts"><a href="/a">Another post</a></section>` +
+		`<div id="comments"><div class="comment"><p>Nice post!</p></div></div></main>` +
+		`<aside><p>About this blog.</p></aside>` +
+		`<footer><p>Copyright 2024</p></footer></body></html>`
+
+	ps := NewParser(WithRemovedRegions(true))
+	article, err := ps.Parse(strings.NewReader(input), fakeHostURL)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	expected := []struct {
+		label RegionLabel
+		text  string
+	}{
+		{RegionNavigation, "Home"},
+		{RegionAds, "Advertisement"},
+		{RegionRelated, "Another post"},
+		{RegionComments, "Nice post!"},
+		{RegionSidebar, "About this blog."},
+		{RegionFooter, "Copyright 2024"},
+	}
+
+	if len(article.RemovedRegions) != len(expected) {
+		t.Fatalf("want %d removed regions, got %d: %+v", len(expected), len(article.RemovedRegions), article.RemovedRegions)
+	}
+
+	for i, region := range article.RemovedRegions {
+		if region.Label != expected[i].label || !strings.Contains(region.HTML, expected[i].text) {
+			t.Errorf("region #%d: want %q with %q, got %q with %s", i, expected[i].label, expected[i].text,
+				region.Label, region.HTML)
+		}
+	}
+
+	// The region that kept in the content is not reported
+	if !strings.Contains(article.Content, "<nav>") {
+		t.Errorf("navigation inside the article should be kept: %s", article.Content)
+	}
+	if strings.Contains(article.Content, regionAttr) {
+		t.Errorf("region attribute should be removed: %s", article.Content)
+	}
+
+	// Nothing is reported when it's disabled
+	article, err = FromReader(strings.NewReader(input), fakeHostURL)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	if len(article.RemovedRegions) != 0 {
+		t.Errorf("removed regions should be empty when disabled: %+v", article.RemovedRegions)
+	}
+}
@@ -0,0 +1,146 @@
+package readability
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// Default values for RetryPolicy.
+const (
+	DefaultMinBackoff = 500 * time.Millisecond
+	DefaultMaxBackoff = 30 * time.Second
+)
+
+// RetryPolicy configures how Fetcher retries the failed requests. The
+// request is retried when the server responds with 429 or 5xx status,
+// or when there is temporary network error. The zero value disables
+// the retry.
+type RetryPolicy struct {
+	// MaxAttempts is the max number of attempts, including the first
+	// request. Default: 0 (no retry).
+	MaxAttempts int
+	// MinBackoff is the delay before the first retry, which will be
+	// doubled on each retry. Default: DefaultMinBackoff.
+	MinBackoff time.Duration
+	// MaxBackoff is the max delay between retries. It also limits the
+	// delay that requested by server using Retry-After header.
+	// Default: DefaultMaxBackoff.
+	MaxBackoff time.Duration
+}
+
+// do sends the request using client, and retries it as configured in
+// the policy. If all attempts are failed, the last response or error
+// will be returned, so the status of response must be checked by caller.
+func (rp RetryPolicy) do(ctx context.Context, client *http.Client, req *http.Request) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		resp, err := client.Do(req.Clone(ctx))
+		if attempt >= rp.MaxAttempts || ctx.Err() != nil {
+			return resp, err
+		}
+
+		var delay time.Duration
+		switch {
+		case err != nil:
+			if !isTemporaryError(err) {
+				return nil, err
+			}
+			delay = rp.backoff(attempt)
+
+		case isRetryableStatus(resp.StatusCode):
+			delay = rp.backoff(attempt)
+			if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+				delay = retryAfter
+			}
+
+			io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+			resp.Body.Close()
+
+		default:
+			return resp, nil
+		}
+
+		if maxBackoff := rp.maxBackoff(); delay > maxBackoff {
+			delay = maxBackoff
+		}
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// backoff returns the delay before the specified attempt is retried.
+func (rp RetryPolicy) backoff(attempt int) time.Duration {
+	delay := rp.MinBackoff
+	if delay <= 0 {
+		delay = DefaultMinBackoff
+	}
+
+	maxBackoff := rp.maxBackoff()
+	for i := 1; i < attempt && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	return delay
+}
+
+func (rp RetryPolicy) maxBackoff() time.Duration {
+	if rp.MaxBackoff <= 0 {
+		return DefaultMaxBackoff
+	}
+	return rp.MaxBackoff
+}
+
+// isRetryableStatus determines if the request that responded with the
+// status code can be retried.
+func isRetryableStatus(status int) bool {
+	return status == http.StatusTooManyRequests ||
+		(status >= 500 && status != http.StatusNotImplemented)
+}
+
+// isTemporaryError determines if err is a network error that might be
+// fixed by retrying the request.
+func isTemporaryError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	return errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, io.EOF)
+}
+
+// parseRetryAfter parses the value of Retry-After header, which can be
+// either delay in seconds or a HTTP date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		delay := time.Until(date)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,39 @@
+package readability
+
+import (
+	"github.com/go-shiori/dom"
+	"golang.org/x/net/html"
+)
+
+// URLRewriter rewrites the URL in the attribute of node in the article
+// content, e.g. href of <a> or src of <img>. The URL is absolute unless
+// Parser.DisableAbsoluteURLs is enabled. If it returns empty string, the
+// URL will be removed.
+type URLRewriter func(node *html.Node, attrName string, url string) string
+
+// rewriteContentURL resolves the URL in article content against the base
+// URL, then passes it to the URL rewriter.
+func (ps *Parser) rewriteContentURL(node *html.Node, attrName, url string) string {
+	if !ps.DisableAbsoluteURLs {
+		baseURL := ps.BaseURL
+		if baseURL == nil {
+			baseURL = ps.documentURI
+		}
+		url = toAbsoluteURI(url, baseURL)
+	}
+
+	if ps.URLRewriter != nil && url != "" {
+		url = ps.URLRewriter(node, attrName, url)
+	}
+	return url
+}
+
+// setOrRemoveAttribute sets the attribute of node, or removes it if the
+// value is empty.
+func setOrRemoveAttribute(node *html.Node, attrName, value string) {
+	if value == "" {
+		dom.RemoveAttribute(node, attrName)
+	} else {
+		dom.SetAttribute(node, attrName, value)
+	}
+}
@@ -0,0 +1,56 @@
+package readability
+
+import (
+	nurl "net/url"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+)
+
+func Test_Parser_URLRewriting(t *testing.T) {
+	paragraph := articleParagraph(10)
+	input := `<html><body><article>` + paragraph +
+		`<p><a href="/post">Link</a> <img src="image.png" srcset="small.png 1x, tracker.png 2x"></p>` +
+		paragraph + `</article></body></html>`
+
+	mirrorURL, _ := nurl.Parse("https://mirror.example.com/archive/")
+	proxy := func(node *html.Node, attrName string, url string) string {
+		if strings.Contains(url, "tracker") {
+			return ""
+		}
+		if attrName == "href" {
+			return url
+		}
+		return "https://proxy.example.com/?url=" + nurl.QueryEscape(url)
+	}
+
+	scenarios := []struct {
+		opts     []Option
+		expected []string
+	}{
+		{nil, []string{`href="http://fakehost/post"`, `src="http://fakehost/test/image.png"`,
+			`srcset="http://fakehost/test/small.png 1x, http://fakehost/test/tracker.png 2x"`}},
+		{[]Option{WithAbsoluteURLs(false)}, []string{`href="/post"`, `src="image.png"`,
+			`srcset="small.png 1x, tracker.png 2x"`}},
+		{[]Option{WithBaseURL(mirrorURL)}, []string{`href="https://mirror.example.com/post"`,
+			`src="https://mirror.example.com/archive/image.png"`}},
+		{[]Option{WithURLRewriter(proxy)}, []string{`href="http://fakehost/post"`,
+			`src="https://proxy.example.com/?url=http%3A%2F%2Ffakehost%2Ftest%2Fimage.png"`,
+			`srcset="https://proxy.example.com/?url=http%3A%2F%2Ffakehost%2Ftest%2Fsmall.png 1x"`}},
+	}
+
+	for i, scenario := range scenarios {
+		ps := NewParser(scenario.opts...)
+		article, err := ps.Parse(strings.NewReader(input), fakeHostURL)
+		if err != nil {
+			t.Fatalf("failed to parse: %v", err)
+		}
+
+		for _, expected := range scenario.expected {
+			if !strings.Contains(article.Content, expected) {
+				t.Errorf("scenario %d, want %s in content:\n%s", i, expected, article.Content)
+			}
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package readability
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/andybalholm/cascadia"
+	"gopkg.in/yaml.v2"
+)
+
+// ruleFile is the declarative form of Rule that stored in JSON or YAML
+// file. Each field accepts either a single selector or a list of them.
+type ruleFile struct {
+	// Hosts is the host patterns where the rule is used. If empty, the
+	// name of the file without its extension is used as the pattern.
+	Hosts          ruleSelectors `json:"hosts" yaml:"hosts"`
+	Content        ruleSelectors `json:"content" yaml:"content"`
+	Strip          ruleSelectors `json:"strip" yaml:"strip"`
+	Title          ruleSelectors `json:"title" yaml:"title"`
+	Byline         ruleSelectors `json:"byline" yaml:"byline"`
+	PublishedTime  ruleSelectors `json:"published_time" yaml:"published_time"`
+	Excerpt        ruleSelectors `json:"excerpt" yaml:"excerpt"`
+	SiteName       ruleSelectors `json:"site_name" yaml:"site_name"`
+	NextPage       ruleSelectors `json:"next_page" yaml:"next_page"`
+	SinglePageLink ruleSelectors `json:"single_page_link" yaml:"single_page_link"`
+}
+
+// ruleSelectors is a list of strings, which may be written as a single
+// string in the rule file.
+type ruleSelectors []string
+
+// UnmarshalJSON decodes either a string or a list of strings.
+func (rs *ruleSelectors) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*rs = ruleSelectors{single}
+		return nil
+	}
+
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*rs = list
+	return nil
+}
+
+// UnmarshalYAML decodes either a string or a list of strings.
+func (rs *ruleSelectors) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var single string
+	if err := unmarshal(&single); err == nil {
+		*rs = ruleSelectors{single}
+		return nil
+	}
+
+	var list []string
+	if err := unmarshal(&list); err != nil {
+		return err
+	}
+	*rs = list
+	return nil
+}
+
+// LoadRules returns new Rules that loaded from the rule files in dir. See
+// Rules.LoadDir for the format of the files.
+func LoadRules(dir string) (*Rules, error) {
+	rules := NewRules()
+	if err := rules.LoadDir(dir); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// LoadDir loads the rule files in dir into the registry, so the rules
+// can be updated without recompiling. See Rules.LoadFS for the format of
+// the files.
+func (r *Rules) LoadDir(dir string) error {
+	return r.LoadFS(os.DirFS(dir))
+}
+
+// LoadFS loads the rule files in the root of fsys into the registry. Each
+// file is a JSON (.json) or YAML (.yaml, .yml) object of a site rule, with
+// fields "content", "strip", "title", "byline", "published_time",
+// "excerpt", "site_name", "next_page" and "single_page_link" that
+// matches the fields of Rule. The rule
+// is registered for the patterns in "hosts", or for the file name
+// without its extension, e.g. "example.com.yaml". Other files are
+// ignored. Loading the files again replaces the rule of the same
+// pattern, so the rules can be reloaded while the parsers are running.
+func (r *Rules) LoadFS(fsys fs.FS) error {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return fmt.Errorf("failed to read rule directory: %v", err)
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") || !isRuleFile(name) {
+			continue
+		}
+
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return fmt.Errorf("failed to read rule file %s: %v", name, err)
+		}
+
+		if err = r.loadRuleFile(name, data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// LoadFile loads a single rule file into the registry. See Rules.LoadFS
+// for the format of the file.
+func (r *Rules) LoadFile(filePath string) error {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to read rule file %s: %v", filePath, err)
+	}
+	return r.loadRuleFile(path.Base(strings.ReplaceAll(filePath, `\`, "/")), data)
+}
+
+// loadRuleFile decodes the content of rule file, then registers its rule.
+func (r *Rules) loadRuleFile(name string, data []byte) error {
+	var file ruleFile
+	var err error
+	switch path.Ext(name) {
+	case ".json":
+		err = json.Unmarshal(data, &file)
+	case ".yaml", ".yml":
+		err = yaml.UnmarshalStrict(data, &file)
+	default:
+		err = fmt.Errorf("unknown file type")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to decode rule file %s: %v", name, err)
+	}
+
+	rule := Rule{
+		Content:        file.Content,
+		Strip:          file.Strip,
+		Title:          file.Title,
+		Byline:         file.Byline,
+		PublishedTime:  file.PublishedTime,
+		Excerpt:        file.Excerpt,
+		SiteName:       file.SiteName,
+		NextPage:       file.NextPage,
+		SinglePageLink: file.SinglePageLink,
+	}
+
+	// Invalid selectors are reported, so the broken file is noticed when
+	// it's loaded instead of being silently ignored while parsing
+	for _, selectors := range [][]string{rule.Content, rule.Strip, rule.Title,
+		rule.Byline, rule.PublishedTime, rule.Excerpt, rule.SiteName, rule.NextPage, rule.SinglePageLink} {
+		for _, selector := range selectors {
+			if _, err := cascadia.ParseGroup(selector); err != nil {
+				return fmt.Errorf("failed to parse selector %q in rule file %s: %v", selector, name, err)
+			}
+		}
+	}
+
+	hosts := file.Hosts
+	if len(hosts) == 0 {
+		hosts = []string{strings.TrimSuffix(name, path.Ext(name))}
+	}
+
+	for _, host := range hosts {
+		r.Register(host, rule)
+	}
+	return nil
+}
+
+// isRuleFile checks if the file name has extension of rule file.
+func isRuleFile(name string) bool {
+	switch path.Ext(name) {
+	case ".json", ".yaml", ".yml":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,83 @@
+package readability
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func Test_Rules_LoadFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"example.com.json": {Data: []byte(`{"content": ".story", "strip": [".related", ".ad"]}`)},
+		"substack.yaml":    {Data: []byte("hosts: ['*.substack.com', substack.com]\ncontent: .post\ntitle:\n  - h1.post-title\n")},
+		"README.md":        {Data: []byte("not a rule")},
+		"nested/a.json":    {Data: []byte(`{"content": ".nested"}`)},
+	}
+
+	rules := NewRules()
+	if err := rules.LoadFS(fsys); err != nil {
+		t.Fatalf("failed to load rules: %v", err)
+	}
+
+	rule, found := rules.Match("www.example.com")
+	if !found || !reflect.DeepEqual(rule.Content, []string{".story"}) || !reflect.DeepEqual(rule.Strip, []string{".related", ".ad"}) {
+		t.Errorf("unexpected rule of example.com: %+v", rule)
+	}
+
+	for _, hostname := range []string{"news.substack.com", "substack.com"} {
+		rule, found = rules.Match(hostname)
+		if !found || !reflect.DeepEqual(rule.Content, []string{".post"}) || !reflect.DeepEqual(rule.Title, []string{"h1.post-title"}) {
+			t.Errorf("unexpected rule of %s: %+v", hostname, rule)
+		}
+	}
+
+	if _, found = rules.Match("nested"); found {
+		t.Error("rule in nested directory is loaded")
+	}
+}
+
+func Test_Rules_LoadFSError(t *testing.T) {
+	scenarios := map[string]string{
+		"invalid.json":  `{"content": 1}`,
+		"invalid.yaml":  "content: [.a\n",
+		"unknown.yaml":  "contents: .a\n",
+		"selector.json": `{"content": "div["}`,
+	}
+
+	for name, data := range scenarios {
+		err := NewRules().LoadFS(fstest.MapFS{name: {Data: []byte(data)}})
+		if err == nil || !strings.Contains(err.Error(), name) {
+			t.Errorf("%s: want error that mentions the file, got %v", name, err)
+		}
+	}
+}
+
+func Test_LoadRules(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "fakehost.yml"), []byte("byline: .author\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	rules, err := LoadRules(dir)
+	if err != nil {
+		t.Fatalf("failed to load rules: %v", err)
+	}
+
+	input := `<html><body><article>` + strings.Repeat("<p>"+strings.Repeat("This is a sentence of the article. ", 15)+"</p>", 2) +
+		`<p class="author">Jane Doe</p></article></body></html>`
+	ps := NewParser(WithRules(rules))
+	article, err := ps.Parse(strings.NewReader(input), fakeHostURL)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	if article.Byline != "Jane Doe" {
+		t.Errorf("want byline %q, got %q", "Jane Doe", article.Byline)
+	}
+
+	if _, err = LoadRules(filepath.Join(dir, "missing")); err == nil {
+		t.Error("want error for missing directory")
+	}
+}
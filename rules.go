@@ -0,0 +1,285 @@
+package readability
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/go-shiori/dom"
+	"golang.org/x/net/html"
+)
+
+// Rule is the extraction rule of a site, which helps the parser on heavily
+// templated sites where the generic scoring is not enough. Each field is
+// a list of CSS selectors, and the first one that matches is used. Invalid
+// selectors are ignored.
+type Rule struct {
+	// Content is the selectors of the article content. When one of them
+	// matches, its elements are used as the article content instead of
+	// the one found by scoring. If none match, the article is grabbed as
+	// usual.
+	Content []string
+	// Strip is the selectors of elements that are removed from the page
+	// before the article is grabbed, e.g. the related posts inside the
+	// article body. Unlike other fields, all of them are used.
+	Strip []string
+	// Title, Byline, PublishedTime, Excerpt and SiteName are the selectors
+	// of elements that override the metadata. The value is taken from the
+	// content attribute of meta, datetime attribute of time, or the text
+	// of other elements.
+	Title         []string
+	Byline        []string
+	PublishedTime []string
+	Excerpt       []string
+	SiteName      []string
+	// NextPage is the selectors of the link to the next page, which is
+	// used instead of the detected one. SinglePageLink is the selectors of
+	// the link to the version of article that has all pages in one, e.g.
+	// the print view. The URL is taken from href attribute of the link.
+	NextPage       []string
+	SinglePageLink []string
+}
+
+// Rules is the registry of site rules, keyed by hostname pattern. Rules
+// is safe for concurrent use, so the rules can be registered while the
+// parsers are running.
+type Rules struct {
+	mu      sync.RWMutex
+	entries []ruleEntry
+}
+
+// ruleEntry is a rule along with hostname pattern where it's used.
+type ruleEntry struct {
+	pattern string
+	rule    Rule
+}
+
+// NewRules returns an empty Rules.
+func NewRules() *Rules {
+	return &Rules{}
+}
+
+// Register registers the rule for hosts that match pattern. Pattern is
+// either a hostname like "example.com", which also matches its subdomains,
+// or a glob like "*.substack.com" which matched using path.Match. Rule
+// that registered with the same pattern replaces the old one.
+func (r *Rules) Register(pattern string, rule Rule) {
+	pattern = strings.ToLower(strings.TrimSpace(pattern))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, entry := range r.entries {
+		if entry.pattern == pattern {
+			r.entries[i].rule = rule
+			return
+		}
+	}
+	r.entries = append(r.entries, ruleEntry{pattern: pattern, rule: rule})
+}
+
+// Match returns the rule for the hostname. When several patterns match,
+// the exact hostname wins over its parent domains, which wins over glob,
+// and the longer pattern wins over the shorter one.
+func (r *Rules) Match(hostname string) (Rule, bool) {
+	hostname = strings.ToLower(strings.TrimSuffix(hostname, "."))
+	if r == nil || hostname == "" {
+		return Rule{}, false
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var best *ruleEntry
+	bestRank := 0
+	for i, entry := range r.entries {
+		rank := matchHostPattern(entry.pattern, hostname)
+		if rank > bestRank {
+			best, bestRank = &r.entries[i], rank
+		}
+	}
+
+	if best == nil {
+		return Rule{}, false
+	}
+	return best.rule, true
+}
+
+// matchHostPattern returns how specific the pattern matches the hostname,
+// or 0 if it doesn't match.
+func matchHostPattern(pattern, hostname string) int {
+	// Offset the ranks by the max length of hostname, so matches of
+	// different kinds are never ranked the same
+	const maxHostLength = 255
+
+	switch {
+	case pattern == hostname:
+		return 3 * maxHostLength
+	case !strings.ContainsAny(pattern, "*?[") && strings.HasSuffix(hostname, "."+pattern):
+		return 2*maxHostLength + len(pattern)
+	}
+
+	if matched, _ := path.Match(pattern, hostname); matched {
+		return maxHostLength + len(pattern)
+	}
+	return 0
+}
+
+// siteRule returns the rule of the page, if any.
+func (ps *Parser) siteRule() (Rule, bool) {
+	if ps.Rules == nil || ps.documentURI == nil {
+		return Rule{}, false
+	}
+	return ps.Rules.Match(ps.documentURI.Hostname())
+}
+
+// stripRuleElements removes the elements that match the strip selectors
+// of rule from node.
+func (ps *Parser) stripRuleElements(node *html.Node, rule Rule) {
+	for _, selector := range rule.Strip {
+		matches := dom.QuerySelectorAll(node, selector)
+
+		for _, match := range matches {
+			if match.Parent != nil {
+				ps.diagnoseRemoval(match, RemovedSiteRule)
+				match.Parent.RemoveChild(match)
+			}
+		}
+	}
+}
+
+// grabRuleContent returns the article content that selected by the content
+// selectors of rule, wrapped the same way as the one from grabArticle.
+// Returns nil if none of the selectors match.
+func (ps *Parser) grabRuleContent(rule Rule) *html.Node {
+	for _, selector := range rule.Content {
+		matches := dom.QuerySelectorAll(ps.doc, selector)
+		if len(matches) == 0 {
+			continue
+		}
+
+		page := dom.CreateElement("div")
+		dom.SetAttribute(page, "id", "readability-page-1")
+		dom.SetAttribute(page, "class", "page")
+
+		// Elements that nested inside the previous match is already
+		// included along with their ancestor
+		var included []*html.Node
+		for _, match := range matches {
+			if hasAncestorIn(match, included) {
+				continue
+			}
+			included = append(included, match)
+			dom.AppendChild(page, dom.Clone(match, true))
+		}
+
+		ps.logInfo("article grabbed using site rule", "selector", selector)
+		ps.strategy = StrategySiteRule
+		articleContent := dom.CreateElement("div")
+		dom.AppendChild(articleContent, page)
+		ps.setArticleDir(articleContent, getNodeDir(included[0]))
+		return articleContent
+	}
+
+	return nil
+}
+
+// applyRuleMetadata overrides the metadata using the metadata selectors
+// of rule.
+func (ps *Parser) applyRuleMetadata(metadata map[string]string, rule Rule) {
+	overrides := map[string][]string{
+		"title":         rule.Title,
+		"byline":        rule.Byline,
+		"publishedTime": rule.PublishedTime,
+		"excerpt":       rule.Excerpt,
+		"siteName":      rule.SiteName,
+	}
+
+	for key, selectors := range overrides {
+		if value := ps.ruleValue(selectors); value != "" {
+			metadata[key] = value
+		}
+	}
+}
+
+// ruleValue returns the value of the first element that matches one of
+// the selectors.
+func (ps *Parser) ruleValue(selectors []string) string {
+	for _, selector := range selectors {
+		matches := dom.QuerySelectorAll(ps.doc, selector)
+
+		for _, match := range matches {
+			var value string
+			switch dom.TagName(match) {
+			case "meta":
+				value = dom.GetAttribute(match, "content")
+			case "time":
+				value = dom.GetAttribute(match, "datetime")
+			}
+			if value == "" {
+				value = dom.TextContent(match)
+			}
+
+			if value = strings.Join(strings.Fields(value), " "); value != "" {
+				return value
+			}
+		}
+	}
+	return ""
+}
+
+// ruleLink returns the absolute URL of the first link that matches one
+// of the selectors, as long as it points to another page of the site.
+func (ps *Parser) ruleLink(selectors []string) string {
+	for _, selector := range selectors {
+		for _, match := range dom.QuerySelectorAll(ps.doc, selector) {
+			if linkURL := ps.validNextPageURL(dom.GetAttribute(match, "href")); linkURL != "" {
+				return linkURL
+			}
+		}
+	}
+	return ""
+}
+
+// fetchSinglePage fetches the single page version of the article, if its
+// site rule has one. The content of the article is replaced, while its
+// metadata is kept as it is. The single page that can't be fetched is only
+// logged, since the article is still usable.
+func (f *Fetcher) fetchSinglePage(ctx context.Context, article *Article) error {
+	if !f.FollowSinglePage || article.SinglePageURL == "" {
+		return nil
+	}
+
+	parser := f.parser()
+	single, _, err := f.fetchArticle(ctx, article.SinglePageURL)
+	if err != nil {
+		parser.logWarn("failed to fetch single page version", "url", article.SinglePageURL, "error", err)
+		return nil
+	}
+
+	if single.Node == nil {
+		return nil
+	}
+
+	if err = parser.replaceContent(article, single); err != nil {
+		return fmt.Errorf("failed to use single page version: %w", err)
+	}
+
+	// The single page has the whole article, so it's not paginated
+	article.NextPageURL = ""
+	return nil
+}
+
+// hasAncestorIn checks if any of the nodes is ancestor of node.
+func hasAncestorIn(node *html.Node, nodes []*html.Node) bool {
+	for parent := node.Parent; parent != nil; parent = parent.Parent {
+		for _, n := range nodes {
+			if parent == n {
+				return true
+			}
+		}
+	}
+	return false
+}
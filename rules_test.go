@@ -0,0 +1,96 @@
+package readability
+
+import (
+	nurl "net/url"
+	"strings"
+	"testing"
+)
+
+func Test_Rules_Match(t *testing.T) {
+	rules := NewRules()
+	rules.Register("example.com", Rule{Content: []string{"parent"}})
+	rules.Register("blog.example.com", Rule{Content: []string{"exact"}})
+	rules.Register("*.example.com", Rule{Content: []string{"glob"}})
+	rules.Register("*.substack.com", Rule{Content: []string{"substack"}})
+
+	scenarios := map[string]string{
+		"example.com":          "parent",
+		"www.example.com":      "parent",
+		"BLOG.example.com":     "exact",
+		"news.substack.com":    "substack",
+		"a.b.substack.com":     "substack",
+		"substack.com":         "",
+		"notexample.com":       "",
+		"example.com.evil.com": "",
+	}
+
+	for hostname, expected := range scenarios {
+		rule, found := rules.Match(hostname)
+		switch {
+		case expected == "" && found:
+			t.Errorf("%s: want no rule, got %v", hostname, rule.Content)
+		case expected != "" && (!found || rule.Content[0] != expected):
+			t.Errorf("%s: want rule %q, got %v", hostname, expected, rule.Content)
+		}
+	}
+
+	// Registering the same pattern replaces the old rule
+	rules.Register("example.com", Rule{Content: []string{"replaced"}})
+	if rule, _ := rules.Match("example.com"); rule.Content[0] != "replaced" {
+		t.Errorf("rule is not replaced, got %v", rule.Content)
+	}
+}
+
+func Test_Parser_Rules(t *testing.T) {
+	paragraph := articleParagraph(10)
+	input := `<html><head><title>Generic title</title>` +
+		`<meta name="custom-date" content="2021-05-06T07:08:09Z"></head><body>` +
+		`<article>` + paragraph + paragraph + `</article>` +
+		`<div class="story"><h1 class="headline">Real Headline</h1><p>The short real story.</p>` +
+		`<div class="related">Related posts</div><p class="author">Jane Doe</p></div>` +
+		`</body></html>`
+
+	rules := NewRules()
+	rules.Register("fakehost", Rule{
+		Content:       []string{".missing", ".story"},
+		Strip:         []string{".related", ".headline"},
+		Title:         []string{".missing", "h1"},
+		Byline:        []string{".author"},
+		PublishedTime: []string{`meta[name="custom-date"]`},
+	})
+
+	ps := NewParser()
+	WithRules(rules)(&ps)
+	article, err := ps.Parse(strings.NewReader(input), fakeHostURL)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	if !strings.Contains(article.TextContent, "The short real story.") || strings.Contains(article.TextContent, "sentence of the article") {
+		t.Errorf("content is not selected by rule: %q", article.TextContent)
+	}
+	if strings.Contains(article.TextContent, "Related posts") {
+		t.Errorf("stripped element is in content: %q", article.TextContent)
+	}
+
+	// The headline is stripped before the title is selected
+	if article.Title != "Generic title" {
+		t.Errorf("want title %q, got %q", "Generic title", article.Title)
+	}
+	if article.Byline != "Jane Doe" {
+		t.Errorf("want byline %q, got %q", "Jane Doe", article.Byline)
+	}
+	if article.PublishedTime == nil || article.PublishedTime.Year() != 2021 {
+		t.Errorf("want published time in 2021, got %v", article.PublishedTime)
+	}
+
+	// Rules are not used on other sites
+	otherURL, _ := nurl.Parse("http://otherhost/page.html")
+	article, err = ps.Parse(strings.NewReader(input), otherURL)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	if !strings.Contains(article.TextContent, "sentence of the article") {
+		t.Errorf("rule is used on other site: %q", article.TextContent)
+	}
+}
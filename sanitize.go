@@ -0,0 +1,254 @@
+package readability
+
+import (
+	"strings"
+
+	"github.com/go-shiori/dom"
+	"golang.org/x/net/html"
+)
+
+// SanitizePolicy is the allowlist that used by the strict sanitizer. Any
+// element, attribute and URL scheme that's not listed is removed.
+type SanitizePolicy struct {
+	// Elements is the allowed tags, mapped to the attributes that allowed
+	// in each of them.
+	Elements map[string][]string
+	// GlobalAttributes is the attributes that allowed in every element.
+	// Attribute that ends with "*" matches by prefix, e.g. "aria-*". The
+	// event handlers and style are never allowed, since the style is
+	// only kept through Parser.KeepStyles.
+	GlobalAttributes []string
+	// URLSchemes is the schemes that allowed in URL attributes like href
+	// and src. Relative URLs are always allowed.
+	URLSchemes []string
+	// AllowDataImages determines whether "data:image/" URLs are allowed
+	// in the src and srcset of images, which are not able to run script.
+	AllowDataImages bool
+}
+
+// DefaultSanitizePolicy is the policy that used when the strict sanitizer
+// is enabled without specifying any policy. It keeps the text formatting,
+// lists, tables and media, but not the embeds, forms, SVG and MathML.
+var DefaultSanitizePolicy = SanitizePolicy{
+	Elements: map[string][]string{
+		"a":          {"href"},
+		"abbr":       nil,
+		"article":    nil,
+		"audio":      {"src", "controls"},
+		"b":          nil,
+		"bdi":        nil,
+		"bdo":        nil,
+		"blockquote": {"cite"},
+		"br":         nil,
+		"caption":    nil,
+		"cite":       nil,
+		"code":       nil,
+		"col":        {"span"},
+		"colgroup":   {"span"},
+		"dd":         nil,
+		"del":        {"cite", "datetime"},
+		"details":    {"open"},
+		"dfn":        nil,
+		"div":        nil,
+		"dl":         nil,
+		"dt":         nil,
+		"em":         nil,
+		"figcaption": nil,
+		"figure":     nil,
+		"h1":         nil,
+		"h2":         nil,
+		"h3":         nil,
+		"h4":         nil,
+		"h5":         nil,
+		"h6":         nil,
+		"hr":         nil,
+		"i":          nil,
+		"img":        {"src", "srcset", "sizes", "alt", "width", "height"},
+		"ins":        {"cite", "datetime"},
+		"kbd":        nil,
+		"li":         {"value"},
+		"mark":       nil,
+		"ol":         {"start", "reversed", "type"},
+		"p":          nil,
+		"picture":    nil,
+		"pre":        nil,
+		"q":          {"cite"},
+		"rp":         nil,
+		"rt":         nil,
+		"ruby":       nil,
+		"s":          nil,
+		"samp":       nil,
+		"section":    nil,
+		"small":      nil,
+		"source":     {"src", "srcset", "sizes", "type", "media"},
+		"span":       nil,
+		"strong":     nil,
+		"sub":        nil,
+		"summary":    nil,
+		"sup":        nil,
+		"table":      nil,
+		"tbody":      nil,
+		"td":         {"colspan", "rowspan", "headers"},
+		"tfoot":      nil,
+		"th":         {"colspan", "rowspan", "headers", "scope"},
+		"thead":      nil,
+		"time":       {"datetime"},
+		"tr":         nil,
+		"track":      {"src", "kind", "srclang", "label"},
+		"u":          nil,
+		"ul":         nil,
+		"var":        nil,
+		"video":      {"src", "poster", "controls", "width", "height"},
+		"wbr":        nil,
+	},
+	GlobalAttributes: []string{"id", "class", "title", "lang", "dir"},
+	URLSchemes:       []string{"http", "https", "mailto"},
+	AllowDataImages:  true,
+}
+
+// sanitizeDropTags is the tags that removed along with their content when
+// they are not allowed. Other elements are unwrapped, i.e. replaced by
+// their children, so their text is kept.
+var sanitizeDropTags = sliceToMap("script", "style", "template", "noscript", "iframe", "frame",
+	"frameset", "object", "embed", "applet", "param", "form", "input", "button", "select", "option",
+	"textarea", "base", "link", "meta", "title", "head", "svg", "math", "canvas", "dialog")
+
+// sanitizeURLAttributes is the attributes whose value is URL.
+var sanitizeURLAttributes = sliceToMap("href", "src", "poster", "cite", "srcset")
+
+// sanitizer is the strict sanitizer, which allows the attributes and
+// styles that kept by parser on top of its policy.
+type sanitizer struct {
+	policy      *SanitizePolicy
+	globalAttrs []string
+	styles      []string
+}
+
+// sanitizeContent runs the article content through the strict sanitizer.
+// Once it's done, the content only has the elements, attributes and URL
+// schemes that allowed by the policy, so it's safe to be embedded in web
+// page as it is: there are no scripts, event handlers, embeds, or comments
+// left, and the style only has the safe values of Parser.KeepStyles.
+func (ps *Parser) sanitizeContent(articleContent *html.Node) {
+	policy := ps.SanitizePolicy
+	if policy == nil {
+		policy = &DefaultSanitizePolicy
+	}
+
+	s := sanitizer{
+		policy:      policy,
+		globalAttrs: append(append([]string{}, policy.GlobalAttributes...), ps.KeepAttributes...),
+		styles:      ps.KeepStyles,
+	}
+	s.sanitize(articleContent)
+}
+
+// sanitize removes the children of node that not allowed by the policy.
+func (s *sanitizer) sanitize(node *html.Node) {
+	for child := node.FirstChild; child != nil; {
+		next := child.NextSibling
+
+		switch child.Type {
+		case html.TextNode:
+		case html.ElementNode:
+			tag := strings.ToLower(child.Data)
+			allowedAttrs, allowed := s.policy.Elements[tag]
+			_, dropped := sanitizeDropTags[tag]
+
+			switch {
+			case allowed && child.Namespace == "":
+				s.sanitizeAttributes(child, allowedAttrs)
+				s.sanitize(child)
+			case dropped || child.Namespace != "":
+				node.RemoveChild(child)
+			default:
+				s.sanitize(child)
+				for child.FirstChild != nil {
+					grandChild := child.FirstChild
+					child.RemoveChild(grandChild)
+					node.InsertBefore(grandChild, child)
+				}
+				node.RemoveChild(child)
+			}
+		default:
+			// Comment may be conditional comment, which is parsed as
+			// HTML by old browsers
+			node.RemoveChild(child)
+		}
+
+		child = next
+	}
+}
+
+// sanitizeAttributes removes the attributes of node that not allowed, and
+// the URL attributes whose scheme is not allowed.
+func (s *sanitizer) sanitizeAttributes(node *html.Node, allowedAttrs []string) {
+	tag := dom.TagName(node)
+	attrs := node.Attr[:0]
+	for _, attr := range node.Attr {
+		key := strings.ToLower(attr.Key)
+		switch {
+		case attr.Namespace != "" || strings.HasPrefix(key, "on"):
+			continue
+		case key == "style":
+			if attr.Val = filterStyle(attr.Val, s.styles, true); attr.Val == "" {
+				continue
+			}
+		case indexOf(allowedAttrs, key) == -1 && !matchNamePattern(key, s.globalAttrs):
+			continue
+		}
+
+		if _, isURL := sanitizeURLAttributes[key]; isURL && !s.policy.allowedURLAttribute(tag, key, attr.Val) {
+			continue
+		}
+
+		attrs = append(attrs, attr)
+	}
+	node.Attr = attrs
+}
+
+// allowedURLAttribute checks if every URL in the attribute value has the
+// allowed scheme.
+func (policy *SanitizePolicy) allowedURLAttribute(tag, key, value string) bool {
+	if key != "srcset" {
+		return policy.allowedURL(tag, value)
+	}
+
+	for _, parts := range rxSrcsetURL.FindAllStringSubmatch(value, -1) {
+		if !policy.allowedURL(tag, strings.TrimSuffix(parts[1], ",")) {
+			return false
+		}
+	}
+	return true
+}
+
+// allowedURL checks if the scheme of URL is allowed. The whitespaces and
+// control characters are removed first, since browsers ignore them, e.g.
+// "java\tscript:" is still treated as "javascript:".
+func (policy *SanitizePolicy) allowedURL(tag, rawURL string) bool {
+	cleanURL := strings.Map(func(r rune) rune {
+		if r <= ' ' || r == 0x7f {
+			return -1
+		}
+		return r
+	}, rawURL)
+
+	// URL without scheme is relative
+	colon := strings.IndexByte(cleanURL, ':')
+	if colon < 0 || strings.ContainsAny(cleanURL[:colon], "/?#") {
+		return true
+	}
+
+	scheme := strings.ToLower(cleanURL[:colon])
+	if scheme == "data" {
+		return policy.AllowDataImages && (tag == "img" || tag == "source") &&
+			strings.HasPrefix(strings.ToLower(cleanURL), "data:image/")
+	}
+
+	for _, allowedScheme := range policy.URLSchemes {
+		if strings.EqualFold(scheme, allowedScheme) {
+			return true
+		}
+	}
+	return false
+}
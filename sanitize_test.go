@@ -0,0 +1,106 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-shiori/dom"
+	"golang.org/x/net/html"
+)
+
+func Test_Parser_sanitizeContent(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{{
+		name:     "script and style are removed with their content",
+		input:    `<p>Text<script>alert(1)</script><style>p{}</style></p>`,
+		expected: `<p>Text</p>`,
+	}, {
+		name:     "unknown element is unwrapped",
+		input:    `<p><font color="red">Red</font> <blink>text</blink></p>`,
+		expected: `<p>Red text</p>`,
+	}, {
+		name:     "event handler and style attribute are removed",
+		input:    `<p onclick="alert(1)" style="color:red" class="lead">Text</p><img src="a.jpg" onerror="alert(1)">`,
+		expected: `<p class="lead">Text</p><img src="a.jpg"/>`,
+	}, {
+		name: "javascript URL is removed even when obfuscated",
+		input: `<a href="javascript:alert(1)">A</a><a href="JaVaScRiPt:alert(1)">B</a>` +
+			`<a href="java&#x09;script:alert(1)">C</a><a href=" &#x01;javascript:alert(1)">D</a>`,
+		expected: `<a>A</a><a>B</a><a>C</a><a>D</a>`,
+	}, {
+		name:     "other dangerous schemes are removed",
+		input:    `<a href="vbscript:x">A</a><a href="data:text/html,<script>alert(1)</script>">B</a>`,
+		expected: `<a>A</a><a>B</a>`,
+	}, {
+		name:     "safe and relative URLs are kept",
+		input:    `<a href="https://example.com/a">A</a><a href="/b?x=1:2">B</a><a href="mailto:me@example.com">C</a>`,
+		expected: `<a href="https://example.com/a">A</a><a href="/b?x=1:2">B</a><a href="mailto:me@example.com">C</a>`,
+	}, {
+		name:     "data image is only allowed in image",
+		input:    `<img src="data:image/png;base64,AAAA"><a href="data:image/png;base64,AAAA">A</a>`,
+		expected: `<img src="data:image/png;base64,AAAA"/><a>A</a>`,
+	}, {
+		name:     "srcset with unsafe URL is removed",
+		input:    `<img srcset="a.jpg 1x, javascript:alert(1) 2x" alt="A"><img srcset="a.jpg 1x, b.jpg 2x">`,
+		expected: `<img alt="A"/><img srcset="a.jpg 1x, b.jpg 2x"/>`,
+	}, {
+		name:     "embeds, forms, SVG and MathML are removed",
+		input:    `<iframe src="https://example.com"></iframe><form><input name="q"></form><svg onload="alert(1)"><script>alert(1)</script></svg><math><mi>x</mi></math><p>Text</p>`,
+		expected: `<p>Text</p>`,
+	}, {
+		name:     "comments are removed",
+		input:    `<p>Text<!--[if IE]><script>alert(1)</script><![endif]--></p>`,
+		expected: `<p>Text</p>`,
+	}, {
+		name:     "target attribute is removed",
+		input:    `<a href="https://example.com" target="_blank" rel="opener">A</a>`,
+		expected: `<a href="https://example.com">A</a>`,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			doc, err := html.Parse(strings.NewReader(`<html><body><div>` + test.input + `</div></body></html>`))
+			if err != nil {
+				t.Fatalf("failed to parse input: %v", err)
+			}
+
+			container := dom.QuerySelector(doc, "body > div")
+			ps := NewParser()
+			ps.sanitizeContent(container)
+
+			if result := dom.InnerHTML(container); result != test.expected {
+				t.Errorf("want %s, got %s", test.expected, result)
+			}
+		})
+	}
+}
+
+func Test_Parser_Sanitize(t *testing.T) {
+	paragraph := articleParagraph(10)
+	input := `<html><body><article>` + paragraph +
+		`<p><a href="javascript:alert(1)"><b>Click</b> me</a> <span style="color:red">styled</span></p>` +
+		`<p><u onmouseover="alert(1)">underlined</u></p>` + paragraph + `</article></body></html>`
+
+	policy := DefaultSanitizePolicy
+	policy.Elements = map[string][]string{"div": nil, "p": nil, "b": nil}
+	ps := NewParser(WithSanitize(&policy))
+	article, err := ps.Parse(strings.NewReader(input), fakeHostURL)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	for _, unexpected := range []string{"<a", "<span", "<u", "style=", "onmouseover", "javascript:"} {
+		if strings.Contains(article.Content, unexpected) {
+			t.Errorf("content should not contain %q: %s", unexpected, article.Content)
+		}
+	}
+
+	for _, expected := range []string{`<div id="readability-page-1" class="page">`, "<b>Click</b> me", "underlined"} {
+		if !strings.Contains(article.Content, expected) {
+			t.Errorf("content should contain %q: %s", expected, article.Content)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package readability
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// stringMatcher is anything that able to check whether a string matches
+// a pattern, e.g. the custom regex in ScoringWeights or keywordMatcher.
+type stringMatcher interface {
+	MatchString(s string) bool
+}
+
+// keywordMatcher is the faster replacement of case insensitive regex that
+// only has alternation of keywords, like the default class patterns. Each
+// keyword is matched as substring, except the anchored ones like "^hid$"
+// and " hid " which are matched as a whole word separated by space. The
+// string that has non-ASCII character is checked by the regex instead,
+// since its case folding is not as simple.
+type keywordMatcher struct {
+	regex    *regexp.Regexp
+	keywords [][]byte
+	words    [][]byte
+}
+
+// newKeywordMatcher creates matcher from pattern, which panics if pattern
+// is not an alternation of keywords.
+func newKeywordMatcher(pattern string) *keywordMatcher {
+	m := &keywordMatcher{regex: regexp.MustCompile(pattern)}
+	for _, alternative := range strings.Split(strings.TrimPrefix(pattern, "(?i)"), "|") {
+		keyword := strings.Trim(alternative, "^$ ")
+		if keyword == "" || regexp.QuoteMeta(keyword) != keyword {
+			panic("readability: pattern is not a list of keywords: " + pattern)
+		}
+
+		lowerKeyword := []byte(strings.ToLower(keyword))
+		switch {
+		case keyword == alternative:
+			m.keywords = append(m.keywords, lowerKeyword)
+		case !m.hasWord(lowerKeyword):
+			m.words = append(m.words, lowerKeyword)
+		}
+	}
+	return m
+}
+
+// MatchString checks if s contains any of keywords.
+func (m *keywordMatcher) MatchString(s string) bool {
+	var buffer [256]byte
+	lower := buffer[:0]
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c >= utf8.RuneSelf:
+			return m.regex.MatchString(s)
+		case 'A' <= c && c <= 'Z':
+			c += 'a' - 'A'
+		}
+		lower = append(lower, c)
+	}
+
+	for _, keyword := range m.keywords {
+		if bytes.Contains(lower, keyword) {
+			return true
+		}
+	}
+
+	for len(m.words) > 0 && len(lower) > 0 {
+		field := lower
+		if space := bytes.IndexByte(lower, ' '); space >= 0 {
+			field, lower = lower[:space], lower[space+1:]
+		} else {
+			lower = nil
+		}
+
+		if m.hasWord(field) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasWord checks if word is one of the whole words in matcher.
+func (m *keywordMatcher) hasWord(word []byte) bool {
+	for _, w := range m.words {
+		if bytes.Equal(w, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// collapseSpaces replaces each sequence of at least minRun whitespaces with
+// a single space, where the whitespaces are the ones matched by `\s` in
+// regex. The text is returned as it is when there are nothing to replace.
+func collapseSpaces(text string, minRun int) string {
+	var builder strings.Builder
+	written := 0
+	for i := 0; i < len(text); {
+		if !isNormalizedSpace(rune(text[i])) {
+			i++
+			continue
+		}
+
+		end := i + 1
+		for end < len(text) && isNormalizedSpace(rune(text[end])) {
+			end++
+		}
+
+		if end-i >= minRun && (end-i > 1 || text[i] != ' ') {
+			if written == 0 {
+				builder.Grow(len(text))
+			}
+			builder.WriteString(text[written:i])
+			builder.WriteByte(' ')
+			written = end
+		}
+		i = end
+	}
+
+	if written == 0 {
+		return text
+	}
+	builder.WriteString(text[written:])
+	return builder.String()
+}
+
+// hasSentencePeriod checks if text has a period that ends a sentence, i.e.
+// it's followed by a space or by the end of text, like rxSentencePeriod.
+func hasSentencePeriod(text string) bool {
+	return strings.HasSuffix(text, ".") || strings.Contains(text, ". ")
+}
+
+// nextParagraphBreak returns the position of the first blank line in text,
+// i.e. two line breaks with only whitespaces between them that matched by
+// `\n\s*\n`. It returns -1 if there are no blank line.
+func nextParagraphBreak(text string) (start, end int) {
+	for i := strings.IndexByte(text, '\n'); i >= 0; {
+		lastBreak := -1
+		j := i + 1
+		for ; j < len(text) && isNormalizedSpace(rune(text[j])); j++ {
+			if text[j] == '\n' {
+				lastBreak = j
+			}
+		}
+
+		if lastBreak >= 0 {
+			return i, lastBreak + 1
+		}
+
+		next := strings.IndexByte(text[j:], '\n')
+		if next < 0 {
+			break
+		}
+		i = j + next
+	}
+	return -1, -1
+}
@@ -0,0 +1,113 @@
+package readability
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var scanTestClassNames = []string{
+	"", "main-content", "Article-Body", "sidebar", "comment-list", "hid", "hid foo", "foo hid", "foo hid bar",
+	"hidden", "hide", "xhid", "hid-x", "post -ad- slot", "footnote", "ENTRY", "com-links", "shareTools",
+	"ſhare", "façade sidebar", "K-content", "nav", "  ", "text hid\tmore", "a " + strings.Repeat("x", 300) + " footer",
+}
+
+func Test_keywordMatcher(t *testing.T) {
+	patterns := []string{
+		DefaultUnlikelyPattern, DefaultMaybeCandidatePattern, DefaultPositivePattern, DefaultNegativePattern,
+		`(?i)cookie|consent|gdpr|ccpa|privacy`, rxPaywallClass.regex.String(),
+	}
+
+	for _, pattern := range patterns {
+		rx := regexp.MustCompile(pattern)
+		matcher := newKeywordMatcher(pattern)
+		for _, className := range scanTestClassNames {
+			if matched, expected := matcher.MatchString(className), rx.MatchString(className); matched != expected {
+				t.Errorf("%q match %q: got %v, want %v", pattern, className, matched, expected)
+			}
+		}
+	}
+}
+
+func Test_collapseSpaces(t *testing.T) {
+	rxNormalize := regexp.MustCompile(`\s{2,}`)
+	rxCollapse := regexp.MustCompile(`[ \t\r\n\f]+`)
+	scenarios := []string{
+		"", "a", "a b", "a  b", "a \t\n b", " lead", "trail ", "a\nb", "a\n\nb", "a  b", "  ", "a \v b",
+	}
+
+	for _, text := range scenarios {
+		if collapsed, expected := collapseSpaces(text, 2), rxNormalize.ReplaceAllString(text, " "); collapsed != expected {
+			t.Errorf("normalize %q: got %q, want %q", text, collapsed, expected)
+		}
+		if collapsed, expected := collapseSpaces(text, 1), rxCollapse.ReplaceAllString(text, " "); collapsed != expected {
+			t.Errorf("collapse %q: got %q, want %q", text, collapsed, expected)
+		}
+	}
+}
+
+func Test_hasSentencePeriod(t *testing.T) {
+	rxSentencePeriod := regexp.MustCompile(`\.( |$)`)
+	for _, text := range []string{"", ".", "End.", "No period", "v1.2", "One. Two", "Dots...", "a.\tb"} {
+		if found, expected := hasSentencePeriod(text), rxSentencePeriod.MatchString(text); found != expected {
+			t.Errorf("%q: got %v, want %v", text, found, expected)
+		}
+	}
+}
+
+func Test_nextParagraphBreak(t *testing.T) {
+	rxParagraphBreak := regexp.MustCompile(`\n\s*\n`)
+	scenarios := []string{
+		"", "no break", "one\nline", "two\n\nlines", "spaced\n \t\nbreak", "many\n\n\n\nbreaks",
+		"line\nthen \n\nbreak", "trailing\n \n ", "\r\n\r\n", "a\n \nb",
+	}
+
+	for _, text := range scenarios {
+		start, end := nextParagraphBreak(text)
+		expected := rxParagraphBreak.FindStringIndex(text)
+		if expected == nil {
+			expected = []int{-1, -1}
+		}
+		if start != expected[0] || end != expected[1] {
+			t.Errorf("%q: got [%d %d], want %v", text, start, end, expected)
+		}
+	}
+}
+
+func Benchmark_keywordMatcher(b *testing.B) {
+	rx := regexp.MustCompile(DefaultNegativePattern)
+	matcher := newKeywordMatcher(DefaultNegativePattern)
+
+	b.Run("regexp", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for _, className := range scanTestClassNames {
+				rx.MatchString(className)
+			}
+		}
+	})
+
+	b.Run("scanner", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for _, className := range scanTestClassNames {
+				matcher.MatchString(className)
+			}
+		}
+	})
+}
+
+func Benchmark_collapseSpaces(b *testing.B) {
+	rxNormalize := regexp.MustCompile(`\s{2,}`)
+	text := strings.Repeat("This is a sentence of the article,\n\t  long enough to be scored. ", 20)
+
+	b.Run("regexp", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			rxNormalize.ReplaceAllString(text, " ")
+		}
+	})
+
+	b.Run("scanner", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			collapseSpaces(text, 2)
+		}
+	})
+}
@@ -0,0 +1,100 @@
+package readability
+
+import (
+	"regexp"
+
+	"github.com/go-shiori/dom"
+	"golang.org/x/net/html"
+)
+
+// Patterns of class and id that used to score the candidates, which can be
+// extended e.g. DefaultNegativePattern + "|paywall|newsletter".
+const (
+	DefaultUnlikelyPattern       = `(?i)-ad-|ai2html|banner|breadcrumbs|combx|comment|community|cover-wrap|disqus|extra|footer|gdpr|header|legends|menu|related|remark|replies|rss|shoutbox|sidebar|skyscraper|social|sponsor|supplemental|ad-break|agegate|pagination|pager|popup|yom-remote`
+	DefaultMaybeCandidatePattern = `(?i)and|article|body|column|content|main|shadow`
+	DefaultPositivePattern       = `(?i)article|body|content|entry|hentry|h-entry|main|page|pagination|post|text|blog|story`
+	DefaultNegativePattern       = `(?i)-ad-|hidden|^hid$| hid$| hid |^hid |banner|combx|comment|com-|contact|foot|footer|footnote|gdpr|masthead|media|meta|outbrain|promo|related|scroll|share|shoutbox|sidebar|skyscraper|sponsor|shopping|tags|tool|widget`
+)
+
+// DefaultClassWeight is the default score that added to candidate whose
+// class or id matches the positive pattern, and subtracted for the
+// negative pattern.
+const DefaultClassWeight = 25
+
+// DefaultTagScores is the default initial score of candidates by their
+// tag. Tags that not listed here start from 0.
+var DefaultTagScores = map[string]float64{
+	"div": 5,
+	"pre": 3, "td": 3, "blockquote": 3,
+	"address": -3, "ol": -3, "ul": -3, "dl": -3, "dd": -3, "dt": -3, "li": -3, "form": -3,
+	"h1": -5, "h2": -5, "h3": -5, "h4": -5, "h5": -5, "h6": -5, "th": -5,
+}
+
+// ScoringWeights is the patterns and weights that used to score the
+// candidates of article content. The zero value of each field means the
+// default value is used.
+type ScoringWeights struct {
+	// UnlikelyCandidates matches the class and id of elements that are
+	// removed before scoring, unless MaybeCandidates also matches them.
+	// Default: DefaultUnlikelyPattern.
+	UnlikelyCandidates *regexp.Regexp
+	// MaybeCandidates matches the class and id of elements that are kept
+	// even though they match UnlikelyCandidates.
+	// Default: DefaultMaybeCandidatePattern.
+	MaybeCandidates *regexp.Regexp
+	// PositiveClasses matches the class and id of elements that likely
+	// contain the article. Default: DefaultPositivePattern.
+	PositiveClasses *regexp.Regexp
+	// NegativeClasses matches the class and id of elements that likely
+	// are clutter. Default: DefaultNegativePattern.
+	NegativeClasses *regexp.Regexp
+	// ClassWeight is the score that added for each match of positive
+	// pattern, and subtracted for each match of negative pattern.
+	// Default: DefaultClassWeight.
+	ClassWeight int
+	// TagScores is the initial score of candidates by their tag.
+	// Default: DefaultTagScores.
+	TagScores map[string]float64
+}
+
+// isUnlikelyCandidate checks if the class and id in matchString look like
+// the element is not part of the article.
+func (ps *Parser) isUnlikelyCandidate(matchString string) bool {
+	var unlikely, maybe stringMatcher = rxUnlikelyCandidates, rxOkMaybeItsACandidate
+	if ps.ScoringWeights.UnlikelyCandidates != nil {
+		unlikely = ps.ScoringWeights.UnlikelyCandidates
+	}
+	if ps.ScoringWeights.MaybeCandidates != nil {
+		maybe = ps.ScoringWeights.MaybeCandidates
+	}
+	return unlikely.MatchString(matchString) && !maybe.MatchString(matchString)
+}
+
+// classPatterns returns the positive and negative patterns of class.
+func (ps *Parser) classPatterns() (positive, negative stringMatcher) {
+	positive, negative = rxPositive, rxNegative
+	if ps.ScoringWeights.PositiveClasses != nil {
+		positive = ps.ScoringWeights.PositiveClasses
+	}
+	if ps.ScoringWeights.NegativeClasses != nil {
+		negative = ps.ScoringWeights.NegativeClasses
+	}
+	return positive, negative
+}
+
+// classWeight returns the weight of each class pattern match.
+func (ps *Parser) classWeight() int {
+	if ps.ScoringWeights.ClassWeight != 0 {
+		return ps.ScoringWeights.ClassWeight
+	}
+	return DefaultClassWeight
+}
+
+// tagScore returns the initial score of node by its tag.
+func (ps *Parser) tagScore(node *html.Node) float64 {
+	tagScores := DefaultTagScores
+	if ps.ScoringWeights.TagScores != nil {
+		tagScores = ps.ScoringWeights.TagScores
+	}
+	return tagScores[dom.TagName(node)]
+}
@@ -0,0 +1,54 @@
+package readability
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/go-shiori/dom"
+)
+
+func Test_Parser_ScoringWeights(t *testing.T) {
+	paywall := dom.CreateElement("div")
+	dom.SetAttribute(paywall, "class", "paywall")
+	comment := dom.CreateElement("div")
+	dom.SetAttribute(comment, "class", "comment")
+	story := dom.CreateElement("li")
+	dom.SetAttribute(story, "id", "story")
+
+	ps := NewParser()
+	ps.flags.useWeightClasses = true
+	if weight := ps.getClassWeight(paywall); weight != 0 {
+		t.Errorf("default weight of paywall, want 0 got %d", weight)
+	}
+	if weight := ps.getClassWeight(comment); weight != -DefaultClassWeight {
+		t.Errorf("default weight of comment, want %d got %d", -DefaultClassWeight, weight)
+	}
+	if !ps.isUnlikelyCandidate("comment ") {
+		t.Error("comment should be unlikely candidate by default")
+	}
+
+	// Penalize paywall, and keep comment where the article lives in it
+	ps = NewParser(WithScoringWeights(ScoringWeights{
+		NegativeClasses: regexp.MustCompile(DefaultNegativePattern + "|paywall"),
+		MaybeCandidates: regexp.MustCompile(DefaultMaybeCandidatePattern + "|comment"),
+		PositiveClasses: regexp.MustCompile(`(?i)story|comment`),
+		ClassWeight:     10,
+		TagScores:       map[string]float64{"li": 7},
+	}))
+	ps.flags.useWeightClasses = true
+
+	if weight := ps.getClassWeight(paywall); weight != -10 {
+		t.Errorf("custom weight of paywall, want -10 got %d", weight)
+	}
+	if weight := ps.getClassWeight(comment); weight != 0 {
+		t.Errorf("custom weight of comment, want 0 got %d", weight)
+	}
+	if ps.isUnlikelyCandidate("comment ") {
+		t.Error("comment should not be unlikely candidate with custom pattern")
+	}
+
+	ps.initializeNode(story)
+	if score := ps.getContentScore(story); score != 17 {
+		t.Errorf("custom score of li#story, want 17 got %v", score)
+	}
+}
@@ -0,0 +1,410 @@
+// Command corpus manages the golden corpus, which is the snapshots of the
+// parsed output for a set of real-world pages. Unlike the test pages that
+// follow Readability.js, the snapshots are made by the default parser with
+// all of its additional cleanups, so any change of extraction behavior is
+// flagged before release:
+//
+//	go run ./scripts/corpus add name url-or-file
+//	go run ./scripts/corpus update [name...]
+//	go run ./scripts/corpus verify [name...]
+//
+// Each page is stored in its own directory inside the corpus, which has
+// the source, the URL that used to parse it, and the snapshot. The corpus
+// is also verified by `go test ./scripts/corpus`.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	nurl "net/url"
+	"os"
+	fp "path/filepath"
+	"strings"
+	"time"
+
+	readability "github.com/go-shiori/go-readability"
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// defaultPageURL is the URL of page that added from file.
+const defaultPageURL = "http://fakehost/test/page.html"
+
+// Files of each page in the corpus.
+const (
+	sourceFile   = "source.html"
+	pageFile     = "page.json"
+	snapshotFile = "snapshot.json"
+	contentFile  = "snapshot.html"
+)
+
+// diffContext is the number of characters around the first difference of
+// content that printed by verify.
+const diffContext = 60
+
+var httpClient = &http.Client{Timeout: time.Minute}
+
+// page is the information of page in the corpus.
+type page struct {
+	URL   string `json:"url"`
+	Added string `json:"added"`
+}
+
+// snapshot is the parsed output of page, except the content which stored
+// in its own file so its changes can be seen in diff.
+type snapshot struct {
+	Title         string `json:"title"`
+	Byline        string `json:"byline"`
+	Excerpt       string `json:"excerpt"`
+	SiteName      string `json:"siteName"`
+	Image         string `json:"image"`
+	Favicon       string `json:"favicon"`
+	Language      string `json:"language"`
+	Dir           string `json:"dir"`
+	PublishedTime string `json:"publishedTime"`
+	ModifiedTime  string `json:"modifiedTime"`
+	Length        int    `json:"length"`
+	Readerable    bool   `json:"readerable"`
+	Content       string `json:"-"`
+}
+
+func main() {
+	corpusDir := flag.String("dir", fp.Join("testdata", "corpus"), "directory of the corpus")
+	pageURL := flag.String("url", "", "URL of page that added from file")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage:\n"+
+			"  corpus [flags] add name url-or-file\n"+
+			"  corpus [flags] update [name...]\n"+
+			"  corpus [flags] verify [name...]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	switch command, names := args[0], args[1:]; command {
+	case "add":
+		if len(names) != 2 {
+			log.Fatalln("add needs the name and the URL or file of page")
+		}
+
+		if err := addPage(*corpusDir, names[0], names[1], *pageURL); err != nil {
+			log.Fatalf("failed to add %s: %v\n", names[0], err)
+		}
+	case "update":
+		if err := updatePages(*corpusDir, names); err != nil {
+			log.Fatalf("failed to update corpus: %v\n", err)
+		}
+	case "verify":
+		changed, err := verifyPages(*corpusDir, names, os.Stdout)
+		if err != nil {
+			log.Fatalf("failed to verify corpus: %v\n", err)
+		}
+
+		if changed > 0 {
+			fmt.Printf("%d page(s) changed, run update if the changes are expected\n", changed)
+			os.Exit(1)
+		}
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+}
+
+// addPage adds the page from URL or file into the corpus, then makes its
+// snapshot.
+func addPage(corpusDir, name, source, pageURL string) error {
+	pageDir := fp.Join(corpusDir, name)
+	if _, err := os.Stat(pageDir); err == nil {
+		return fmt.Errorf("page %s already exists", name)
+	}
+
+	var content []byte
+	var err error
+	if isURL(source) {
+		pageURL = source
+		content, err = downloadWebPage(source)
+	} else {
+		if pageURL == "" {
+			pageURL = defaultPageURL
+		}
+		content, err = os.ReadFile(source)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read source: %v", err)
+	}
+
+	if err = os.MkdirAll(pageDir, 0o755); err != nil {
+		return err
+	}
+
+	if err = os.WriteFile(fp.Join(pageDir, sourceFile), content, 0o644); err != nil {
+		return err
+	}
+
+	info := page{URL: pageURL, Added: time.Now().Format("2006-01-02")}
+	if err = writeJSON(fp.Join(pageDir, pageFile), info); err != nil {
+		return err
+	}
+
+	return updatePages(corpusDir, []string{name})
+}
+
+// updatePages replaces the snapshot of pages with their current output. All
+// pages in the corpus are updated when names is empty.
+func updatePages(corpusDir string, names []string) error {
+	names, err := pageNames(corpusDir, names)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		log.Println("updating", name)
+		result, err := parsePage(fp.Join(corpusDir, name))
+		if err != nil {
+			return fmt.Errorf("failed to parse %s: %v", name, err)
+		}
+
+		if err = writeSnapshot(fp.Join(corpusDir, name), result); err != nil {
+			return fmt.Errorf("failed to write snapshot of %s: %v", name, err)
+		}
+	}
+	return nil
+}
+
+// verifyPages compares the current output of pages with their snapshot,
+// and prints the changes into w. It returns the number of changed pages.
+func verifyPages(corpusDir string, names []string, w io.Writer) (int, error) {
+	names, err := pageNames(corpusDir, names)
+	if err != nil {
+		return 0, err
+	}
+
+	changed := 0
+	for _, name := range names {
+		pageDir := fp.Join(corpusDir, name)
+		expected, err := readSnapshot(pageDir)
+		if err != nil {
+			return changed, fmt.Errorf("failed to read snapshot of %s: %v", name, err)
+		}
+
+		result, err := parsePage(pageDir)
+		if err != nil {
+			return changed, fmt.Errorf("failed to parse %s: %v", name, err)
+		}
+
+		changes := compareSnapshot(expected, result)
+		if len(changes) == 0 {
+			fmt.Fprintf(w, "ok      %s\n", name)
+			continue
+		}
+
+		changed++
+		fmt.Fprintf(w, "CHANGED %s\n", name)
+		for _, change := range changes {
+			fmt.Fprintf(w, "    %s\n", change)
+		}
+	}
+	return changed, nil
+}
+
+// pageNames returns the names of pages, or all pages in the corpus when
+// names is empty.
+func pageNames(corpusDir string, names []string) ([]string, error) {
+	if len(names) > 0 {
+		return names, nil
+	}
+
+	items, err := os.ReadDir(corpusDir)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range items {
+		if item.IsDir() {
+			names = append(names, item.Name())
+		}
+	}
+	return names, nil
+}
+
+// parsePage parses the source of page using the default parser.
+func parsePage(pageDir string) (snapshot, error) {
+	var info page
+	if err := readJSON(fp.Join(pageDir, pageFile), &info); err != nil {
+		return snapshot{}, err
+	}
+
+	pageURL, err := nurl.ParseRequestURI(info.URL)
+	if err != nil {
+		return snapshot{}, fmt.Errorf("URL %s is not valid: %v", info.URL, err)
+	}
+
+	source, err := os.ReadFile(fp.Join(pageDir, sourceFile))
+	if err != nil {
+		return snapshot{}, err
+	}
+
+	parser := readability.NewParser()
+	article, err := parser.Parse(strings.NewReader(string(source)), pageURL)
+	if err != nil {
+		return snapshot{}, err
+	}
+
+	return snapshot{
+		Title:         article.Title,
+		Byline:        article.Byline,
+		Excerpt:       article.Excerpt,
+		SiteName:      article.SiteName,
+		Image:         article.Image,
+		Favicon:       article.Favicon,
+		Language:      article.Language,
+		Dir:           article.Dir,
+		PublishedTime: formatTime(article.PublishedTime),
+		ModifiedTime:  formatTime(article.ModifiedTime),
+		Length:        article.Length,
+		Readerable:    parser.Check(strings.NewReader(string(source))),
+		Content:       article.Content,
+	}, nil
+}
+
+// compareSnapshot returns the description of each change from expected.
+func compareSnapshot(expected, result snapshot) []string {
+	var changes []string
+	compare := func(field string, want, got interface{}) {
+		if want != got {
+			changes = append(changes, fmt.Sprintf("%s: want %q got %q", field, fmt.Sprint(want), fmt.Sprint(got)))
+		}
+	}
+
+	compare("title", expected.Title, result.Title)
+	compare("byline", expected.Byline, result.Byline)
+	compare("excerpt", expected.Excerpt, result.Excerpt)
+	compare("siteName", expected.SiteName, result.SiteName)
+	compare("image", expected.Image, result.Image)
+	compare("favicon", expected.Favicon, result.Favicon)
+	compare("language", expected.Language, result.Language)
+	compare("dir", expected.Dir, result.Dir)
+	compare("publishedTime", expected.PublishedTime, result.PublishedTime)
+	compare("modifiedTime", expected.ModifiedTime, result.ModifiedTime)
+	compare("length", expected.Length, result.Length)
+	compare("readerable", expected.Readerable, result.Readerable)
+
+	if expected.Content != result.Content {
+		changes = append(changes, "content: "+describeContentChange(expected.Content, result.Content))
+	}
+	return changes
+}
+
+// describeContentChange describes the size of change in content, and
+// where it starts.
+func describeContentChange(want, got string) string {
+	comparator := diffmatchpatch.New()
+	diffs := comparator.DiffMain(want, got, false)
+
+	var inserted, deleted int
+	offset, first := 0, -1
+	for _, diff := range diffs {
+		switch diff.Type {
+		case diffmatchpatch.DiffEqual:
+			offset += len(diff.Text)
+		case diffmatchpatch.DiffInsert:
+			inserted += len(diff.Text)
+		case diffmatchpatch.DiffDelete:
+			deleted += len(diff.Text)
+		}
+
+		if diff.Type != diffmatchpatch.DiffEqual && first < 0 {
+			first = offset
+		}
+	}
+
+	start := first - diffContext
+	if start < 0 {
+		start = 0
+	}
+	wantEnd, gotEnd := first+diffContext, first+diffContext
+	if wantEnd > len(want) {
+		wantEnd = len(want)
+	}
+	if gotEnd > len(got) {
+		gotEnd = len(got)
+	}
+
+	return fmt.Sprintf("%d bytes inserted, %d bytes deleted, first at byte %d\n"+
+		"        want: %q\n"+
+		"        got : %q", inserted, deleted, first, want[start:wantEnd], got[start:gotEnd])
+}
+
+// readSnapshot reads the snapshot of page.
+func readSnapshot(pageDir string) (snapshot, error) {
+	var result snapshot
+	if err := readJSON(fp.Join(pageDir, snapshotFile), &result); err != nil {
+		return snapshot{}, err
+	}
+
+	content, err := os.ReadFile(fp.Join(pageDir, contentFile))
+	if err != nil {
+		return snapshot{}, err
+	}
+
+	result.Content = string(content)
+	return result, nil
+}
+
+// writeSnapshot writes the snapshot of page.
+func writeSnapshot(pageDir string, result snapshot) error {
+	if err := writeJSON(fp.Join(pageDir, snapshotFile), result); err != nil {
+		return err
+	}
+	return os.WriteFile(fp.Join(pageDir, contentFile), []byte(result.Content), 0o644)
+}
+
+func readJSON(path string, v interface{}) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(content, v)
+}
+
+func writeJSON(path string, v interface{}) error {
+	content, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(content, '\n'), 0o644)
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func isURL(source string) bool {
+	parsedURL, err := nurl.ParseRequestURI(source)
+	return err == nil && (parsedURL.Scheme == "http" || parsedURL.Scheme == "https")
+}
+
+func downloadWebPage(srcURL string) ([]byte, error) {
+	resp, err := httpClient.Get(srcURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.New(resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	fp "path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_verifyPages(t *testing.T) {
+	var output bytes.Buffer
+	changed, err := verifyPages(fp.Join("..", "..", "testdata", "corpus"), nil, &output)
+	if err != nil {
+		t.Fatalf("failed to verify corpus: %v", err)
+	}
+
+	if changed > 0 {
+		t.Errorf("%d page(s) changed, run `go run ./scripts/corpus update` "+
+			"if the changes are expected\n%s", changed, output.String())
+	}
+}
+
+func Test_addPage(t *testing.T) {
+	corpusDir := t.TempDir()
+	source := fp.Join("..", "..", "test-pages", "001", "source.html")
+	if err := addPage(corpusDir, "001", source, ""); err != nil {
+		t.Fatalf("failed to add page: %v", err)
+	}
+
+	if err := addPage(corpusDir, "001", source, ""); err == nil {
+		t.Error("page is added twice")
+	}
+
+	var output bytes.Buffer
+	if changed, err := verifyPages(corpusDir, nil, &output); err != nil || changed != 0 {
+		t.Fatalf("new page, want unchanged got %d changed and error %v\n%s", changed, err, output.String())
+	}
+
+	// Tamper the snapshot, so it looks like the output is changed
+	pageDir := fp.Join(corpusDir, "001")
+	expected, err := readSnapshot(pageDir)
+	if err != nil {
+		t.Fatalf("failed to read snapshot: %v", err)
+	}
+
+	expected.Title = "Old title"
+	expected.Content = strings.Replace(expected.Content, "<p>", "<p>Old text ", 1)
+	if err = writeSnapshot(pageDir, expected); err != nil {
+		t.Fatalf("failed to write snapshot: %v", err)
+	}
+
+	output.Reset()
+	if changed, _ := verifyPages(corpusDir, nil, &output); changed != 1 {
+		t.Errorf("tampered page, want 1 changed got %d", changed)
+	}
+
+	for _, expected := range []string{"CHANGED 001", `title: want "Old title"`, "content: 0 bytes inserted, 9 bytes deleted"} {
+		if !strings.Contains(output.String(), expected) {
+			t.Errorf("output should contain %q\n%s", expected, output.String())
+		}
+	}
+
+	// Update brings back the snapshot to the current output
+	if err = updatePages(corpusDir, []string{"001"}); err != nil {
+		t.Fatalf("failed to update page: %v", err)
+	}
+
+	output.Reset()
+	if changed, err := verifyPages(corpusDir, nil, &output); err != nil || changed != 0 {
+		t.Errorf("updated page, want unchanged got %d changed and error %v\n%s", changed, err, output.String())
+	}
+
+	if _, err := os.Stat(fp.Join(pageDir, pageFile)); err != nil {
+		t.Errorf("page info is not written: %v", err)
+	}
+}
@@ -187,6 +187,7 @@ func renderMetadataToFile(original *html.Node, article readability.Article, file
 		Excerpt    string `json:"excerpt,omitempty"`
 		Language   string `json:"language,omitempty"`
 		SiteName   string `json:"siteName,omitempty"`
+		Dir        string `json:"dir,omitempty"`
 		Readerable bool   `json:"readerable"`
 	}{
 		Title:      article.Title,
@@ -194,6 +195,7 @@ func renderMetadataToFile(original *html.Node, article readability.Article, file
 		Excerpt:    article.Excerpt,
 		Language:   article.Language,
 		SiteName:   article.SiteName,
+		Dir:        article.Dir,
 		Readerable: readability.CheckDocument(original),
 	}
 
@@ -0,0 +1,356 @@
+// Command jsdiff runs the same HTML pages through Readability.js and this
+// package, then reports how different their results are: the similarity
+// of the article text and of its HTML structure, and the metadata that
+// don't match. Readability.js is run by Node, so it requires Node with
+// jsdom and @mozilla/readability installed, either in the working
+// directory or in NODE_PATH:
+//
+//	npm install jsdom @mozilla/readability
+//	go run ./scripts/jsdiff -compat test-pages
+//
+// Each argument is an HTML file, or a directory whose HTML files are
+// compared recursively. The expected.html in directory of test pages
+// is skipped, so only its source.html is compared.
+package main
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/fs"
+	"log"
+	nurl "net/url"
+	"os"
+	"os/exec"
+	fp "path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+	"unicode/utf8"
+
+	"github.com/go-shiori/dom"
+	readability "github.com/go-shiori/go-readability"
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+//go:embed runner.js
+var runnerScript string
+
+// jsResult is the output of runner.js.
+type jsResult struct {
+	Readerable bool       `json:"readerable"`
+	Article    *jsArticle `json:"article"`
+}
+
+// jsArticle is the article that returned by Readability.js.
+type jsArticle struct {
+	Title       string `json:"title"`
+	Byline      string `json:"byline"`
+	Dir         string `json:"dir"`
+	Lang        string `json:"lang"`
+	Content     string `json:"content"`
+	TextContent string `json:"textContent"`
+	Excerpt     string `json:"excerpt"`
+	SiteName    string `json:"siteName"`
+}
+
+// fieldDiff is the different values of a field.
+type fieldDiff struct {
+	JS string `json:"js"`
+	Go string `json:"go"`
+}
+
+// pageResult is the comparison result of a page.
+type pageResult struct {
+	Path                string               `json:"path"`
+	Error               string               `json:"error,omitempty"`
+	TextSimilarity      float64              `json:"text_similarity"`
+	StructureSimilarity float64              `json:"structure_similarity"`
+	Diffs               map[string]fieldDiff `json:"diffs,omitempty"`
+}
+
+// comparedFields is the fields that compared in each page, in the order
+// they are reported.
+var comparedFields = []string{"article", "readerable", "title", "byline", "excerpt", "siteName", "lang", "dir"}
+
+func main() {
+	nodePath := flag.String("node", "node", "path of Node executable")
+	pageURL := flag.String("url", "http://fakehost/test/page.html", "URL of the pages")
+	compat := flag.Bool("compat", false, "disable the cleanups that not exist in Readability.js")
+	reportPath := flag.String("report", "", "write the result of each page as JSON to this file")
+	verbose := flag.Bool("v", false, "print the different fields of each page")
+	timeout := flag.Duration("timeout", time.Minute, "timeout for each page")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: jsdiff [flags] path...\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	parsedURL, err := nurl.ParseRequestURI(*pageURL)
+	if err != nil {
+		log.Fatalf("URL %s is not valid: %v\n", *pageURL, err)
+	}
+
+	paths, err := collectPages(flag.Args())
+	if err != nil {
+		log.Fatalf("failed to collect pages: %v\n", err)
+	}
+
+	var opts []readability.Option
+	if *compat {
+		opts = append(opts, readability.WithDisablePromoRemoval(true), readability.WithDisableShareBarRemoval(true),
+			readability.WithPropagateDir(false))
+	}
+	parser := readability.NewParser(opts...)
+
+	var results []pageResult
+	for _, path := range paths {
+		ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+		result := comparePage(ctx, &parser, *nodePath, parsedURL, path)
+		cancel()
+
+		result.Path = path
+		results = append(results, result)
+	}
+
+	printResults(results, *verbose)
+
+	if *reportPath != "" {
+		report, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			log.Fatalf("failed to encode report: %v\n", err)
+		}
+
+		if err = os.WriteFile(*reportPath, report, 0o644); err != nil {
+			log.Fatalf("failed to write report: %v\n", err)
+		}
+	}
+}
+
+// collectPages returns the HTML files in args, which is either file or
+// directory.
+func collectPages(args []string) ([]string, error) {
+	var paths []string
+	for _, arg := range args {
+		err := fp.WalkDir(arg, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			ext := strings.ToLower(fp.Ext(path))
+			switch {
+			case d.IsDir(), ext != ".html" && ext != ".htm":
+			case path != arg && d.Name() == "expected.html":
+			default:
+				paths = append(paths, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return paths, nil
+}
+
+// comparePage runs the page through both Readability.js and this package,
+// and compares their results.
+func comparePage(ctx context.Context, parser *readability.Parser, nodePath string, pageURL *nurl.URL, path string) pageResult {
+	source, err := os.ReadFile(path)
+	if err != nil {
+		return pageResult{Error: err.Error()}
+	}
+
+	jsRes, err := runReadabilityJS(ctx, nodePath, pageURL, source)
+	if err != nil {
+		return pageResult{Error: fmt.Sprintf("failed to run Readability.js: %v", err)}
+	}
+
+	goReaderable := parser.Check(bytes.NewReader(source))
+	article, goErr := parser.ParseWithContext(ctx, bytes.NewReader(source), pageURL)
+
+	result := pageResult{Diffs: make(map[string]fieldDiff)}
+	compareField := func(field, jsValue, goValue string) {
+		if jsValue != goValue {
+			result.Diffs[field] = fieldDiff{JS: jsValue, Go: goValue}
+		}
+	}
+
+	compareField("readerable", fmt.Sprint(jsRes.Readerable), fmt.Sprint(goReaderable))
+
+	// Neither of them finds the article, so they still agree
+	switch {
+	case jsRes.Article == nil && goErr != nil:
+		result.TextSimilarity, result.StructureSimilarity = 1, 1
+		return result
+	case jsRes.Article == nil:
+		compareField("article", "no article", "found")
+		return result
+	case goErr != nil:
+		compareField("article", "found", goErr.Error())
+		return result
+	}
+
+	js := jsRes.Article
+	compareField("title", js.Title, article.Title)
+	compareField("byline", js.Byline, article.Byline)
+	compareField("excerpt", js.Excerpt, article.Excerpt)
+	compareField("siteName", js.SiteName, article.SiteName)
+	compareField("lang", js.Lang, article.Language)
+	compareField("dir", js.Dir, article.Dir)
+
+	result.TextSimilarity = textSimilarity(js.TextContent, article.TextContent)
+	result.StructureSimilarity = structureSimilarity(js.Content, article.Content)
+	return result
+}
+
+// runReadabilityJS runs runner.js for the source, and decodes its output.
+func runReadabilityJS(ctx context.Context, nodePath string, pageURL *nurl.URL, source []byte) (jsResult, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, nodePath, "-e", runnerScript, pageURL.String())
+	cmd.Stdin = bytes.NewReader(source)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return jsResult{}, fmt.Errorf("%v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	var result jsResult
+	if err := json.Unmarshal(stdout.Bytes(), &result); err != nil {
+		return jsResult{}, fmt.Errorf("failed to decode output: %v", err)
+	}
+	return result, nil
+}
+
+// textSimilarity returns the similarity of both text ignoring whitespaces,
+// which is based on the Levenshtein distance of their characters.
+func textSimilarity(a, b string) float64 {
+	a = strings.Join(strings.Fields(a), " ")
+	b = strings.Join(strings.Fields(b), " ")
+	if a == b {
+		return 1
+	}
+
+	comparator := diffmatchpatch.New()
+	diffs := comparator.DiffMain(a, b, false)
+	return similarity(comparator.DiffLevenshtein(diffs), utf8.RuneCountInString(a), utf8.RuneCountInString(b))
+}
+
+// structureSimilarity returns the similarity of both HTML, which is based
+// on the Levenshtein distance of their elements in document order.
+func structureSimilarity(a, b string) float64 {
+	tags := make(map[string]rune)
+	tagsA, tagsB := elementSequence(a, tags), elementSequence(b, tags)
+	if string(tagsA) == string(tagsB) {
+		return 1
+	}
+
+	comparator := diffmatchpatch.New()
+	diffs := comparator.DiffMainRunes(tagsA, tagsB, false)
+	return similarity(comparator.DiffLevenshtein(diffs), len(tagsA), len(tagsB))
+}
+
+// elementSequence returns the tag names of elements in the HTML, which are
+// encoded as runes using the shared tags, so they can be diffed.
+func elementSequence(content string, tags map[string]rune) []rune {
+	doc, err := dom.FastParse(strings.NewReader(content))
+	if err != nil {
+		return nil
+	}
+
+	var sequence []rune
+	body := dom.QuerySelector(doc, "body")
+	for _, node := range dom.QuerySelectorAll(body, "*") {
+		tag := dom.TagName(node)
+		if _, exist := tags[tag]; !exist {
+			// Start from the private use area, so it's never a surrogate
+			tags[tag] = rune(0xE000 + len(tags))
+		}
+		sequence = append(sequence, tags[tag])
+	}
+	return sequence
+}
+
+// similarity converts the Levenshtein distance into a ratio from 0 to 1.
+func similarity(distance, lengthA, lengthB int) float64 {
+	total := lengthA
+	if lengthB > total {
+		total = lengthB
+	}
+
+	if total == 0 || distance >= total {
+		return 0
+	}
+	return 1 - float64(distance)/float64(total)
+}
+
+// printResults prints the result of each page, then the aggregate stats.
+func printResults(results []pageResult, verbose bool) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PAGE\tTEXT\tSTRUCTURE\tDIFFS")
+
+	var nErrors, nExact int
+	var totalText, totalStructure float64
+	fieldMismatches := make(map[string]int)
+	for _, result := range results {
+		if result.Error != "" {
+			nErrors++
+			fmt.Fprintf(w, "%s\t-\t-\terror: %s\n", result.Path, result.Error)
+			continue
+		}
+
+		fields := make([]string, 0, len(result.Diffs))
+		for _, field := range comparedFields {
+			if _, exist := result.Diffs[field]; exist {
+				fields = append(fields, field)
+				fieldMismatches[field]++
+			}
+		}
+
+		if result.TextSimilarity == 1 {
+			nExact++
+		}
+		totalText += result.TextSimilarity
+		totalStructure += result.StructureSimilarity
+
+		diffs := "-"
+		if len(fields) > 0 {
+			diffs = strings.Join(fields, ",")
+		}
+		fmt.Fprintf(w, "%s\t%.1f%%\t%.1f%%\t%s\n", result.Path,
+			100*result.TextSimilarity, 100*result.StructureSimilarity, diffs)
+
+		if verbose {
+			for _, field := range fields {
+				diff := result.Diffs[field]
+				fmt.Fprintf(w, "\t\t\t%s: js %q, go %q\n", field, diff.JS, diff.Go)
+			}
+		}
+	}
+	w.Flush()
+
+	compared := len(results) - nErrors
+	fmt.Printf("\npages: %d, compared: %d, errors: %d\n", len(results), compared, nErrors)
+	if compared == 0 {
+		return
+	}
+
+	fmt.Printf("mean text similarity: %.1f%%, mean structure similarity: %.1f%%\n",
+		100*totalText/float64(compared), 100*totalStructure/float64(compared))
+	fmt.Printf("identical text: %d/%d\n", nExact, compared)
+
+	var mismatches []string
+	for _, field := range comparedFields {
+		mismatches = append(mismatches, fmt.Sprintf("%s %d", field, fieldMismatches[field]))
+	}
+	fmt.Printf("mismatched fields: %s\n", strings.Join(mismatches, ", "))
+}
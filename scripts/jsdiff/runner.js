@@ -0,0 +1,23 @@
+// Runner of Readability.js for jsdiff. It reads the HTML document from
+// stdin, and prints the result of Readability.js as JSON to stdout. The
+// URL of document is the first argument.
+//
+// It requires jsdom and @mozilla/readability, which can be installed by:
+//
+//	npm install jsdom @mozilla/readability
+"use strict";
+
+const { JSDOM } = require("jsdom");
+const { Readability, isProbablyReaderable } = require("@mozilla/readability");
+
+const chunks = [];
+process.stdin.on("data", (chunk) => chunks.push(chunk));
+process.stdin.on("end", () => {
+  const html = Buffer.concat(chunks).toString("utf8");
+  const url = process.argv[process.argv.length - 1];
+
+  const readerable = isProbablyReaderable(new JSDOM(html, { url }).window.document);
+  const article = new Readability(new JSDOM(html, { url }).window.document).parse();
+
+  process.stdout.write(JSON.stringify({ readerable, article }));
+});
@@ -0,0 +1,204 @@
+package readability
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/go-shiori/dom"
+	"golang.org/x/net/html"
+)
+
+// headingTags is the tags of heading, ordered by its level.
+var headingTags = []string{"h1", "h2", "h3", "h4", "h5", "h6"}
+
+// Section is a part of article content that started by a heading. The
+// content before the first heading is put in section with level 0.
+type Section struct {
+	HeadingLevel int    `json:"heading_level"`
+	HeadingText  string `json:"heading_text"`
+	AnchorID     string `json:"anchor_id"`
+	HTML         string `json:"html"`
+}
+
+// TOCEntry is an entry in the table of contents of the article.
+type TOCEntry struct {
+	Level    int        `json:"level"`
+	Text     string     `json:"text"`
+	AnchorID string     `json:"anchor_id"`
+	Children []TOCEntry `json:"children,omitempty"`
+}
+
+// TableOfContents generates the table of contents from the sections of
+// the article. Entries are nested following their heading level, so a
+// section is put inside the nearest previous section with lower level.
+func (article Article) TableOfContents() []TOCEntry {
+	var root TOCEntry
+	stack := []*TOCEntry{&root}
+
+	for _, section := range article.Sections {
+		if section.HeadingLevel == 0 {
+			continue
+		}
+
+		for len(stack) > 1 && stack[len(stack)-1].Level >= section.HeadingLevel {
+			stack = stack[:len(stack)-1]
+		}
+
+		parent := stack[len(stack)-1]
+		parent.Children = append(parent.Children, TOCEntry{
+			Level:    section.HeadingLevel,
+			Text:     section.HeadingText,
+			AnchorID: section.AnchorID,
+		})
+		stack = append(stack, &parent.Children[len(parent.Children)-1])
+	}
+
+	return root.Children
+}
+
+// getSections splits the article content into sections by its headings.
+// Headings that don't have id are given one generated from their text,
+// so the anchor of each section can be linked to.
+func (ps *Parser) getSections(articleContent *html.Node) []Section {
+	usedIDs := make(map[string]struct{})
+	for _, node := range dom.QuerySelectorAll(articleContent, "[id]") {
+		usedIDs[dom.ID(node)] = struct{}{}
+	}
+
+	var sections []Section
+	var current *Section
+	var sb strings.Builder
+
+	flush := func() {
+		if current != nil {
+			current.HTML = strings.TrimSpace(sb.String())
+			if current.HeadingLevel > 0 || current.HTML != "" {
+				sections = append(sections, *current)
+			}
+		}
+		sb.Reset()
+	}
+
+	current = &Section{}
+	for _, block := range ps.sectionBlocks(articleContent) {
+		level := headingLevel(block)
+		if level == 0 {
+			sb.WriteString(dom.OuterHTML(block))
+			continue
+		}
+
+		flush()
+		text := strings.Join(strings.Fields(dom.TextContent(block)), " ")
+		id := dom.ID(block)
+		if id == "" {
+			id = uniqueAnchorID(slugify(text), usedIDs)
+			dom.SetAttribute(block, "id", id)
+		}
+		current = &Section{HeadingLevel: level, HeadingText: text, AnchorID: id}
+	}
+
+	flush()
+	return sections
+}
+
+// sectionBlocks returns the blocks of node in document order. Containers
+// that have heading inside are flattened, so every heading ends up as a
+// block of its own.
+func (ps *Parser) sectionBlocks(node *html.Node) []*html.Node {
+	var blocks []*html.Node
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		switch {
+		case child.Type == html.TextNode && strings.TrimSpace(child.Data) == "":
+			continue
+		case child.Type == html.ElementNode && headingLevel(child) == 0 &&
+			len(ps.getAllNodesWithTag(child, headingTags...)) > 0:
+			blocks = append(blocks, ps.sectionBlocks(child)...)
+		default:
+			blocks = append(blocks, child)
+		}
+	}
+	return blocks
+}
+
+// normalizeHeadings changes the level of headings so the top headings
+// are at base level, and each heading is at most one level deeper than
+// its parent heading. Headings that are deeper than h6 are kept as h6.
+func (ps *Parser) normalizeHeadings(articleContent *html.Node, baseLevel int) {
+	type level struct {
+		original   int
+		normalized int
+	}
+
+	// Headings must be processed in document order
+	var stack []level
+	for _, heading := range dom.QuerySelectorAll(articleContent, strings.Join(headingTags, ",")) {
+		original := headingLevel(heading)
+		for len(stack) > 0 && stack[len(stack)-1].original > original {
+			stack = stack[:len(stack)-1]
+		}
+
+		normalized := baseLevel
+		switch {
+		case len(stack) > 0 && stack[len(stack)-1].original == original:
+			normalized = stack[len(stack)-1].normalized
+		case len(stack) > 0:
+			normalized = stack[len(stack)-1].normalized + 1
+			stack = append(stack, level{original, normalized})
+		default:
+			stack = append(stack, level{original, normalized})
+		}
+
+		if normalized > len(headingTags) {
+			normalized = len(headingTags)
+		}
+		ps.setNodeTag(heading, headingTags[normalized-1])
+	}
+}
+
+// headingLevel returns the level of heading node, or 0 if the node is
+// not a heading.
+func headingLevel(node *html.Node) int {
+	if node.Type != html.ElementNode {
+		return 0
+	}
+	return indexOf(headingTags, dom.TagName(node)) + 1
+}
+
+// slugify converts the text into a string that usable as anchor ID.
+func slugify(text string) string {
+	var sb strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if dash && sb.Len() > 0 {
+				sb.WriteRune('-')
+			}
+			sb.WriteRune(r)
+			dash = false
+		default:
+			dash = true
+		}
+	}
+
+	if sb.Len() == 0 {
+		return "section"
+	}
+	return sb.String()
+}
+
+// uniqueAnchorID makes sure the id hasn't been used by appending number
+// into it, then marks it as used.
+func uniqueAnchorID(id string, usedIDs map[string]struct{}) string {
+	unique := id
+	for i := 2; ; i++ {
+		if _, used := usedIDs[unique]; !used {
+			break
+		}
+		unique = fmt.Sprintf("%s-%d", id, i)
+	}
+
+	usedIDs[unique] = struct{}{}
+	return unique
+}
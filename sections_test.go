@@ -0,0 +1,125 @@
+package readability
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-shiori/dom"
+)
+
+func Test_Parser_Sections(t *testing.T) {
+	paragraph := articleParagraph(10)
+	input := `<html><body><article>` + paragraph +
+		`<h2>Getting Started</h2>` + paragraph +
+		`<section><h3 id="install">Install it</h3>` + paragraph + `</section>` +
+		`<h3>Usage &amp; Examples</h3>` + paragraph +
+		`<h2>Getting Started</h2>` + paragraph +
+		`</article></body></html>`
+
+	ps := NewParser(WithSections(true))
+	article, err := ps.Parse(strings.NewReader(input), fakeHostURL)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	expected := []Section{
+		{HeadingLevel: 0},
+		{HeadingLevel: 2, HeadingText: "Getting Started", AnchorID: "getting-started"},
+		{HeadingLevel: 3, HeadingText: "Install it", AnchorID: "install"},
+		{HeadingLevel: 3, HeadingText: "Usage & Examples", AnchorID: "usage-examples"},
+		{HeadingLevel: 2, HeadingText: "Getting Started", AnchorID: "getting-started-2"},
+	}
+
+	if len(article.Sections) != len(expected) {
+		t.Fatalf("want %d sections, got %d: %+v", len(expected), len(article.Sections), article.Sections)
+	}
+
+	for i, section := range article.Sections {
+		if !strings.HasPrefix(section.HTML, "<p>This is a sentence") || strings.Count(section.HTML, "<p>") != 1 {
+			t.Errorf("section %d should contain one paragraph, got %q", i, section.HTML)
+		}
+
+		section.HTML = ""
+		if section != expected[i] {
+			t.Errorf("section %d, want %+v got %+v", i, expected[i], section)
+		}
+	}
+
+	// Generated anchors should be put in the content
+	if !strings.Contains(article.Content, `<h2 id="getting-started-2">`) {
+		t.Errorf("content should contain the generated anchor, got %q", article.Content)
+	}
+
+	// Sections are only extracted when requested
+	ps = NewParser()
+	article, err = ps.Parse(strings.NewReader(input), fakeHostURL)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	if article.Sections != nil || strings.Contains(article.Content, "getting-started") {
+		t.Errorf("sections shouldn't be extracted by default")
+	}
+}
+
+func Test_Article_TableOfContents(t *testing.T) {
+	article := Article{Sections: []Section{
+		{HeadingLevel: 0},
+		{HeadingLevel: 2, HeadingText: "A", AnchorID: "a"},
+		{HeadingLevel: 3, HeadingText: "A.1", AnchorID: "a-1"},
+		{HeadingLevel: 4, HeadingText: "A.1.1", AnchorID: "a-1-1"},
+		{HeadingLevel: 3, HeadingText: "A.2", AnchorID: "a-2"},
+		{HeadingLevel: 2, HeadingText: "B", AnchorID: "b"},
+	}}
+
+	expected := []TOCEntry{
+		{Level: 2, Text: "A", AnchorID: "a", Children: []TOCEntry{
+			{Level: 3, Text: "A.1", AnchorID: "a-1", Children: []TOCEntry{
+				{Level: 4, Text: "A.1.1", AnchorID: "a-1-1"},
+			}},
+			{Level: 3, Text: "A.2", AnchorID: "a-2"},
+		}},
+		{Level: 2, Text: "B", AnchorID: "b"},
+	}
+
+	if toc := article.TableOfContents(); !reflect.DeepEqual(toc, expected) {
+		t.Errorf("\n"+
+			"want : %+v\n"+
+			"got  : %+v", expected, toc)
+	}
+}
+
+func Test_Parser_NormalizeHeadings(t *testing.T) {
+	paragraph := articleParagraph(10)
+	input := `<html><body><article>` +
+		`<h1>One</h1>` + paragraph +
+		`<h4>Two</h4>` + paragraph +
+		`<h3>Three</h3>` + paragraph +
+		`<h1>Four</h1>` + paragraph +
+		`<h2>Five</h2>` + paragraph +
+		`</article></body></html>`
+
+	scenarios := map[int]string{
+		0: "h2,h4,h3,h2,h2",
+		2: "h2,h3,h3,h2,h3",
+		5: "h5,h6,h6,h5,h6",
+	}
+
+	for baseLevel, expected := range scenarios {
+		ps := NewParser(WithNormalizeHeadings(baseLevel))
+		article, err := ps.Parse(strings.NewReader(input), fakeHostURL)
+		if err != nil {
+			t.Fatalf("failed to parse: %v", err)
+		}
+
+		var tags []string
+		for _, heading := range dom.QuerySelectorAll(article.ContentNode(), "h1,h2,h3,h4,h5,h6") {
+			tags = append(tags, dom.TagName(heading))
+		}
+
+		if got := strings.Join(tags, ","); got != expected {
+			t.Errorf("base level %d, want %q got %q", baseLevel, expected, got)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package readability
+
+import (
+	"strings"
+	"unicode"
+)
+
+// abbreviations is the abbreviations of languages that usually followed
+// by a period, but don't end the sentence. They are written in lowercase
+// without the last period.
+var abbreviations = map[string][]string{
+	"en": {"mr", "mrs", "ms", "dr", "prof", "sr", "jr", "st", "vs", "e.g", "i.e", "inc", "ltd", "co", "corp",
+		"jan", "feb", "mar", "apr", "jun", "jul", "aug", "sep", "sept", "oct", "nov", "dec", "no", "fig",
+		"approx", "dept", "gen", "gov", "lt", "col", "sgt", "capt", "rev", "u.s", "u.k"},
+	"de": {"z.b", "usw", "bzw", "ca", "dr", "prof", "nr", "str", "vgl", "evtl", "ggf", "d.h", "inkl", "hr", "fr"},
+	"fr": {"m", "mme", "mlle", "dr", "p.ex", "cf", "av", "bd"},
+	"es": {"sr", "sra", "srta", "dr", "dra", "ud", "uds", "pág", "avda"},
+	"it": {"sig", "sigg", "dott", "prof", "pag", "ecc"},
+	"pt": {"sr", "sra", "dr", "dra", "pág", "av"},
+	"nl": {"dhr", "mevr", "bijv", "nr", "enz", "blz"},
+}
+
+// fullStops is the sentence terminators that don't need to be followed by
+// space, e.g. the ideographic full stop that used in CJK text.
+var fullStops = map[rune]struct{}{
+	'。': {}, '！': {}, '？': {}, '．': {}, '｡': {}, '।': {}, '॥': {}, '؟': {}, '۔': {},
+}
+
+// closingPunctuation is the punctuations that may follow the terminator
+// of a sentence, but still belong to the sentence.
+const closingPunctuation = `"')]}»”’」』）】`
+
+// Sentence is a sentence inside a text.
+type Sentence struct {
+	// Text is the text of the sentence, with its whitespaces collapsed.
+	Text string `json:"text"`
+	// Start and End is the byte offsets of the sentence in the original
+	// text, so text[Start:End] is the sentence as it's written.
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// Sentences splits the text content of the article into sentences, using
+// the rules of its language.
+func (article Article) Sentences() []Sentence {
+	return SplitSentences(article.TextContent, article.Language)
+}
+
+// SplitSentences splits text into sentences, using the rules of language
+// which is a BCP 47 tag like "en" or "de-AT". Blank lines always end the
+// sentence. The abbreviations of the language, initials and decimal
+// numbers don't end the sentence, while the CJK full stops end it even
+// when there are no space after them.
+func SplitSentences(text, language string) []Sentence {
+	language = strings.ToLower(strings.SplitN(language, "-", 2)[0])
+	abbrs := make(map[string]struct{})
+	for _, abbr := range abbreviations["en"] {
+		abbrs[abbr] = struct{}{}
+	}
+	for _, abbr := range abbreviations[language] {
+		abbrs[abbr] = struct{}{}
+	}
+
+	var sentences []Sentence
+	start := 0
+	for {
+		breakStart, breakEnd := nextParagraphBreak(text[start:])
+		if breakStart < 0 {
+			break
+		}
+
+		sentences = append(sentences, splitParagraph(text[start:start+breakStart], start, abbrs)...)
+		start += breakEnd
+	}
+	return append(sentences, splitParagraph(text[start:], start, abbrs)...)
+}
+
+// splitParagraph splits a paragraph of text into sentences. The offset is
+// the position of paragraph in the original text.
+func splitParagraph(text string, offset int, abbrs map[string]struct{}) []Sentence {
+	runes := make([]rune, 0, len(text))
+	offsets := make([]int, 0, len(text)+1)
+	for i, r := range text {
+		runes = append(runes, r)
+		offsets = append(offsets, offset+i)
+	}
+	offsets = append(offsets, offset+len(text))
+
+	var sentences []Sentence
+	start := 0
+	flush := func(end int) {
+		first, last := start, end
+		for first < last && unicode.IsSpace(runes[first]) {
+			first++
+		}
+		for last > first && unicode.IsSpace(runes[last-1]) {
+			last--
+		}
+
+		if first < last {
+			sentences = append(sentences, Sentence{
+				Text:  strings.Join(strings.Fields(string(runes[first:last])), " "),
+				Start: offsets[first],
+				End:   offsets[last],
+			})
+		}
+		start = end
+	}
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+
+		_, isFullStop := fullStops[r]
+		if !isFullStop && r != '.' && r != '!' && r != '?' && r != '…' {
+			continue
+		}
+
+		// Include the repeated terminators and the closing punctuations
+		end := i + 1
+		for end < len(runes) && (strings.ContainsRune(".!?…", runes[end]) ||
+			strings.ContainsRune(closingPunctuation, runes[end])) {
+			end++
+		}
+
+		if !isFullStop {
+			// Sentence must be followed by space, then the next sentence
+			// must not start in lowercase
+			if end < len(runes) && !unicode.IsSpace(runes[end]) {
+				i = end - 1
+				continue
+			}
+
+			next := nextNonSpace(runes, end)
+			if next >= 0 && unicode.IsLower(runes[next]) {
+				i = end - 1
+				continue
+			}
+
+			if r == '.' && end == i+1 && isAbbreviation(runes[start:i], abbrs) {
+				continue
+			}
+		}
+
+		flush(end)
+		i = end - 1
+	}
+
+	flush(len(runes))
+	return sentences
+}
+
+// isAbbreviation checks if the last word of text is an abbreviation or
+// an initial, so the period after it doesn't end the sentence.
+func isAbbreviation(text []rune, abbrs map[string]struct{}) bool {
+	wordStart := len(text)
+	for wordStart > 0 && !unicode.IsSpace(text[wordStart-1]) &&
+		!strings.ContainsRune(`"'([{«“‘`, text[wordStart-1]) {
+		wordStart--
+	}
+
+	word := text[wordStart:]
+	if len(word) == 1 && unicode.IsUpper(word[0]) {
+		return true
+	}
+
+	_, exist := abbrs[strings.ToLower(string(word))]
+	return exist
+}
+
+// nextNonSpace returns the index of the first non space rune in runes
+// since start, or -1 if there are none.
+func nextNonSpace(runes []rune, start int) int {
+	for i := start; i < len(runes); i++ {
+		if !unicode.IsSpace(runes[i]) {
+			return i
+		}
+	}
+	return -1
+}
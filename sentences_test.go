@@ -0,0 +1,64 @@
+package readability
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_SplitSentences(t *testing.T) {
+	scenarios := []struct {
+		text     string
+		language string
+		expected []string
+	}{{
+		text:     "Dr. Smith arrived at 3.30 p.m. today. He met Mr. J. Doe! Was it planned? Nobody knows…",
+		language: "en",
+		expected: []string{"Dr. Smith arrived at 3.30 p.m. today.", "He met Mr. J. Doe!", "Was it planned?", "Nobody knows…"},
+	}, {
+		text:     `He said "stop." Then he left.`,
+		language: "en",
+		expected: []string{`He said "stop."`, "Then he left."},
+	}, {
+		text:     "Das ist z.B. ein Test. Er kam ca. um acht.",
+		language: "de-AT",
+		expected: []string{"Das ist z.B. ein Test.", "Er kam ca. um acht."},
+	}, {
+		text:     "今日は晴れです。明日は雨でしょうか？そうですね！",
+		language: "ja",
+		expected: []string{"今日は晴れです。", "明日は雨でしょうか？", "そうですね！"},
+	}, {
+		text:     "A heading\n\nThe first\n  paragraph",
+		language: "en",
+		expected: []string{"A heading", "The first paragraph"},
+	}}
+
+	for _, scenario := range scenarios {
+		var got []string
+		for _, sentence := range SplitSentences(scenario.text, scenario.language) {
+			got = append(got, sentence.Text)
+		}
+
+		if !reflect.DeepEqual(got, scenario.expected) {
+			t.Errorf("\n"+
+				"text : %q\n"+
+				"want : %q\n"+
+				"got  : %q", scenario.text, scenario.expected, got)
+		}
+	}
+}
+
+func Test_SplitSentences_Offsets(t *testing.T) {
+	text := "  Première phrase.  Deuxième\nphrase !\n\n日本語です。次"
+	sentences := SplitSentences(text, "fr")
+
+	expected := []string{"Première phrase.", "Deuxième\nphrase !", "日本語です。", "次"}
+	if len(sentences) != len(expected) {
+		t.Fatalf("want %d sentences, got %+v", len(expected), sentences)
+	}
+
+	for i, sentence := range sentences {
+		if got := text[sentence.Start:sentence.End]; got != expected[i] {
+			t.Errorf("sentence %d: want %q, got %q", i, expected[i], got)
+		}
+	}
+}
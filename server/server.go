@@ -0,0 +1,294 @@
+// Package server exposes go-readability as HTTP service, so it can be
+// used as sidecar service by applications that not written in Go.
+//
+// The service has a single endpoint, /parse:
+//
+//   - GET /parse?url=... fetches the web page from the specified URL
+//     then returns its readable content.
+//   - POST /parse?url=... parses the HTML document in request body. The
+//     url is optional, and used to convert relative URIs in the content
+//     into absolute.
+//
+// In both cases the response is the article encoded as JSON, or an
+// object with "error" field if the page can't be parsed.
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	nurl "net/url"
+	"sync"
+	"syscall"
+	"time"
+
+	readability "github.com/go-shiori/go-readability"
+)
+
+// DefaultMaxBodyBytes is the default max size of request body.
+const DefaultMaxBodyBytes = 10 << 20
+
+// DefaultMaxFetchBytes is the default max size of the fetched web page.
+const DefaultMaxFetchBytes = 10 << 20
+
+// ErrForbiddenAddress is returned when the web page is hosted on address
+// that's not public, e.g. loopback or private network, while it's not
+// allowed by Server.AllowPrivateNetworks.
+var ErrForbiddenAddress = errors.New("address is not allowed")
+
+// nonPublicNetworks are the networks that are not public, but not covered
+// by the methods of net.IP: "this network", which reaches the local host
+// on some systems, and the carrier-grade NAT.
+var nonPublicNetworks = []*net.IPNet{
+	{IP: net.IPv4(0, 0, 0, 0), Mask: net.CIDRMask(8, 32)},
+	{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)},
+}
+
+// Server is HTTP handler that serves the readable content of web page.
+// The zero value is ready to use.
+type Server struct {
+	// Client is the HTTP client that used to fetch the web page. If nil,
+	// http.DefaultClient will be used. Unless AllowPrivateNetworks is true,
+	// its transport must be nil or *http.Transport, which is copied so it
+	// only connects to the public addresses, and proxy is not used.
+	Client *http.Client
+	// Parser is the parser that used to parse the web page. If nil, a
+	// parser with default value will be used.
+	Parser *readability.Parser
+	// MaxConcurrency is the max number of pages that parsed at the same
+	// time. The other requests will wait until there is a free slot, or
+	// until the request is canceled. Default: 0 (no limit).
+	MaxConcurrency int
+	// MaxBodyBytes is the max size of request body for POST request.
+	// Default: 0 (use DefaultMaxBodyBytes).
+	MaxBodyBytes int64
+	// MaxFetchBytes is the max size of the web page that fetched for GET
+	// request, after it's decoded. Default: 0 (use DefaultMaxFetchBytes).
+	MaxFetchBytes int64
+	// AllowPrivateNetworks determines whether the web page can be fetched
+	// from loopback, private, link-local and other addresses that are not
+	// public. Since the URL is given by the caller, it must only be enabled
+	// when the callers are trusted. Default: false.
+	AllowPrivateNetworks bool
+	// Logger is used to log the failed requests. If nil, nothing will
+	// be logged.
+	Logger *log.Logger
+
+	semOnce sync.Once
+	sem     chan struct{}
+
+	clientOnce sync.Once
+	client     *http.Client
+	clientErr  error
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/parse" {
+		s.writeError(w, http.StatusNotFound, fmt.Errorf("path %s is not found", r.URL.Path))
+		return
+	}
+
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		w.Header().Set("Allow", "GET, POST")
+		s.writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s is not allowed", r.Method))
+		return
+	}
+
+	// Wait for a free slot
+	if release, acquired := s.acquire(r); acquired {
+		defer release()
+	} else {
+		s.writeError(w, http.StatusServiceUnavailable, r.Context().Err())
+		return
+	}
+
+	var article readability.Article
+	var status int
+	var err error
+	if r.Method == http.MethodGet {
+		article, status, err = s.parseURL(r)
+	} else {
+		article, status, err = s.parseBody(w, r)
+	}
+
+	if err != nil {
+		s.writeError(w, status, err)
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, article)
+}
+
+// parseURL fetches and parses the web page in "url" query parameter.
+func (s *Server) parseURL(r *http.Request) (readability.Article, int, error) {
+	pageURL := r.URL.Query().Get("url")
+	if pageURL == "" {
+		return readability.Article{}, http.StatusBadRequest, fmt.Errorf("url is required")
+	}
+
+	client, err := s.fetchClient()
+	if err != nil {
+		return readability.Article{}, http.StatusInternalServerError, err
+	}
+
+	maxFetchBytes := s.MaxFetchBytes
+	if maxFetchBytes <= 0 {
+		maxFetchBytes = DefaultMaxFetchBytes
+	}
+
+	fetcher := readability.Fetcher{Client: client, Parser: s.Parser, MaxBodyBytes: maxFetchBytes}
+	article, err := fetcher.Fetch(r.Context(), pageURL)
+	if errors.Is(err, ErrForbiddenAddress) {
+		return readability.Article{}, http.StatusForbidden, err
+	}
+	if err != nil {
+		return readability.Article{}, http.StatusBadGateway, err
+	}
+
+	return article, http.StatusOK, nil
+}
+
+// fetchClient returns the HTTP client that used to fetch the web page.
+// Unless private networks are allowed, its transport refuses to connect
+// to the address that's not public. The address is checked once it's
+// resolved, so it also covers the redirects and the DNS rebinding.
+func (s *Server) fetchClient() (*http.Client, error) {
+	s.clientOnce.Do(func() {
+		client := s.Client
+		if client == nil {
+			client = http.DefaultClient
+		}
+
+		if s.AllowPrivateNetworks {
+			s.client = client
+			return
+		}
+
+		var transport *http.Transport
+		switch t := client.Transport.(type) {
+		case nil:
+			transport = http.DefaultTransport.(*http.Transport).Clone()
+		case *http.Transport:
+			transport = t.Clone()
+		default:
+			s.clientErr = fmt.Errorf("transport %T can't be restricted to public addresses", t)
+			return
+		}
+
+		// The proxy would be dialed instead of the web page, so the
+		// address of page can't be checked
+		dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second, Control: checkPublicAddress}
+		transport.Proxy = nil
+		transport.DialContext = dialer.DialContext
+
+		guarded := *client
+		guarded.Transport = transport
+		s.client = &guarded
+	})
+	return s.client, s.clientErr
+}
+
+// checkPublicAddress is the control function of dialer, which fails when
+// the resolved address is not public.
+func checkPublicAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil || !isPublicIP(ip) {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, host)
+	}
+	return nil
+}
+
+// isPublicIP checks if ip is a public unicast address.
+func isPublicIP(ip net.IP) bool {
+	if ip.IsUnspecified() || ip.IsLoopback() || ip.IsPrivate() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+
+	for _, network := range nonPublicNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// parseBody parses the HTML document in request body.
+func (s *Server) parseBody(w http.ResponseWriter, r *http.Request) (readability.Article, int, error) {
+	var pageURL *nurl.URL
+	if strURL := r.URL.Query().Get("url"); strURL != "" {
+		var err error
+		pageURL, err = nurl.ParseRequestURI(strURL)
+		if err != nil {
+			return readability.Article{}, http.StatusBadRequest, fmt.Errorf("failed to parse URL: %v", err)
+		}
+	}
+
+	maxBodyBytes := s.MaxBodyBytes
+	if maxBodyBytes <= 0 {
+		maxBodyBytes = DefaultMaxBodyBytes
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	if err != nil {
+		return readability.Article{}, http.StatusRequestEntityTooLarge, fmt.Errorf("failed to read body: %v", err)
+	}
+
+	parser := readability.NewParser()
+	if s.Parser != nil {
+		parser = *s.Parser
+	}
+
+	article, err := parser.ParseWithContext(r.Context(), bytes.NewReader(body), pageURL)
+	if err != nil {
+		return readability.Article{}, http.StatusUnprocessableEntity, err
+	}
+
+	return article, http.StatusOK, nil
+}
+
+// acquire waits until there is a free slot to process the request. The
+// returned function must be called to release the slot. Returns false
+// if the request is canceled before the slot is available.
+func (s *Server) acquire(r *http.Request) (func(), bool) {
+	if s.MaxConcurrency <= 0 {
+		return func() {}, true
+	}
+
+	s.semOnce.Do(func() {
+		s.sem = make(chan struct{}, s.MaxConcurrency)
+	})
+
+	select {
+	case s.sem <- struct{}{}:
+		return func() { <-s.sem }, true
+	case <-r.Context().Done():
+		return nil, false
+	}
+}
+
+func (s *Server) writeError(w http.ResponseWriter, status int, err error) {
+	if s.Logger != nil {
+		s.Logger.Printf("request failed: %v\n", err)
+	}
+
+	s.writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+func (s *Server) writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,147 @@
+package server
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	readability "github.com/go-shiori/go-readability"
+)
+
+func readTestPage(t *testing.T) []byte {
+	source, err := os.ReadFile("../test-pages/001/source.html")
+	if err != nil {
+		t.Fatalf("failed to read source: %v", err)
+	}
+	return source
+}
+
+func decodeArticle(t *testing.T, resp *httptest.ResponseRecorder) readability.Article {
+	if resp.Code != http.StatusOK {
+		t.Fatalf("status, want %d got %d: %s", http.StatusOK, resp.Code, resp.Body.String())
+	}
+
+	var article readability.Article
+	if err := json.NewDecoder(resp.Body).Decode(&article); err != nil {
+		t.Fatalf("failed to decode article: %v", err)
+	}
+	return article
+}
+
+func Test_Server_Post(t *testing.T) {
+	source := readTestPage(t)
+	server := &Server{MaxConcurrency: 1}
+
+	req := httptest.NewRequest("POST", "/parse?url=http://fakehost/test/page.html", strings.NewReader(string(source)))
+	resp := httptest.NewRecorder()
+	server.ServeHTTP(resp, req)
+
+	article := decodeArticle(t, resp)
+	if article.Title == "" || article.Content == "" {
+		t.Errorf("article should have title and content")
+	}
+}
+
+func Test_Server_Get(t *testing.T) {
+	source := readTestPage(t)
+	pageServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write(source)
+	}))
+	defer pageServer.Close()
+
+	// The test server is on loopback, so it must be allowed explicitly
+	server := &Server{AllowPrivateNetworks: true}
+	req := httptest.NewRequest("GET", "/parse?url="+url.QueryEscape(pageServer.URL), nil)
+	resp := httptest.NewRecorder()
+	server.ServeHTTP(resp, req)
+
+	article := decodeArticle(t, resp)
+	if article.Title == "" || article.Content == "" {
+		t.Errorf("article should have title and content")
+	}
+
+	// Missing URL should be a bad request
+	req = httptest.NewRequest("GET", "/parse", nil)
+	resp = httptest.NewRecorder()
+	server.ServeHTTP(resp, req)
+	if resp.Code != http.StatusBadRequest {
+		t.Errorf("status, want %d got %d", http.StatusBadRequest, resp.Code)
+	}
+}
+
+func Test_Server_PrivateNetworks(t *testing.T) {
+	source := readTestPage(t)
+	requested := false
+	pageServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = true
+		w.Header().Set("Content-Type", "text/html")
+		w.Write(source)
+	}))
+	defer pageServer.Close()
+
+	// By default, the page on loopback is never requested
+	server := &Server{}
+	req := httptest.NewRequest("GET", "/parse?url="+url.QueryEscape(pageServer.URL), nil)
+	resp := httptest.NewRecorder()
+	server.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusForbidden || requested {
+		t.Errorf("status, want %d got %d: %s", http.StatusForbidden, resp.Code, resp.Body.String())
+	}
+
+	// The custom transport that can't be restricted is refused as well
+	server = &Server{Client: &http.Client{Transport: http.NewFileTransport(http.Dir("."))}}
+	resp = httptest.NewRecorder()
+	server.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusInternalServerError {
+		t.Errorf("status, want %d got %d: %s", http.StatusInternalServerError, resp.Code, resp.Body.String())
+	}
+
+	scenarios := map[string]bool{
+		"8.8.8.8":          true,
+		"2606:4700::1111":  true,
+		"127.0.0.1":        false,
+		"10.1.2.3":         false,
+		"172.16.0.1":       false,
+		"192.168.1.1":      false,
+		"169.254.169.254":  false,
+		"100.64.0.1":       false,
+		"0.0.0.0":          false,
+		"0.1.2.3":          false,
+		"::1":              false,
+		"fe80::1":          false,
+		"fc00::1":          false,
+		"::ffff:127.0.0.1": false,
+	}
+
+	for ip, expected := range scenarios {
+		if isPublic := isPublicIP(net.ParseIP(ip)); isPublic != expected {
+			t.Errorf("%s: want public %v, got %v", ip, expected, isPublic)
+		}
+	}
+}
+
+func Test_Server_MaxFetchBytes(t *testing.T) {
+	source := readTestPage(t)
+	pageServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write(source)
+	}))
+	defer pageServer.Close()
+
+	server := &Server{AllowPrivateNetworks: true, MaxFetchBytes: 100}
+	req := httptest.NewRequest("GET", "/parse?url="+url.QueryEscape(pageServer.URL), nil)
+	resp := httptest.NewRecorder()
+	server.ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusBadGateway || !strings.Contains(resp.Body.String(), "too large") {
+		t.Errorf("status, want %d got %d: %s", http.StatusBadGateway, resp.Code, resp.Body.String())
+	}
+}
@@ -0,0 +1,110 @@
+package readability
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/go-shiori/dom"
+	"golang.org/x/net/html"
+)
+
+// DefaultSharePatterns is the class names and ids of share buttons and
+// social widgets, e.g. Jetpack's sharedaddy and AddToAny.
+var DefaultSharePatterns = []string{"share", "sharedaddy", "addtoany", "a2a_kit", "sharethis", "shariff", "ssba"}
+
+// shareBarMaxLength is the max length of text in icon-only share bar, which
+// usually only has a label like "Share this:".
+const shareBarMaxLength = 30
+
+var (
+	rxShareElements = sharePatternRegexp(DefaultSharePatterns)
+	rxShareURL      = regexp.MustCompile(`(?i)^((https?:)?//([a-z0-9-]+\.)*` +
+		`(facebook\.com/(sharer|share\.php|dialog/(share|feed))|twitter\.com/(intent|share)|x\.com/intent|` +
+		`linkedin\.com/(sharearticle|share|cws/share)|pinterest\.[a-z.]+/pin/create|reddit\.com/submit|` +
+		`tumblr\.com/(share|widgets/share)|api\.whatsapp\.com/send|wa\.me/\?|t(elegram)?\.me/share|` +
+		`getpocket\.com/(save|edit)|news\.ycombinator\.com/submitlink|vk\.com/share\.php|` +
+		`threads\.net/intent|bsky\.app/intent)|mailto:\?)`)
+)
+
+// sharePatternRegexp returns the regex that matches the patterns as a word
+// in class name or id, where underscore is also treated as delimiter.
+func sharePatternRegexp(patterns []string) *regexp.Regexp {
+	quoted := make([]string, len(patterns))
+	for i, pattern := range patterns {
+		quoted[i] = regexp.QuoteMeta(pattern)
+	}
+	return regexp.MustCompile(`(?i)(\b|_)(` + strings.Join(quoted, "|") + `)(\b|_)`)
+}
+
+// shareElements returns the regex of share element patterns of parser.
+func (ps *Parser) shareElements() *regexp.Regexp {
+	switch {
+	case ps.SharePatterns == nil:
+		return rxShareElements
+	case len(ps.SharePatterns) == 0:
+		return nil
+	default:
+		return sharePatternRegexp(ps.SharePatterns)
+	}
+}
+
+// removeShareBars removes the share bars that don't have share class, which
+// are detected from their links: each of them either points to the share
+// endpoint of social media, or only shows an icon from SVG, icon font or
+// CSS sprite. Otherwise those bars leak into content as empty links.
+func (ps *Parser) removeShareBars(articleContent *html.Node) {
+	var bars []*html.Node
+	for _, link := range ps.getAllNodesWithTag(articleContent, "a") {
+		if !ps.isShareLink(link) {
+			continue
+		}
+
+		// Climb up to the outermost share bar, passing through the
+		// wrapper of each link, e.g. the list item
+		var bar *html.Node
+		for node := link.Parent; node != nil && !isPageContainer(node, articleContent); node = node.Parent {
+			links := ps.getAllNodesWithTag(node, "a")
+			if !ps.isShareBar(node, links) {
+				break
+			}
+			if len(links) >= 2 {
+				bar = node
+			}
+		}
+
+		if bar != nil && (len(bars) == 0 || bars[len(bars)-1] != bar) {
+			bars = append(bars, bar)
+		}
+	}
+
+	for _, bar := range bars {
+		if bar.Parent != nil {
+			ps.diagnoseRemoval(bar, RemovedShareElement)
+			bar.Parent.RemoveChild(bar)
+		}
+	}
+}
+
+// isShareBar checks if all links in node are share links. Other than the
+// links, it may only have a short label, but not the media or the date
+// that usually put next to the share bar.
+func (ps *Parser) isShareBar(node *html.Node, links []*html.Node) bool {
+	if !ps.everyNode(links, ps.isShareLink) {
+		return false
+	}
+
+	content := ps.getAllNodesWithTag(node, "img", "picture", "video", "audio", "iframe", "time")
+	return len(content) == 0 && ps.getTextLength(node) <= shareBarMaxLength
+}
+
+// isShareLink checks if the link points to share endpoint, or it's an icon
+// without any text. Link with image is not counted as icon, since it may
+// be the thumbnail of gallery.
+func (ps *Parser) isShareLink(link *html.Node) bool {
+	if rxShareURL.MatchString(strings.TrimSpace(dom.GetAttribute(link, "href"))) {
+		return true
+	}
+
+	return ps.getInnerText(link, true) == "" &&
+		len(ps.getAllNodesWithTag(link, "img", "picture", "video", "audio", "iframe")) == 0
+}
@@ -0,0 +1,80 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_Parser_removeShareBars(t *testing.T) {
+	paragraph := articleParagraph(10)
+	icon := `<svg width="16" height="16"><use href="#icon"></use></svg>`
+	input := `<html><body><article>` + paragraph +
+		`<ul class="post-tools"><li><a href="/u/1">` + icon + `</a></li><li><a href="/u/2"><i class="fa"></i></a></li>` +
+		`<li><a href="/u/3"><span class="sprite"></span></a></li></ul>` +
+		`<div><span>Share:</span> <a href="https://www.facebook.com/sharer/sharer.php?u=x">Facebook</a> ` +
+		`<a href="https://twitter.com/intent/tweet?url=x">Tweet</a></div>` +
+		`<div class="gallery"><a href="/a.jpg"><img src="/a.jpg"></a><a href="/b.jpg"><img src="/b.jpg"></a></div>` +
+		`<div class="reader-picks"><p>Read more stories like this one.</p><p>Or browse the archive.</p></div>` +
+		paragraph + `</article></body></html>`
+
+	tests := []struct {
+		name       string
+		opts       []Option
+		expected   []string
+		unexpected []string
+	}{{
+		name:       "default",
+		expected:   []string{`src="http://fakehost/a.jpg"`, "Read more stories"},
+		unexpected: []string{"<svg", `class="fa"`, "sprite", "Facebook", "Tweet"},
+	}, {
+		name:       "custom patterns",
+		opts:       []Option{WithSharePatterns(append(DefaultSharePatterns, "reader-picks")...)},
+		expected:   []string{`src="http://fakehost/a.jpg"`},
+		unexpected: []string{"<svg", "Facebook", "Read more stories"},
+	}, {
+		name:     "disable share bar removal",
+		opts:     []Option{WithDisableShareBarRemoval(true)},
+		expected: []string{"<svg", `class="fa"`, "Read more stories"},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ps := NewParser(WithKeepClasses(true))
+			for _, opt := range test.opts {
+				opt(&ps)
+			}
+
+			article, err := ps.Parse(strings.NewReader(input), fakeHostURL)
+			if err != nil {
+				t.Fatalf("failed to parse: %v", err)
+			}
+
+			for _, expected := range test.expected {
+				if !strings.Contains(article.Content, expected) {
+					t.Errorf("content should contain %q: %s", expected, article.Content)
+				}
+			}
+
+			for _, unexpected := range test.unexpected {
+				if strings.Contains(article.Content, unexpected) {
+					t.Errorf("content should not contain %q: %s", unexpected, article.Content)
+				}
+			}
+		})
+	}
+}
+
+func Test_sharePatternRegexp(t *testing.T) {
+	rx := sharePatternRegexp(DefaultSharePatterns)
+	for _, matched := range []string{"share", "post-share buttons", "wp_share", "sharedaddy sd-block", "a2a_kit"} {
+		if !rx.MatchString(matched) {
+			t.Errorf("%q should be matched", matched)
+		}
+	}
+
+	for _, unmatched := range []string{"shared-content", "shareholders", "timeshare"} {
+		if rx.MatchString(unmatched) {
+			t.Errorf("%q should not be matched", unmatched)
+		}
+	}
+}
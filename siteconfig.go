@@ -0,0 +1,489 @@
+package readability
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/andybalholm/cascadia"
+)
+
+// ParseSiteConfig parses a site config in the format of FiveFilters Full-Text
+// RSS (ftr-site-config) into Rule. The directives that supported are "body",
+// "strip", "strip_id_or_class", "strip_image_src", "title", "author",
+// "date", "next_page_link" and "single_page_link", while the others are
+// ignored. Their XPath expressions are converted into CSS selectors, and
+// the expressions that can't be converted are skipped.
+func ParseSiteConfig(r io.Reader) (Rule, error) {
+	var rule Rule
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		// Value of these directives is not an XPath
+		switch key {
+		case "strip_id_or_class":
+			value = strings.Trim(value, `"'`)
+			if value != "" {
+				rule.Strip = append(rule.Strip, fmt.Sprintf("[id*=%s], [class*=%s]", cssString(value), cssString(value)))
+			}
+			continue
+		case "strip_image_src":
+			value = strings.Trim(value, `"'`)
+			if value != "" {
+				rule.Strip = append(rule.Strip, fmt.Sprintf("img[src*=%s]", cssString(value)))
+			}
+			continue
+		}
+
+		var target *[]string
+		switch key {
+		case "body":
+			target = &rule.Content
+		case "strip":
+			target = &rule.Strip
+		case "title":
+			target = &rule.Title
+		case "author":
+			target = &rule.Byline
+		case "date":
+			target = &rule.PublishedTime
+		case "next_page_link":
+			target = &rule.NextPage
+		case "single_page_link":
+			target = &rule.SinglePageLink
+		default:
+			continue
+		}
+
+		selector, err := xpathToCSS(value)
+		if err != nil {
+			continue
+		}
+		if _, err = cascadia.ParseGroup(selector); err != nil {
+			continue
+		}
+		*target = append(*target, selector)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return Rule{}, fmt.Errorf("failed to read site config: %v", err)
+	}
+	return rule, nil
+}
+
+// LoadSiteConfigDir loads the FiveFilters site configs in dir into the
+// registry. See Rules.LoadSiteConfigFS for the naming of the files.
+func (r *Rules) LoadSiteConfigDir(dir string) error {
+	return r.LoadSiteConfigFS(os.DirFS(dir))
+}
+
+// LoadSiteConfigFS loads the FiveFilters site configs in the root of fsys
+// into the registry. Just like in ftr-site-config, each config is named
+// after its hostname, e.g. "example.com.txt", and it's used for the host
+// and its subdomains. Other files are ignored.
+func (r *Rules) LoadSiteConfigFS(fsys fs.FS) error {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return fmt.Errorf("failed to read site config directory: %v", err)
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		host := strings.TrimPrefix(strings.TrimSuffix(name, ".txt"), ".")
+		if entry.IsDir() || path.Ext(name) != ".txt" || !strings.Contains(host, ".") {
+			continue
+		}
+
+		f, err := fsys.Open(name)
+		if err != nil {
+			return fmt.Errorf("failed to open site config %s: %v", name, err)
+		}
+
+		rule, err := ParseSiteConfig(f)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("failed to parse site config %s: %v", name, err)
+		}
+
+		r.Register(host, rule)
+	}
+
+	return nil
+}
+
+// xpathFunctions is the XPath functions that supported in predicate, along
+// with the CSS attribute operator that does the same.
+var xpathFunctions = []struct {
+	name     string
+	operator string
+}{
+	{"contains", "*="},
+	{"starts-with", "^="},
+	{"ends-with", "$="},
+}
+
+// xpathParser converts XPath expression into CSS selector. Only the subset
+// that commonly used in site configs is supported: the child and descendant
+// steps, and the predicates that test attributes, text and position.
+type xpathParser struct {
+	expr string
+	pos  int
+}
+
+// xpathToCSS converts the XPath expression into CSS selector. The trailing
+// attribute or text() step is dropped, since the value of matching element
+// is taken from its attribute or text anyway.
+func xpathToCSS(expr string) (string, error) {
+	p := &xpathParser{expr: strings.TrimSpace(expr)}
+
+	var selectors []string
+	for {
+		alternatives, err := p.path()
+		if err != nil {
+			return "", err
+		}
+		selectors = append(selectors, alternatives...)
+
+		p.skipSpace()
+		if p.done() {
+			break
+		}
+		if !p.consume("|") {
+			return "", p.errorf("unexpected character")
+		}
+	}
+
+	return strings.Join(selectors, ", "), nil
+}
+
+// path parses a location path, returning the alternatives of selector
+// since "or" in predicate can only be written as several selectors.
+func (p *xpathParser) path() ([]string, error) {
+	// The root of document is always the ancestor of element, so the
+	// path from root can be treated as the path from anywhere
+	p.skipSpace()
+	_ = p.consume(".//") || p.consume("//") || p.consume("/")
+	combinator := ""
+
+	selectors := []string{""}
+	for {
+		// The attribute and text steps can only be the last step
+		if p.consume("@") {
+			p.name()
+			return selectors, nil
+		}
+		if p.consume("text()") {
+			return selectors, nil
+		}
+
+		name := p.name()
+		if name == "" {
+			return nil, p.errorf("expected element name")
+		}
+		if strings.Contains(name, "::") {
+			return nil, p.errorf("axis is not supported")
+		}
+
+		steps := []string{name}
+		for p.consume("[") {
+			predicates, err := p.or(name)
+			if err != nil {
+				return nil, err
+			}
+			if p.skipSpace(); !p.consume("]") {
+				return nil, p.errorf("expected ]")
+			}
+			steps = crossJoin(steps, predicates, "")
+		}
+
+		selectors = crossJoin(selectors, steps, combinator)
+
+		switch {
+		case p.consume("//"):
+			combinator = " "
+		case p.consume("/"):
+			combinator = " > "
+		default:
+			return selectors, nil
+		}
+	}
+}
+
+// or parses predicate expression that joined by "or".
+func (p *xpathParser) or(name string) ([]string, error) {
+	alternatives, err := p.and(name)
+	if err != nil {
+		return nil, err
+	}
+
+	for p.consumeWord("or") {
+		right, err := p.and(name)
+		if err != nil {
+			return nil, err
+		}
+		alternatives = append(alternatives, right...)
+	}
+	return alternatives, nil
+}
+
+// and parses predicate expression that joined by "and".
+func (p *xpathParser) and(name string) ([]string, error) {
+	alternatives, err := p.condition(name)
+	if err != nil {
+		return nil, err
+	}
+
+	for p.consumeWord("and") {
+		right, err := p.condition(name)
+		if err != nil {
+			return nil, err
+		}
+		alternatives = crossJoin(alternatives, right, "")
+	}
+	return alternatives, nil
+}
+
+// condition parses a single condition inside predicate.
+func (p *xpathParser) condition(name string) ([]string, error) {
+	p.skipSpace()
+	switch {
+	case p.consume("("):
+		alternatives, err := p.or(name)
+		if err != nil {
+			return nil, err
+		}
+		if p.skipSpace(); !p.consume(")") {
+			return nil, p.errorf("expected )")
+		}
+		return alternatives, nil
+
+	case p.consumeWord("not") && p.consume("("):
+		alternatives, err := p.or(name)
+		if err != nil {
+			return nil, err
+		}
+		if p.skipSpace(); !p.consume(")") {
+			return nil, p.errorf("expected )")
+		}
+
+		// not(a or b) is the same as not(a) and not(b)
+		var sb strings.Builder
+		for _, alternative := range alternatives {
+			sb.WriteString(":not(*" + alternative + ")")
+		}
+		return []string{sb.String()}, nil
+
+	case p.consume("last()"):
+		return []string{positionSelector(name, "last")}, nil
+
+	case p.consume("@"):
+		attr := p.name()
+		if attr == "" {
+			return nil, p.errorf("expected attribute name")
+		}
+
+		p.skipSpace()
+		negate := p.consume("!=")
+		if negate || p.consume("=") {
+			value, err := p.literal()
+			if err != nil {
+				return nil, err
+			}
+
+			selector := fmt.Sprintf("[%s=%s]", attr, cssString(value))
+			if negate {
+				selector = ":not(" + selector + ")"
+			}
+			return []string{selector}, nil
+		}
+		return []string{"[" + attr + "]"}, nil
+	}
+
+	if start := p.pos; p.digits() {
+		return []string{positionSelector(name, p.expr[start:p.pos])}, nil
+	}
+
+	for _, fn := range xpathFunctions {
+		function, operator := fn.name, fn.operator
+		if !p.consumeWord(function) || !p.consume("(") {
+			continue
+		}
+
+		// The first argument is either an attribute, the text of element,
+		// or the class in space-separated form, i.e.
+		// concat(' ', normalize-space(@class), ' ')
+		p.skipSpace()
+		attr, isText, isWord := "", false, false
+		switch {
+		case p.consume("@"):
+			attr = p.name()
+		case p.consume("text()"), p.consume("."), p.consume("normalize-space()"), p.consume("normalize-space(.)"):
+			isText = true
+		case p.consume("concat(' ',normalize-space(@class),' ')"),
+			p.consume("concat(' ', normalize-space(@class), ' ')"),
+			p.consume(`concat(" ",normalize-space(@class)," ")`),
+			p.consume(`concat(" ", normalize-space(@class), " ")`):
+			attr, isWord = "class", true
+		default:
+			return nil, p.errorf("unsupported argument of " + function)
+		}
+
+		if p.skipSpace(); !p.consume(",") {
+			return nil, p.errorf("expected ,")
+		}
+		value, err := p.literal()
+		if err != nil {
+			return nil, err
+		}
+		if p.skipSpace(); !p.consume(")") {
+			return nil, p.errorf("expected )")
+		}
+
+		switch {
+		case isText && function == "contains":
+			return []string{":contains(" + cssString(value) + ")"}, nil
+		case isText:
+			return nil, p.errorf(function + " of text is not supported")
+		case isWord && function == "contains" && strings.TrimSpace(value) != "":
+			return []string{fmt.Sprintf("[class~=%s]", cssString(strings.TrimSpace(value)))}, nil
+		case attr == "":
+			return nil, p.errorf("expected attribute name")
+		default:
+			return []string{fmt.Sprintf("[%s%s%s]", attr, operator, cssString(value))}, nil
+		}
+	}
+
+	return nil, p.errorf("unsupported predicate")
+}
+
+// name parses the name of element or attribute, including "*".
+func (p *xpathParser) name() string {
+	start := p.pos
+	for !p.done() {
+		c := p.expr[p.pos]
+		if c == '*' || c == '-' || c == '_' || c == ':' ||
+			c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' {
+			p.pos++
+			continue
+		}
+		break
+	}
+	return strings.ToLower(p.expr[start:p.pos])
+}
+
+// literal parses a quoted string.
+func (p *xpathParser) literal() (string, error) {
+	p.skipSpace()
+	if p.done() || (p.expr[p.pos] != '\'' && p.expr[p.pos] != '"') {
+		return "", p.errorf("expected string")
+	}
+
+	quote := p.expr[p.pos]
+	end := strings.IndexByte(p.expr[p.pos+1:], quote)
+	if end < 0 {
+		return "", p.errorf("unterminated string")
+	}
+
+	value := p.expr[p.pos+1 : p.pos+1+end]
+	p.pos += end + 2
+	return value, nil
+}
+
+// digits parses a number.
+func (p *xpathParser) digits() bool {
+	start := p.pos
+	for !p.done() && p.expr[p.pos] >= '0' && p.expr[p.pos] <= '9' {
+		p.pos++
+	}
+	return p.pos > start
+}
+
+// consume skips s if the expression continues with it.
+func (p *xpathParser) consume(s string) bool {
+	if strings.HasPrefix(p.expr[p.pos:], s) {
+		p.pos += len(s)
+		return true
+	}
+	return false
+}
+
+// consumeWord skips the keyword word if the expression continues with it,
+// along with the spaces around it.
+func (p *xpathParser) consumeWord(word string) bool {
+	start := p.pos
+	p.skipSpace()
+	if p.consume(word) {
+		if p.done() || strings.IndexByte(" ([", p.expr[p.pos]) >= 0 {
+			p.skipSpace()
+			return true
+		}
+	}
+	p.pos = start
+	return false
+}
+
+func (p *xpathParser) skipSpace() {
+	for !p.done() && (p.expr[p.pos] == ' ' || p.expr[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+func (p *xpathParser) done() bool {
+	return p.pos >= len(p.expr)
+}
+
+func (p *xpathParser) errorf(msg string) error {
+	return fmt.Errorf("failed to convert XPath %q at %d: %s", p.expr, p.pos, msg)
+}
+
+// positionSelector returns the selector of element at position among its
+// siblings, which is either a number or "last".
+func positionSelector(name, position string) string {
+	switch {
+	case position == "last" && name == "*":
+		return ":last-child"
+	case position == "last":
+		return ":last-of-type"
+	case name == "*":
+		return ":nth-child(" + position + ")"
+	default:
+		return ":nth-of-type(" + position + ")"
+	}
+}
+
+// crossJoin returns every combination of prefixes and suffixes, joined by
+// separator.
+func crossJoin(prefixes, suffixes []string, separator string) []string {
+	var joined []string
+	for _, prefix := range prefixes {
+		for _, suffix := range suffixes {
+			if prefix == "" {
+				joined = append(joined, suffix)
+			} else {
+				joined = append(joined, prefix+separator+suffix)
+			}
+		}
+	}
+	return joined
+}
+
+// cssString quotes the value as CSS string.
+func cssString(value string) string {
+	return strconv.Quote(value)
+}
@@ -0,0 +1,132 @@
+package readability
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func Test_xpathToCSS(t *testing.T) {
+	scenarios := map[string]string{
+		`//h1`:                                  `h1`,
+		`//div[@id='content']//p`:               `div[id="content"] p`,
+		`//div[@class="body"]/p`:                `div[class="body"] > p`,
+		`//*[@itemprop='articleBody']`:          `*[itemprop="articleBody"]`,
+		`//div[contains(@class, 'entry')]`:      `div[class*="entry"]`,
+		`//a[starts-with(@href, '/print/')]`:    `a[href^="/print/"]`,
+		`//meta[@property='og:title']/@content`: `meta[property="og:title"]`,
+		`//span[@class='date']/text()`:          `span[class="date"]`,
+		`//div[@class='a' and @id='b']`:         `div[class="a"][id="b"]`,
+		`//div[@class='a' or @class='b']`:       `div[class="a"], div[class="b"]`,
+		`//div[@class='a'] | //section`:         `div[class="a"], section`,
+		`//div[not(@class)]`:                    `div:not(*[class])`,
+		`//ul/li[1]`:                            `ul > li:nth-of-type(1)`,
+		`//p[last()]`:                           `p:last-of-type`,
+		`//h2[contains(text(), 'Related')]`:     `h2:contains("Related")`,
+		`/html/body/article`:                    `html > body > article`,
+		`//div[contains(concat(' ',normalize-space(@class),' '),' post ')]`: `div[class~="post"]`,
+	}
+
+	for xpath, expected := range scenarios {
+		got, err := xpathToCSS(xpath)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", xpath, err)
+			continue
+		}
+		if got != expected {
+			t.Errorf("\n"+
+				"xpath : %q\n"+
+				"want  : %q\n"+
+				"got   : %q", xpath, expected, got)
+		}
+	}
+
+	for _, xpath := range []string{`//div/following-sibling::p`, `//div[@id='a']/..`,
+		`substring-after(//p, ':')`, `//p[position() > 1]`} {
+		if got, err := xpathToCSS(xpath); err == nil {
+			t.Errorf("%s: want error, got %q", xpath, got)
+		}
+	}
+}
+
+func Test_ParseSiteConfig(t *testing.T) {
+	config := `# Site config of example.com
+title: //h1[@class='headline']
+body: //div[@id='story']
+body: //article
+strip: //div[@class='related']
+strip_id_or_class: share
+strip_image_src: /tracking/
+author: //a[@rel='author']
+date: //meta[@name='date']/@content
+date: substring-before(//time, ' ')
+single_page_link: //a[contains(@href, 'print')]
+next_page_link: //a[@class='next']
+prune: no
+tidy: no
+test_url: http://example.com/article
+replace_string(<br /><br />): <p>
+`
+
+	rule, err := ParseSiteConfig(strings.NewReader(config))
+	if err != nil {
+		t.Fatalf("failed to parse site config: %v", err)
+	}
+
+	expected := Rule{
+		Content:        []string{`div[id="story"]`, `article`},
+		Strip:          []string{`div[class="related"]`, `[id*="share"], [class*="share"]`, `img[src*="/tracking/"]`},
+		Title:          []string{`h1[class="headline"]`},
+		Byline:         []string{`a[rel="author"]`},
+		PublishedTime:  []string{`meta[name="date"]`},
+		NextPage:       []string{`a[class="next"]`},
+		SinglePageLink: []string{`a[href*="print"]`},
+	}
+	if !reflect.DeepEqual(rule, expected) {
+		t.Errorf("\nwant : %+v\ngot  : %+v", expected, rule)
+	}
+}
+
+func Test_Rules_LoadSiteConfigFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"fakehost.test.txt": {Data: []byte("body: //div[@class='story']\n")},
+		".example.com.txt":  {Data: []byte("body: //main\n")},
+		"README.md":         {Data: []byte("body: //p\n")},
+	}
+
+	rules := NewRules()
+	if err := rules.LoadSiteConfigFS(fsys); err != nil {
+		t.Fatalf("failed to load site configs: %v", err)
+	}
+
+	if rule, found := rules.Match("www.fakehost.test"); !found || rule.Content[0] != `div[class="story"]` {
+		t.Errorf("unexpected rule of fakehost.test: %+v", rule)
+	}
+	if rule, found := rules.Match("news.example.com"); !found || rule.Content[0] != "main" {
+		t.Errorf("unexpected rule of example.com: %+v", rule)
+	}
+}
+
+func Test_Parser_SinglePageLink(t *testing.T) {
+	paragraph := articleParagraph(10)
+	input := `<html><body><article>` + paragraph + paragraph + `</article>` +
+		`<a class="print" href="/test/page.html?print=1">Print</a><a class="more" href="/test/2.html">More</a>` +
+		`</body></html>`
+
+	rules := NewRules()
+	rules.Register("fakehost", Rule{SinglePageLink: []string{"a.print"}, NextPage: []string{"a.more"}})
+
+	ps := NewParser(WithRules(rules))
+	article, err := ps.Parse(strings.NewReader(input), fakeHostURL)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	if article.SinglePageURL != "http://fakehost/test/page.html?print=1" {
+		t.Errorf("unexpected single page URL: %q", article.SinglePageURL)
+	}
+	if article.NextPageURL != "http://fakehost/test/2.html" {
+		t.Errorf("unexpected next page URL: %q", article.NextPageURL)
+	}
+}
@@ -0,0 +1,270 @@
+package readability
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	nurl "net/url"
+	"regexp"
+	"strings"
+
+	"github.com/go-shiori/dom"
+	"golang.org/x/net/html"
+)
+
+var (
+	rxTweetPermalink    = regexp.MustCompile(`(?i)^https?://(www\.|mobile\.)?(twitter|x)\.com/[^/]+/status(es)?/\d+`)
+	rxInstagramShared   = regexp.MustCompile(`(?i)^a post shared by\s+(.+)$`)
+	rxInstagramBoiler   = regexp.MustCompile(`(?i)^(view this post on instagram|a post shared by\b.*)$`)
+	rxSocialAuthorDash  = regexp.MustCompile(`^\s*[—–-]\s*`)
+	socialEmbedProvider = map[string]string{
+		"twitter-tweet":   "twitter",
+		"twitter-video":   "twitter",
+		"instagram-media": "instagram",
+	}
+)
+
+// SocialPost is a social media post that embedded in the article.
+type SocialPost struct {
+	Provider  string
+	Author    string
+	Text      string
+	Permalink string
+	Date      string
+}
+
+// SocialEmbedResolver resolves the content of a social media post from its
+// permalink, e.g. using oEmbed API. It's used when the content can't be
+// extracted from the embed markup.
+type SocialEmbedResolver func(ctx context.Context, post SocialPost) (*SocialPost, error)
+
+// convertSocialEmbeds converts the known social media embeds, which rely
+// on script to render their content, into static blockquotes.
+func (ps *Parser) convertSocialEmbeds(doc *html.Node) {
+	blockquotes := dom.GetElementsByTagName(doc, "blockquote")
+	ps.forEachNode(blockquotes, func(blockquote *html.Node, _ int) {
+		var provider string
+		for _, class := range strings.Fields(dom.ClassName(blockquote)) {
+			if name, exist := socialEmbedProvider[class]; exist {
+				provider = name
+				break
+			}
+		}
+
+		var post SocialPost
+		switch provider {
+		case "twitter":
+			post = ps.parseTweetEmbed(blockquote)
+		case "instagram":
+			post = ps.parseInstagramEmbed(blockquote)
+		default:
+			return
+		}
+
+		if post.Text == "" && post.Permalink != "" && ps.SocialEmbedResolver != nil {
+			ctx := ps.ctx
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			resolved, err := ps.SocialEmbedResolver(ctx, post)
+			if err != nil {
+				ps.logWarn("failed to resolve social embed", "provider", provider, "permalink", post.Permalink, "error", err)
+			} else if resolved != nil {
+				post = *resolved
+			}
+		}
+
+		if post.Text == "" && post.Permalink == "" {
+			return
+		}
+
+		if blockquote.Parent != nil {
+			dom.ReplaceChild(blockquote.Parent, socialPostNode(post), blockquote)
+		}
+	})
+}
+
+// parseTweetEmbed extracts the tweet from its embed markup, which looks like:
+// <blockquote class="twitter-tweet"><p>Text</p>&mdash; Author (@handle)
+// <a href="https://twitter.com/handle/status/1">June 1, 2021</a></blockquote>
+func (ps *Parser) parseTweetEmbed(blockquote *html.Node) SocialPost {
+	post := SocialPost{Provider: "twitter"}
+
+	var texts []string
+	for _, p := range dom.GetElementsByTagName(blockquote, "p") {
+		if text := normalizeText(dom.TextContent(p)); text != "" {
+			texts = append(texts, text)
+		}
+	}
+	post.Text = strings.Join(texts, "\n")
+
+	for _, a := range dom.GetElementsByTagName(blockquote, "a") {
+		if href := dom.GetAttribute(a, "href"); rxTweetPermalink.MatchString(href) {
+			post.Permalink = cleanPermalink(href)
+			post.Date = normalizeText(dom.TextContent(a))
+		}
+	}
+
+	// Author is the text node right under the blockquote
+	var author strings.Builder
+	for child := blockquote.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.TextNode {
+			author.WriteString(child.Data)
+		}
+	}
+	post.Author = normalizeText(rxSocialAuthorDash.ReplaceAllString(author.String(), ""))
+
+	return post
+}
+
+// parseInstagramEmbed extracts the Instagram post from its embed markup.
+// The embed doesn't always contain the caption, in that case only its
+// permalink and author are extracted.
+func (ps *Parser) parseInstagramEmbed(blockquote *html.Node) SocialPost {
+	post := SocialPost{
+		Provider:  "instagram",
+		Permalink: cleanPermalink(dom.GetAttribute(blockquote, "data-instgrm-permalink")),
+	}
+
+	var texts []string
+	for _, p := range dom.GetElementsByTagName(blockquote, "p") {
+		text := normalizeText(dom.TextContent(p))
+		if parts := rxInstagramShared.FindStringSubmatch(text); len(parts) == 2 {
+			post.Author = parts[1]
+		}
+
+		if text != "" && !rxInstagramBoiler.MatchString(text) {
+			texts = append(texts, text)
+		}
+	}
+	post.Text = strings.Join(texts, "\n")
+
+	if times := dom.GetElementsByTagName(blockquote, "time"); len(times) > 0 {
+		post.Date = normalizeText(dom.TextContent(times[0]))
+	}
+
+	if post.Permalink == "" {
+		if links := dom.GetElementsByTagName(blockquote, "a"); len(links) > 0 {
+			post.Permalink = cleanPermalink(dom.GetAttribute(links[0], "href"))
+		}
+	}
+
+	return post
+}
+
+// socialPostNode creates the static blockquote for the social post.
+func socialPostNode(post SocialPost) *html.Node {
+	blockquote := dom.CreateElement("blockquote")
+	if post.Permalink != "" {
+		dom.SetAttribute(blockquote, "cite", post.Permalink)
+	}
+
+	for _, line := range strings.Split(post.Text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			p := dom.CreateElement("p")
+			dom.AppendChild(p, dom.CreateTextNode(line))
+			dom.AppendChild(blockquote, p)
+		}
+	}
+
+	footer := dom.CreateElement("p")
+	if post.Author != "" {
+		dom.AppendChild(footer, dom.CreateTextNode("— "+post.Author+" "))
+	}
+
+	if post.Permalink != "" {
+		linkText := post.Date
+		if linkText == "" {
+			linkText = post.Permalink
+		}
+
+		a := dom.CreateElement("a")
+		dom.SetAttribute(a, "href", post.Permalink)
+		dom.AppendChild(a, dom.CreateTextNode(linkText))
+		dom.AppendChild(footer, a)
+	}
+
+	if footer.FirstChild != nil {
+		dom.AppendChild(blockquote, footer)
+	}
+
+	return blockquote
+}
+
+// cleanPermalink removes the tracking query from the permalink.
+func cleanPermalink(permalink string) string {
+	parsedURL, err := nurl.Parse(strings.TrimSpace(permalink))
+	if err != nil || parsedURL.Host == "" {
+		return ""
+	}
+
+	parsedURL.RawQuery = ""
+	parsedURL.Fragment = ""
+	return parsedURL.String()
+}
+
+// normalizeText collapses the whitespaces in text.
+func normalizeText(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// OEmbedResolver returns SocialEmbedResolver that resolves the social post
+// using oEmbed endpoint, e.g. "https://publish.twitter.com/oembed". If
+// client is nil, http.DefaultClient will be used.
+func OEmbedResolver(client *http.Client, endpoint string) SocialEmbedResolver {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return func(ctx context.Context, post SocialPost) (*SocialPost, error) {
+		reqURL := endpoint + "?omit_script=1&url=" + nurl.QueryEscape(post.Permalink)
+		req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %v", err)
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch oEmbed: %w", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("failed to fetch oEmbed: status %d", resp.StatusCode)
+		}
+
+		var oembed struct {
+			AuthorName string `json:"author_name"`
+			Title      string `json:"title"`
+			HTML       string `json:"html"`
+		}
+		if err = json.NewDecoder(resp.Body).Decode(&oembed); err != nil {
+			return nil, fmt.Errorf("failed to decode oEmbed: %v", err)
+		}
+
+		// The HTML of oEmbed is the same embed markup, so extract the
+		// text from its paragraphs.
+		resolved := post
+		resolved.Author = strOr(oembed.AuthorName, post.Author)
+		resolved.Text = oembed.Title
+		if oembed.HTML != "" {
+			root := dom.CreateElement("div")
+			dom.SetInnerHTML(root, oembed.HTML)
+
+			var texts []string
+			for _, p := range dom.GetElementsByTagName(root, "p") {
+				if text := normalizeText(dom.TextContent(p)); text != "" && !rxInstagramBoiler.MatchString(text) {
+					texts = append(texts, text)
+				}
+			}
+
+			if len(texts) > 0 {
+				resolved.Text = strings.Join(texts, "\n")
+			}
+		}
+
+		return &resolved, nil
+	}
+}
@@ -0,0 +1,70 @@
+package readability
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-shiori/dom"
+)
+
+func Test_Parser_convertSocialEmbeds(t *testing.T) {
+	scenarios := map[string]string{
+		`<blockquote class="twitter-tweet"><p lang="en" dir="ltr">Hello world!</p>&mdash; Jane Doe (@jane) ` +
+			`<a href="https://twitter.com/jane/status/123?ref_src=twsrc%5Etfw">June 1, 2021</a></blockquote>` +
+			`<script async src="https://platform.twitter.com/widgets.js"></script>`: `<blockquote cite="https://twitter.com/jane/status/123">` +
+			`<p>Hello world!</p><p>— Jane Doe (@jane) <a href="https://twitter.com/jane/status/123">June 1, 2021</a></p></blockquote>` +
+			`<script async="" src="https://platform.twitter.com/widgets.js"></script>`,
+		`<blockquote class="instagram-media" data-instgrm-permalink="https://www.instagram.com/p/abc/?utm_source=ig_embed">` +
+			`<div><a href="https://www.instagram.com/p/abc/?utm_source=ig_embed">View this post on Instagram</a></div>` +
+			`<p><a href="https://www.instagram.com/p/abc/">Sunset at the beach</a></p>` +
+			`<p>A post shared by John (@john)</p></blockquote>`: `<blockquote cite="https://www.instagram.com/p/abc/">` +
+			`<p>Sunset at the beach</p><p>— John (@john) <a href="https://www.instagram.com/p/abc/">https://www.instagram.com/p/abc/</a></p></blockquote>`,
+		`<blockquote class="other"><p>Quote</p></blockquote>`: `<blockquote class="other"><p>Quote</p></blockquote>`,
+	}
+
+	ps := NewParser(WithConvertSocialEmbeds(true, nil))
+	for input, expected := range scenarios {
+		doc, err := dom.Parse(strings.NewReader(input))
+		if err != nil {
+			t.Fatalf("failed to parse %q: %v", input, err)
+		}
+
+		ps.convertSocialEmbeds(doc)
+		if result := dom.InnerHTML(dom.QuerySelector(doc, "body")); result != expected {
+			t.Errorf("\n"+
+				"html : %q\n"+
+				"want : %q\n"+
+				"got  : %q", input, expected, result)
+		}
+	}
+}
+
+func Test_OEmbedResolver(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"author_name":"John","html":"<blockquote><p>Caption of %s</p></blockquote>"}`,
+			r.URL.Query().Get("url"))
+	}))
+	defer server.Close()
+
+	input := `<blockquote class="instagram-media" data-instgrm-permalink="https://www.instagram.com/p/abc/">` +
+		`<a href="https://www.instagram.com/p/abc/">View this post on Instagram</a></blockquote>`
+	doc, err := dom.Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	ps := NewParser(WithConvertSocialEmbeds(true, OEmbedResolver(nil, server.URL)))
+	ps.ctx = context.Background()
+	ps.convertSocialEmbeds(doc)
+
+	expected := `<blockquote cite="https://www.instagram.com/p/abc/"><p>Caption of https://www.instagram.com/p/abc/</p>` +
+		`<p>— John <a href="https://www.instagram.com/p/abc/">https://www.instagram.com/p/abc/</a></p></blockquote>`
+	if result := dom.InnerHTML(dom.QuerySelector(doc, "body")); result != expected {
+		t.Errorf("\nwant : %q\ngot  : %q", expected, result)
+	}
+}
@@ -0,0 +1,97 @@
+package readability
+
+import (
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/go-shiori/dom"
+	"golang.org/x/net/html"
+)
+
+// Default reading speed that used to estimate the reading time.
+const (
+	DefaultWordsPerMinute    = 200
+	DefaultCJKCharsPerMinute = 500
+)
+
+// isCJK checks if the rune is Chinese or Japanese character, which words
+// are not delimited by space. Korean uses space between its words, so
+// it's counted like other languages.
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana)
+}
+
+// countWords counts the words in text. Since CJK text is not delimited by
+// space, its characters are counted separately.
+func countWords(text string) (words int, cjkChars int) {
+	inWord := false
+	for _, r := range text {
+		switch {
+		case isCJK(r):
+			cjkChars++
+			inWord = false
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r):
+			if !inWord {
+				words++
+			}
+			inWord = true
+		case unicode.IsSpace(r):
+			inWord = false
+		}
+	}
+	return words, cjkChars
+}
+
+// articleWordCount returns the number of words in text, where each CJK
+// character is counted as a word. Unlike wordCount, punctuations are not
+// counted as word.
+func articleWordCount(text string) int {
+	words, cjkChars := countWords(text)
+	return words + cjkChars
+}
+
+// nonSpaceCharCount returns the number of characters in text, excluding
+// the whitespaces.
+func nonSpaceCharCount(text string) int {
+	count := 0
+	for _, r := range text {
+		if !unicode.IsSpace(r) {
+			count++
+		}
+	}
+	return count
+}
+
+// paragraphCount returns the number of paragraphs that have text in the
+// article content.
+func paragraphCount(articleContent *html.Node) int {
+	if articleContent == nil {
+		return 0
+	}
+
+	count := 0
+	for _, p := range dom.GetElementsByTagName(articleContent, "p") {
+		if strings.TrimSpace(dom.TextContent(p)) != "" {
+			count++
+		}
+	}
+	return count
+}
+
+// estimateReadingTime estimates the time needed to read the text.
+func (ps *Parser) estimateReadingTime(text string) time.Duration {
+	wordsPerMinute := ps.WordsPerMinute
+	if wordsPerMinute <= 0 {
+		wordsPerMinute = DefaultWordsPerMinute
+	}
+
+	cjkCharsPerMinute := ps.CJKCharsPerMinute
+	if cjkCharsPerMinute <= 0 {
+		cjkCharsPerMinute = DefaultCJKCharsPerMinute
+	}
+
+	words, cjkChars := countWords(text)
+	minutes := float64(words)/float64(wordsPerMinute) + float64(cjkChars)/float64(cjkCharsPerMinute)
+	return (time.Duration(minutes * float64(time.Minute))).Round(time.Second)
+}
@@ -0,0 +1,79 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_countWords(t *testing.T) {
+	scenarios := map[string][2]int{
+		"":                                 {0, 0},
+		"Hello, world! It's 2021.":         {4, 0},
+		"  multiple   spaces\nand\tlines ": {4, 0},
+		"안녕하세요 세계":                         {2, 0},
+		"我们的世界":                            {0, 5},
+		"これはペンです。This is a pen.":           {4, 7},
+	}
+
+	for text, expected := range scenarios {
+		words, cjkChars := countWords(text)
+		if words != expected[0] || cjkChars != expected[1] {
+			t.Errorf("\n"+
+				"text : %q\n"+
+				"want : %v\n"+
+				"got  : %v", text, expected, [2]int{words, cjkChars})
+		}
+	}
+}
+
+func Test_Parser_ReadingTime(t *testing.T) {
+	english := strings.Repeat("word ", 400)
+	chinese := strings.Repeat("字", 1000)
+
+	scenarios := []struct {
+		text        string
+		wpm, cpm    int
+		readingTime time.Duration
+	}{
+		{english, 0, 0, 2 * time.Minute},
+		{english, 100, 0, 4 * time.Minute},
+		{chinese, 0, 0, 2 * time.Minute},
+		{chinese, 0, 250, 4 * time.Minute},
+		{english + chinese, 0, 0, 4 * time.Minute},
+		{strings.Repeat("word ", 10), 0, 0, 3 * time.Second},
+	}
+
+	for _, scenario := range scenarios {
+		ps := NewParser(WithReadingSpeed(scenario.wpm, scenario.cpm))
+		if readingTime := ps.estimateReadingTime(scenario.text); readingTime != scenario.readingTime {
+			t.Errorf("speed %d/%d, want %v got %v", scenario.wpm, scenario.cpm, scenario.readingTime, readingTime)
+		}
+	}
+}
+
+func Test_Parser_TextCounts(t *testing.T) {
+	paragraph := articleParagraph(10)
+	chinese := "<p>" + strings.Repeat("这是文章的一个句子，足够长可以被评分。", 10) + "</p>"
+	input := "<html><body><article>" + paragraph + chinese + "<p> </p>" + paragraph + "</article></body></html>"
+
+	ps := NewParser()
+	article, err := ps.Parse(strings.NewReader(input), fakeHostURL)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	// Each english paragraph has 120 words and 490 non-space chars, while
+	// the chinese one has 190 chars which 20 of them are punctuations.
+	if article.WordCount != 2*120+170 {
+		t.Errorf("word count, want %d got %d", 2*120+170, article.WordCount)
+	}
+
+	if article.CharCount != 2*490+190 {
+		t.Errorf("char count, want %d got %d", 2*490+190, article.CharCount)
+	}
+
+	if article.ParagraphCount != 3 {
+		t.Errorf("paragraph count, want %d got %d", 3, article.ParagraphCount)
+	}
+}
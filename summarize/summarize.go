@@ -0,0 +1,281 @@
+// Package summarize creates extractive summary of readable article, by
+// picking its most important sentences using TextRank.
+package summarize
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/go-shiori/dom"
+	readability "github.com/go-shiori/go-readability"
+	"golang.org/x/net/html"
+)
+
+const (
+	// dampingFactor is the probability of following the similarity edges
+	// when ranking the sentences, as used in PageRank.
+	dampingFactor = 0.85
+	// maxIterations is the max number of iterations when ranking.
+	maxIterations = 100
+	// convergence is the max change of score between iterations that
+	// stops the ranking early.
+	convergence = 1e-6
+)
+
+// blockTags is the tags of elements whose text is split into sentences.
+var blockTags = map[string]struct{}{
+	"p": {}, "li": {}, "blockquote": {}, "dd": {}, "td": {}, "div": {}, "section": {},
+	"article": {}, "figcaption": {},
+}
+
+// skippedTags is the tags of elements that don't contain the sentences
+// of the article, e.g. headings and code.
+var skippedTags = map[string]struct{}{
+	"h1": {}, "h2": {}, "h3": {}, "h4": {}, "h5": {}, "h6": {}, "pre": {}, "code": {},
+	"table": {}, "figure": {}, "math": {}, "svg": {},
+}
+
+// Sentence is a sentence of the text along with its rank.
+type Sentence struct {
+	// Text is the text of the sentence, with its whitespaces collapsed.
+	Text string
+	// Index is the position of the sentence in the text, starting from 0.
+	Index int
+	// Score is the TextRank score of the sentence. Higher is better.
+	Score float64
+}
+
+// Article returns the n most important sentences of the article, in the
+// order they appear in the article. The language of the article is used
+// to split its text into sentences.
+func Article(article readability.Article, n int) []string {
+	if article.Node == nil {
+		return Text(article.TextContent, article.Language, n)
+	}
+
+	var sentences []string
+	for _, text := range blockTexts(article.Node) {
+		sentences = append(sentences, sentenceTexts(text, article.Language)...)
+	}
+	return top(rank(sentences), n)
+}
+
+// Text returns the n most important sentences of the text, in the order
+// they appear in the text.
+func Text(text, language string, n int) []string {
+	return top(Rank(text, language), n)
+}
+
+// Rank splits the text into sentences and scores each of them using
+// TextRank. The sentences are returned in the order they first appear,
+// and the repeated sentences are only returned once.
+func Rank(text, language string) []Sentence {
+	return rank(sentenceTexts(text, language))
+}
+
+// sentenceTexts splits the text into sentences and returns their text.
+func sentenceTexts(text, language string) []string {
+	var texts []string
+	for _, sentence := range readability.SplitSentences(text, language) {
+		texts = append(texts, sentence.Text)
+	}
+	return texts
+}
+
+// rank scores the sentences using TextRank. Sentences are the vertices
+// of a graph whose edges are weighted by the similarity between the two
+// sentences, then they are ranked like PageRank ranks the web pages.
+func rank(texts []string) []Sentence {
+	// Repeated sentences, e.g. pull quotes, would boost each other, so
+	// they are only ranked once
+	seen := make(map[string]struct{})
+	unique := texts[:0:0]
+	for _, text := range texts {
+		if _, exist := seen[text]; !exist {
+			seen[text] = struct{}{}
+			unique = append(unique, text)
+		}
+	}
+	texts = unique
+
+	sentences := make([]Sentence, len(texts))
+	words := make([]map[string]int, len(texts))
+	for i, text := range texts {
+		sentences[i] = Sentence{Text: text, Index: i, Score: 1}
+		words[i] = sentenceWords(text)
+	}
+
+	// Build the similarity matrix, along with total weight of each vertex
+	weights := make([][]float64, len(texts))
+	totals := make([]float64, len(texts))
+	for i := range weights {
+		weights[i] = make([]float64, len(texts))
+	}
+
+	for i := range texts {
+		for j := i + 1; j < len(texts); j++ {
+			similarity := sentenceSimilarity(words[i], words[j])
+			weights[i][j], weights[j][i] = similarity, similarity
+			totals[i] += similarity
+			totals[j] += similarity
+		}
+	}
+
+	// Iterate until the scores converge
+	scores := make([]float64, len(texts))
+	for iteration := 0; iteration < maxIterations; iteration++ {
+		maxDelta := 0.0
+		for i := range sentences {
+			sum := 0.0
+			for j := range sentences {
+				if weights[j][i] > 0 && totals[j] > 0 {
+					sum += weights[j][i] / totals[j] * sentences[j].Score
+				}
+			}
+
+			scores[i] = (1 - dampingFactor) + dampingFactor*sum
+			if delta := math.Abs(scores[i] - sentences[i].Score); delta > maxDelta {
+				maxDelta = delta
+			}
+		}
+
+		for i := range sentences {
+			sentences[i].Score = scores[i]
+		}
+
+		if maxDelta < convergence {
+			break
+		}
+	}
+
+	return sentences
+}
+
+// top returns the text of n sentences with the highest score, in their
+// original order.
+func top(sentences []Sentence, n int) []string {
+	if n <= 0 || len(sentences) == 0 {
+		return nil
+	}
+
+	ranked := make([]Sentence, len(sentences))
+	copy(ranked, sentences)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].Score > ranked[j].Score
+	})
+
+	if len(ranked) > n {
+		ranked = ranked[:n]
+	}
+
+	sort.Slice(ranked, func(i, j int) bool {
+		return ranked[i].Index < ranked[j].Index
+	})
+
+	texts := make([]string, len(ranked))
+	for i, sentence := range ranked {
+		texts[i] = sentence.Text
+	}
+	return texts
+}
+
+// sentenceWords returns the frequency of words in the sentence. Since
+// CJK words are not delimited by space, their characters are used as
+// the words instead.
+func sentenceWords(sentence string) map[string]int {
+	words := make(map[string]int)
+	var word strings.Builder
+	closeWord := func() {
+		if word.Len() > 0 {
+			words[strings.ToLower(word.String())]++
+			word.Reset()
+		}
+	}
+
+	for _, r := range sentence {
+		switch {
+		case unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana):
+			closeWord()
+			words[string(r)]++
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r):
+			word.WriteRune(r)
+		default:
+			closeWord()
+		}
+	}
+
+	closeWord()
+	return words
+}
+
+// sentenceSimilarity returns the similarity of two sentences, which is
+// the number of their common words normalized by their length, as
+// defined in the TextRank paper.
+func sentenceSimilarity(a, b map[string]int) float64 {
+	lengthA, lengthB := 0, 0
+	for _, count := range a {
+		lengthA += count
+	}
+	for _, count := range b {
+		lengthB += count
+	}
+
+	common := 0
+	for word, count := range a {
+		if countB := b[word]; countB > 0 {
+			if countB < count {
+				count = countB
+			}
+			common += count
+		}
+	}
+
+	if common == 0 {
+		return 0
+	}
+
+	norm := math.Log(float64(lengthA)+1) + math.Log(float64(lengthB)+1)
+	return float64(common) / norm
+}
+
+// blockTexts returns the text of the innermost blocks in node, so the
+// sentences of each paragraph are kept apart. Headings and code are
+// skipped since they are not the sentences of the article.
+func blockTexts(node *html.Node) []string {
+	var texts []string
+	var sb strings.Builder
+
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			sb.WriteString(n.Data)
+			return
+		}
+
+		tagName := dom.TagName(n)
+		if _, skipped := skippedTags[tagName]; skipped && n.Type == html.ElementNode {
+			return
+		}
+
+		_, isBlock := blockTags[tagName]
+		if isBlock && n.Type == html.ElementNode {
+			texts = append(texts, sb.String())
+			sb.Reset()
+		}
+
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+
+		if isBlock && n.Type == html.ElementNode {
+			texts = append(texts, sb.String())
+			sb.Reset()
+		}
+	}
+
+	walk(node)
+	texts = append(texts, sb.String())
+	return texts
+}
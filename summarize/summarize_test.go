@@ -0,0 +1,53 @@
+package summarize
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	readability "github.com/go-shiori/go-readability"
+)
+
+func Test_Text(t *testing.T) {
+	text := "Solar power is growing quickly around the world. " +
+		"My cat likes to sleep on the sofa. " +
+		"Solar panels convert sunlight into electric power. " +
+		"The cost of solar power has fallen every year. " +
+		"Yesterday it rained in the afternoon."
+
+	summary := Text(text, "en", 2)
+	if len(summary) != 2 {
+		t.Fatalf("want 2 sentences, got %q", summary)
+	}
+
+	for _, sentence := range summary {
+		if !strings.Contains(sentence, "olar") {
+			t.Errorf("unrelated sentence in summary: %q", summary)
+		}
+	}
+
+	if got := Text(text, "en", 10); len(got) != 5 {
+		t.Errorf("want all 5 sentences, got %q", got)
+	}
+
+	if got := Text(text, "en", 0); got != nil {
+		t.Errorf("want no sentences, got %q", got)
+	}
+}
+
+func Test_Article(t *testing.T) {
+	paragraph := "<p>Solar power is growing quickly. Solar power is cheap. My cat sleeps.</p>"
+	input := "<html><body><article><h1>Solar power</h1>" + paragraph +
+		"<pre>solar power solar power</pre>" + paragraph + "</article></body></html>"
+
+	article, err := readability.FromReader(strings.NewReader(input), nil)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	summary := Article(article, 2)
+	expected := []string{"Solar power is growing quickly.", "Solar power is cheap."}
+	if !reflect.DeepEqual(summary, expected) {
+		t.Errorf("want %q, got %q", expected, summary)
+	}
+}
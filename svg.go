@@ -0,0 +1,135 @@
+package readability
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/go-shiori/dom"
+	"golang.org/x/net/html"
+)
+
+// SVGPolicy is the policy for handling inline SVG in the article content.
+type SVGPolicy int
+
+const (
+	// SVGKeepAll keeps every inline SVG, which is the default behavior.
+	SVGKeepAll SVGPolicy = iota
+	// SVGKeepLarge only keeps SVG whose size is at least SVGMinSize, since
+	// the small one is usually decorative icon.
+	SVGKeepLarge
+	// SVGStripAll removes all inline SVG.
+	SVGStripAll
+	// SVGSanitize keeps every inline SVG, but removes their scripts, event
+	// handlers, embedded HTML and external references.
+	SVGSanitize
+)
+
+// DefaultSVGMinSize is the default min size of SVG that kept by SVGKeepLarge.
+const DefaultSVGMinSize = 64
+
+// applySVGPolicy handles the inline SVG in article content as specified
+// in SVGPolicy.
+func (ps *Parser) applySVGPolicy(articleContent *html.Node) {
+	if ps.SVGPolicy == SVGKeepAll {
+		return
+	}
+
+	svgs := dom.GetElementsByTagName(articleContent, "svg")
+	switch ps.SVGPolicy {
+	case SVGStripAll:
+		ps.removeNodes(svgs, nil)
+
+	case SVGKeepLarge:
+		minSize := ps.SVGMinSize
+		if minSize <= 0 {
+			minSize = DefaultSVGMinSize
+		}
+
+		ps.removeNodes(svgs, func(svg *html.Node) bool {
+			width, height, known := svgSize(svg)
+			if !known {
+				// Unsized icon is usually hidden from screen reader
+				return dom.GetAttribute(svg, "aria-hidden") == "true"
+			}
+			return width < minSize && height < minSize
+		})
+
+	case SVGSanitize:
+		ps.forEachNode(svgs, func(svg *html.Node, _ int) {
+			sanitizeSVG(svg)
+		})
+	}
+}
+
+// svgSize returns the size of SVG, from its width and height attributes or
+// from its viewBox. Returns false if the size is unknown.
+func svgSize(svg *html.Node) (float64, float64, bool) {
+	width, widthErr := parseSVGLength(dom.GetAttribute(svg, "width"))
+	height, heightErr := parseSVGLength(dom.GetAttribute(svg, "height"))
+	if widthErr == nil && heightErr == nil {
+		return width, height, true
+	}
+
+	viewBox := strings.Fields(strings.ReplaceAll(dom.GetAttribute(svg, "viewBox"), ",", " "))
+	if len(viewBox) == 4 {
+		vbWidth, errW := strconv.ParseFloat(viewBox[2], 64)
+		vbHeight, errH := strconv.ParseFloat(viewBox[3], 64)
+		if errW == nil && errH == nil {
+			if widthErr == nil {
+				return width, width * vbHeight / vbWidth, vbWidth > 0
+			}
+			if heightErr == nil {
+				return height * vbWidth / vbHeight, height, vbHeight > 0
+			}
+			return vbWidth, vbHeight, true
+		}
+	}
+
+	return 0, 0, false
+}
+
+// parseSVGLength parses the absolute length in SVG, e.g. "24" or "24px".
+// Relative length like "100%" or "1em" is not supported.
+func parseSVGLength(length string) (float64, error) {
+	length = strings.TrimSuffix(strings.TrimSpace(length), "px")
+	return strconv.ParseFloat(length, 64)
+}
+
+// sanitizeSVG removes the dangerous parts of SVG: scripts, event handlers,
+// embedded HTML, and references to external resource.
+func sanitizeSVG(svg *html.Node) {
+	var nodesToRemove []*html.Node
+	var sanitize func(*html.Node)
+	sanitize = func(node *html.Node) {
+		switch strings.ToLower(node.Data) {
+		case "script", "foreignobject", "iframe", "style":
+			nodesToRemove = append(nodesToRemove, node)
+			return
+		}
+
+		attrs := node.Attr[:0]
+		for _, attr := range node.Attr {
+			key := strings.ToLower(attr.Key)
+			value := strings.ToLower(strings.TrimSpace(attr.Val))
+			switch {
+			case strings.HasPrefix(key, "on"):
+				continue
+			case key == "href" || key == "src":
+				if !strings.HasPrefix(value, "#") && !strings.HasPrefix(value, "data:image/") {
+					continue
+				}
+			}
+			attrs = append(attrs, attr)
+		}
+		node.Attr = attrs
+
+		for child := dom.FirstElementChild(node); child != nil; child = dom.NextElementSibling(child) {
+			sanitize(child)
+		}
+	}
+
+	sanitize(svg)
+	for _, node := range nodesToRemove {
+		node.Parent.RemoveChild(node)
+	}
+}
@@ -0,0 +1,41 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-shiori/dom"
+)
+
+func Test_Parser_SVGPolicy(t *testing.T) {
+	icon := `<svg width="16" height="16"><use href="#icon-share"></use></svg>`
+	hiddenIcon := `<svg aria-hidden="true"><path d="M0 0h8v8H0z"></path></svg>`
+	chart := `<svg viewBox="0 0 600 400" onload="alert(1)"><script>alert(1)</script>` +
+		`<a href="javascript:alert(1)"><rect width="600" height="400"></rect></a></svg>`
+	input := icon + hiddenIcon + chart
+
+	scenarios := map[SVGPolicy]string{
+		SVGKeepAll:   input,
+		SVGKeepLarge: chart,
+		SVGStripAll:  "",
+		SVGSanitize:  icon + hiddenIcon + `<svg viewBox="0 0 600 400"><a><rect width="600" height="400"></rect></a></svg>`,
+	}
+
+	for policy, expected := range scenarios {
+		doc, err := dom.Parse(strings.NewReader(input))
+		if err != nil {
+			t.Fatalf("failed to parse: %v", err)
+		}
+
+		body := dom.QuerySelector(doc, "body")
+		ps := NewParser(WithSVGPolicy(policy, 0))
+		ps.applySVGPolicy(body)
+
+		if result := dom.InnerHTML(body); result != expected {
+			t.Errorf("\n"+
+				"policy : %d\n"+
+				"want   : %q\n"+
+				"got    : %q", policy, expected, result)
+		}
+	}
+}
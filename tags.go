@@ -0,0 +1,71 @@
+package readability
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/go-shiori/dom"
+)
+
+// rxTaxonomyContainer matches the class or id of element that contains
+// the links to tags or categories of the article.
+var rxTaxonomyContainer = regexp.MustCompile(`(?i)(^|[\s_-])(tags|tag-?list|tag-?links|post-?tags|entry-?tags|article-?tags|categories|cat-?links|post-?categories|entry-?categories)($|[\s_-])`)
+
+// maxTagLength is the max length of tag, to skip links that obviously
+// are not a tag.
+const maxTagLength = 50
+
+// getArticleTags collects the tags and categories of the article from
+// article:tag meta, JSON-LD keywords, h-entry categories, rel="tag" and
+// rel="category" links, and the links inside taxonomy containers. The
+// duplicates are removed case-insensitively.
+func (ps *Parser) getArticleTags(jsonLd map[string]string, openGraph *OpenGraph, hEntry *HEntry) []string {
+	var tags []string
+	seen := make(map[string]struct{})
+	add := func(tag string) {
+		tag = strings.Join(strings.Fields(tag), " ")
+		tag = strings.TrimPrefix(tag, "#")
+		key := strings.ToLower(tag)
+		if _, exist := seen[key]; exist || tag == "" || charCount(tag) > maxTagLength {
+			return
+		}
+
+		seen[key] = struct{}{}
+		tags = append(tags, tag)
+	}
+
+	if openGraph != nil {
+		for _, tag := range openGraph.Article.Tags {
+			add(tag)
+		}
+	}
+
+	for _, keyword := range strings.Split(jsonLd["keywords"], ",") {
+		add(keyword)
+	}
+
+	if hEntry != nil {
+		for _, category := range hEntry.Categories {
+			add(category)
+		}
+	}
+
+	for _, a := range dom.QuerySelectorAll(ps.doc, `a[rel][href]`) {
+		rels := strings.Fields(strings.ToLower(dom.GetAttribute(a, "rel")))
+		if indexOf(rels, "tag") >= 0 || indexOf(rels, "category") >= 0 {
+			add(dom.TextContent(a))
+		}
+	}
+
+	for _, container := range dom.QuerySelectorAll(ps.doc, "[class], [id]") {
+		if !rxTaxonomyContainer.MatchString(dom.ClassName(container) + " " + dom.ID(container)) {
+			continue
+		}
+
+		for _, a := range dom.GetElementsByTagName(container, "a") {
+			add(dom.TextContent(a))
+		}
+	}
+
+	return tags
+}
@@ -0,0 +1,34 @@
+package readability
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_Parser_Tags(t *testing.T) {
+	paragraph := articleParagraph(10)
+	input := `<html><head>` +
+		`<meta property="article:tag" content="Go">` +
+		`<meta property="article:tag" content="Parser">` +
+		`<script type="application/ld+json">{"@context":"https://schema.org","@type":"Article",` +
+		`"keywords":["readability", "go"]}</script>` +
+		`</head><body><article>` + paragraph + `</article>` +
+		`<div class="post-tags"><a href="/tag/html">#HTML</a> <a href="/tag/web">Web</a></div>` +
+		`<span class="cat-links"><a href="/category/dev" rel="category tag">Development</a></span>` +
+		`<a href="/tag/long" rel="tag">` + strings.Repeat("long ", 20) + `</a>` +
+		`<div class="sidebar"><a href="/about">About</a></div>` +
+		`</body></html>`
+
+	article, err := FromReader(strings.NewReader(input), fakeHostURL)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	expected := []string{"Go", "Parser", "readability", "Development", "HTML", "Web"}
+	if !reflect.DeepEqual(article.Tags, expected) {
+		t.Errorf("\n"+
+			"want : %q\n"+
+			"got  : %q", expected, article.Tags)
+	}
+}
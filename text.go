@@ -0,0 +1,217 @@
+package readability
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-shiori/dom"
+	"golang.org/x/net/html"
+)
+
+// FormattedText renders the content of the article as plain text that
+// still readable in terminal and plaintext email. Unlike TextContent,
+// paragraphs are separated by blank line, list items are rendered with
+// bullets or numbers, headings are underlined and code blocks keep
+// their whitespace.
+func (article Article) FormattedText() string {
+	root := article.ContentNode()
+	if root == nil {
+		return ""
+	}
+
+	var tr textRenderer
+	return strings.Join(tr.blocks(root), "\n\n")
+}
+
+// textRenderer converts HTML node into formatted plain text.
+type textRenderer struct{}
+
+// blocks renders the children of node as a list of text blocks.
+func (tr *textRenderer) blocks(node *html.Node) []string {
+	var blocks []string
+	var inlines strings.Builder
+
+	flushInlines := func() {
+		paragraph := tr.cleanInline(inlines.String())
+		if paragraph != "" {
+			blocks = append(blocks, paragraph)
+		}
+		inlines.Reset()
+	}
+
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		if _, isBlock := renderBlockElems[dom.TagName(child)]; !isBlock || child.Type != html.ElementNode {
+			inlines.WriteString(tr.inline(child))
+			continue
+		}
+
+		flushInlines()
+		if block := tr.block(child); block != "" {
+			blocks = append(blocks, block)
+		}
+	}
+
+	flushInlines()
+	return blocks
+}
+
+// block renders a single block element.
+func (tr *textRenderer) block(node *html.Node) string {
+	tagName := dom.TagName(node)
+	switch tagName {
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		text := tr.cleanInline(tr.inlineChildren(node))
+		if text == "" {
+			return ""
+		}
+
+		text = strings.Join(strings.Fields(text), " ")
+		underline := "~"
+		switch tagName {
+		case "h1":
+			underline = "="
+		case "h2":
+			underline = "-"
+		}
+		return text + "\n" + strings.Repeat(underline, charCount(text))
+
+	case "p", "dt", "summary", "figcaption":
+		return tr.cleanInline(tr.inlineChildren(node))
+
+	case "hr":
+		return strings.Repeat("-", 40)
+
+	case "pre":
+		code := strings.Trim(dom.TextContent(node), "\n")
+		return prefixLines(code, "    ", "    ")
+
+	case "blockquote":
+		content := strings.Join(tr.blocks(node), "\n\n")
+		if content == "" {
+			return ""
+		}
+		return prefixLines(content, "> ", "> ")
+
+	case "ul", "ol":
+		return tr.list(node)
+
+	case "dd":
+		content := strings.Join(tr.blocks(node), "\n\n")
+		return prefixLines(content, "    ", "    ")
+
+	case "table":
+		return tr.table(node)
+	}
+
+	return strings.Join(tr.blocks(node), "\n\n")
+}
+
+// list renders <ul> or <ol> element. Items are rendered on their own
+// line, prefixed by bullet or number.
+func (tr *textRenderer) list(node *html.Node) string {
+	ordered := dom.TagName(node) == "ol"
+	number := 1
+	if ordered {
+		fmt.Sscanf(dom.GetAttribute(node, "start"), "%d", &number)
+	}
+
+	var items []string
+	for _, child := range dom.Children(node) {
+		if dom.TagName(child) != "li" {
+			continue
+		}
+
+		marker := "* "
+		if ordered {
+			marker = fmt.Sprintf("%d. ", number)
+			number++
+		}
+
+		content := strings.Join(tr.blocks(child), "\n")
+		if content == "" {
+			continue
+		}
+
+		indent := strings.Repeat(" ", len(marker))
+		items = append(items, prefixLines(content, marker, indent))
+	}
+
+	return strings.Join(items, "\n")
+}
+
+// table renders each table row in its own line, with the cells
+// separated by vertical bar.
+func (tr *textRenderer) table(node *html.Node) string {
+	var rows []string
+	for _, row := range dom.GetElementsByTagName(node, "tr") {
+		var cells []string
+		hasText := false
+		for _, cell := range dom.Children(row) {
+			if tag := dom.TagName(cell); tag == "td" || tag == "th" {
+				text := strings.Join(strings.Fields(dom.TextContent(cell)), " ")
+				hasText = hasText || text != ""
+				cells = append(cells, text)
+			}
+		}
+
+		if hasText {
+			rows = append(rows, strings.Join(cells, " | "))
+		}
+	}
+	return strings.Join(rows, "\n")
+}
+
+// inline renders a phrasing node.
+func (tr *textRenderer) inline(node *html.Node) string {
+	switch node.Type {
+	case html.TextNode:
+		return rxRenderSpaces.ReplaceAllString(node.Data, " ")
+	case html.ElementNode:
+	default:
+		return ""
+	}
+
+	switch dom.TagName(node) {
+	case "br":
+		return "\n"
+
+	case "img":
+		alt := strings.TrimSpace(dom.GetAttribute(node, "alt"))
+		if alt == "" {
+			return ""
+		}
+		return "[" + alt + "]"
+
+	case "a":
+		text := tr.inlineChildren(node)
+		href := dom.GetAttribute(node, "href")
+		trimmedText := strings.TrimSpace(text)
+		if href == "" || strings.HasPrefix(href, "#") || trimmedText == "" || trimmedText == href {
+			return text
+		}
+		return text + " <" + href + ">"
+
+	case "script", "style", "template":
+		return ""
+	}
+
+	return tr.inlineChildren(node)
+}
+
+// inlineChildren renders all children of node as phrasing content.
+func (tr *textRenderer) inlineChildren(node *html.Node) string {
+	var sb strings.Builder
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		sb.WriteString(tr.inline(child))
+	}
+	return sb.String()
+}
+
+// cleanInline removes the excess whitespaces in rendered phrasing content.
+func (tr *textRenderer) cleanInline(text string) string {
+	lines := strings.Split(text, "\n")
+	for i := range lines {
+		lines[i] = strings.TrimSpace(lines[i])
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,39 @@
+package readability
+
+import (
+	"testing"
+)
+
+func Test_Article_FormattedText(t *testing.T) {
+	scenarios := map[string]string{
+		`<h1>Title</h1><p>First   paragraph.</p><p>Second<br>line.</p>`: "" +
+			"Title\n" +
+			"=====\n\n" +
+			"First paragraph.\n\n" +
+			"Second\n" +
+			"line.",
+		`<h2>Section</h2><ul><li>One</li><li>Two<ol start="3"><li>Three</li></ol></li></ul>`: "" +
+			"Section\n" +
+			"-------\n\n" +
+			"* One\n" +
+			"* Two\n" +
+			"  3. Three",
+		`<p>See <a href="http://example.com">this page</a>.</p><blockquote>Quote</blockquote>`: "" +
+			"See this page <http://example.com>.\n\n" +
+			"> Quote",
+		"<pre>func main() {\n\tfmt.Println()\n}</pre>": "" +
+			"    func main() {\n" +
+			"    \tfmt.Println()\n" +
+			"    }",
+	}
+
+	for content, expected := range scenarios {
+		article := Article{Content: content}
+		if result := article.FormattedText(); result != expected {
+			t.Errorf("\n"+
+				"html : %q\n"+
+				"want : %q\n"+
+				"got  : %q", content, expected, result)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package readability
+
+import (
+	nurl "net/url"
+	"strings"
+
+	"github.com/go-shiori/dom"
+	"golang.org/x/net/html"
+)
+
+// DefaultTrackingParams is the query parameters that used for tracking by
+// analytics and ad platforms. Parameter that ends with "*" matches all
+// parameters with that prefix.
+var DefaultTrackingParams = []string{
+	"utm_*", "fbclid", "gclid", "gclsrc", "dclid", "msclkid", "yclid", "igshid",
+	"mc_cid", "mc_eid", "_hsenc", "_hsmi", "mkt_tok", "oly_anon_id", "oly_enc_id",
+	"vero_id", "wickedid", "_ga", "_gl", "ncid", "cmpid", "s_cid",
+}
+
+// stripTrackingParams removes the tracking parameters from the URLs of
+// links and media in the article content.
+func (ps *Parser) stripTrackingParams(articleContent *html.Node) {
+	params := ps.TrackingParams
+	if params == nil {
+		params = DefaultTrackingParams
+	}
+
+	for _, node := range dom.QuerySelectorAll(articleContent, "[href], [src], [poster], [srcset]") {
+		for _, attrName := range []string{"href", "src", "poster"} {
+			if value := dom.GetAttribute(node, attrName); value != "" {
+				dom.SetAttribute(node, attrName, stripURLParams(value, params))
+			}
+		}
+
+		if srcset := dom.GetAttribute(node, "srcset"); srcset != "" {
+			srcset = rxSrcsetURL.ReplaceAllStringFunc(srcset, func(s string) string {
+				p := rxSrcsetURL.FindStringSubmatch(s)
+				return stripURLParams(p[1], params) + p[2] + p[3]
+			})
+			dom.SetAttribute(node, "srcset", srcset)
+		}
+	}
+}
+
+// stripURLParams removes the matching query parameters from the URL. The
+// order of the rest of parameters is kept as it is.
+func stripURLParams(rawURL string, params []string) string {
+	parsedURL, err := nurl.Parse(rawURL)
+	if err != nil || parsedURL.RawQuery == "" {
+		return rawURL
+	}
+
+	var kept []string
+	removed := false
+	for _, pair := range strings.Split(parsedURL.RawQuery, "&") {
+		key := pair
+		if i := strings.Index(pair, "="); i >= 0 {
+			key = pair[:i]
+		}
+
+		if unescaped, err := nurl.QueryUnescape(key); err == nil {
+			key = unescaped
+		}
+
+		if pair != "" && matchNamePattern(key, params) {
+			removed = true
+		} else if pair != "" {
+			kept = append(kept, pair)
+		}
+	}
+
+	if !removed {
+		return rawURL
+	}
+
+	parsedURL.RawQuery = strings.Join(kept, "&")
+	parsedURL.ForceQuery = false
+	return parsedURL.String()
+}
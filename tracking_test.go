@@ -0,0 +1,59 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_stripURLParams(t *testing.T) {
+	scenarios := map[string]string{
+		"http://fakehost/a?utm_source=x&id=1&utm_medium=y": "http://fakehost/a?id=1",
+		"http://fakehost/a?fbclid=abc":                     "http://fakehost/a",
+		"http://fakehost/a?b=2&a=1&gclid=abc#section":      "http://fakehost/a?b=2&a=1#section",
+		"http://fakehost/a?UTM_Campaign=x&q=go+lang":       "http://fakehost/a?q=go+lang",
+		"http://fakehost/a?id=1&utm":                       "http://fakehost/a?id=1&utm",
+		"http://fakehost/a?q=%3Cb%3E":                      "http://fakehost/a?q=%3Cb%3E",
+		"http://fakehost/a":                                "http://fakehost/a",
+	}
+
+	for rawURL, expected := range scenarios {
+		if result := stripURLParams(rawURL, DefaultTrackingParams); result != expected {
+			t.Errorf("\n"+
+				"url  : %q\n"+
+				"want : %q\n"+
+				"got  : %q", rawURL, expected, result)
+		}
+	}
+}
+
+func Test_Parser_StripTrackingParams(t *testing.T) {
+	paragraph := articleParagraph(10)
+	input := `<html><body><article>` + paragraph +
+		`<p><a href="/post?utm_source=feed&id=1">Link</a> <img src="/a.png?ref=home&fbclid=1" srcset="/a.png?utm_x=1 2x"></p>` +
+		paragraph + `</article></body></html>`
+
+	scenarios := []struct {
+		opts     []Option
+		expected []string
+	}{
+		{nil, []string{`href="http://fakehost/post?utm_source=feed&amp;id=1"`, `src="http://fakehost/a.png?ref=home&amp;fbclid=1"`}},
+		{[]Option{WithStripTrackingParams()}, []string{`href="http://fakehost/post?id=1"`,
+			`src="http://fakehost/a.png?ref=home"`, `srcset="http://fakehost/a.png 2x"`}},
+		{[]Option{WithStripTrackingParams("ref")}, []string{`href="http://fakehost/post?utm_source=feed&amp;id=1"`,
+			`src="http://fakehost/a.png?fbclid=1"`}},
+	}
+
+	for _, scenario := range scenarios {
+		ps := NewParser(scenario.opts...)
+		article, err := ps.Parse(strings.NewReader(input), fakeHostURL)
+		if err != nil {
+			t.Fatalf("failed to parse: %v", err)
+		}
+
+		for _, expected := range scenario.expected {
+			if !strings.Contains(article.Content, expected) {
+				t.Errorf("params %v, want %s in content:\n%s", ps.TrackingParams, expected, article.Content)
+			}
+		}
+	}
+}
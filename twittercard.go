@@ -0,0 +1,70 @@
+package readability
+
+import (
+	"strings"
+
+	"github.com/go-shiori/dom"
+)
+
+// TwitterCard is the Twitter Card metadata of the page.
+type TwitterCard struct {
+	Card        string `json:"card,omitempty"`
+	Site        string `json:"site,omitempty"`
+	Creator     string `json:"creator,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	Image       string `json:"image,omitempty"`
+	ImageAlt    string `json:"image_alt,omitempty"`
+}
+
+// getTwitterCard collects the Twitter Card metadata from meta tags, which
+// may use either name or property attribute. Returns nil if the page
+// doesn't have any Twitter Card metadata.
+func (ps *Parser) getTwitterCard() *TwitterCard {
+	var card TwitterCard
+	found := false
+
+	for _, meta := range dom.GetElementsByTagName(ps.doc, "meta") {
+		name := strings.ToLower(strings.TrimSpace(dom.GetAttribute(meta, "name")))
+		if name == "" {
+			name = strings.ToLower(strings.TrimSpace(dom.GetAttribute(meta, "property")))
+		}
+
+		if !strings.HasPrefix(name, "twitter:") {
+			continue
+		}
+
+		content := strings.TrimSpace(dom.GetAttribute(meta, "content"))
+		if content == "" {
+			// Old markup put the value in value attribute
+			content = strings.TrimSpace(dom.GetAttribute(meta, "value"))
+		}
+
+		if content == "" {
+			continue
+		}
+
+		found = true
+		switch name {
+		case "twitter:card":
+			card.Card = content
+		case "twitter:site":
+			card.Site = content
+		case "twitter:creator":
+			card.Creator = content
+		case "twitter:title":
+			card.Title = content
+		case "twitter:description":
+			card.Description = content
+		case "twitter:image", "twitter:image:src":
+			card.Image = toAbsoluteURI(content, ps.documentURI)
+		case "twitter:image:alt":
+			card.ImageAlt = content
+		}
+	}
+
+	if !found {
+		return nil
+	}
+	return &card
+}
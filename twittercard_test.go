@@ -0,0 +1,51 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_Parser_TwitterCard(t *testing.T) {
+	paragraph := articleParagraph(10)
+	input := `<html><head>` +
+		`<meta name="twitter:card" content="summary_large_image">` +
+		`<meta name="twitter:site" content="@fakehost">` +
+		`<meta name="twitter:creator" content="@johndoe">` +
+		`<meta property="twitter:title" content="The Title">` +
+		`<meta name="twitter:description" value="The description">` +
+		`<meta name="twitter:image" content="/image.png">` +
+		`<meta name="twitter:image:alt" content="The image">` +
+		`</head><body><article>` + paragraph + `</article></body></html>`
+
+	ps := NewParser()
+	article, err := ps.Parse(strings.NewReader(input), fakeHostURL)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	expected := TwitterCard{
+		Card:        "summary_large_image",
+		Site:        "@fakehost",
+		Creator:     "@johndoe",
+		Title:       "The Title",
+		Description: "The description",
+		Image:       "http://fakehost/image.png",
+		ImageAlt:    "The image",
+	}
+
+	if article.TwitterCard == nil || *article.TwitterCard != expected {
+		t.Errorf("\n"+
+			"want : %+v\n"+
+			"got  : %+v", expected, article.TwitterCard)
+	}
+
+	// Page without Twitter Card doesn't have the metadata
+	article, err = ps.Parse(strings.NewReader(`<html><body><article>`+paragraph+`</article></body></html>`), fakeHostURL)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	if article.TwitterCard != nil {
+		t.Errorf("twitter card should be nil, got %+v", article.TwitterCard)
+	}
+}
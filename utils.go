@@ -2,10 +2,65 @@ package readability
 
 import (
 	nurl "net/url"
+	"regexp"
 	"strings"
+	"time"
 	"unicode/utf8"
 )
 
+// dateFormats is the date layouts that commonly used in metadata and
+// article text. Dates without time zone are treated as UTC. The zone
+// abbreviations are converted into their offset before parsing, so only
+// the layouts with numeric offset are listed here.
+var dateFormats = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	"2006/01/02 15:04:05",
+	"2006/01/02",
+	time.RFC1123Z,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Monday, 02-Jan-06 15:04:05 -0700",
+	time.ANSIC,
+	"January 2, 2006 3:04 PM -0700",
+	"January 2, 2006 3:04 PM",
+	"January 2, 2006 15:04",
+	"Monday, January 2, 2006",
+	"January 2, 2006",
+	"Jan 2, 2006 3:04 PM",
+	"Jan 2, 2006",
+	"2 January 2006 15:04",
+	"2 January 2006",
+	"2 Jan 2006",
+}
+
+// timeZoneOffsets is the offset of time zone abbreviations that commonly
+// used in dates. The ambiguous ones follow their most common usage, e.g.
+// CST is the US Central Standard Time. Date with other abbreviation is not
+// parsed, since Go would treat it as UTC which gives the wrong time.
+var timeZoneOffsets = map[string]string{
+	"UT": "+0000", "UTC": "+0000", "GMT": "+0000",
+	"EST": "-0500", "EDT": "-0400", "CST": "-0600", "CDT": "-0500",
+	"MST": "-0700", "MDT": "-0600", "PST": "-0800", "PDT": "-0700",
+	"AKST": "-0900", "AKDT": "-0800", "HST": "-1000",
+	"WET": "+0000", "WEST": "+0100", "BST": "+0100", "CET": "+0100", "CEST": "+0200",
+	"EET": "+0200", "EEST": "+0300", "MSK": "+0300", "IST": "+0530",
+	"JST": "+0900", "KST": "+0900", "AWST": "+0800", "ACST": "+0930",
+	"AEST": "+1000", "AEDT": "+1100", "NZST": "+1200", "NZDT": "+1300",
+}
+
+var (
+	// rxDateOrdinal matches the ordinal suffix of day, e.g. "2nd" in "January 2nd".
+	rxDateOrdinal = regexp.MustCompile(`(?i)\b(\d{1,2})(st|nd|rd|th)\b`)
+	// rxDateTimeZone matches the time zone abbreviation at the end of date.
+	rxDateTimeZone = regexp.MustCompile(`\s([A-Z]{2,4})$`)
+)
+
 // indexOf returns the position of the first occurrence of a
 // specified  value in a string array. Returns -1 if the
 // value to search for never occurs.
@@ -18,6 +73,21 @@ func indexOf(array []string, key string) int {
 	return -1
 }
 
+// matchNamePattern checks if the name matches any of patterns, ignoring
+// the case. Pattern that ends with "*" matches by prefix.
+func matchNamePattern(name string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if prefix := strings.TrimSuffix(pattern, "*"); prefix != pattern {
+			if len(name) >= len(prefix) && strings.EqualFold(name[:len(prefix)], prefix) {
+				return true
+			}
+		} else if strings.EqualFold(name, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
 // wordCount returns number of word in str.
 func wordCount(str string) int {
 	return len(strings.Fields(str))
@@ -98,3 +168,29 @@ func trim(s string) string {
 	s = strings.Join(strings.Fields(s), " ")
 	return strings.TrimSpace(s)
 }
+
+// parseDate parses str which formatted in one of the common date
+// formats. Returns nil if str can't be parsed.
+func parseDate(str string) *time.Time {
+	str = strings.Join(strings.Fields(str), " ")
+	str = rxDateOrdinal.ReplaceAllString(str, "$1")
+	str = strings.Replace(str, "Sept ", "Sep ", 1)
+	if str == "" {
+		return nil
+	}
+
+	if match := rxDateTimeZone.FindStringSubmatch(str); match != nil && match[1] != "AM" && match[1] != "PM" {
+		offset, known := timeZoneOffsets[match[1]]
+		if !known {
+			return nil
+		}
+		str = strings.TrimSuffix(str, match[1]) + offset
+	}
+
+	for _, layout := range dateFormats {
+		if t, err := time.Parse(layout, str); err == nil {
+			return &t
+		}
+	}
+	return nil
+}
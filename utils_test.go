@@ -4,6 +4,7 @@ import (
 	nurl "net/url"
 	"strings"
 	"testing"
+	"time"
 )
 
 func Test_indexOf(t *testing.T) {
@@ -70,3 +71,41 @@ func Test_toAbsoluteURI(t *testing.T) {
 		}
 	}
 }
+
+func Test_parseDate(t *testing.T) {
+	scenarios := map[string]string{
+		"":                                "",
+		"not a date":                      "",
+		"2021-06-27T11:15:28Z":            "2021-06-27T11:15:28Z",
+		"2021-06-27T11:15:28.123+07:00":   "2021-06-27T04:15:28Z",
+		"2021-06-27T11:15:28+0700":        "2021-06-27T04:15:28Z",
+		"2021-06-27 11:15:28":             "2021-06-27T11:15:28Z",
+		"2021/06/27":                      "2021-06-27T00:00:00Z",
+		"Sun, 27 Jun 2021 11:15:28 -0400": "2021-06-27T15:15:28Z",
+		"June 27, 2021":                   "2021-06-27T00:00:00Z",
+		"June 27th, 2021 3:15 PM":         "2021-06-27T15:15:00Z",
+		"Sunday, June 27, 2021":           "2021-06-27T00:00:00Z",
+		"Sept 1, 2021":                    "2021-09-01T00:00:00Z",
+		"  27   June\n2021 ":              "2021-06-27T00:00:00Z",
+		"Sun, 27 Jun 2021 11:15:28 GMT":   "2021-06-27T11:15:28Z",
+		"Sun, 27 Jun 2021 11:15:28 EST":   "2021-06-27T16:15:28Z",
+		"Sunday, 27-Jun-21 11:15:28 CEST": "2021-06-27T09:15:28Z",
+		"June 27, 2021 3:15 PM PDT":       "2021-06-27T22:15:00Z",
+		"Sun, 27 Jun 2021 11:15:28 XYZ":   "",
+		"20210627":                        "",
+	}
+
+	for str, expected := range scenarios {
+		result := ""
+		if date := parseDate(str); date != nil {
+			result = date.UTC().Format(time.RFC3339)
+		}
+
+		if result != expected {
+			t.Errorf("\n"+
+				"date : %q\n"+
+				"want : %q\n"+
+				"got  : %q", str, expected, result)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package readability
+
+import "golang.org/x/net/html"
+
+// visitResult tells walkNodes how to continue after a node is visited.
+type visitResult int
+
+const (
+	// visitChildren continues the traversal into the children of node.
+	visitChildren visitResult = iota
+	// visitSkipChildren skips the children of node, but only for the
+	// visitor that returns it.
+	visitSkipChildren
+	// visitRemoved tells that node has been removed from the document,
+	// so neither the rest of visitors nor its children visit it.
+	visitRemoved
+	// visitDone stops the visitor for the rest of traversal.
+	visitDone
+)
+
+// nodeVisitor is a pass over the document, which can be fused with other
+// passes into a single traversal by walkNodes.
+type nodeVisitor func(node *html.Node) visitResult
+
+// walkNodes walks node and its descendants in document order, and calls
+// each visitor in the order they are given. The result is the same as
+// running each pass over the whole tree one after another, as long as
+// the visitor doesn't depend on what the previous visitors do to the
+// descendants of node, e.g. to remove scripts then check the class names
+// of the remaining elements. It saves a traversal for each fused pass,
+// which is noticeable on large document.
+func walkNodes(node *html.Node, visitors ...nodeVisitor) {
+	if len(visitors) == 0 {
+		return
+	}
+
+	w := nodeWalker{
+		visitors: visitors,
+		skipped:  make([]*html.Node, len(visitors)),
+		done:     make([]bool, len(visitors)),
+	}
+	w.walk(node)
+}
+
+// nodeWalker is the state of walkNodes.
+type nodeWalker struct {
+	visitors []nodeVisitor
+	skipped  []*html.Node
+	done     []bool
+}
+
+// walk visits node with the active visitors, then walks its children.
+func (w *nodeWalker) walk(node *html.Node) {
+	for i, visit := range w.visitors {
+		if w.done[i] || w.skipped[i] != nil {
+			continue
+		}
+
+		switch visit(node) {
+		case visitSkipChildren:
+			w.skipped[i] = node
+		case visitRemoved:
+			return
+		case visitDone:
+			w.done[i] = true
+		}
+	}
+
+	// The subtree is not walked if no visitor needs it
+	if w.hasActiveVisitor() {
+		for child := node.FirstChild; child != nil; {
+			next := child.NextSibling
+			w.walk(child)
+			child = next
+		}
+	}
+
+	for i, skipped := range w.skipped {
+		if skipped == node {
+			w.skipped[i] = nil
+		}
+	}
+}
+
+// hasActiveVisitor checks if any visitor is neither done nor skipped.
+func (w *nodeWalker) hasActiveVisitor() bool {
+	for i := range w.visitors {
+		if !w.done[i] && w.skipped[i] == nil {
+			return true
+		}
+	}
+	return false
+}
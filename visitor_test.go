@@ -0,0 +1,57 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-shiori/dom"
+	"golang.org/x/net/html"
+)
+
+func Test_walkNodes(t *testing.T) {
+	doc, err := dom.FastParse(strings.NewReader(`<html><body>` +
+		`<div id="a"><script>x</script><p id="b">text</p></div>` +
+		`<aside id="c"><p id="d">skipped</p></aside><p id="e">last</p></body></html>`))
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	var visited, collected []string
+	removeScripts := func(node *html.Node) visitResult {
+		if dom.TagName(node) == "script" {
+			node.Parent.RemoveChild(node)
+			return visitRemoved
+		}
+		return visitChildren
+	}
+	skipAside := func(node *html.Node) visitResult {
+		if dom.TagName(node) == "aside" {
+			return visitSkipChildren
+		}
+		if id := dom.ID(node); id != "" {
+			visited = append(visited, id)
+		}
+		return visitChildren
+	}
+	stopAtLast := func(node *html.Node) visitResult {
+		if node.Type == html.ElementNode {
+			collected = append(collected, dom.TagName(node))
+		}
+		if dom.ID(node) == "d" {
+			return visitDone
+		}
+		return visitChildren
+	}
+
+	walkNodes(doc, removeScripts, skipAside, stopAtLast)
+
+	if got := strings.Join(visited, ","); got != "a,b,e" {
+		t.Errorf("visited ids, want %q got %q", "a,b,e", got)
+	}
+	if got := strings.Join(collected, ","); got != "html,head,body,div,p,aside,p" {
+		t.Errorf("collected tags, want %q got %q", "html,head,body,div,p,aside,p", got)
+	}
+	if len(dom.GetElementsByTagName(doc, "script")) != 0 {
+		t.Error("script should be removed")
+	}
+}
@@ -0,0 +1,218 @@
+package readability
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/textproto"
+	nurl "net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WARCReader reads the WARC file, e.g. from Common Crawl or `wget --warc-file`,
+// and parses the HTML page in each of its response records. The other
+// records, like request, metadata or the response that's not a successful
+// HTML page, are skipped. Both the plain and gzipped WARC are supported.
+type WARCReader struct {
+	// Parser is the parser that used to parse the pages. If nil, a parser
+	// with default value will be used.
+	Parser *Parser
+	// MaxBodyBytes is the max size of the HTTP body in record after it's
+	// decoded. The page that exceeds this limit is not parsed, and its
+	// result has ErrBodyTooLarge. Default: 0 (no limit).
+	MaxBodyBytes int64
+
+	reader *bufio.Reader
+	parser *Parser
+}
+
+// WARCRecord is the result of parsing a response record in WARC file.
+type WARCRecord struct {
+	// RecordID is the WARC-Record-ID of record.
+	RecordID string
+	// URL is the target URI of record, which used as the page URL.
+	URL string
+	// Date is the time when the page is archived.
+	Date time.Time
+	// Article is the article that parsed from the page.
+	Article Article
+	// Err is the error that prevents the page from being parsed. The
+	// other records can still be read after it.
+	Err error
+}
+
+// NewWARCReader returns the reader that reads the WARC file from input.
+// The gzipped input is detected and decompressed automatically.
+func NewWARCReader(input io.Reader) (*WARCReader, error) {
+	reader := bufio.NewReader(input)
+	if magic, _ := reader.Peek(2); len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+		gzipReader, err := gzip.NewReader(reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress WARC: %v", err)
+		}
+		reader = bufio.NewReader(gzipReader)
+	}
+
+	return &WARCReader{reader: reader}, nil
+}
+
+// Next reads until the next response record that has HTML page, then
+// parses it. It returns io.EOF once there are no records left. The other
+// error means the WARC file is malformed, so it can't be read any further.
+func (wr *WARCReader) Next() (WARCRecord, error) {
+	return wr.NextWithContext(context.Background())
+}
+
+// NextWithContext is like Next, but the page is parsed using ctx, so it
+// stops parsing and the record has the context's error as soon as ctx is
+// done.
+func (wr *WARCReader) NextWithContext(ctx context.Context) (WARCRecord, error) {
+	for {
+		header, err := wr.readHeader()
+		if err != nil {
+			return WARCRecord{}, err
+		}
+
+		length, err := strconv.ParseInt(header.Get("Content-Length"), 10, 64)
+		if err != nil || length < 0 {
+			return WARCRecord{}, fmt.Errorf("invalid WARC content length %q", header.Get("Content-Length"))
+		}
+
+		block := io.LimitReader(wr.reader, length)
+		record, isPage := wr.readRecord(ctx, header, block)
+
+		// Skip the rest of block, so the next record can be read
+		if _, err := io.Copy(io.Discard, block); err != nil {
+			return WARCRecord{}, fmt.Errorf("failed to read WARC record: %v", err)
+		}
+
+		if isPage {
+			return record, nil
+		}
+	}
+}
+
+// readHeader reads the header of next record, skipping the empty lines
+// that separate the records.
+func (wr *WARCReader) readHeader() (textproto.MIMEHeader, error) {
+	for {
+		line, err := wr.reader.ReadString('\n')
+		if err == io.EOF && strings.TrimSpace(line) == "" {
+			return nil, io.EOF
+		}
+		if err != nil && err != io.EOF {
+			return nil, fmt.Errorf("failed to read WARC record: %v", err)
+		}
+
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		if !strings.HasPrefix(line, "WARC/") {
+			return nil, fmt.Errorf("invalid WARC record version %q", line)
+		}
+		break
+	}
+
+	header, err := textproto.NewReader(wr.reader).ReadMIMEHeader()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read WARC header: %v", err)
+	}
+	return header, nil
+}
+
+// readRecord parses the HTML page in the record. It returns false if the
+// record is not a response record of HTML page.
+func (wr *WARCReader) readRecord(ctx context.Context, header textproto.MIMEHeader, block io.Reader) (WARCRecord, bool) {
+	if header.Get("WARC-Type") != "response" {
+		return WARCRecord{}, false
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(header.Get("Content-Type"))
+	if mediaType != "application/http" {
+		return WARCRecord{}, false
+	}
+
+	// Some writers follow the WARC 1.0 example, which wraps the URI in <>
+	targetURI := strings.Trim(header.Get("WARC-Target-URI"), "<>")
+	record := WARCRecord{
+		RecordID: header.Get("WARC-Record-ID"),
+		URL:      targetURI,
+	}
+	record.Date, _ = time.Parse(time.RFC3339, header.Get("WARC-Date"))
+
+	resp, err := http.ReadResponse(bufio.NewReader(block), nil)
+	if err != nil {
+		record.Err = fmt.Errorf("failed to read HTTP response: %v", err)
+		return record, true
+	}
+	defer resp.Body.Close()
+
+	// Only the successful HTML page has the article
+	contentType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if resp.StatusCode != http.StatusOK || (contentType != "text/html" && contentType != "application/xhtml+xml") {
+		return WARCRecord{}, false
+	}
+
+	pageURL, err := nurl.ParseRequestURI(targetURI)
+	if err != nil {
+		record.Err = fmt.Errorf("failed to parse URL: %v", err)
+		return record, true
+	}
+
+	body, err := wr.readBody(resp)
+	if err != nil {
+		record.Err = err
+		return record, true
+	}
+
+	record.Article, record.Err = wr.getParser().parseContent(ctx, body, pageURL)
+	return record, true
+}
+
+// readBody reads the decoded HTTP body while respecting MaxBodyBytes.
+func (wr *WARCReader) readBody(resp *http.Response) ([]byte, error) {
+	reader, err := decodeContent(resp.Body, resp.Header.Get("Content-Encoding"))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	if wr.MaxBodyBytes > 0 {
+		reader = io.NopCloser(io.LimitReader(reader, wr.MaxBodyBytes+1))
+	}
+
+	// The body may be truncated by the crawler, which is marked using the
+	// WARC-Truncated header, so the partial body is still parsed
+	var body bytes.Buffer
+	if _, err = body.ReadFrom(reader); err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, fmt.Errorf("failed to read the page: %w", err)
+	}
+
+	if wr.MaxBodyBytes > 0 && int64(body.Len()) > wr.MaxBodyBytes {
+		return nil, fmt.Errorf("%w: exceeds %d bytes", ErrBodyTooLarge, wr.MaxBodyBytes)
+	}
+	return body.Bytes(), nil
+}
+
+// getParser returns the parser that used by reader. Unlike Fetcher, the
+// same parser is used for all records since they are read one by one.
+func (wr *WARCReader) getParser() *Parser {
+	if wr.parser == nil {
+		parser := NewParser()
+		if wr.Parser != nil {
+			parser = *wr.Parser
+		}
+		wr.parser = &parser
+	}
+	return wr.parser
+}
@@ -0,0 +1,152 @@
+package readability
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+// testWARCRecord returns the WARC record with the specified type and block.
+func testWARCRecord(recordType, targetURI, contentType, block string) string {
+	return "WARC/1.0\r\n" +
+		"WARC-Type: " + recordType + "\r\n" +
+		"WARC-Record-ID: <urn:uuid:" + recordType + "-" + targetURI + ">\r\n" +
+		"WARC-Date: 2024-01-02T03:04:05Z\r\n" +
+		"WARC-Target-URI: " + targetURI + "\r\n" +
+		"Content-Type: " + contentType + "\r\n" +
+		fmt.Sprintf("Content-Length: %d\r\n", len(block)) +
+		"\r\n" + block + "\r\n\r\n"
+}
+
+// testWARCResponse returns the response record of HTTP response.
+func testWARCResponse(targetURI, status, header, body string) string {
+	block := "HTTP/1.1 " + status + "\r\n" + header + "\r\n" + body
+	return testWARCRecord("response", targetURI, "application/http; msgtype=response", block)
+}
+
+func testWARCFile() []string {
+	paragraph := articleParagraph(10)
+	page := func(title string) string {
+		return `<html><head><title>` + title + `</title></head><body><article>` + paragraph +
+			`<p><a href="/about">About</a></p></article></body></html>`
+	}
+
+	chunked := page("Chunked")
+	return []string{
+		testWARCRecord("warcinfo", "", "application/warc-fields", "software: test\r\n"),
+		testWARCRecord("request", "http://example.com/first", "application/http; msgtype=request",
+			"GET /first HTTP/1.1\r\nHost: example.com\r\n\r\n"),
+		testWARCResponse("http://example.com/first", "200 OK",
+			"Content-Type: text/html; charset=utf-8\r\n", page("First")),
+		testWARCResponse("http://example.com/image.png", "200 OK", "Content-Type: image/png\r\n", "image"),
+		testWARCResponse("http://example.com/missing", "404 Not Found", "Content-Type: text/html\r\n", page("Missing")),
+		testWARCRecord("metadata", "http://example.com/first", "application/warc-fields", "fetchTimeMs: 10\r\n"),
+		testWARCResponse("<https://example.com/chunked>", "200 OK",
+			"Content-Type: text/html\r\nTransfer-Encoding: chunked\r\n",
+			fmt.Sprintf("%x\r\n%s\r\n0\r\n\r\n", len(chunked), chunked)),
+		testWARCResponse("http://example.com/empty", "200 OK", "Content-Type: text/html\r\n", "<html></html>"),
+	}
+}
+
+func Test_WARCReader(t *testing.T) {
+	plain := strings.Join(testWARCFile(), "")
+
+	// Each record is compressed as its own gzip member, like Common Crawl
+	var compressed bytes.Buffer
+	for _, record := range testWARCFile() {
+		gzipWriter := gzip.NewWriter(&compressed)
+		gzipWriter.Write([]byte(record))
+		gzipWriter.Close()
+	}
+
+	for name, input := range map[string]io.Reader{"plain": strings.NewReader(plain), "gzip": &compressed} {
+		reader, err := NewWARCReader(input)
+		if err != nil {
+			t.Fatalf("%s: failed to create reader: %v", name, err)
+		}
+
+		var records []WARCRecord
+		for {
+			record, err := reader.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("%s: failed to read record: %v", name, err)
+			}
+			records = append(records, record)
+		}
+
+		if len(records) != 3 {
+			t.Fatalf("%s: want 3 records, got %d", name, len(records))
+		}
+
+		first := records[0]
+		if first.Err != nil || first.Article.Title != "First" || first.URL != "http://example.com/first" {
+			t.Errorf("%s: want first page, got %+v", name, first)
+		}
+
+		if first.RecordID != "<urn:uuid:response-http://example.com/first>" || first.Date.Year() != 2024 {
+			t.Errorf("%s: want record ID and date, got %q %v", name, first.RecordID, first.Date)
+		}
+
+		if !strings.Contains(first.Article.Content, `href="http://example.com/about"`) {
+			t.Errorf("%s: want URL resolved against target URI, got %s", name, first.Article.Content)
+		}
+
+		chunked := records[1]
+		if chunked.Err != nil || chunked.Article.Title != "Chunked" || chunked.URL != "https://example.com/chunked" {
+			t.Errorf("%s: want chunked page, got %+v", name, chunked)
+		}
+
+		// The page without article still yields the record
+		if records[2].URL != "http://example.com/empty" || records[2].Article.Content != "" {
+			t.Errorf("%s: want empty page, got %+v", name, records[2])
+		}
+	}
+}
+
+func Test_WARCReader_MaxBodyBytes(t *testing.T) {
+	reader, err := NewWARCReader(strings.NewReader(strings.Join(testWARCFile(), "")))
+	if err != nil {
+		t.Fatalf("failed to create reader: %v", err)
+	}
+
+	reader.MaxBodyBytes = 100
+	record, err := reader.Next()
+	if err != nil {
+		t.Fatalf("failed to read record: %v", err)
+	}
+
+	if !errors.Is(record.Err, ErrBodyTooLarge) {
+		t.Errorf("want ErrBodyTooLarge, got %v", record.Err)
+	}
+
+	// The next records can still be read
+	if record, err = reader.Next(); err != nil || record.URL != "https://example.com/chunked" {
+		t.Errorf("want chunked page, got %+v %v", record, err)
+	}
+}
+
+func Test_WARCReader_malformed(t *testing.T) {
+	scenarios := []string{
+		"not a WARC file\r\n",
+		"WARC/1.0\r\nWARC-Type: response\r\nContent-Length: abc\r\n\r\n",
+		"WARC/1.0\r\nWARC-Type: response\r\n",
+	}
+
+	for _, input := range scenarios {
+		reader, err := NewWARCReader(strings.NewReader(input))
+		if err != nil {
+			t.Fatalf("failed to create reader: %v", err)
+		}
+
+		if _, err = reader.Next(); err == nil || err == io.EOF {
+			t.Errorf("%q: want error, got %v", input, err)
+		}
+	}
+}